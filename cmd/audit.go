@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/services"
+	"github.com/spf13/cobra"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Inspect the audit log.",
+	Long:  `The audit command lets you inspect all mutating actions (including shell sessions) performed through punq.`,
+}
+
+var listAuditCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List audit entries.",
+	Long:  `The list command lets you list the newest audit entries. Use the flags to filter by user, context, kind or namespace.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		entries := services.ListAuditEntries(dtos.PunqAuditFilter{
+			User:      userFilter,
+			ContextId: contextFilter,
+			Kind:      kind,
+			Namespace: namespace,
+			Limit:     limit,
+		})
+		dtos.ListAuditEntriesToTerminal(entries)
+	},
+}
+
+func init() {
+	auditCmd.AddCommand(listAuditCmd)
+	listAuditCmd.Flags().StringVarP(&userFilter, "user", "u", "", "Filter by user id or email")
+	listAuditCmd.Flags().StringVar(&contextFilter, "context", "", "Filter by context id")
+	listAuditCmd.Flags().StringVarP(&kind, "kind", "k", "", "Filter by kind (e.g. Pod)")
+	listAuditCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Filter by namespace")
+	listAuditCmd.Flags().IntVar(&limit, "limit", 50, "Maximum number of entries")
+
+	rootCmd.AddCommand(auditCmd)
+}
@@ -29,6 +29,24 @@ var listContextCmd = &cobra.Command{
 	},
 }
 
+var statusContextCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Check the health of punq contexts.",
+	Long: `The status command probes the api server of all contexts (or the given one) and shows reachability, latency, kubernetes version, node count and the expiry of the credentials.
+The operator does the same in the background (see context_health.interval) and serves the results at /context/health.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		contexts := services.ListContexts()
+		if cmd.Flags().Changed("context-id") {
+			ctx, _ := services.GetContext(contextId)
+			if ctx == nil {
+				utils.FatalError(fmt.Sprintf("context '%s' not found.", contextId))
+			}
+			contexts = []dtos.PunqContext{*ctx}
+		}
+		dtos.ListContextHealthToTerminal(services.ProbeContexts(contexts))
+	},
+}
+
 var addContextCmd = &cobra.Command{
 	Use:   "add",
 	Short: "Add punq context.",
@@ -94,18 +112,25 @@ var addContextCmd = &cobra.Command{
 var addContextAccessCmd = &cobra.Command{
 	Use:   "add-access",
 	Short: "Add access to punq context.",
-	Long:  `The add-access command lets you add a user + access level to a context in punq.`,
+	Long:  `The add-access command lets you add a user or group + access level to a context in punq.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		RequireStringFlag(contextId, "context-id")
 		RequireStringFlag(accessLevel, "access-level")
-		RequireStringFlag(userId, "user-id")
+		requireUserOrGroupFlag()
 
 		ctx, _ := services.GetContext(contextId)
 		if ctx == nil {
 			utils.FatalError(fmt.Sprintf("context '%s' not found.", contextId))
 		}
 
-		ctx.AddAccess(userId, dtos.AccessLevelFromString(accessLevel))
+		if groupId != "" {
+			if _, err := services.GetGroup(groupId); err != nil {
+				utils.FatalError(err.Error())
+			}
+			ctx.AddGroupAccess(groupId, dtos.AccessLevelFromString(accessLevel))
+		} else {
+			ctx.AddAccess(userId, dtos.AccessLevelFromString(accessLevel))
+		}
 		services.UpdateContext(*ctx)
 	},
 }
@@ -113,21 +138,84 @@ var addContextAccessCmd = &cobra.Command{
 var removeContextAccessCmd = &cobra.Command{
 	Use:   "remove-access",
 	Short: "Remove access from punq context.",
-	Long:  `The remove-access command lets you remove a users access level from a context in punq.`,
+	Long:  `The remove-access command lets you remove a users or groups access level from a context in punq.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		RequireStringFlag(contextId, "context-id")
-		RequireStringFlag(userId, "user-id")
+		requireUserOrGroupFlag()
 
 		ctx, _ := services.GetContext(contextId)
 		if ctx == nil {
 			utils.FatalError(fmt.Sprintf("context '%s' not found.", contextId))
 		}
 
-		ctx.RemoveAccess(userId)
+		if groupId != "" {
+			ctx.RemoveGroupAccess(groupId)
+		} else {
+			ctx.RemoveAccess(userId)
+		}
 		services.UpdateContext(*ctx)
 	},
 }
 
+var impersonationContextCmd = &cobra.Command{
+	Use:   "impersonation",
+	Short: "Configure kubernetes impersonation of a punq context.",
+	Long: `The impersonation command lets you run all kubernetes requests of a context as the punq user, so the RBAC of the cluster decides what each user can do.
+The kubeconfig of the context needs the "impersonate" verb on users and groups. Only given flags are changed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(contextId, "context-id")
+
+		ctx, _ := services.GetContext(contextId)
+		if ctx == nil {
+			utils.FatalError(fmt.Sprintf("context '%s' not found.", contextId))
+		}
+
+		impersonation := ctx.Impersonation
+		if impersonation == nil {
+			impersonation = &dtos.PunqImpersonation{}
+		}
+		impersonation.Enabled = !impersonationDisable
+		if cmd.Flags().Changed("username-attribute") {
+			impersonation.UsernameAttribute = impersonationUsernameAttribute
+		}
+		if cmd.Flags().Changed("username-prefix") {
+			impersonation.UsernamePrefix = impersonationUsernamePrefix
+		}
+		if cmd.Flags().Changed("group-prefix") {
+			impersonation.GroupPrefix = impersonationGroupPrefix
+		}
+		if cmd.Flags().Changed("extra-groups") {
+			impersonation.ExtraGroups = impersonationExtraGroups
+		}
+		if cmd.Flags().Changed("user-map") {
+			impersonation.Users = impersonationUserMap
+		}
+		if cmd.Flags().Changed("group-map") {
+			impersonation.Groups = map[string][]string{}
+			for _, entry := range impersonationGroupMap {
+				name, group, found := strings.Cut(entry, "=")
+				if !found || name == "" || group == "" {
+					utils.FatalError(fmt.Sprintf("invalid group mapping '%s' (expected punqGroup=kubernetesGroup).", entry))
+				}
+				impersonation.Groups[name] = append(impersonation.Groups[name], group)
+			}
+		}
+
+		ctx, err := services.SetContextImpersonation(contextId, impersonation)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		utils.PrintInfo("Impersonation updated succesfully ✅.")
+		dtos.ListContextsToTerminal([]dtos.PunqContext{*ctx})
+	},
+}
+
+func requireUserOrGroupFlag() {
+	if (userId == "") == (groupId == "") {
+		utils.FatalError("Either --user-id or --group-id is required.")
+	}
+}
+
 var deleteContextCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete punq context.",
@@ -181,12 +269,25 @@ var exportNamespaceCmd = &cobra.Command{
 func init() {
 	contextCmd.AddCommand(listContextCmd)
 
+	contextCmd.AddCommand(statusContextCmd)
+
 	contextCmd.AddCommand(addContextAccessCmd)
 	addContextAccessCmd.Flags().StringVarP(&userId, "user-id", "u", "", "Id of the user you want to add")
-	addContextAccessCmd.Flags().StringVarP(&accessLevel, "access-level", "l", string(dtos.ADMIN), "Access level of the user you want to add (READER, USER, ADMIN)")
+	addContextAccessCmd.Flags().StringVarP(&groupId, "group-id", "g", "", "Id of the group you want to add")
+	addContextAccessCmd.Flags().StringVarP(&accessLevel, "access-level", "l", "ADMIN", "Access level of the user or group you want to add (READER, USER, ADMIN)")
 
 	contextCmd.AddCommand(removeContextAccessCmd)
 	removeContextAccessCmd.Flags().StringVarP(&userId, "user-id", "u", "", "Id of the user you want to add")
+	removeContextAccessCmd.Flags().StringVarP(&groupId, "group-id", "g", "", "Id of the group you want to remove")
+
+	contextCmd.AddCommand(impersonationContextCmd)
+	impersonationContextCmd.Flags().BoolVar(&impersonationDisable, "disable", false, "Disable impersonation (the settings are kept)")
+	impersonationContextCmd.Flags().StringVar(&impersonationUsernameAttribute, "username-attribute", "", "User attribute used as kubernetes username (email, id)")
+	impersonationContextCmd.Flags().StringVar(&impersonationUsernamePrefix, "username-prefix", "", "Prefix of kubernetes usernames, e.g. punq:")
+	impersonationContextCmd.Flags().StringVar(&impersonationGroupPrefix, "group-prefix", "", "Prefix of kubernetes groups created from punq groups")
+	impersonationContextCmd.Flags().StringSliceVar(&impersonationExtraGroups, "extra-groups", []string{}, "Kubernetes groups added for every user")
+	impersonationContextCmd.Flags().StringToStringVar(&impersonationUserMap, "user-map", map[string]string{}, "Explicit kubernetes usernames by punq user id, e.g. userId=jane")
+	impersonationContextCmd.Flags().StringArrayVar(&impersonationGroupMap, "group-map", []string{}, "Additional kubernetes group of a punq group (repeatable), e.g. developers=team-a:edit")
 
 	contextCmd.AddCommand(addContextCmd)
 	addContextCmd.Flags().StringVarP(&filePath, "filepath", "f", "", "FilePath to the context you want to add")
@@ -4,21 +4,70 @@ Copyright © 2022 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"context"
+	"os"
+
 	"github.com/mogenius/punq/kubernetes"
+	"github.com/mogenius/punq/utils"
+	"golang.org/x/term"
+	"k8s.io/client-go/tools/remotecommand"
 
 	"github.com/spf13/cobra"
 )
 
+type terminalSizeOnce struct {
+	sent bool
+}
+
+func (t *terminalSizeOnce) Next() *remotecommand.TerminalSize {
+	if t.sent {
+		return nil
+	}
+	t.sent = true
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return nil
+	}
+	return &remotecommand.TerminalSize{Width: uint16(width), Height: uint16(height)}
+}
+
 var execCmd = &cobra.Command{
 	Use:   "exec",
 	Short: "Exec a command in a container.",
 	Long:  `Exec a command in a container.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		kubernetes.ExecTest()
+		RequireStringFlag(namespace, "namespace")
+		RequireStringFlag(resource, "resource")
+		RequireStringFlag(container, "container")
+
+		oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
+		if err == nil {
+			defer term.Restore(int(os.Stdin.Fd()), oldState)
+		}
+
+		err = kubernetes.ExecStream(context.Background(), kubernetes.ExecOptions{
+			Namespace:  namespace,
+			PodName:    resource,
+			Container:  container,
+			Mode:       execMode,
+			DebugImage: debugImage,
+			Command:    args,
+			Stdin:      os.Stdin,
+			Stdout:     os.Stdout,
+			SizeQueue:  &terminalSizeOnce{},
+		}, &contextId)
+		if err != nil {
+			utils.PrintError(err.Error())
+		}
 	},
 }
 
 func init() {
 	execCmd.Hidden = true
+	execCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Define a namespace")
+	execCmd.Flags().StringVarP(&resource, "resource", "r", "", "Define a pod name")
+	execCmd.Flags().StringVarP(&container, "container", "", "", "Define a container name")
+	execCmd.Flags().StringVarP(&execMode, "mode", "m", kubernetes.EXEC_MODE_EXEC, "exec, attach or debug")
+	execCmd.Flags().StringVarP(&debugImage, "image", "i", kubernetes.DEFAULT_DEBUG_IMAGE, "Image of the ephemeral debug container")
 	rootCmd.AddCommand(execCmd)
 }
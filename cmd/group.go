@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/services"
+	"github.com/mogenius/punq/structs"
+	"github.com/mogenius/punq/utils"
+	"github.com/spf13/cobra"
+)
+
+var groupCmd = &cobra.Command{
+	Use:   "group",
+	Short: "Manage punq groups.",
+	Long:  `The group command lets you manage groups of users. Contexts can grant access to groups (see punq context add-access --group-id).`,
+}
+
+var listGroupCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List punq groups.",
+	Long:  `The list command lets you list all groups of punq.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		groups, err := services.ListGroups()
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		dtos.ListGroupsToTerminal(groups)
+	},
+}
+
+var addGroupCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add punq group.",
+	Long:  `The add command lets you add a group into punq.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(groupName, "name")
+
+		group, err := services.AddGroup(dtos.PunqGroupCreateInput{
+			Name:        groupName,
+			Description: groupDescription,
+			Members:     groupMembers,
+		})
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		utils.PrintInfo("Group added succesfully ✅.")
+		dtos.ListGroupsToTerminal([]dtos.PunqGroup{*group})
+	},
+}
+
+var updateGroupCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update punq group.",
+	Long:  `The update command lets you change name and description of a group.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(groupId, "group-id")
+
+		group, err := services.GetGroup(groupId)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		if groupName != "" {
+			group.Name = groupName
+		}
+		if cmd.Flags().Changed("description") {
+			group.Description = groupDescription
+		}
+		group, err = services.UpdateGroup(*group)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		utils.PrintInfo("Group updated succesfully ✅.")
+		dtos.ListGroupsToTerminal([]dtos.PunqGroup{*group})
+	},
+}
+
+var deleteGroupCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete punq group.",
+	Long:  `The delete command lets you delete a group. Its access to contexts is removed as well.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(groupId, "group-id")
+
+		err := services.DeleteGroup(groupId)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		utils.PrintInfo(fmt.Sprintf("Group %s successfully deleted.", groupId))
+	},
+}
+
+var getGroupCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get specific punq group.",
+	Long:  `The get command lets you get a specific group of punq.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(groupId, "group-id")
+
+		group, err := services.GetGroup(groupId)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		structs.PrettyPrint(group)
+	},
+}
+
+var addMemberGroupCmd = &cobra.Command{
+	Use:   "add-member",
+	Short: "Add a user to a punq group.",
+	Long:  `The add-member command lets you add a user to a group.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(groupId, "group-id")
+		RequireStringFlag(userId, "user-id")
+
+		_, err := services.AddGroupMember(groupId, userId)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		utils.PrintInfo(fmt.Sprintf("User %s successfully added to group %s.", userId, groupId))
+	},
+}
+
+var removeMemberGroupCmd = &cobra.Command{
+	Use:   "remove-member",
+	Short: "Remove a user from a punq group.",
+	Long:  `The remove-member command lets you remove a user from a group.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(groupId, "group-id")
+		RequireStringFlag(userId, "user-id")
+
+		_, err := services.RemoveGroupMember(groupId, userId)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		utils.PrintInfo(fmt.Sprintf("User %s successfully removed from group %s.", userId, groupId))
+	},
+}
+
+func init() {
+	groupCmd.AddCommand(listGroupCmd)
+
+	groupCmd.AddCommand(addGroupCmd)
+	addGroupCmd.Flags().StringVar(&groupName, "name", "", "Name of the new group")
+	addGroupCmd.Flags().StringVar(&groupDescription, "description", "", "Description of the new group")
+	addGroupCmd.Flags().StringSliceVar(&groupMembers, "members", []string{}, "UserIds of the members")
+
+	groupCmd.AddCommand(updateGroupCmd)
+	updateGroupCmd.Flags().StringVarP(&groupId, "group-id", "g", "", "Id of the group")
+	updateGroupCmd.Flags().StringVar(&groupName, "name", "", "Name of the group")
+	updateGroupCmd.Flags().StringVar(&groupDescription, "description", "", "Description of the group")
+
+	groupCmd.AddCommand(deleteGroupCmd)
+	deleteGroupCmd.Flags().StringVarP(&groupId, "group-id", "g", "", "Id of the group")
+
+	groupCmd.AddCommand(getGroupCmd)
+	getGroupCmd.Flags().StringVarP(&groupId, "group-id", "g", "", "Id of the group")
+
+	groupCmd.AddCommand(addMemberGroupCmd)
+	addMemberGroupCmd.Flags().StringVarP(&groupId, "group-id", "g", "", "Id of the group")
+	addMemberGroupCmd.Flags().StringVarP(&userId, "user-id", "u", "", "UserId of the new member")
+
+	groupCmd.AddCommand(removeMemberGroupCmd)
+	removeMemberGroupCmd.Flags().StringVarP(&groupId, "group-id", "g", "", "Id of the group")
+	removeMemberGroupCmd.Flags().StringVarP(&userId, "user-id", "u", "", "UserId of the member")
+
+	rootCmd.AddCommand(groupCmd)
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mogenius/punq/kubernetes"
+	"github.com/mogenius/punq/utils"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+var helmCmd = &cobra.Command{
+	Use:   "helm",
+	Short: "Browse and manage helm releases.",
+	Long:  `The helm command lets you inspect, roll back and uninstall helm releases of a context (without the helm binary).`,
+}
+
+var helmListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List helm releases.",
+	Long:  `The list command lets you list the latest revision of all helm releases.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		kubernetes.ListHelmReleasesTerminal(namespace, &contextId)
+	},
+}
+
+var helmHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the revisions of a helm release.",
+	Long:  `The history command lets you list all revisions of a helm release.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(namespace, "namespace")
+		RequireStringFlag(resource, "resource")
+
+		kubernetes.ListHelmReleaseHistoryTerminal(namespace, resource, &contextId)
+	},
+}
+
+var helmValuesCmd = &cobra.Command{
+	Use:   "values",
+	Short: "Show the values of a helm release.",
+	Long:  `The values command prints the user supplied values (or all values using --all) of a helm release revision.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(namespace, "namespace")
+		RequireStringFlag(resource, "resource")
+
+		values, err := kubernetes.HelmReleaseValues(namespace, resource, revision, allValues, &contextId)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		yamlData, err := yaml.Marshal(values)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		fmt.Println(string(yamlData))
+	},
+}
+
+var helmManifestCmd = &cobra.Command{
+	Use:   "manifest",
+	Short: "Show the manifest of a helm release.",
+	Long:  `The manifest command prints the rendered manifest of a helm release revision.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(namespace, "namespace")
+		RequireStringFlag(resource, "resource")
+
+		manifest, err := kubernetes.HelmReleaseManifest(namespace, resource, revision, &contextId)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		fmt.Println(manifest)
+	},
+}
+
+var helmDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Diff two revisions of a helm release.",
+	Long:  `The diff command prints the differences of values and manifest between two revisions (default: latest and the one before).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(namespace, "namespace")
+		RequireStringFlag(resource, "resource")
+
+		diff, err := kubernetes.HelmReleaseDiff(namespace, resource, fromRevision, toRevision, &contextId)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		utils.PrintInfo(fmt.Sprintf("Values (revision %d -> %d):", diff.FromRevision, diff.ToRevision))
+		fmt.Println(diff.Values)
+		utils.PrintInfo(fmt.Sprintf("Manifest (revision %d -> %d):", diff.FromRevision, diff.ToRevision))
+		fmt.Println(diff.Manifest)
+	},
+}
+
+var helmRollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Roll back a helm release.",
+	Long:  `The rollback command re-applies the manifest of the given revision and records it as a new revision.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(namespace, "namespace")
+		RequireStringFlag(resource, "resource")
+		if revision <= 0 {
+			utils.FatalError("--revision flag is required for this command.")
+		}
+
+		release, err := kubernetes.HelmRollback(namespace, resource, revision, &contextId)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		fmt.Printf("Rolled back '%s/%s' to revision %d (new revision %d) ✅.\n", namespace, resource, revision, release.Version)
+	},
+}
+
+var helmUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Uninstall a helm release.",
+	Long:  `The uninstall command deletes all objects and the history of a helm release.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(namespace, "namespace")
+		RequireStringFlag(resource, "resource")
+
+		if !utils.ConfirmTask(fmt.Sprintf("Do you really want to uninstall helm release '%s/%s'?", namespace, resource), 1) {
+			return
+		}
+		err := kubernetes.HelmUninstall(namespace, resource, &contextId)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		fmt.Printf("Helm release '%s/%s' uninstalled ✅.\n", namespace, resource)
+	},
+}
+
+func init() {
+	helmCmd.AddCommand(helmListCmd)
+	helmListCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Define a namespace")
+
+	helmCmd.AddCommand(helmHistoryCmd)
+	helmHistoryCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Define a namespace")
+	helmHistoryCmd.Flags().StringVarP(&resource, "resource", "r", "", "Define a release name")
+
+	helmCmd.AddCommand(helmValuesCmd)
+	helmValuesCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Define a namespace")
+	helmValuesCmd.Flags().StringVarP(&resource, "resource", "r", "", "Define a release name")
+	helmValuesCmd.Flags().IntVar(&revision, "revision", 0, "Define a revision (default: latest)")
+	helmValuesCmd.Flags().BoolVarP(&allValues, "all", "a", false, "Include the default values of the chart")
+
+	helmCmd.AddCommand(helmManifestCmd)
+	helmManifestCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Define a namespace")
+	helmManifestCmd.Flags().StringVarP(&resource, "resource", "r", "", "Define a release name")
+	helmManifestCmd.Flags().IntVar(&revision, "revision", 0, "Define a revision (default: latest)")
+
+	helmCmd.AddCommand(helmDiffCmd)
+	helmDiffCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Define a namespace")
+	helmDiffCmd.Flags().StringVarP(&resource, "resource", "r", "", "Define a release name")
+	helmDiffCmd.Flags().IntVar(&fromRevision, "from", 0, "Define the old revision (default: the one before --to)")
+	helmDiffCmd.Flags().IntVar(&toRevision, "to", 0, "Define the new revision (default: latest)")
+
+	helmCmd.AddCommand(helmRollbackCmd)
+	helmRollbackCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Define a namespace")
+	helmRollbackCmd.Flags().StringVarP(&resource, "resource", "r", "", "Define a release name")
+	helmRollbackCmd.Flags().IntVar(&revision, "revision", 0, "Define the revision to roll back to")
+
+	helmCmd.AddCommand(helmUninstallCmd)
+	helmUninstallCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Define a namespace")
+	helmUninstallCmd.Flags().StringVarP(&resource, "resource", "r", "", "Define a release name")
+
+	rootCmd.AddCommand(helmCmd)
+}
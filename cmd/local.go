@@ -24,6 +24,8 @@ var localCmd = &cobra.Command{
 
 		contexts := services.ListContexts()
 		utils.PrintInfo(fmt.Sprintf("Initialized operator with %d contexts.", len(contexts)))
+		services.StartContextHealthMonitor()
+		services.FlushAuditOnShutdown()
 
 		go operator.InitFrontend()
 		utils.OpenBrowser(fmt.Sprintf("http://%s:%d", utils.CONFIG.Frontend.Host, utils.CONFIG.Frontend.Port))
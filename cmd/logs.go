@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/mogenius/punq/kubernetes"
+	"github.com/mogenius/punq/utils"
+	"github.com/spf13/cobra"
+)
+
+var logPrefixColors = []color.Attribute{color.FgCyan, color.FgGreen, color.FgMagenta, color.FgYellow, color.FgBlue, color.FgHiCyan, color.FgHiGreen, color.FgHiMagenta}
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Stream aggregated logs of multiple pods/containers.",
+	Long: `The logs command streams the logs of all containers of all pods matching a label selector (--selector) or owner (--owner deployment/name).
+Lines are interleaved by timestamp and prefixed with pod/container. Use --include/--exclude to filter lines by regex.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := kubernetes.K8sLogStreamOptions{
+			Namespace:     namespace,
+			LabelSelector: labelSelector,
+			Containers:    logContainers,
+			Include:       includePattern,
+			Exclude:       excludePattern,
+			SinceSeconds:  int64(since.Seconds()),
+			TailLines:     tailLines,
+			Follow:        follow,
+		}
+		if owner != "" {
+			opts.OwnerKind, opts.OwnerName = parseOwnerFlag(owner)
+		}
+
+		logStream, err := kubernetes.NewAggregatedLogStream(opts, &contextId)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+		err = logStream.Run(ctx, func(line kubernetes.K8sLogLine) {
+			fmt.Printf("%s %s\n", logPrefixColor(line.Prefix())(line.Prefix()), line.Line)
+		})
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+	},
+}
+
+var logsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export logs, events and describe output as tar.gz.",
+	Long: `The export command creates a support bundle (tar.gz) for all pods matching a label selector (--selector) or owner (--owner deployment/name).
+It contains the current and previous log of every container, the related events and the describe output.
+--since and --until accept a duration (e.g. 2h = two hours ago) or an RFC3339 timestamp.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(namespace, "namespace")
+
+		opts := kubernetes.K8sLogExportOptions{
+			Namespace:     namespace,
+			LabelSelector: labelSelector,
+			Since:         parseTimeFlag(sinceTime, "since"),
+			Until:         parseTimeFlag(untilTime, "until"),
+		}
+		if owner != "" {
+			opts.OwnerKind, opts.OwnerName = parseOwnerFlag(owner)
+		}
+
+		if filePath == "" {
+			filePath = kubernetes.LogExportFileName(opts)
+		}
+		file, err := os.Create(filePath)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		defer file.Close()
+
+		err = kubernetes.ExportLogs(opts, &contextId, file)
+		if err != nil {
+			os.Remove(filePath)
+			utils.FatalError(err.Error())
+		}
+		fmt.Printf("Logs exported to '%s' ✅.\n", filePath)
+	},
+}
+
+func parseOwnerFlag(value string) (string, string) {
+	kind, name, found := strings.Cut(value, "/")
+	if !found {
+		utils.FatalError("--owner must be in the format kind/name (e.g. deployment/my-app).")
+	}
+	return kind, name
+}
+
+// parseTimeFlag accepts a duration (relative to now) or an RFC3339 timestamp
+func parseTimeFlag(value string, flagName string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	if duration, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-duration)
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		utils.FatalError(fmt.Sprintf("--%s must be a duration (e.g. 2h) or an RFC3339 timestamp.", flagName))
+	}
+	return parsed
+}
+
+// the same pod/container always gets the same color
+func logPrefixColor(prefix string) func(a ...interface{}) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(prefix))
+	return color.New(logPrefixColors[hash.Sum32()%uint32(len(logPrefixColors))]).SprintFunc()
+}
+
+func init() {
+	logsCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Define a namespace")
+	logsCmd.Flags().StringVarP(&labelSelector, "selector", "l", "", "Define a label selector (e.g. app=web)")
+	logsCmd.Flags().StringVarP(&owner, "owner", "o", "", "Define an owner (deployment|statefulset|daemonset|replicaset|job)/name")
+	logsCmd.Flags().StringSliceVar(&logContainers, "container", []string{}, "Define containers (default: all)")
+	logsCmd.Flags().StringVar(&includePattern, "include", "", "Only show lines matching this regex")
+	logsCmd.Flags().StringVar(&excludePattern, "exclude", "", "Hide lines matching this regex")
+	logsCmd.Flags().DurationVar(&since, "since", 0, "Only show lines newer than this duration (e.g. 10m)")
+	logsCmd.Flags().Int64Var(&tailLines, "tail", 100, "Number of lines per container")
+	logsCmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep streaming new lines")
+
+	logsCmd.AddCommand(logsExportCmd)
+	logsExportCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Define a namespace")
+	logsExportCmd.Flags().StringVarP(&labelSelector, "selector", "l", "", "Define a label selector (e.g. app=web)")
+	logsExportCmd.Flags().StringVarP(&owner, "owner", "o", "", "Define an owner (deployment|statefulset|daemonset|replicaset|job)/name")
+	logsExportCmd.Flags().StringVar(&sinceTime, "since", "", "Start of the time range (duration like 2h or RFC3339 timestamp)")
+	logsExportCmd.Flags().StringVar(&untilTime, "until", "", "End of the time range (duration like 1h or RFC3339 timestamp)")
+	logsExportCmd.Flags().StringVarP(&filePath, "file", "f", "", "Define the output file (default: generated name)")
+
+	rootCmd.AddCommand(logsCmd)
+}
@@ -26,6 +26,8 @@ var operatorCmd = &cobra.Command{
 		contexts := services.ListContexts()
 		utils.PrintInfo(fmt.Sprintf("Initialized operator with %d contexts.", len(contexts)))
 		kubernetes.ContextAddMany(contexts)
+		services.StartContextHealthMonitor()
+		services.FlushAuditOnShutdown()
 
 		go operator.InitBackend()
 		go operator.InitWebsocket()
@@ -58,7 +58,7 @@ var proxyCmd = &cobra.Command{
 			break
 		}
 
-		quit := make(chan os.Signal)
+		quit := make(chan os.Signal, 1)
 		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 		<-quit
 	},
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/services"
+	"github.com/mogenius/punq/structs"
+	"github.com/mogenius/punq/utils"
+	"github.com/spf13/cobra"
+)
+
+var roleCmd = &cobra.Command{
+	Use:   "role",
+	Short: "Manage punq roles.",
+	Long: `The role command lets you manage roles, which allow verbs on kinds of workloads in namespaces and contexts.
+Every user gets the built-in role of its access level (reader, user, admin). Custom roles are bound to users and groups and add permissions.
+Rules look like "verbs=get,restart;kinds=Deployment;namespaces=team-a-*;contexts=<context-id>" (namespaces and contexts are optional).`,
+}
+
+var listRoleCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List punq roles.",
+	Long:  `The list command lets you list the built-in and custom roles of punq.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		roles, err := services.ListRoles()
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		dtos.ListRolesToTerminal(roles)
+	},
+}
+
+var kindsRoleCmd = &cobra.Command{
+	Use:   "kinds",
+	Short: "List kinds for role rules.",
+	Long:  `The kinds command lets you list all kinds which can be used in rules (besides *).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, kind := range services.RoleKinds() {
+			fmt.Println(kind)
+		}
+	},
+}
+
+var addRoleCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add punq role.",
+	Long:  `The add command lets you add a custom role into punq.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(roleName, "name")
+
+		rules := parseRoleRules()
+		if len(rules) == 0 {
+			utils.FatalError("At least one --rule is required.")
+		}
+		role, err := services.AddRole(dtos.PunqRoleCreateInput{
+			Name:        roleName,
+			Description: roleDescription,
+			Rules:       rules,
+			Users:       roleUsers,
+			Groups:      roleGroups,
+		})
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		utils.PrintInfo("Role added succesfully ✅.")
+		dtos.ListRolesToTerminal([]dtos.PunqRole{*role})
+	},
+}
+
+var updateRoleCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update punq role.",
+	Long:  `The update command lets you change a custom role. Given rules, users and groups replace the existing ones.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(roleId, "role-id")
+
+		role, err := services.GetRole(roleId)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		if roleName != "" {
+			role.Name = roleName
+		}
+		if cmd.Flags().Changed("description") {
+			role.Description = roleDescription
+		}
+		if cmd.Flags().Changed("rule") {
+			role.Rules = parseRoleRules()
+		}
+		if cmd.Flags().Changed("users") {
+			role.Users = roleUsers
+		}
+		if cmd.Flags().Changed("groups") {
+			role.Groups = roleGroups
+		}
+		role, err = services.UpdateRole(*role)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		utils.PrintInfo("Role updated succesfully ✅.")
+		dtos.ListRolesToTerminal([]dtos.PunqRole{*role})
+	},
+}
+
+var deleteRoleCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete punq role.",
+	Long:  `The delete command lets you delete a custom role.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(roleId, "role-id")
+
+		err := services.DeleteRole(roleId)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		utils.PrintInfo(fmt.Sprintf("Role %s successfully deleted.", roleId))
+	},
+}
+
+var getRoleCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get specific punq role.",
+	Long:  `The get command lets you get a specific role of punq.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(roleId, "role-id")
+
+		role, err := services.GetRole(roleId)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		structs.PrettyPrint(role)
+	},
+}
+
+func parseRoleRules() []dtos.PunqRule {
+	rules := []dtos.PunqRule{}
+	for _, entry := range roleRules {
+		rule, err := dtos.ParseRule(entry)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func init() {
+	roleCmd.AddCommand(listRoleCmd)
+	roleCmd.AddCommand(kindsRoleCmd)
+
+	roleCmd.AddCommand(addRoleCmd)
+	addRoleCmd.Flags().StringVar(&roleName, "name", "", "Name of the new role")
+	addRoleCmd.Flags().StringVar(&roleDescription, "description", "", "Description of the new role")
+	addRoleCmd.Flags().StringArrayVar(&roleRules, "rule", []string{}, "Rule of the role (repeatable), e.g. \"verbs=get,restart;kinds=Deployment;namespaces=team-a-*\"")
+	addRoleCmd.Flags().StringSliceVar(&roleUsers, "users", []string{}, "UserIds the role is bound to")
+	addRoleCmd.Flags().StringSliceVar(&roleGroups, "groups", []string{}, "GroupIds the role is bound to")
+
+	roleCmd.AddCommand(updateRoleCmd)
+	updateRoleCmd.Flags().StringVarP(&roleId, "role-id", "r", "", "Id of the role")
+	updateRoleCmd.Flags().StringVar(&roleName, "name", "", "Name of the role")
+	updateRoleCmd.Flags().StringVar(&roleDescription, "description", "", "Description of the role")
+	updateRoleCmd.Flags().StringArrayVar(&roleRules, "rule", []string{}, "Rule of the role (repeatable, replaces all rules)")
+	updateRoleCmd.Flags().StringSliceVar(&roleUsers, "users", []string{}, "UserIds the role is bound to")
+	updateRoleCmd.Flags().StringSliceVar(&roleGroups, "groups", []string{}, "GroupIds the role is bound to")
+
+	roleCmd.AddCommand(deleteRoleCmd)
+	deleteRoleCmd.Flags().StringVarP(&roleId, "role-id", "r", "", "Id of the role")
+
+	roleCmd.AddCommand(getRoleCmd)
+	getRoleCmd.Flags().StringVarP(&roleId, "role-id", "r", "", "Id of the role")
+
+	rootCmd.AddCommand(roleCmd)
+}
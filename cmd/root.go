@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
 	cc "github.com/ivanpirog/coloredcobra"
 	mokubernetes "github.com/mogenius/punq/kubernetes"
@@ -27,6 +28,53 @@ var contextId string
 var accessLevel string
 var forceUpgrade bool
 var resources []string
+var container string
+var execMode string
+var debugImage string
+var revision int
+var fromRevision int
+var toRevision int
+var allValues bool
+var labelSelector string
+var owner string
+var logContainers []string
+var includePattern string
+var excludePattern string
+var since time.Duration
+var tailLines int64
+var follow bool
+var sinceTime string
+var untilTime string
+var userFilter string
+var contextFilter string
+var kind string
+var limit int
+var tokenName string
+var tokenContexts []string
+var tokenNamespaces []string
+var tokenVerbs []string
+var tokenExpires string
+var tokenId string
+var gracePeriod time.Duration
+var loginName string
+var clientIp string
+var groupId string
+var groupName string
+var groupDescription string
+var groupMembers []string
+var roleId string
+var roleName string
+var roleDescription string
+var roleRules []string
+var roleUsers []string
+var roleGroups []string
+var impersonationDisable bool
+var impersonationUsernameAttribute string
+var impersonationUsernamePrefix string
+var impersonationGroupPrefix string
+var impersonationExtraGroups []string
+var impersonationUserMap map[string]string
+var impersonationGroupMap []string
 
 var cmdsWithoutContext = []string{
 	"punq",
@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/mogenius/punq/kubernetes"
+	"github.com/mogenius/punq/services"
 	"github.com/mogenius/punq/utils"
 
 	"github.com/fatih/color"
@@ -138,12 +140,43 @@ var ingressControllerCmd = &cobra.Command{
 	},
 }
 
+var rotateJwtKeyCmd = &cobra.Command{
+	Use:   "rotate-jwt-key",
+	Short: "Rotate the key used to sign access tokens.",
+	Long: `
+	This cmd generates a new key to sign access tokens. Tokens signed with previous keys stay valid until the 
+	grace period has passed, afterwards the old keys are pruned. Running operators pick up the new key within 30 seconds.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		keyPairs, err := services.RotateJwtKey(gracePeriod)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.AppendHeader(table.Row{"Key ID", "Created", "Status"})
+		for _, keyPair := range keyPairs {
+			status := "active"
+			if !keyPair.RetiredAt.IsZero() {
+				status = fmt.Sprintf("verifies tokens until %s", keyPair.VerifyUntil.Format(time.RFC3339))
+			}
+			t.AppendRow(
+				table.Row{keyPair.Id, keyPair.Created.Format(time.RFC3339), status},
+			)
+		}
+		t.Render()
+		utils.PrintInfo(fmt.Sprintf("Jwt key rotated succesfully ✅. New key: %s", keyPairs[0].Id))
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(systemCmd)
 	systemCmd.AddCommand(resetConfig)
 	systemCmd.AddCommand(infoCmd)
 	systemCmd.AddCommand(ingressControllerCmd)
 	systemCmd.AddCommand(checkCmd)
+	systemCmd.AddCommand(rotateJwtKeyCmd)
+	rotateJwtKeyCmd.Flags().DurationVar(&gracePeriod, "grace-period", services.JWT_KEY_GRACE_PERIOD, "Time tokens signed with the previous keys stay valid")
 }
 
 // UTILS
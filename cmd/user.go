@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mogenius/punq/dtos"
 	"github.com/mogenius/punq/services"
@@ -72,6 +74,35 @@ var addUserCmd = &cobra.Command{
 	},
 }
 
+var inviteUserCmd = &cobra.Command{
+	Use:   "invite",
+	Short: "Invite a punq user.",
+	Long:  `The invite command creates a user without password and mails a one-time link to set it (see smtp in the config). Without SMTP relay the link is printed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(email, "email")
+
+		input := dtos.PunqInvitationInput{
+			Email:       email,
+			DisplayName: displayName,
+			AccessLevel: dtos.READER,
+		}
+		if accessLevel != "" {
+			input.AccessLevel = dtos.AccessLevelFromString(accessLevel)
+		}
+
+		invitation, err := services.InviteUser(input)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		if invitation.Sent {
+			utils.PrintInfo(fmt.Sprintf("Invitation sent to %s succesfully ✅.", invitation.Email))
+		} else {
+			utils.PrintInfo(fmt.Sprintf("User %s invited succesfully ✅. Please send this link to the user (valid until %s):", invitation.Email, invitation.ExpiresAt.Format(time.RFC1123)))
+			fmt.Println(invitation.Link)
+		}
+	},
+}
+
 var updateUserCmd = &cobra.Command{
 	Use:   "update",
 	Short: "Update punq user.",
@@ -136,10 +167,183 @@ var getUserCmd = &cobra.Command{
 		if err != nil {
 			utils.FatalError(err.Error())
 		}
-		structs.PrettyPrint(user)
+		structs.PrettyPrint(user.Redacted())
+	},
+}
+
+var tokenUserCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage personal api tokens.",
+	Long:  `The token command lets you create, list and revoke personal api tokens (punq_...) of users for CI and automation.`,
+}
+
+var createTokenUserCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a personal api token.",
+	Long:  `The create command lets you create a personal api token restricted to contexts, namespaces and verbs (get, create, update, delete, exec).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(userId, "user-id")
+		RequireStringFlag(tokenName, "name")
+
+		input := dtos.PunqApiTokenCreateInput{
+			Name:       tokenName,
+			Contexts:   tokenContexts,
+			Namespaces: tokenNamespaces,
+			Verbs:      tokenVerbs,
+		}
+		if tokenExpires != "" {
+			input.Expires = parseExpiresFlag(tokenExpires)
+		}
+
+		token, err := services.CreateApiToken(userId, input)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		utils.PrintInfo("Api token created succesfully ✅. Store it now, it cannot be shown again:")
+		fmt.Println(token.Token)
+	},
+}
+
+var listTokenUserCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List personal api tokens.",
+	Long:  `The list command lets you list the personal api tokens of a user (or of all users).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		tokens, err := services.ListApiTokens(userId)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		dtos.ListApiTokensToTerminal(tokens)
+	},
+}
+
+var revokeTokenUserCmd = &cobra.Command{
+	Use:   "revoke",
+	Short: "Revoke a personal api token.",
+	Long:  `The revoke command lets you revoke a personal api token. Requests using it are rejected immediately.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(tokenId, "token-id")
+
+		err := services.RevokeApiToken(userId, tokenId)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		utils.PrintInfo(fmt.Sprintf("Api token %s successfully revoked.", tokenId))
+	},
+}
+
+var twoFactorUserCmd = &cobra.Command{
+	Use:   "2fa",
+	Short: "Manage two-factor authentication of users.",
+	Long:  `The 2fa command lets you manage the TOTP two-factor authentication of users.`,
+}
+
+var resetTwoFactorUserCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Reset two-factor authentication of a user.",
+	Long:  `The reset command removes the TOTP device and recovery codes of a user (e.g. if the device has been lost). The user can enroll again afterwards.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(userId, "user-id")
+
+		err := services.ResetTwoFactor(userId)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		utils.PrintInfo(fmt.Sprintf("2FA of user %s successfully reset.", userId))
+	},
+}
+
+var deactivateUserCmd = &cobra.Command{
+	Use:   "deactivate",
+	Short: "Deactivate a punq user.",
+	Long:  `The deactivate command ends all sessions and api tokens of a user and prevents further logins. Unlike delete, the user is kept (e.g. for the audit log).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(userId, "user-id")
+
+		_, err := services.SetUserDisabled(userId, true)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		utils.PrintInfo(fmt.Sprintf("User %s deactivated succesfully ✅.", userId))
 	},
 }
 
+var activateUserCmd = &cobra.Command{
+	Use:   "activate",
+	Short: "Activate a deactivated punq user.",
+	Long:  `The activate command allows a deactivated user to sign in again.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RequireStringFlag(userId, "user-id")
+
+		_, err := services.SetUserDisabled(userId, false)
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		utils.PrintInfo(fmt.Sprintf("User %s activated succesfully ✅.", userId))
+	},
+}
+
+var unlockUserCmd = &cobra.Command{
+	Use:   "unlock",
+	Short: "Unlock a login after failed attempts.",
+	Long:  `The unlock command lets you remove the lockout and failed attempts of a user, a login name or an IP address.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if userId == "" && loginName == "" && clientIp == "" {
+			utils.FatalError("--user-id, --login or --ip is required.")
+		}
+
+		if userId != "" {
+			user, err := services.GetUser(userId)
+			if err != nil {
+				utils.FatalError(err.Error())
+			}
+			loginName = user.Email
+		}
+		if loginName != "" {
+			err := services.UnlockLogin(dtos.LOGIN_ATTEMPT_ACCOUNT, loginName)
+			if err != nil {
+				utils.FatalError(err.Error())
+			}
+			utils.PrintInfo(fmt.Sprintf("Login %s successfully unlocked.", loginName))
+		}
+		if clientIp != "" {
+			err := services.UnlockLogin(dtos.LOGIN_ATTEMPT_IP, clientIp)
+			if err != nil {
+				utils.FatalError(err.Error())
+			}
+			utils.PrintInfo(fmt.Sprintf("IP address %s successfully unlocked.", clientIp))
+		}
+	},
+}
+
+var lockoutsUserCmd = &cobra.Command{
+	Use:   "lockouts",
+	Short: "List failed login attempts.",
+	Long:  `The lockouts command lets you list the logins and IP addresses with recent failed attempts and their lockouts.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		attempts, err := services.ListLoginAttempts()
+		if err != nil {
+			utils.FatalError(err.Error())
+		}
+		dtos.ListLoginAttemptsToTerminal(attempts)
+	},
+}
+
+// parseExpiresFlag accepts a duration (e.g. 720h), days (e.g. 90d) or an RFC3339 timestamp
+func parseExpiresFlag(value string) time.Time {
+	if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && strings.HasSuffix(value, "d") {
+		return time.Now().AddDate(0, 0, days)
+	}
+	if duration, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(duration)
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		utils.FatalError("--expires must be a duration (e.g. 720h), days (e.g. 90d) or an RFC3339 timestamp.")
+	}
+	return parsed
+}
+
 func init() {
 	userCmd.AddCommand(listUserCmd)
 
@@ -156,11 +360,49 @@ func init() {
 	addUserCmd.Flags().StringVarP(&password, "password", "p", "", "Password of the new user")
 	addUserCmd.Flags().StringVarP(&accessLevel, "accesslevel", "a", "", "AccessLeve of the new user")
 
+	userCmd.AddCommand(inviteUserCmd)
+	inviteUserCmd.Flags().StringVarP(&email, "email", "e", "", "E-Mail address of the invited user")
+	inviteUserCmd.Flags().StringVarP(&displayName, "displayname", "j", "", "Display name of the invited user (default: email)")
+	inviteUserCmd.Flags().StringVarP(&accessLevel, "accesslevel", "a", "", "AccessLevel of the invited user (default: READER)")
+
 	userCmd.AddCommand(deleteUserCmd)
 	deleteUserCmd.Flags().StringVarP(&userId, "userid", "u", "", "UserId of the user")
 
 	userCmd.AddCommand(getUserCmd)
 	getUserCmd.Flags().StringVarP(&userId, "userid", "u", "", "UserId of the user")
 
+	userCmd.AddCommand(tokenUserCmd)
+	tokenUserCmd.AddCommand(createTokenUserCmd)
+	createTokenUserCmd.Flags().StringVarP(&userId, "user-id", "u", "", "UserId of the owner of the token")
+	createTokenUserCmd.Flags().StringVar(&tokenName, "name", "", "Name of the token")
+	createTokenUserCmd.Flags().StringSliceVar(&tokenContexts, "contexts", []string{}, "Allowed context ids (default: all)")
+	createTokenUserCmd.Flags().StringSliceVar(&tokenNamespaces, "namespaces", []string{}, "Allowed namespaces (default: all)")
+	createTokenUserCmd.Flags().StringSliceVar(&tokenVerbs, "verbs", []string{dtos.API_TOKEN_VERB_GET}, "Allowed verbs (get, create, update, delete, exec)")
+	createTokenUserCmd.Flags().StringVar(&tokenExpires, "expires", "", "Expiry as duration (720h), days (90d) or RFC3339 timestamp (default: never)")
+
+	tokenUserCmd.AddCommand(listTokenUserCmd)
+	listTokenUserCmd.Flags().StringVarP(&userId, "user-id", "u", "", "UserId of the owner (default: all users)")
+
+	tokenUserCmd.AddCommand(revokeTokenUserCmd)
+	revokeTokenUserCmd.Flags().StringVarP(&tokenId, "token-id", "t", "", "Id of the token")
+	revokeTokenUserCmd.Flags().StringVarP(&userId, "user-id", "u", "", "UserId of the owner of the token")
+
+	userCmd.AddCommand(twoFactorUserCmd)
+	twoFactorUserCmd.AddCommand(resetTwoFactorUserCmd)
+	resetTwoFactorUserCmd.Flags().StringVarP(&userId, "user-id", "u", "", "UserId of the user")
+
+	userCmd.AddCommand(deactivateUserCmd)
+	deactivateUserCmd.Flags().StringVarP(&userId, "user-id", "u", "", "UserId of the user")
+
+	userCmd.AddCommand(activateUserCmd)
+	activateUserCmd.Flags().StringVarP(&userId, "user-id", "u", "", "UserId of the user")
+
+	userCmd.AddCommand(unlockUserCmd)
+	unlockUserCmd.Flags().StringVarP(&userId, "user-id", "u", "", "UserId of the user")
+	unlockUserCmd.Flags().StringVar(&loginName, "login", "", "Login as entered (e.g. email or LDAP login name)")
+	unlockUserCmd.Flags().StringVar(&clientIp, "ip", "", "IP address")
+
+	userCmd.AddCommand(lockoutsUserCmd)
+
 	rootCmd.AddCommand(userCmd)
 }
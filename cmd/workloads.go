@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 
-	"github.com/mogenius/punq/dtos"
 	"github.com/mogenius/punq/kubernetes"
 	"github.com/spf13/cobra"
 )
@@ -19,7 +18,7 @@ var listWorkloadsCmd = &cobra.Command{
 	Short: "List punq supported workloads.",
 	Long:  `The workloads command lets you list all workloads managed by punq.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		kubernetes.ListWorkloadsOnTerminal(dtos.ADMIN)
+		kubernetes.ListWorkloadsOnTerminal()
 	},
 }
 
@@ -1,6 +1,9 @@
 package dtos
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 // NAME       RESTRICTION
 // READER     (no logs, no yaml, no secrets, no exec, no useradmin)
@@ -16,9 +19,18 @@ const (
 	// and so on...
 )
 
+// PunqAccess grants a level to a user or (if GroupId is set) to all members of a group
 type PunqAccess struct {
-	UserId string      `json:"userId" validate:"required"`
-	Level  AccessLevel `json:"level" validate:"required"`
+	UserId  string      `json:"userId"`
+	GroupId string      `json:"groupId,omitempty"`
+	Level   AccessLevel `json:"level" validate:"required"`
+}
+
+func (a *PunqAccess) String() string {
+	if a.GroupId != "" {
+		return fmt.Sprintf("group:%s (%d)", a.GroupId, a.Level)
+	}
+	return fmt.Sprintf("%s (%d)", a.UserId, a.Level)
 }
 
 func AccessLevelFromString(level string) AccessLevel {
@@ -0,0 +1,98 @@
+package dtos
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/mogenius/punq/utils"
+)
+
+// personal api tokens look like punq_<id>_<secret>
+const API_TOKEN_PREFIX = "punq_"
+
+const (
+	API_TOKEN_VERB_GET    = "get"    // GET requests
+	API_TOKEN_VERB_CREATE = "create" // POST requests
+	API_TOKEN_VERB_UPDATE = "update" // PATCH and PUT requests
+	API_TOKEN_VERB_DELETE = "delete" // DELETE requests
+	API_TOKEN_VERB_EXEC   = "exec"   // shell sessions
+)
+
+var API_TOKEN_VERBS = []string{API_TOKEN_VERB_GET, API_TOKEN_VERB_CREATE, API_TOKEN_VERB_UPDATE, API_TOKEN_VERB_DELETE, API_TOKEN_VERB_EXEC}
+
+type PunqApiToken struct {
+	Id         string    `json:"id"`
+	UserId     string    `json:"userId"`
+	Name       string    `json:"name"`
+	Contexts   []string  `json:"contexts"`   // empty = all contexts of the user
+	Namespaces []string  `json:"namespaces"` // empty = all namespaces
+	Verbs      []string  `json:"verbs"`
+	Created    time.Time `json:"createdAt"`
+	Expires    time.Time `json:"expiresAt,omitempty"` // zero = never
+	LastUsed   time.Time `json:"lastUsedAt,omitempty"`
+	// sha256 of the secret part (never returned by the api)
+	TokenHash string `json:"tokenHash,omitempty"`
+}
+
+type PunqApiTokenCreateInput struct {
+	Name       string    `json:"name" validate:"required"`
+	Contexts   []string  `json:"contexts"`
+	Namespaces []string  `json:"namespaces"`
+	Verbs      []string  `json:"verbs" validate:"required"`
+	Expires    time.Time `json:"expiresAt,omitempty"`
+}
+
+// PunqApiTokenCreated contains the token itself, which is only shown once
+type PunqApiTokenCreated struct {
+	PunqApiToken
+	Token string `json:"token"`
+}
+
+func (t *PunqApiToken) IsExpired() bool {
+	return !t.Expires.IsZero() && time.Now().After(t.Expires)
+}
+
+// Allows checks the scope of the token. Empty contexts/namespaces allow all, but requests without context or
+// namespace are denied if the token is restricted to some.
+func (t *PunqApiToken) Allows(verb string, contextId string, namespace string) error {
+	if !utils.ContainsEqual(t.Verbs, verb) {
+		return fmt.Errorf("api token '%s' does not allow '%s'", t.Name, verb)
+	}
+	if len(t.Contexts) > 0 && !utils.ContainsEqual(t.Contexts, contextId) {
+		return fmt.Errorf("api token '%s' does not allow context '%s'", t.Name, contextId)
+	}
+	if len(t.Namespaces) > 0 && !utils.ContainsEqual(t.Namespaces, namespace) {
+		return fmt.Errorf("api token '%s' does not allow namespace '%s'", t.Name, namespace)
+	}
+	return nil
+}
+
+func ListApiTokensToTerminal(tokens []PunqApiToken) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"#", "ID", "Name", "User", "Contexts", "Namespaces", "Verbs", "Expires", "Last used"})
+	for index, token := range tokens {
+		expires := "never"
+		if !token.Expires.IsZero() {
+			expires = token.Expires.Format(time.RFC3339)
+		}
+		lastUsed := "-"
+		if !token.LastUsed.IsZero() {
+			lastUsed = token.LastUsed.Format(time.RFC3339)
+		}
+		t.AppendRow(
+			table.Row{index + 1, token.Id, token.Name, token.UserId, listOrAll(token.Contexts), listOrAll(token.Namespaces), strings.Join(token.Verbs, ","), expires, lastUsed},
+		)
+	}
+	t.Render()
+}
+
+func listOrAll(list []string) string {
+	if len(list) == 0 {
+		return "all"
+	}
+	return strings.Join(list, ",")
+}
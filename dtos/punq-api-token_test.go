@@ -0,0 +1,59 @@
+package dtos
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPunqApiTokenAllows(t *testing.T) {
+	unrestricted := PunqApiToken{Name: "ci", Verbs: []string{API_TOKEN_VERB_GET, API_TOKEN_VERB_UPDATE}}
+	restricted := PunqApiToken{Name: "deploy", Verbs: []string{API_TOKEN_VERB_GET}, Contexts: []string{"prod"}, Namespaces: []string{"team-a"}}
+
+	tests := []struct {
+		name      string
+		token     PunqApiToken
+		verb      string
+		contextId string
+		namespace string
+		wantErr   bool
+	}{
+		{"allowed verb", unrestricted, API_TOKEN_VERB_UPDATE, "prod", "team-a", false},
+		{"other verb", unrestricted, API_TOKEN_VERB_DELETE, "prod", "team-a", true},
+		{"no shell without exec", unrestricted, API_TOKEN_VERB_EXEC, "prod", "team-a", true},
+		{"all contexts and namespaces", unrestricted, API_TOKEN_VERB_GET, "dev", "", false},
+		{"restricted context and namespace", restricted, API_TOKEN_VERB_GET, "prod", "team-a", false},
+		{"other context", restricted, API_TOKEN_VERB_GET, "dev", "team-a", true},
+		{"other namespace", restricted, API_TOKEN_VERB_GET, "prod", "team-b", true},
+		{"no context", restricted, API_TOKEN_VERB_GET, "", "team-a", true},
+		{"cluster wide request", restricted, API_TOKEN_VERB_GET, "prod", "", true},
+		{"no verbs", PunqApiToken{Name: "empty"}, API_TOKEN_VERB_GET, "prod", "team-a", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.token.Allows(tt.verb, tt.contextId, tt.namespace)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Allows(%s, %s, %s) error = %v, wantErr %t", tt.verb, tt.contextId, tt.namespace, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPunqApiTokenIsExpired(t *testing.T) {
+	tests := []struct {
+		name    string
+		expires time.Time
+		want    bool
+	}{
+		{"never", time.Time{}, false},
+		{"future", time.Now().Add(time.Hour), false},
+		{"past", time.Now().Add(-time.Second), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := PunqApiToken{Expires: tt.expires}
+			if got := token.IsExpired(); got != tt.want {
+				t.Errorf("IsExpired() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package dtos
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jedib0t/go-pretty/table"
+)
+
+const (
+	AUDIT_RESULT_SUCCESS string = "success"
+	AUDIT_RESULT_FAILURE string = "failure"
+)
+
+type PunqAuditEntry struct {
+	Id         string    `json:"id"`
+	Timestamp  time.Time `json:"timestamp"`
+	UserId     string    `json:"userId"`
+	UserEmail  string    `json:"userEmail"`
+	ContextId  string    `json:"contextId"`
+	Action     string    `json:"action"` // http method, exec|attach|debug for shell sessions or health for reachability changes of contexts
+	Path       string    `json:"path"`
+	Kind       string    `json:"kind"`
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	Diff       string    `json:"diff,omitempty"` // unified diff of the current state and the request body (sensitive values redacted)
+	StatusCode int       `json:"statusCode"`
+	Result     string    `json:"result"`
+	Message    string    `json:"message,omitempty"`
+}
+
+type PunqAuditFilter struct {
+	User      string `json:"user"` // user id or email
+	ContextId string `json:"contextId"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Limit     int    `json:"limit"`
+}
+
+func (e *PunqAuditEntry) Matches(filter PunqAuditFilter) bool {
+	if filter.User != "" && filter.User != e.UserId && filter.User != e.UserEmail {
+		return false
+	}
+	if filter.ContextId != "" && filter.ContextId != e.ContextId {
+		return false
+	}
+	if filter.Kind != "" && filter.Kind != e.Kind {
+		return false
+	}
+	if filter.Namespace != "" && filter.Namespace != e.Namespace {
+		return false
+	}
+	return true
+}
+
+func ListAuditEntriesToTerminal(entries []PunqAuditEntry) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"#", "Time", "User", "Context", "Action", "Kind", "Resource", "Result"})
+	for index, entry := range entries {
+		resource := entry.Name
+		if entry.Namespace != "" {
+			resource = fmt.Sprintf("%s/%s", entry.Namespace, entry.Name)
+		}
+		t.AppendRow(
+			table.Row{index + 1, entry.Timestamp.Format(time.RFC3339), entry.UserEmail, entry.ContextId, entry.Action, entry.Kind, resource, fmt.Sprintf("%s (%d)", entry.Result, entry.StatusCode)},
+		)
+	}
+	t.Render()
+}
@@ -0,0 +1,43 @@
+package dtos
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/mogenius/punq/utils"
+)
+
+// PunqContextHealth is the result of the last probe of a context (see services.StartContextHealthMonitor)
+type PunqContextHealth struct {
+	ContextId         string `json:"contextId"`
+	ContextName       string `json:"contextName"`
+	Reachable         bool   `json:"reachable"`
+	Provider          string `json:"provider"`
+	LatencyMs         int64  `json:"latencyMs"` // duration of the version request
+	KubernetesVersion string `json:"kubernetesVersion"`
+	NodeCount         int    `json:"nodeCount"`
+	// earliest expiry of the client certificate and the bearer token of the kubeconfig (nil = unknown or never)
+	CredentialsExpireAt *time.Time `json:"credentialsExpireAt,omitempty"`
+	LastError           string     `json:"lastError,omitempty"`
+	CheckedAt           time.Time  `json:"checkedAt"`
+	// time of the last change of Reachable
+	Since time.Time `json:"since"`
+}
+
+func ListContextHealthToTerminal(health []PunqContextHealth) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"#", "ID", "Name", "Reachable", "Latency", "Version", "Nodes", "Credentials expire", "Checked", "Error"})
+	for index, entry := range health {
+		expires := "-"
+		if entry.CredentialsExpireAt != nil {
+			expires = entry.CredentialsExpireAt.Format(time.RFC3339)
+		}
+		t.AppendRow(
+			table.Row{index + 1, entry.ContextId, entry.ContextName, utils.StatusEmoji(entry.Reachable), fmt.Sprintf("%d ms", entry.LatencyMs), entry.KubernetesVersion, entry.NodeCount, expires, entry.CheckedAt.Format(time.RFC3339), entry.LastError},
+		)
+	}
+	t.Render()
+}
@@ -20,6 +20,8 @@ type PunqContext struct {
 	Provider    string       `json:"provider" validate:"required"`
 	Reachable   bool         `json:"reachable" validate:"required"`
 	Access      []PunqAccess `json:"access" validate:"required"`
+	// nil = kubernetes requests use the rights of the kubeconfig
+	Impersonation *PunqImpersonation `json:"impersonation,omitempty"`
 }
 
 func CreateContext(id string, name string, context string, provider string, access []PunqAccess) PunqContext {
@@ -52,10 +54,10 @@ func CreateContext(id string, name string, context string, provider string, acce
 }
 
 func (c *PunqContext) AddAccess(userId string, accessLevel AccessLevel) {
-	for _, access := range c.Access {
-		if access.UserId == userId {
+	for i := range c.Access {
+		if c.Access[i].GroupId == "" && c.Access[i].UserId == userId {
 			// UPDATE EXISTING
-			access.Level = accessLevel
+			c.Access[i].Level = accessLevel
 			return
 		}
 	}
@@ -69,11 +71,79 @@ func (c *PunqContext) AddAccess(userId string, accessLevel AccessLevel) {
 func (c *PunqContext) RemoveAccess(userId string) {
 	resultingArray := []PunqAccess{}
 	for _, access := range c.Access {
-		if access.UserId != userId {
+		if access.GroupId != "" || access.UserId != userId {
+			resultingArray = append(resultingArray, access)
+		}
+	}
+	c.Access = resultingArray
+}
+
+func (c *PunqContext) AddGroupAccess(groupId string, accessLevel AccessLevel) {
+	for i := range c.Access {
+		if c.Access[i].GroupId == groupId {
+			c.Access[i].Level = accessLevel
+			return
+		}
+	}
+	c.Access = append(c.Access, PunqAccess{
+		GroupId: groupId,
+		Level:   accessLevel,
+	})
+}
+
+// RemoveGroupAccess returns false if the group had no access
+func (c *PunqContext) RemoveGroupAccess(groupId string) bool {
+	resultingArray := []PunqAccess{}
+	for _, access := range c.Access {
+		if access.GroupId != groupId {
 			resultingArray = append(resultingArray, access)
 		}
 	}
+	removed := len(resultingArray) != len(c.Access)
 	c.Access = resultingArray
+	return removed
+}
+
+// AccessLevelFor returns the effective access level of a user (member of groupIds) for this context.
+// Contexts without access entries are open to every user with their global level.
+// Otherwise only users granted directly or by one of their groups are allowed. They get the highest of these grants,
+// but never more than their global level. Global admins always keep full access.
+func (c *PunqContext) AccessLevelFor(user *PunqUser, groupIds []string) (AccessLevel, bool) {
+	if user.AccessLevel == ADMIN || len(c.Access) == 0 {
+		return user.AccessLevel, true
+	}
+	granted := false
+	level := READER
+	for _, access := range c.Access {
+		if access.GroupId == "" && access.UserId != user.Id {
+			continue
+		}
+		if access.GroupId != "" && !utils.ContainsEqual(groupIds, access.GroupId) {
+			continue
+		}
+		if !granted || access.Level > level {
+			level = access.Level
+		}
+		granted = true
+	}
+	if !granted {
+		return READER, false
+	}
+	if level > user.AccessLevel {
+		return user.AccessLevel, true
+	}
+	return level, true
+}
+
+// Redacted returns a copy of the context without the kubeconfig and the user mappings so it can be shown to
+// non-admin users.
+func (c PunqContext) Redacted() PunqContext {
+	c.Context = ""
+	c.Access = []PunqAccess{}
+	if c.Impersonation != nil {
+		c.Impersonation = &PunqImpersonation{Enabled: c.Impersonation.Enabled}
+	}
+	return c
 }
 
 func (c *PunqContext) PrintToTerminal() {
@@ -83,7 +153,7 @@ func (c *PunqContext) PrintToTerminal() {
 	accessStr := "*"
 	accessEntries := []string{}
 	for _, access := range c.Access {
-		accessEntries = append(accessEntries, fmt.Sprintf("%s (%d)", access.UserId, access.Level))
+		accessEntries = append(accessEntries, access.String())
 	}
 	if len(accessEntries) > 0 {
 		accessStr = strings.Join(accessEntries, ", ")
@@ -97,18 +167,18 @@ func (c *PunqContext) PrintToTerminal() {
 func ListContextsToTerminal(contexts []PunqContext) {
 	t := table.NewWriter()
 	t.SetOutputMirror(os.Stdout)
-	t.AppendHeader(table.Row{"#", "ID", "Name", "Reachable", "Provider", "Access"})
+	t.AppendHeader(table.Row{"#", "ID", "Name", "Reachable", "Provider", "Access", "Impersonation"})
 	for index, context := range contexts {
 		accessStr := "*"
 		accessEntries := []string{}
 		for _, access := range context.Access {
-			accessEntries = append(accessEntries, fmt.Sprintf("%s (%d)", access.UserId, access.Level))
+			accessEntries = append(accessEntries, access.String())
 		}
 		if len(accessEntries) > 0 {
 			accessStr = strings.Join(accessEntries, ", ")
 		}
 		t.AppendRow(
-			table.Row{index + 1, context.Id, context.Name, utils.StatusEmoji(context.Reachable), context.Provider, accessStr},
+			table.Row{index + 1, context.Id, context.Name, utils.StatusEmoji(context.Reachable), context.Provider, accessStr, context.Impersonation.String()},
 		)
 	}
 	t.Render()
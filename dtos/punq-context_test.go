@@ -0,0 +1,60 @@
+package dtos
+
+import "testing"
+
+func TestPunqContextAccessLevelFor(t *testing.T) {
+	admin := &PunqUser{Id: "admin", AccessLevel: ADMIN}
+	user := &PunqUser{Id: "user", AccessLevel: USER}
+	reader := &PunqUser{Id: "reader", AccessLevel: READER}
+
+	tests := []struct {
+		name      string
+		access    []PunqAccess
+		user      *PunqUser
+		groupIds  []string
+		wantLevel AccessLevel
+		wantOk    bool
+	}{
+		{"open context", []PunqAccess{}, user, nil, USER, true},
+		{"admin without grant", []PunqAccess{{UserId: "other", Level: READER}}, admin, nil, ADMIN, true},
+		{"no grant", []PunqAccess{{UserId: "other", Level: USER}}, user, nil, READER, false},
+		{"direct grant", []PunqAccess{{UserId: "user", Level: READER}}, user, nil, READER, true},
+		{"group grant", []PunqAccess{{GroupId: "team", Level: USER}}, user, []string{"team"}, USER, true},
+		{"grant of another group", []PunqAccess{{GroupId: "team", Level: USER}}, user, []string{"other"}, READER, false},
+		{"group grant higher than direct grant", []PunqAccess{{UserId: "user", Level: READER}, {GroupId: "team", Level: USER}}, user, []string{"team"}, USER, true},
+		{"direct grant higher than group grant", []PunqAccess{{UserId: "user", Level: USER}, {GroupId: "team", Level: READER}}, user, []string{"team"}, USER, true},
+		{"highest of several groups", []PunqAccess{{GroupId: "a", Level: READER}, {GroupId: "b", Level: USER}}, user, []string{"a", "b"}, USER, true},
+		{"capped by the global level", []PunqAccess{{GroupId: "team", Level: ADMIN}}, reader, []string{"team"}, READER, true},
+		{"group entry is no user grant", []PunqAccess{{UserId: "user", GroupId: "team", Level: USER}}, user, nil, READER, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := PunqContext{Access: tt.access}
+			level, ok := ctx.AccessLevelFor(tt.user, tt.groupIds)
+			if level != tt.wantLevel || ok != tt.wantOk {
+				t.Errorf("AccessLevelFor() = %d, %t, want %d, %t", level, ok, tt.wantLevel, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestPunqContextGroupAccess(t *testing.T) {
+	ctx := PunqContext{Access: []PunqAccess{}}
+	ctx.AddAccess("team", READER)
+	ctx.AddGroupAccess("team", READER)
+	ctx.AddGroupAccess("team", USER)
+	if len(ctx.Access) != 2 || ctx.Access[1].GroupId != "team" || ctx.Access[1].Level != USER {
+		t.Fatalf("AddGroupAccess() access = %v, want the updated group entry next to the user entry", ctx.Access)
+	}
+
+	ctx.RemoveAccess("team")
+	if len(ctx.Access) != 1 || ctx.Access[0].GroupId != "team" {
+		t.Errorf("RemoveAccess() access = %v, want the group entry to be kept", ctx.Access)
+	}
+	if !ctx.RemoveGroupAccess("team") || len(ctx.Access) != 0 {
+		t.Errorf("RemoveGroupAccess() access = %v, want no entries", ctx.Access)
+	}
+	if ctx.RemoveGroupAccess("team") {
+		t.Errorf("RemoveGroupAccess() of a group without access = true, want false")
+	}
+}
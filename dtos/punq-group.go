@@ -0,0 +1,43 @@
+package dtos
+
+import (
+	"os"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/mogenius/punq/utils"
+)
+
+// PunqGroup bundles users, contexts can grant access to all members at once (see PunqAccess)
+type PunqGroup struct {
+	Id          string   `json:"id" validate:"required"`
+	Name        string   `json:"name" validate:"required"`
+	Description string   `json:"description"`
+	Members     []string `json:"members"` // user ids
+	Created     string   `json:"createdAt" validate:"required"`
+	// id of the group at the identity provider (SCIM)
+	ExternalId string `json:"externalId,omitempty"`
+}
+
+type PunqGroupCreateInput struct {
+	Name        string   `json:"name" validate:"required"`
+	Description string   `json:"description"`
+	Members     []string `json:"members"`
+	ExternalId  string   `json:"externalId,omitempty"`
+}
+
+func (g *PunqGroup) HasMember(userId string) bool {
+	return utils.ContainsEqual(g.Members, userId)
+}
+
+func ListGroupsToTerminal(groups []PunqGroup) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"#", "ID", "Name", "Description", "Members", "Created"})
+	for index, group := range groups {
+		t.AppendRow(
+			table.Row{index + 1, group.Id, group.Name, group.Description, strings.Join(group.Members, ","), utils.JsonStringToHumanDuration(group.Created)},
+		)
+	}
+	t.Render()
+}
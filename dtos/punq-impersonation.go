@@ -0,0 +1,84 @@
+package dtos
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	IMPERSONATION_USERNAME_EMAIL = "email"
+	IMPERSONATION_USERNAME_ID    = "id"
+)
+
+// PunqImpersonation lets kubernetes requests of a context run as the punq user (rest.Config.Impersonate), so the
+// RBAC of the cluster decides and its audit log shows the user. The kubeconfig of the context needs the
+// "impersonate" verb on users and groups.
+type PunqImpersonation struct {
+	Enabled bool `json:"enabled"`
+	// user attribute used as kubernetes username: email (default) or id
+	UsernameAttribute string `json:"usernameAttribute,omitempty"`
+	UsernamePrefix    string `json:"usernamePrefix,omitempty"`
+	// punq groups are passed by name with this prefix
+	GroupPrefix string   `json:"groupPrefix,omitempty"`
+	ExtraGroups []string `json:"extraGroups,omitempty"` // added for every user
+	// explicit kubernetes usernames by punq user id (no prefix is applied)
+	Users map[string]string `json:"users,omitempty"`
+	// additional kubernetes groups by punq group name
+	Groups map[string][]string `json:"groups,omitempty"`
+}
+
+func (i *PunqImpersonation) Validate() error {
+	switch i.UsernameAttribute {
+	case "", IMPERSONATION_USERNAME_EMAIL, IMPERSONATION_USERNAME_ID:
+		return nil
+	default:
+		return fmt.Errorf("unknown username attribute '%s' (%s, %s)", i.UsernameAttribute, IMPERSONATION_USERNAME_EMAIL, IMPERSONATION_USERNAME_ID)
+	}
+}
+
+// Identity maps the user (member of the groups with groupNames) to a kubernetes username and groups
+func (i *PunqImpersonation) Identity(user *PunqUser, groupNames []string) (string, []string) {
+	username, ok := i.Users[user.Id]
+	if !ok {
+		attribute := user.Email
+		if i.UsernameAttribute == IMPERSONATION_USERNAME_ID {
+			attribute = user.Id
+		}
+		username = i.UsernamePrefix + attribute
+	}
+
+	groups := []string{}
+	addGroup := func(group string) {
+		for _, existing := range groups {
+			if existing == group {
+				return
+			}
+		}
+		groups = append(groups, group)
+	}
+	for _, group := range i.ExtraGroups {
+		addGroup(group)
+	}
+	for _, name := range groupNames {
+		addGroup(i.GroupPrefix + name)
+		for _, group := range i.Groups[name] {
+			addGroup(group)
+		}
+	}
+	return username, groups
+}
+
+func (i *PunqImpersonation) String() string {
+	if i == nil || !i.Enabled {
+		return "-"
+	}
+	attribute := i.UsernameAttribute
+	if attribute == "" {
+		attribute = IMPERSONATION_USERNAME_EMAIL
+	}
+	result := fmt.Sprintf("%s%s", i.UsernamePrefix, attribute)
+	if len(i.ExtraGroups) > 0 {
+		result += fmt.Sprintf(" (+%s)", strings.Join(i.ExtraGroups, ","))
+	}
+	return result
+}
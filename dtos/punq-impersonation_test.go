@@ -0,0 +1,56 @@
+package dtos
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPunqImpersonationIdentity(t *testing.T) {
+	user := &PunqUser{Id: "u1", Email: "jane@example.com"}
+
+	tests := []struct {
+		name          string
+		impersonation PunqImpersonation
+		groupNames    []string
+		wantUsername  string
+		wantGroups    []string
+	}{
+		{"email by default", PunqImpersonation{}, nil, "jane@example.com", []string{}},
+		{"id with prefix", PunqImpersonation{UsernameAttribute: IMPERSONATION_USERNAME_ID, UsernamePrefix: "punq:"}, nil, "punq:u1", []string{}},
+		{"explicit username without prefix", PunqImpersonation{UsernamePrefix: "punq:", Users: map[string]string{"u1": "jane"}}, nil, "jane", []string{}},
+		{"groups with prefix", PunqImpersonation{GroupPrefix: "punq:"}, []string{"dev", "ops"}, "jane@example.com", []string{"punq:dev", "punq:ops"}},
+		{"extra and mapped groups without duplicates", PunqImpersonation{ExtraGroups: []string{"viewers"}, Groups: map[string][]string{"dev": {"viewers", "editors"}}}, []string{"dev"}, "jane@example.com", []string{"viewers", "dev", "editors"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			username, groups := tt.impersonation.Identity(user, tt.groupNames)
+			if username != tt.wantUsername || !reflect.DeepEqual(groups, tt.wantGroups) {
+				t.Errorf("Identity() = %s, %v, want %s, %v", username, groups, tt.wantUsername, tt.wantGroups)
+			}
+		})
+	}
+}
+
+func TestPunqImpersonationValidate(t *testing.T) {
+	for _, attribute := range []string{"", IMPERSONATION_USERNAME_EMAIL, IMPERSONATION_USERNAME_ID} {
+		impersonation := PunqImpersonation{UsernameAttribute: attribute}
+		if err := impersonation.Validate(); err != nil {
+			t.Errorf("Validate(%s) error = %v, want nil", attribute, err)
+		}
+	}
+	impersonation := PunqImpersonation{UsernameAttribute: "name"}
+	if err := impersonation.Validate(); err == nil {
+		t.Errorf("Validate(name) error = nil, want an error")
+	}
+}
+
+func TestPunqContextRedactedImpersonation(t *testing.T) {
+	ctx := PunqContext{Context: "kubeconfig", Impersonation: &PunqImpersonation{Enabled: true, Users: map[string]string{"u1": "jane"}}}
+	redacted := ctx.Redacted()
+	if redacted.Context != "" || !redacted.Impersonation.Enabled || redacted.Impersonation.Users != nil {
+		t.Errorf("Redacted() = %+v, want only the enabled flag of the impersonation", redacted)
+	}
+	if ctx.Impersonation.Users == nil {
+		t.Errorf("Redacted() changed the original context")
+	}
+}
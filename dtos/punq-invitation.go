@@ -0,0 +1,29 @@
+package dtos
+
+import "time"
+
+// PunqInvitationInput creates a local user without password. The user chooses it with the link of the invitation.
+type PunqInvitationInput struct {
+	Email       string      `json:"email" validate:"required"`
+	DisplayName string      `json:"displayName"`
+	AccessLevel AccessLevel `json:"accessLevel"`
+}
+
+// PunqInvitation is the result of an invitation. The link is only returned if it could not be mailed.
+type PunqInvitation struct {
+	UserId    string    `json:"userId"`
+	Email     string    `json:"email"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Sent      bool      `json:"sent"`
+	Link      string    `json:"link,omitempty"`
+}
+
+type PunqPasswordForgotInput struct {
+	Email string `json:"email" validate:"required"`
+}
+
+// PunqPasswordResetInput sets the password with the token of an invitation or password reset link
+type PunqPasswordResetInput struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
@@ -0,0 +1,16 @@
+package dtos
+
+// PunqJwk is the public part of a token signing key (RFC 7517)
+type PunqJwk struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+}
+
+type PunqJwks struct {
+	Keys []PunqJwk `json:"keys"`
+}
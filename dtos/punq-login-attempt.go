@@ -0,0 +1,42 @@
+package dtos
+
+import (
+	"os"
+	"time"
+
+	"github.com/jedib0t/go-pretty/table"
+)
+
+const (
+	LOGIN_ATTEMPT_ACCOUNT = "account" // login name as entered, tracked whether the user exists or not
+	LOGIN_ATTEMPT_IP      = "ip"
+)
+
+// PunqLoginAttempt counts the failed logins of an account or IP address
+type PunqLoginAttempt struct {
+	Kind        string    `json:"kind"`
+	Subject     string    `json:"subject"`
+	Failures    int       `json:"failures"`
+	LastFailure time.Time `json:"lastFailureAt"`
+	LockedUntil time.Time `json:"lockedUntil,omitempty"`
+}
+
+func (a *PunqLoginAttempt) IsLocked() bool {
+	return time.Now().Before(a.LockedUntil)
+}
+
+func ListLoginAttemptsToTerminal(attempts []PunqLoginAttempt) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"#", "Kind", "Subject", "Failures", "Last failure", "Locked until"})
+	for index, attempt := range attempts {
+		lockedUntil := "-"
+		if attempt.IsLocked() {
+			lockedUntil = attempt.LockedUntil.Format(time.RFC3339)
+		}
+		t.AppendRow(
+			table.Row{index + 1, attempt.Kind, attempt.Subject, attempt.Failures, attempt.LastFailure.Format(time.RFC3339), lockedUntil},
+		)
+	}
+	t.Render()
+}
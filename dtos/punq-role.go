@@ -0,0 +1,208 @@
+package dtos
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/mogenius/punq/utils"
+)
+
+const (
+	ROLE_VERB_GET     = "get"     // GET requests (list and describe)
+	ROLE_VERB_CREATE  = "create"  // POST requests
+	ROLE_VERB_UPDATE  = "update"  // PATCH and PUT requests, helm rollbacks
+	ROLE_VERB_DELETE  = "delete"  // DELETE requests
+	ROLE_VERB_LOGS    = "logs"    // pod logs
+	ROLE_VERB_RESTART = "restart" // rollout restarts
+	ROLE_VERB_EXEC    = "exec"    // shell sessions
+
+	// matches every verb, kind, namespace or context
+	ROLE_WILDCARD = "*"
+)
+
+var ROLE_VERBS = []string{ROLE_VERB_GET, ROLE_VERB_CREATE, ROLE_VERB_UPDATE, ROLE_VERB_DELETE, ROLE_VERB_LOGS, ROLE_VERB_RESTART, ROLE_VERB_EXEC}
+
+// PunqRule allows its verbs on its kinds. Empty namespaces/contexts match all, otherwise requests without namespace
+// (e.g. cluster wide lists) do not match. Namespaces may be patterns like "team-a-*".
+type PunqRule struct {
+	Verbs      []string `json:"verbs" validate:"required"`
+	Kinds      []string `json:"kinds" validate:"required"`
+	Namespaces []string `json:"namespaces"`
+	Contexts   []string `json:"contexts"`
+}
+
+// PunqRole is a set of rules which is bound to users and groups. The built-in roles reader, user and admin
+// correspond to the access levels and cannot be changed.
+type PunqRole struct {
+	Id          string     `json:"id" validate:"required"`
+	Name        string     `json:"name" validate:"required"`
+	Description string     `json:"description"`
+	BuiltIn     bool       `json:"builtIn"`
+	Rules       []PunqRule `json:"rules"`
+	Users       []string   `json:"users"`  // user ids
+	Groups      []string   `json:"groups"` // group ids
+	Created     string     `json:"createdAt"`
+}
+
+type PunqRoleCreateInput struct {
+	Name        string     `json:"name" validate:"required"`
+	Description string     `json:"description"`
+	Rules       []PunqRule `json:"rules" validate:"required"`
+	Users       []string   `json:"users"`
+	Groups      []string   `json:"groups"`
+}
+
+func (r *PunqRule) Matches(verb string, kind string, namespace string, contextId string) bool {
+	if !r.matchesKind(verb, kind, contextId) {
+		return false
+	}
+	if len(r.Namespaces) == 0 {
+		return true
+	}
+	for _, pattern := range r.Namespaces {
+		if pattern == ROLE_WILDCARD {
+			return true
+		}
+		if namespace == "" {
+			continue
+		}
+		if matched, _ := path.Match(pattern, namespace); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *PunqRule) matchesKind(verb string, kind string, contextId string) bool {
+	if !utils.ContainsEqual(r.Verbs, ROLE_WILDCARD) && !utils.ContainsEqual(r.Verbs, verb) {
+		return false
+	}
+	kindMatches := false
+	for _, ruleKind := range r.Kinds {
+		if ruleKind == ROLE_WILDCARD || strings.EqualFold(ruleKind, kind) {
+			kindMatches = true
+			break
+		}
+	}
+	if !kindMatches {
+		return false
+	}
+	return len(r.Contexts) == 0 || utils.ContainsEqual(r.Contexts, ROLE_WILDCARD) || utils.ContainsEqual(r.Contexts, contextId)
+}
+
+// Validate checks verbs and namespace patterns. Kinds are checked by the role service.
+func (r *PunqRule) Validate() error {
+	if len(r.Verbs) == 0 || len(r.Kinds) == 0 {
+		return fmt.Errorf("rule '%s' needs verbs and kinds", r.String())
+	}
+	for _, verb := range r.Verbs {
+		if verb != ROLE_WILDCARD && !utils.ContainsEqual(ROLE_VERBS, verb) {
+			return fmt.Errorf("unknown verb '%s' (%s or %s)", verb, strings.Join(ROLE_VERBS, ", "), ROLE_WILDCARD)
+		}
+	}
+	for _, pattern := range r.Namespaces {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid namespace pattern '%s'", pattern)
+		}
+	}
+	return nil
+}
+
+// String returns the rule in the format of ParseRule
+func (r *PunqRule) String() string {
+	parts := []string{
+		fmt.Sprintf("verbs=%s", strings.Join(r.Verbs, ",")),
+		fmt.Sprintf("kinds=%s", strings.Join(r.Kinds, ",")),
+	}
+	if len(r.Namespaces) > 0 {
+		parts = append(parts, fmt.Sprintf("namespaces=%s", strings.Join(r.Namespaces, ",")))
+	}
+	if len(r.Contexts) > 0 {
+		parts = append(parts, fmt.Sprintf("contexts=%s", strings.Join(r.Contexts, ",")))
+	}
+	return strings.Join(parts, ";")
+}
+
+// ParseRule reads rules like "verbs=get,restart;kinds=Deployment;namespaces=team-a-*"
+func ParseRule(rule string) (PunqRule, error) {
+	result := PunqRule{}
+	for _, part := range strings.Split(rule, ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		key, value, found := strings.Cut(part, "=")
+		if !found {
+			return result, fmt.Errorf("invalid rule part '%s' (expected key=value)", part)
+		}
+		values := []string{}
+		for _, entry := range strings.Split(value, ",") {
+			if entry = strings.TrimSpace(entry); entry != "" {
+				values = append(values, entry)
+			}
+		}
+		switch strings.TrimSpace(key) {
+		case "verbs":
+			result.Verbs = values
+		case "kinds":
+			result.Kinds = values
+		case "namespaces":
+			result.Namespaces = values
+		case "contexts":
+			result.Contexts = values
+		default:
+			return result, fmt.Errorf("unknown rule key '%s' (verbs, kinds, namespaces, contexts)", key)
+		}
+	}
+	return result, result.Validate()
+}
+
+// Allows returns true if any rule of the role matches the request
+func (r *PunqRole) Allows(verb string, kind string, namespace string, contextId string) bool {
+	for _, rule := range r.Rules {
+		if rule.Matches(verb, kind, namespace, contextId) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsKind returns true if the role allows the verb on the kind in at least one namespace
+func (r *PunqRole) AllowsKind(verb string, kind string, contextId string) bool {
+	for _, rule := range r.Rules {
+		if rule.matchesKind(verb, kind, contextId) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *PunqRole) BindsTo(userId string, groupIds []string) bool {
+	if utils.ContainsEqual(r.Users, userId) {
+		return true
+	}
+	for _, groupId := range groupIds {
+		if utils.ContainsEqual(r.Groups, groupId) {
+			return true
+		}
+	}
+	return false
+}
+
+func ListRolesToTerminal(roles []PunqRole) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"#", "ID", "Name", "Built-in", "Rules", "Users", "Groups"})
+	for index, role := range roles {
+		rules := []string{}
+		for _, rule := range role.Rules {
+			rules = append(rules, rule.String())
+		}
+		t.AppendRow(
+			table.Row{index + 1, role.Id, role.Name, utils.StatusEmoji(role.BuiltIn), strings.Join(rules, "\n"), strings.Join(role.Users, ","), strings.Join(role.Groups, ",")},
+		)
+	}
+	t.Render()
+}
@@ -0,0 +1,87 @@
+package dtos
+
+import "testing"
+
+func TestPunqRuleMatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      PunqRule
+		verb      string
+		kind      string
+		namespace string
+		contextId string
+		want      bool
+	}{
+		{"verb and kind", PunqRule{Verbs: []string{ROLE_VERB_GET}, Kinds: []string{"Pod"}}, ROLE_VERB_GET, "Pod", "default", "ctx", true},
+		{"kind is case-insensitive", PunqRule{Verbs: []string{ROLE_VERB_GET}, Kinds: []string{"pod"}}, ROLE_VERB_GET, "Pod", "default", "ctx", true},
+		{"other verb", PunqRule{Verbs: []string{ROLE_VERB_GET}, Kinds: []string{"Pod"}}, ROLE_VERB_DELETE, "Pod", "default", "ctx", false},
+		{"other kind", PunqRule{Verbs: []string{ROLE_VERB_GET}, Kinds: []string{"Pod"}}, ROLE_VERB_GET, "Secret", "default", "ctx", false},
+		{"wildcard verb and kind", PunqRule{Verbs: []string{ROLE_WILDCARD}, Kinds: []string{ROLE_WILDCARD}}, ROLE_VERB_EXEC, "Secret", "", "ctx", true},
+		{"no namespaces match all", PunqRule{Verbs: []string{ROLE_VERB_GET}, Kinds: []string{"Pod"}}, ROLE_VERB_GET, "Pod", "", "ctx", true},
+		{"namespace pattern", PunqRule{Verbs: []string{ROLE_VERB_GET}, Kinds: []string{"Pod"}, Namespaces: []string{"team-a-*"}}, ROLE_VERB_GET, "Pod", "team-a-dev", "ctx", true},
+		{"namespace pattern mismatch", PunqRule{Verbs: []string{ROLE_VERB_GET}, Kinds: []string{"Pod"}, Namespaces: []string{"team-a-*"}}, ROLE_VERB_GET, "Pod", "team-b-dev", "ctx", false},
+		{"namespace pattern denies cluster wide requests", PunqRule{Verbs: []string{ROLE_VERB_GET}, Kinds: []string{"Pod"}, Namespaces: []string{"team-a-*"}}, ROLE_VERB_GET, "Pod", "", "ctx", false},
+		{"wildcard namespace allows cluster wide requests", PunqRule{Verbs: []string{ROLE_VERB_GET}, Kinds: []string{"Pod"}, Namespaces: []string{ROLE_WILDCARD}}, ROLE_VERB_GET, "Pod", "", "ctx", true},
+		{"context", PunqRule{Verbs: []string{ROLE_VERB_GET}, Kinds: []string{"Pod"}, Contexts: []string{"ctx"}}, ROLE_VERB_GET, "Pod", "default", "ctx", true},
+		{"other context", PunqRule{Verbs: []string{ROLE_VERB_GET}, Kinds: []string{"Pod"}, Contexts: []string{"ctx"}}, ROLE_VERB_GET, "Pod", "default", "other", false},
+		{"wildcard context", PunqRule{Verbs: []string{ROLE_VERB_GET}, Kinds: []string{"Pod"}, Contexts: []string{ROLE_WILDCARD}}, ROLE_VERB_GET, "Pod", "default", "other", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Matches(tt.verb, tt.kind, tt.namespace, tt.contextId); got != tt.want {
+				t.Errorf("Matches(%s, %s, %s, %s) = %t, want %t", tt.verb, tt.kind, tt.namespace, tt.contextId, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPunqRoleAllowsKind(t *testing.T) {
+	role := PunqRole{Rules: []PunqRule{
+		{Verbs: []string{ROLE_VERB_GET}, Kinds: []string{"Pod"}, Namespaces: []string{"team-a"}},
+	}}
+	tests := []struct {
+		verb string
+		kind string
+		want bool
+	}{
+		{ROLE_VERB_GET, "Pod", true},
+		{ROLE_VERB_DELETE, "Pod", false},
+		{ROLE_VERB_GET, "Deployment", false},
+	}
+	for _, tt := range tests {
+		if got := role.AllowsKind(tt.verb, tt.kind, "ctx"); got != tt.want {
+			t.Errorf("AllowsKind(%s, %s) = %t, want %t", tt.verb, tt.kind, got, tt.want)
+		}
+	}
+	if role.Allows(ROLE_VERB_GET, "Pod", "team-b", "ctx") {
+		t.Errorf("Allows in namespace team-b = true, want false")
+	}
+}
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    string
+		wantErr bool
+	}{
+		{"verbs=get,restart;kinds=Deployment;namespaces=team-a-*", "verbs=get,restart;kinds=Deployment;namespaces=team-a-*", false},
+		{" verbs = get ; kinds=Pod,Service ;contexts=ctx;", "verbs=get;kinds=Pod,Service;contexts=ctx", false},
+		{"verbs=*;kinds=*", "verbs=*;kinds=*", false},
+		{"verbs=get", "", true},
+		{"verbs=fly;kinds=Pod", "", true},
+		{"verbs=get;kinds=Pod;namespaces=[", "", true},
+		{"verbs=get;kinds=Pod;owner=me", "", true},
+		{"verbs", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			rule, err := ParseRule(tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRule() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if err == nil && rule.String() != tt.want {
+				t.Errorf("ParseRule() = %s, want %s", rule.String(), tt.want)
+			}
+		})
+	}
+}
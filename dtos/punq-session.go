@@ -0,0 +1,23 @@
+package dtos
+
+import "time"
+
+// PunqSession is created on login and lives as long as its refresh token is used (every refresh rotates the token)
+type PunqSession struct {
+	Id          string    `json:"id"`
+	UserId      string    `json:"userId"`
+	Provider    string    `json:"provider,omitempty"`
+	UserAgent   string    `json:"userAgent,omitempty"`
+	ClientIp    string    `json:"clientIp,omitempty"`
+	Created     time.Time `json:"createdAt"`
+	LastRefresh time.Time `json:"lastRefreshAt"`
+	Expires     time.Time `json:"expiresAt"`
+	// sha256 of the current and the previous refresh token (never returned by the api)
+	RefreshTokenHash         string `json:"refreshTokenHash,omitempty"`
+	PreviousRefreshTokenHash string `json:"previousRefreshTokenHash,omitempty"`
+}
+
+type PunqSessionClient struct {
+	UserAgent string
+	ClientIp  string
+}
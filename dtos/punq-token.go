@@ -1,7 +1,11 @@
 package dtos
 
 type PunqToken struct {
-	Token string `json:"token" validate:"required"`
+	Token        string `json:"token" validate:"required"`
+	RefreshToken string `json:"refreshToken,omitempty"`
+	ExpiresIn    int64  `json:"expiresIn,omitempty"` // seconds until the token expires
+	// only set if 2fa has been enrolled during the login, shown once
+	RecoveryCodes []string `json:"recoveryCodes,omitempty"`
 }
 
 func CreateToken(token string) *PunqToken {
@@ -0,0 +1,35 @@
+package dtos
+
+import "time"
+
+// PunqUserTwoFactor is stored in the user record. Secrets are removed before users are returned (see Redacted).
+type PunqUserTwoFactor struct {
+	Enabled    bool      `json:"enabled"` // false = enrollment started but not confirmed yet
+	EnrolledAt time.Time `json:"enrolledAt,omitempty"`
+	// base32 TOTP secret
+	Secret string `json:"secret,omitempty"`
+	// sha256 of the unused recovery codes
+	RecoveryCodeHashes []string `json:"recoveryCodeHashes,omitempty"`
+	// codes of this or earlier time steps are rejected to prevent replays
+	LastUsedStep int64 `json:"lastUsedStep,omitempty"`
+}
+
+// PunqTwoFactorEnrollment contains the secret to add to an authenticator app (manually or as QR code of Uri)
+type PunqTwoFactorEnrollment struct {
+	Secret string `json:"secret"`
+	Uri    string `json:"uri"`
+}
+
+// PunqTwoFactorChallenge is returned by /auth/login instead of a token if a second factor is required
+type PunqTwoFactorChallenge struct {
+	TwoFactorRequired bool   `json:"twoFactorRequired"`
+	Challenge         string `json:"challenge"`
+	ExpiresIn         int64  `json:"expiresIn"` // seconds until the challenge expires
+	// set if the user has to enroll before signing in (see two_factor.enforce_access_level)
+	Enrollment *PunqTwoFactorEnrollment `json:"enrollment,omitempty"`
+}
+
+// PunqTwoFactorRecoveryCodes are shown once, every code can be used once instead of a TOTP code
+type PunqTwoFactorRecoveryCodes struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
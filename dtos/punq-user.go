@@ -1,7 +1,6 @@
 package dtos
 
 import (
-	"errors"
 	"os"
 
 	"golang.org/x/crypto/bcrypt"
@@ -10,6 +9,15 @@ import (
 	"github.com/mogenius/punq/utils"
 )
 
+// users without provider are local users
+const (
+	USER_PROVIDER_LOCAL = "local"
+	USER_PROVIDER_LDAP  = "ldap"
+	USER_PROVIDER_OIDC  = "oidc"
+	// provisioned by an identity provider via SCIM, the user signs in with OIDC or LDAP (matched by email)
+	USER_PROVIDER_SCIM = "scim"
+)
+
 type PunqUser struct {
 	Id          string      `json:"id" validate:"required"`
 	Email       string      `json:"email" validate:"required"`
@@ -17,6 +25,15 @@ type PunqUser struct {
 	DisplayName string      `json:"displayName" validate:"required"`
 	AccessLevel AccessLevel `json:"accessLevel" validate:"required"`
 	Created     string      `json:"createdAt" validate:"required"`
+	Provider    string      `json:"provider,omitempty"`
+	// can only be changed by the 2fa endpoints, UpdateUser keeps it
+	TwoFactor *PunqUserTwoFactor `json:"twoFactor,omitempty"`
+	// bcrypt hashes of previous passwords (see password_policy.history_size), UpdateUser maintains it
+	PasswordHistory []string `json:"passwordHistory,omitempty"`
+	// deactivated users cannot sign in, can only be changed by SetUserDisabled
+	Disabled bool `json:"disabled,omitempty"`
+	// id of the user at the identity provider (SCIM)
+	ExternalId string `json:"externalId,omitempty"`
 }
 
 type PunqUserCreateInput struct {
@@ -24,15 +41,27 @@ type PunqUserCreateInput struct {
 	Password    string      `json:"password" validate:"required"`
 	DisplayName string      `json:"displayName" validate:"required"`
 	AccessLevel AccessLevel `json:"accessLevel" validate:"required"`
+	Provider    string      `json:"provider,omitempty"`
+	ExternalId  string      `json:"externalId,omitempty"`
+}
+
+// PunqProfileUpdateInput contains the fields users can change themselves
+type PunqProfileUpdateInput struct {
+	DisplayName string `json:"displayName" validate:"required"`
+}
+
+type PunqPasswordChangeInput struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	NewPassword     string `json:"newPassword" validate:"required"`
 }
 
 func ListUsers(users []PunqUser) {
 	t := table.NewWriter()
 	t.SetOutputMirror(os.Stdout)
-	t.AppendHeader(table.Row{"#", "ID", "DisplayName", "Email", "AccessLevel", "Created"})
+	t.AppendHeader(table.Row{"#", "ID", "DisplayName", "Email", "AccessLevel", "Provider", "2FA", "Active", "Created"})
 	for index, user := range users {
 		t.AppendRow(
-			table.Row{index + 1, user.Id, user.DisplayName, user.Email, user.AccessLevel, utils.JsonStringToHumanDuration(user.Created)},
+			table.Row{index + 1, user.Id, user.DisplayName, user.Email, user.AccessLevel, user.ProviderName(), utils.StatusEmoji(user.HasTwoFactor()), utils.StatusEmoji(!user.Disabled), utils.JsonStringToHumanDuration(user.Created)},
 		)
 	}
 	t.Render()
@@ -40,9 +69,41 @@ func ListUsers(users []PunqUser) {
 
 func (user *PunqUser) PasswordCheck(password string) (bool, error) {
 	err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
-
-	if err != nil && errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+	if err != nil {
+		// e.g. a malformed hash must not be accepted either
 		return false, err
 	}
 	return true, nil
 }
+
+func (user *PunqUser) ProviderName() string {
+	if user.Provider == "" {
+		return USER_PROVIDER_LOCAL
+	}
+	return user.Provider
+}
+
+func (user *PunqUser) HasTwoFactor() bool {
+	return user.TwoFactor != nil && user.TwoFactor.Enabled
+}
+
+// Redacted returns a copy without the 2fa secrets and password history for api responses
+func (user *PunqUser) Redacted() PunqUser {
+	result := *user
+	result.PasswordHistory = nil
+	if user.TwoFactor != nil {
+		result.TwoFactor = &PunqUserTwoFactor{
+			Enabled:    user.TwoFactor.Enabled,
+			EnrolledAt: user.TwoFactor.EnrolledAt,
+		}
+	}
+	return result
+}
+
+func RedactedUsers(users []PunqUser) []PunqUser {
+	result := []PunqUser{}
+	for _, user := range users {
+		result = append(result, user.Redacted())
+	}
+	return result
+}
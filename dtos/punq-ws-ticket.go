@@ -0,0 +1,19 @@
+package dtos
+
+import "time"
+
+// PunqWsTicketInput is the target of a websocket ticket. Tickets for /exec-sh need the pod and container, tickets
+// without pod are only valid for /watch. The context is the one of the ticket request (X-Context-Id).
+type PunqWsTicketInput struct {
+	Namespace string `json:"namespace"`
+	PodName   string `json:"podName"`
+	Container string `json:"container"`
+	Mode      string `json:"mode"`  // exec (default), attach or debug
+	Image     string `json:"image"` // image of the debug container (debug only)
+}
+
+// PunqWsTicket is passed as ?ticket= to the websocket server, which accepts it exactly once before it expires
+type PunqWsTicket struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
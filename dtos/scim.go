@@ -0,0 +1,123 @@
+package dtos
+
+// SCIM 2.0 (RFC 7643, RFC 7644) resources and messages of /scim/v2
+const (
+	SCIM_SCHEMA_USER                    = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SCIM_SCHEMA_GROUP                   = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SCIM_SCHEMA_SERVICE_PROVIDER_CONFIG = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SCIM_SCHEMA_RESOURCE_TYPE           = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	SCIM_SCHEMA_LIST_RESPONSE           = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SCIM_SCHEMA_PATCH_OP                = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SCIM_SCHEMA_ERROR                   = "urn:ietf:params:scim:api:messages:2.0:Error"
+
+	SCIM_RESOURCE_USER  = "User"
+	SCIM_RESOURCE_GROUP = "Group"
+
+	SCIM_CONTENT_TYPE = "application/scim+json"
+	// maximum of the count parameter of list requests
+	SCIM_MAX_RESULTS = 1000
+)
+
+type ScimMeta struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Location     string `json:"location,omitempty"`
+}
+
+type ScimName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// ScimMultiValue is an entry of emails, groups or members
+type ScimMultiValue struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// ScimUser maps to PunqUser: userName is the email (login) of the user, active is the opposite of Disabled
+type ScimUser struct {
+	Schemas     []string         `json:"schemas"`
+	Id          string           `json:"id,omitempty"`
+	ExternalId  string           `json:"externalId,omitempty"`
+	UserName    string           `json:"userName"`
+	Name        *ScimName        `json:"name,omitempty"`
+	DisplayName string           `json:"displayName,omitempty"`
+	Emails      []ScimMultiValue `json:"emails,omitempty"`
+	Active      *bool            `json:"active,omitempty"` // nil = true
+	Groups      []ScimMultiValue `json:"groups,omitempty"` // read-only
+	Meta        *ScimMeta        `json:"meta,omitempty"`
+}
+
+// ScimGroup maps to PunqGroup, members are user ids
+type ScimGroup struct {
+	Schemas     []string         `json:"schemas"`
+	Id          string           `json:"id,omitempty"`
+	ExternalId  string           `json:"externalId,omitempty"`
+	DisplayName string           `json:"displayName"`
+	Members     []ScimMultiValue `json:"members,omitempty"`
+	Meta        *ScimMeta        `json:"meta,omitempty"`
+}
+
+type ScimListResponse struct {
+	Schemas      []string      `json:"schemas"`
+	TotalResults int           `json:"totalResults"`
+	StartIndex   int           `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Resources    []interface{} `json:"Resources"`
+}
+
+type ScimPatchOperation struct {
+	Op    string      `json:"op"` // add, replace or remove (case-insensitive)
+	Path  string      `json:"path,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+type ScimPatchRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []ScimPatchOperation `json:"Operations"`
+}
+
+type ScimError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+// ScimSupported is a capability of the ServiceProviderConfig
+type ScimSupported struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults,omitempty"`
+}
+
+type ScimAuthenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type ScimServiceProviderConfig struct {
+	Schemas               []string                   `json:"schemas"`
+	Patch                 ScimSupported              `json:"patch"`
+	Bulk                  ScimSupported              `json:"bulk"`
+	Filter                ScimSupported              `json:"filter"`
+	ChangePassword        ScimSupported              `json:"changePassword"`
+	Sort                  ScimSupported              `json:"sort"`
+	Etag                  ScimSupported              `json:"etag"`
+	AuthenticationSchemes []ScimAuthenticationScheme `json:"authenticationSchemes"`
+}
+
+type ScimResourceType struct {
+	Schemas  []string  `json:"schemas"`
+	Id       string    `json:"id"`
+	Name     string    `json:"name"`
+	Endpoint string    `json:"endpoint"`
+	Schema   string    `json:"schema"`
+	Meta     *ScimMeta `json:"meta,omitempty"`
+}
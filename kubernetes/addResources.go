@@ -5,8 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
-	"runtime"
 
 	"github.com/mogenius/punq/dtos"
 	"github.com/mogenius/punq/version"
@@ -19,11 +17,16 @@ import (
 	rbac "k8s.io/api/rbac/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	applyconfapp "k8s.io/client-go/applyconfigurations/apps/v1"
 	applyconfcore "k8s.io/client-go/applyconfigurations/core/v1"
 	applyconfmeta "k8s.io/client-go/applyconfigurations/meta/v1"
+	"k8s.io/client-go/dynamic"
 	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"sigs.k8s.io/yaml"
 )
 
 func Deploy(clusterName string, ingressHostname string) {
@@ -119,30 +122,31 @@ func addTraefikIngress(provider *KubeProvider, ingressHostname string) {
 	fmt.Printf("Created TRAEFIK punq ingress (%s). ✅\n", ingressHostname)
 }
 
+// the middleware strips the /backend and /websocket prefixes for the traefik ingress (see punq-ingress-traefik.yaml)
+var TRAEFIK_MIDDLEWARE_RESOURCE = schema.GroupVersionResource{Group: "traefik.containo.us", Version: "v1alpha1", Resource: "middlewares"}
+
 func addTraefikMiddleware(provider *KubeProvider, ingressHostname string) {
 	fmt.Printf("Creating TRAEFIK middleware (%s) ...\n", ingressHostname)
-	mwYaml := utils.InitPunqIngressTraefikMiddlewareYaml()
-
-	if err := os.WriteFile("traefik-middleware.yaml", []byte(mwYaml), 0600); err != nil {
+	middleware := unstructured.Unstructured{}
+	err := yaml.Unmarshal([]byte(utils.InitPunqIngressTraefikMiddlewareYaml()), &middleware.Object)
+	if err != nil {
 		utils.FatalError(err.Error())
 	}
-
-	var cmd *exec.Cmd
-	if runtime.GOOS == "windows" {
-		cmd = exec.Command("cmd", "/C", fmt.Sprintf("kubectl %s apply -f traefik-middleware.yaml", ContextFlag(nil)))
-	} else {
-		cmd = exec.Command("bash", "-c", fmt.Sprintf("kubectl %s apply -f traefik-middleware.yaml", ContextFlag(nil)))
-	}
-
-	output, err := cmd.CombinedOutput()
+	middleware.SetNamespace(utils.CONFIG.Kubernetes.OwnNamespace)
+	data, err := middleware.MarshalJSON()
 	if err != nil {
-		utils.FatalError(fmt.Sprintf("failed to execute command (%s): %s\n%s", cmd.String(), err.Error(), string(output)))
-
+		utils.FatalError(err.Error())
 	}
 
-	if err := os.Remove("traefik-middleware.yaml"); err != nil {
+	dynamicClient, err := dynamic.NewForConfig(&provider.ClientConfig)
+	if err != nil {
 		utils.FatalError(err.Error())
 	}
+	middlewareClient := dynamicClient.Resource(TRAEFIK_MIDDLEWARE_RESOURCE).Namespace(utils.CONFIG.Kubernetes.OwnNamespace)
+	_, err = middlewareClient.Patch(context.TODO(), middleware.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{FieldManager: version.Name, Force: utils.Pointer(true)})
+	if err != nil {
+		utils.FatalError(fmt.Sprintf("failed to apply TRAEFIK middleware: %s", err.Error()))
+	}
 
 	fmt.Printf("Created TRAEFIK middleware (%s). ✅\n", ingressHostname)
 }
@@ -327,7 +331,8 @@ func addDeployment(provider *KubeProvider) {
 	deploymentContainer.WithName(version.Name)
 
 	podSpec := applyconfcore.PodSpec()
-	podSpec.WithTerminationGracePeriodSeconds(0)
+	// gives the operator time to write the pending audit entries
+	podSpec.WithTerminationGracePeriodSeconds(10)
 	podSpec.WithServiceAccountName(SERVICEACCOUNTNAME)
 
 	podSpec.WithContainers(deploymentContainer)
@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/logger"
 	"github.com/mogenius/punq/utils"
@@ -13,17 +12,13 @@ import (
 func AllClusterRoleBindings(contextId *string) utils.K8sWorkloadResult {
 	result := []v1.ClusterRoleBinding{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	rolesList, err := provider.ClientSet.RbacV1().ClusterRoleBindings().List(context.TODO(), metav1.ListOptions{})
+	rolesList, err := cachedList[v1.ClusterRoleBinding](contextId, v1.SchemeGroupVersion.WithResource("clusterrolebindings"), "")
 	if err != nil {
 		logger.Log.Errorf("AllClusterRoleBindings ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	for _, role := range rolesList.Items {
+	for _, role := range rolesList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, role.ObjectMeta.Namespace) {
 			result = append(result, role)
 		}
@@ -32,11 +27,7 @@ func AllClusterRoleBindings(contextId *string) utils.K8sWorkloadResult {
 }
 
 func GetClusterRoleBinding(name string, contextId *string) (*v1.ClusterRoleBinding, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.RbacV1().ClusterRoleBindings().Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[v1.ClusterRoleBinding](contextId, v1.SchemeGroupVersion.WithResource("clusterrolebindings"), "", name)
 }
 
 func UpdateK8sClusterRoleBinding(data v1.ClusterRoleBinding, contextId *string) utils.K8sWorkloadResult {
@@ -75,15 +66,7 @@ func DeleteK8sClusterRoleBindingBy(name string, contextId *string) error {
 }
 
 func DescribeK8sClusterRoleBinding(name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "clusterrolebinding", name, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_CLUSTER_ROLE_BINDING, "", name, contextId)
 }
 
 func CreateK8sClusterRoleBinding(data v1.ClusterRoleBinding, contextId *string) utils.K8sWorkloadResult {
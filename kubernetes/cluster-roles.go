@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/utils"
 
@@ -16,17 +15,13 @@ import (
 func AllClusterRoles(contextId *string) utils.K8sWorkloadResult {
 	result := []v1.ClusterRole{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	rolesList, err := provider.ClientSet.RbacV1().ClusterRoles().List(context.TODO(), metav1.ListOptions{})
+	rolesList, err := cachedList[v1.ClusterRole](contextId, v1.SchemeGroupVersion.WithResource("clusterroles"), "")
 	if err != nil {
 		logger.Log.Errorf("AllClusterRoles ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	for _, role := range rolesList.Items {
+	for _, role := range rolesList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, role.ObjectMeta.Namespace) {
 			result = append(result, role)
 		}
@@ -35,11 +30,7 @@ func AllClusterRoles(contextId *string) utils.K8sWorkloadResult {
 }
 
 func GetClusterRole(name string, contextId *string) (*v1.ClusterRole, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.RbacV1().ClusterRoles().Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[v1.ClusterRole](contextId, v1.SchemeGroupVersion.WithResource("clusterroles"), "", name)
 }
 
 func UpdateK8sClusterRole(data v1.ClusterRole, contextId *string) utils.K8sWorkloadResult {
@@ -78,15 +69,7 @@ func DeleteK8sClusterRoleBy(name string, contextId *string) error {
 }
 
 func DescribeK8sClusterRole(name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "clusterrole", name, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_CLUSTER_ROLE, "", name, contextId)
 }
 
 func CreateK8sClusterRole(data v1.ClusterRole, contextId *string) utils.K8sWorkloadResult {
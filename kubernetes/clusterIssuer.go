@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/utils"
 
@@ -77,15 +76,7 @@ func DeleteK8sClusterIssuerBy(name string, contextId *string) error {
 }
 
 func DescribeK8sClusterIssuer(name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "clusterissuer", name, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_CLUSTER_ISSUER, "", name, contextId)
 }
 
 func CreateK8sClusterIssuer(data cmapi.ClusterIssuer, contextId *string) utils.K8sWorkloadResult {
@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/utils"
 
@@ -29,17 +28,13 @@ func ConfigMapFor(namespace string, configMapName string, contextId *string) *v1
 func AllConfigmaps(namespaceName string, contextId *string) []v1.ConfigMap {
 	result := []v1.ConfigMap{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return result
-	}
-	configmapList, err := provider.ClientSet.CoreV1().ConfigMaps(namespaceName).List(context.TODO(), metav1.ListOptions{FieldSelector: "metadata.namespace!=kube-system"})
+	configmapList, err := cachedList[v1.ConfigMap](contextId, v1.SchemeGroupVersion.WithResource("configmaps"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllConfigmaps ERROR: %s", err.Error())
 		return result
 	}
 
-	for _, configmap := range configmapList.Items {
+	for _, configmap := range configmapList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, configmap.ObjectMeta.Namespace) {
 			result = append(result, configmap)
 		}
@@ -50,17 +45,13 @@ func AllConfigmaps(namespaceName string, contextId *string) []v1.ConfigMap {
 func AllK8sConfigmaps(namespaceName string, contextId *string) utils.K8sWorkloadResult {
 	result := []v1.ConfigMap{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	configmapList, err := provider.ClientSet.CoreV1().ConfigMaps(namespaceName).List(context.TODO(), metav1.ListOptions{FieldSelector: "metadata.namespace!=kube-system"})
+	configmapList, err := cachedList[v1.ConfigMap](contextId, v1.SchemeGroupVersion.WithResource("configmaps"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllConfigmaps ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	for _, configmap := range configmapList.Items {
+	for _, configmap := range configmapList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, configmap.ObjectMeta.Namespace) {
 			result = append(result, configmap)
 		}
@@ -69,11 +60,7 @@ func AllK8sConfigmaps(namespaceName string, contextId *string) utils.K8sWorkload
 }
 
 func GetK8sConfigmap(namespaceName string, name string, contextId *string) (*v1.ConfigMap, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.CoreV1().ConfigMaps(namespaceName).Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[v1.ConfigMap](contextId, v1.SchemeGroupVersion.WithResource("configmaps"), namespaceName, name)
 }
 
 func UpdateK8sConfigMap(data v1.ConfigMap, contextId *string) utils.K8sWorkloadResult {
@@ -113,15 +100,7 @@ func DeleteK8sConfigmapBy(namespaceName string, name string, contextId *string)
 }
 
 func DescribeK8sConfigmap(namespace string, name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "configmap", name, "-n", namespace, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_CONFIG_MAP, namespace, name, contextId)
 }
 
 func CreateK8sConfigMap(data v1.ConfigMap, contextId *string) utils.K8sWorkloadResult {
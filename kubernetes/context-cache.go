@@ -0,0 +1,393 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mogenius/punq/logger"
+	"github.com/mogenius/punq/utils"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+const CACHE_SYNC_TIMEOUT = 30 * time.Second
+
+// discovery information is refreshed periodically so newly installed CRDs show up
+const CACHE_MAPPER_TTL = 5 * time.Minute
+
+// objects of this namespace are never listed, independent of misc.ignore_namespaces (like the field selector
+// "metadata.namespace!=kube-system" of the list requests before the informers)
+const HIDDEN_NAMESPACE = "kube-system"
+
+// resources which are only cached per namespace, a cluster-wide informer would keep all of them in memory. Lists
+// over all namespaces are requested directly.
+var CACHE_PER_NAMESPACE_RESOURCES = []schema.GroupVersionResource{
+	{Group: "", Version: "v1", Resource: "secrets"},
+}
+
+// contextCacheEntry holds the clients and shared informers of one context. It is built lazily on first use and
+// replaced as soon as the kubeconfig of the context changes (detected by the hash of the kubeconfig).
+type contextCacheEntry struct {
+	hash            string
+	provider        *KubeProvider
+	informerFactory informers.SharedInformerFactory
+	// factories for CACHE_PER_NAMESPACE_RESOURCES by namespace
+	namespaceInformerFactories map[string]informers.SharedInformerFactory
+	// dynamic informers used by watch subscriptions (by watchInformerKey), stopped when their last watcher leaves
+	watchInformers  map[string]*watchInformer
+	mapper          meta.RESTMapper
+	mapperCreatedAt time.Time
+	stopCh          chan struct{}
+	mutex           sync.Mutex
+}
+
+type watchInformer struct {
+	informer cache.SharedIndexInformer
+	watchers int
+	stopCh   chan struct{}
+}
+
+var contextCache = map[string]*contextCacheEntry{}
+var contextCacheMutex sync.Mutex
+
+// "" is used for the local kubeconfig / in-cluster config
+func contextCacheKey(contextId *string) string {
+	if contextId == nil {
+		return ""
+	}
+	return *contextId
+}
+
+func contextCacheHash(contextId *string) string {
+	if contextId == nil || *contextId == "" {
+		return ""
+	}
+	ctx := ContextForId(*contextId)
+	if ctx == nil {
+		return ""
+	}
+	return utils.HashString(ctx.Context)
+}
+
+func contextCacheEntryFor(contextId *string) (*contextCacheEntry, error) {
+	key := contextCacheKey(contextId)
+	hash := contextCacheHash(contextId)
+
+	contextCacheMutex.Lock()
+	defer contextCacheMutex.Unlock()
+
+	if entry, ok := contextCache[key]; ok {
+		if entry.hash == hash {
+			return entry, nil
+		}
+		logger.Log.Infof("Kubeconfig of context '%s' changed. Rebuilding cache.", key)
+		close(entry.stopCh)
+		delete(contextCache, key)
+	}
+
+	provider, err := newKubeProvider(contextId)
+	if err != nil {
+		return nil, err
+	}
+	entry := &contextCacheEntry{
+		hash:                       hash,
+		provider:                   provider,
+		informerFactory:            informers.NewSharedInformerFactory(provider.ClientSet, 0),
+		namespaceInformerFactories: map[string]informers.SharedInformerFactory{},
+		watchInformers:             map[string]*watchInformer{},
+		stopCh:                     make(chan struct{}),
+	}
+	contextCache[key] = entry
+	return entry, nil
+}
+
+// EvictContextCache stops all informers of the context and drops its clients (including the impersonated ones)
+func EvictContextCache(contextId string) {
+	contextCacheMutex.Lock()
+	defer contextCacheMutex.Unlock()
+
+	for _, key := range append(removeImpersonations(contextId), contextId) {
+		if entry, ok := contextCache[key]; ok {
+			close(entry.stopCh)
+			delete(contextCache, key)
+		}
+	}
+}
+
+func CachedRESTMapper(contextId *string) (meta.RESTMapper, error) {
+	entry, err := contextCacheEntryFor(contextId)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+	if entry.mapper == nil || time.Since(entry.mapperCreatedAt) > CACHE_MAPPER_TTL {
+		mapper, err := restMapperFor(entry.provider)
+		if err != nil {
+			return nil, err
+		}
+		entry.mapper = mapper
+		entry.mapperCreatedAt = time.Now()
+	}
+	return entry.mapper, nil
+}
+
+// acquireWatchInformer returns the started dynamic informer of the context for the resource in the namespace
+// ("" = all namespaces). Watchers share the informer; it is stopped when the returned release function has been
+// called by all of them (or the context cache is evicted).
+func acquireWatchInformer(contextId *string, gvr schema.GroupVersionResource, namespace string) (cache.SharedIndexInformer, func(), error) {
+	entry, err := contextCacheEntryFor(contextId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+	key := watchInformerKey(gvr, namespace)
+	watch, ok := entry.watchInformers[key]
+	if !ok {
+		dynamicClient, err := dynamic.NewForConfig(&entry.provider.ClientConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		watch = &watchInformer{
+			informer: dynamicinformer.NewFilteredDynamicInformer(dynamicClient, gvr, namespace, 0, cache.Indexers{}, nil).Informer(),
+			stopCh:   make(chan struct{}),
+		}
+		entry.watchInformers[key] = watch
+		go watch.informer.Run(mergeStopChannels(watch.stopCh, entry.stopCh))
+	}
+	watch.watchers++
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			entry.mutex.Lock()
+			defer entry.mutex.Unlock()
+			watch.watchers--
+			if watch.watchers == 0 {
+				close(watch.stopCh)
+				delete(entry.watchInformers, key)
+			}
+		})
+	}
+	return watch.informer, release, nil
+}
+
+func watchInformerKey(gvr schema.GroupVersionResource, namespace string) string {
+	return fmt.Sprintf("%s/%s", namespace, gvr.String())
+}
+
+// mergeStopChannels returns a channel which is closed as soon as one of the given channels is closed
+func mergeStopChannels(a chan struct{}, b chan struct{}) chan struct{} {
+	merged := make(chan struct{})
+	go func() {
+		select {
+		case <-a:
+		case <-b:
+		}
+		close(merged)
+	}()
+	return merged
+}
+
+// cachedInformer returns the synced shared informer for a built-in resource of the context. Resources of
+// CACHE_PER_NAMESPACE_RESOURCES get an informer of the namespace.
+func cachedInformer(contextId *string, gvr schema.GroupVersionResource, namespace string) (cache.SharedIndexInformer, error) {
+	entry, err := contextCacheEntryFor(contextId)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.mutex.Lock()
+	factory := entry.informerFactory
+	informerNamespace := ""
+	if isCachedPerNamespace(gvr) {
+		informerNamespace = namespace
+		factory = entry.namespaceInformerFactories[namespace]
+		if factory == nil {
+			factory = informers.NewSharedInformerFactoryWithOptions(entry.provider.ClientSet, 0, informers.WithNamespace(namespace))
+			entry.namespaceInformerFactories[namespace] = factory
+		}
+	}
+	genericInformer, err := factory.ForResource(gvr)
+	if err != nil {
+		entry.mutex.Unlock()
+		return nil, err
+	}
+	informer := genericInformer.Informer()
+	if !informer.HasSynced() {
+		// an informer without list/watch permission would never sync and retry forever, so check once before starting it
+		dynamicClient, err := dynamic.NewForConfig(&entry.provider.ClientConfig)
+		if err == nil {
+			_, err = dynamicClient.Resource(gvr).Namespace(informerNamespace).List(context.TODO(), metav1.ListOptions{Limit: 1})
+		}
+		if err != nil {
+			entry.mutex.Unlock()
+			return nil, err
+		}
+	}
+	factory.Start(entry.stopCh)
+	entry.mutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), CACHE_SYNC_TIMEOUT)
+	defer cancel()
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return nil, fmt.Errorf("cache for '%s' did not sync within %s", gvr.String(), CACHE_SYNC_TIMEOUT)
+	}
+	return informer, nil
+}
+
+func isCachedPerNamespace(gvr schema.GroupVersionResource) bool {
+	for _, resource := range CACHE_PER_NAMESPACE_RESOURCES {
+		if resource == gvr {
+			return true
+		}
+	}
+	return false
+}
+
+// isHidden is true for objects of HIDDEN_NAMESPACE
+func isHidden(obj interface{}) bool {
+	accessor, err := meta.Accessor(obj)
+	return err == nil && accessor.GetNamespace() == HIDDEN_NAMESPACE
+}
+
+// cachedList returns deep copies of all cached objects of the resource in the namespace ("" = all namespaces) sorted
+// by namespace and name. Objects of HIDDEN_NAMESPACE are left out.
+func cachedList[T any](contextId *string, gvr schema.GroupVersionResource, namespace string) ([]T, error) {
+	result := []T{}
+	if namespace == HIDDEN_NAMESPACE {
+		return result, nil
+	}
+	if isImpersonated(contextId) || namespace == "" && isCachedPerNamespace(gvr) {
+		return directList[T](contextId, gvr, namespace)
+	}
+
+	informer, err := cachedInformer(contextId, gvr, namespace)
+	if err != nil {
+		return result, err
+	}
+
+	var objs []interface{}
+	if namespace == "" {
+		objs = informer.GetIndexer().List()
+	} else {
+		objs, err = informer.GetIndexer().ByIndex(cache.NamespaceIndex, namespace)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	sort.Slice(objs, func(i, j int) bool {
+		a, _ := cache.MetaNamespaceKeyFunc(objs[i])
+		b, _ := cache.MetaNamespaceKeyFunc(objs[j])
+		return a < b
+	})
+
+	for _, obj := range objs {
+		runtimeObj, ok := obj.(runtime.Object)
+		if !ok || isHidden(obj) {
+			continue
+		}
+		var copied interface{} = runtimeObj.DeepCopyObject()
+		if typed, ok := copied.(*T); ok {
+			result = append(result, *typed)
+		}
+	}
+	return result, nil
+}
+
+// cachedGet returns a deep copy of the cached object (namespace is empty for cluster-scoped resources)
+func cachedGet[T any](contextId *string, gvr schema.GroupVersionResource, namespace string, name string) (*T, error) {
+	if isImpersonated(contextId) {
+		return directGet[T](contextId, gvr, namespace, name)
+	}
+	informer, err := cachedInformer(contextId, gvr, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	key := name
+	if namespace != "" {
+		key = fmt.Sprintf("%s/%s", namespace, name)
+	}
+	obj, exists, err := informer.GetIndexer().GetByKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, apierrors.NewNotFound(gvr.GroupResource(), name)
+	}
+	runtimeObj, ok := obj.(runtime.Object)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object in cache for '%s'", gvr.String())
+	}
+	var copied interface{} = runtimeObj.DeepCopyObject()
+	typed, ok := copied.(*T)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object type in cache for '%s'", gvr.String())
+	}
+	return typed, nil
+}
+
+// directList is used instead of the informers for impersonated contexts, so every request is checked by the cluster,
+// and for CACHE_PER_NAMESPACE_RESOURCES in all namespaces
+func directList[T any](contextId *string, gvr schema.GroupVersionResource, namespace string) ([]T, error) {
+	result := []T{}
+	provider, err := NewKubeProvider(contextId)
+	if err != nil {
+		return result, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(&provider.ClientConfig)
+	if err != nil {
+		return result, err
+	}
+	list, err := dynamicClient.Resource(gvr).Namespace(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return result, err
+	}
+	for _, item := range list.Items {
+		if item.GetNamespace() == HIDDEN_NAMESPACE {
+			continue
+		}
+		var typed T
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &typed); err != nil {
+			return result, err
+		}
+		result = append(result, typed)
+	}
+	return result, nil
+}
+
+func directGet[T any](contextId *string, gvr schema.GroupVersionResource, namespace string, name string) (*T, error) {
+	provider, err := NewKubeProvider(contextId)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(&provider.ClientConfig)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var typed T
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &typed); err != nil {
+		return nil, err
+	}
+	return &typed, nil
+}
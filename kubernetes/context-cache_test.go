@@ -0,0 +1,75 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/mogenius/punq/dtos"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsHidden(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  interface{}
+		want bool
+	}{
+		{"kube-system", &v1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: HIDDEN_NAMESPACE, Name: "token"}}, true},
+		{"other namespace", &v1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "token"}}, false},
+		{"cluster-scoped", &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: HIDDEN_NAMESPACE}}, false},
+		{"no object", "kube-system", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isHidden(tt.obj); got != tt.want {
+				t.Errorf("isHidden() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCachedListHidesKubeSystem(t *testing.T) {
+	// answered before any client is built, so no cluster is needed
+	secrets, err := cachedList[v1.Secret](nil, v1.SchemeGroupVersion.WithResource("secrets"), HIDDEN_NAMESPACE)
+	if err != nil || len(secrets) != 0 {
+		t.Errorf("cachedList(%s) = %v, %v, want no objects", HIDDEN_NAMESPACE, secrets, err)
+	}
+}
+
+func TestContextSyncEvictsRemovedContexts(t *testing.T) {
+	previous := ContextList()
+	t.Cleanup(func() { ContextSync(previous) })
+
+	ContextSync([]dtos.PunqContext{{Id: "kept"}, {Id: "removed"}})
+	kept := &contextCacheEntry{stopCh: make(chan struct{})}
+	removed := &contextCacheEntry{stopCh: make(chan struct{})}
+	contextCacheMutex.Lock()
+	contextCache["kept"] = kept
+	contextCache["removed"] = removed
+	contextCacheMutex.Unlock()
+	t.Cleanup(func() { EvictContextCache("kept") })
+
+	ContextSync([]dtos.PunqContext{{Id: "kept"}})
+
+	if ContextForId("removed") != nil || ContextForId("kept") == nil {
+		t.Errorf("ContextSync() contexts = %v, want only 'kept'", ContextList())
+	}
+	contextCacheMutex.Lock()
+	_, keptCached := contextCache["kept"]
+	_, removedCached := contextCache["removed"]
+	contextCacheMutex.Unlock()
+	if !keptCached || removedCached {
+		t.Errorf("ContextSync() cached kept = %t, removed = %t, want true, false", keptCached, removedCached)
+	}
+	select {
+	case <-removed.stopCh:
+	default:
+		t.Errorf("informers of the removed context were not stopped")
+	}
+	select {
+	case <-kept.stopCh:
+		t.Errorf("informers of the kept context were stopped")
+	default:
+	}
+}
@@ -0,0 +1,113 @@
+package kubernetes
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mogenius/punq/dtos"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// ProbeContext checks the api server of the context with its own client (not the cached one), so a hanging cluster
+// cannot block other requests. The context is reachable if the version request succeeds.
+func ProbeContext(ctx dtos.PunqContext, timeout time.Duration) dtos.PunqContextHealth {
+	health := dtos.PunqContextHealth{
+		ContextId:   ctx.Id,
+		ContextName: ctx.Name,
+		Provider:    ctx.Provider,
+		CheckedAt:   time.Now(),
+	}
+
+	configFromString, err := clientcmd.NewClientConfigFromBytes([]byte(ctx.Context))
+	if err != nil {
+		health.LastError = err.Error()
+		return health
+	}
+	config, err := configFromString.ClientConfig()
+	if err != nil {
+		health.LastError = err.Error()
+		return health
+	}
+	health.CredentialsExpireAt = credentialsExpiry(config)
+
+	config.Timeout = timeout
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		health.LastError = err.Error()
+		return health
+	}
+
+	start := time.Now()
+	info, err := clientset.Discovery().ServerVersion()
+	health.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		health.LastError = err.Error()
+		return health
+	}
+	health.Reachable = true
+	health.KubernetesVersion = info.String()
+
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	nodeList, err := clientset.CoreV1().Nodes().List(timeoutCtx, metav1.ListOptions{})
+	if err != nil {
+		health.LastError = err.Error()
+		return health
+	}
+	health.NodeCount = len(nodeList.Items)
+	if provider, err := GuessCluserProviderFromNodeList(nodeList); err == nil {
+		health.Provider = string(provider)
+	}
+	return health
+}
+
+// credentialsExpiry returns the earlier expiry of the client certificate and the bearer token (if it is a JWT)
+func credentialsExpiry(config *rest.Config) *time.Time {
+	var result *time.Time
+	earliest := func(t time.Time) {
+		if result == nil || t.Before(*result) {
+			result = &t
+		}
+	}
+
+	certData := config.CertData
+	if len(certData) == 0 && config.CertFile != "" {
+		certData, _ = os.ReadFile(config.CertFile)
+	}
+	for block, remaining := pem.Decode(certData); block != nil; block, remaining = pem.Decode(remaining) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err == nil {
+			earliest(cert.NotAfter)
+		}
+		// the first certificate is the one of the client, the others are intermediates
+		break
+	}
+
+	token := config.BearerToken
+	if token == "" && config.BearerTokenFile != "" {
+		data, _ := os.ReadFile(config.BearerTokenFile)
+		token = strings.TrimSpace(string(data))
+	}
+	if parts := strings.Split(token, "."); len(parts) == 3 {
+		payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+		claims := struct {
+			Exp int64 `json:"exp"`
+		}{}
+		if err == nil && json.Unmarshal(payload, &claims) == nil && claims.Exp > 0 {
+			earliest(time.Unix(claims.Exp, 0))
+		}
+	}
+	return result
+}
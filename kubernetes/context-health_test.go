@@ -0,0 +1,94 @@
+package kubernetes
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+func testCertificate(t *testing.T, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "punq"},
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func testToken(payload string) string {
+	encode := base64.RawURLEncoding.EncodeToString
+	return fmt.Sprintf("%s.%s.signature", encode([]byte(`{"alg":"RS256"}`)), encode([]byte(payload)))
+}
+
+func TestCredentialsExpiry(t *testing.T) {
+	certExpiry := time.Unix(2000000000, 0)
+	tokenExpiry := time.Unix(1900000000, 0)
+	cert := testCertificate(t, certExpiry)
+	intermediate := testCertificate(t, time.Unix(1800000000, 0))
+	key := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte("key")})
+	token := testToken(fmt.Sprintf(`{"sub":"punq","exp":%d}`, tokenExpiry.Unix()))
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.crt")
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(certFile, cert, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(tokenFile, []byte(token+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		config rest.Config
+		want   *time.Time
+	}{
+		{"none", rest.Config{}, nil},
+		{"certificate", rest.Config{TLSClientConfig: rest.TLSClientConfig{CertData: cert}}, &certExpiry},
+		{"certificate file", rest.Config{TLSClientConfig: rest.TLSClientConfig{CertFile: certFile}}, &certExpiry},
+		{"certificate after other blocks", rest.Config{TLSClientConfig: rest.TLSClientConfig{CertData: append(append([]byte{}, key...), cert...)}}, &certExpiry},
+		{"intermediates are ignored", rest.Config{TLSClientConfig: rest.TLSClientConfig{CertData: append(append([]byte{}, cert...), intermediate...)}}, &certExpiry},
+		{"invalid certificate", rest.Config{TLSClientConfig: rest.TLSClientConfig{CertData: []byte("not pem")}}, nil},
+		{"missing certificate file", rest.Config{TLSClientConfig: rest.TLSClientConfig{CertFile: filepath.Join(dir, "missing.crt")}}, nil},
+		{"token", rest.Config{BearerToken: token}, &tokenExpiry},
+		{"token file", rest.Config{BearerTokenFile: tokenFile}, &tokenExpiry},
+		{"token without exp", rest.Config{BearerToken: testToken(`{"sub":"punq"}`)}, nil},
+		{"opaque token", rest.Config{BearerToken: "abcdef0123456789"}, nil},
+		{"invalid token payload", rest.Config{BearerToken: "a.!!!.c"}, nil},
+		{"earliest of certificate and token", rest.Config{BearerToken: token, TLSClientConfig: rest.TLSClientConfig{CertData: cert}}, &tokenExpiry},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := credentialsExpiry(&tt.config)
+			switch {
+			case got == nil && tt.want == nil:
+			case got == nil || tt.want == nil:
+				t.Errorf("credentialsExpiry() = %v, want %v", got, tt.want)
+			case !got.Equal(*tt.want):
+				t.Errorf("credentialsExpiry() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
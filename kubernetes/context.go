@@ -2,20 +2,28 @@ package kubernetes
 
 import (
 	"context"
-	"errors"
-	"fmt"
-	"os"
+	"sync"
 
 	"github.com/mogenius/punq/dtos"
-	"github.com/mogenius/punq/utils"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
 var allContexts []dtos.PunqContext = []dtos.PunqContext{}
+var allContextsMutex sync.RWMutex
 
+// ContextForId also resolves impersonated ids (see ImpersonatedContextId) to their context
 func ContextForId(id string) *dtos.PunqContext {
+	if isImpersonated(&id) {
+		entry := impersonationFor(&id)
+		if entry == nil {
+			return nil
+		}
+		id = entry.contextId
+	}
+	allContextsMutex.RLock()
+	defer allContextsMutex.RUnlock()
 	for _, ctx := range allContexts {
 		if ctx.Id == id {
 			return &ctx
@@ -24,12 +32,18 @@ func ContextForId(id string) *dtos.PunqContext {
 	return nil
 }
 
+// ContextAddOne adds the context or replaces an existing one with the same id.
+// Cached clients of a replaced context are rebuilt on next use if the kubeconfig changed.
 func ContextAddOne(ctx dtos.PunqContext) {
-	if ContextForId(ctx.Id) != nil {
-		return
+	allContextsMutex.Lock()
+	defer allContextsMutex.Unlock()
+	for i := range allContexts {
+		if allContexts[i].Id == ctx.Id {
+			allContexts[i] = ctx
+			return
+		}
 	}
 	allContexts = append(allContexts, ctx)
-	contextWrite(ctx.Id)
 }
 
 func ContextAddMany(ctxs []dtos.PunqContext) {
@@ -38,34 +52,49 @@ func ContextAddMany(ctxs []dtos.PunqContext) {
 	}
 }
 
-func ContextList() []dtos.PunqContext {
-	return allContexts
-}
-
-func ContextFlag(id *string) string {
-	if id == nil {
-		return ""
+// ContextRemove removes the context and stops all cached clients/informers of it
+func ContextRemove(id string) {
+	allContextsMutex.Lock()
+	for i := range allContexts {
+		if allContexts[i].Id == id {
+			allContexts = append(allContexts[:i], allContexts[i+1:]...)
+			break
+		}
 	}
-	return fmt.Sprintf("--kubeconfig=%s.yaml", *id)
+	allContextsMutex.Unlock()
+	EvictContextCache(id)
 }
 
-func contextWrite(id string) error {
-	if !utils.CONFIG.Kubernetes.RunInCluster {
-		return nil
+// ContextSync replaces the contexts with the stored ones (which another replica may have changed) and stops all
+// cached clients/informers of the removed ones
+func ContextSync(ctxs []dtos.PunqContext) {
+	allContextsMutex.Lock()
+	removed := []string{}
+	for _, existing := range allContexts {
+		found := false
+		for _, ctx := range ctxs {
+			if ctx.Id == existing.Id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			removed = append(removed, existing.Id)
+		}
 	}
+	allContexts = append([]dtos.PunqContext{}, ctxs...)
+	allContextsMutex.Unlock()
 
-	ctx := ContextForId(id)
-	if ctx == nil {
-		return errors.New("context not found")
+	for _, id := range removed {
+		EvictContextCache(id)
 	}
+}
 
-	// write ctx.context into file
-	err := os.WriteFile(fmt.Sprintf("%s.yaml", ctx.Id), []byte(ctx.Context), 0644)
-	if err != nil {
-		_ = os.Remove(ctx.Id)
-		return err
-	}
-	return nil
+// ContextList returns a copy, the contexts can change while the caller iterates them
+func ContextList() []dtos.PunqContext {
+	allContextsMutex.RLock()
+	defer allContextsMutex.RUnlock()
+	return append([]dtos.PunqContext{}, allContexts...)
 }
 
 func CheckContext(ctx dtos.PunqContext) (bool, dtos.KubernetesProvider, error) {
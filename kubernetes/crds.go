@@ -1,82 +1,81 @@
 package kubernetes
 
 import (
-	"os/exec"
+	"context"
 
+	"github.com/mogenius/punq/logger"
 	"github.com/mogenius/punq/utils"
 
-	"github.com/mogenius/punq/logger"
-	apiExt "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiExtV1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// TODO
-
-func AllCustomResourceDefinitions() utils.K8sWorkloadResult {
-	// TODO
-
-	// 	result := []apiExt.CustomResourceDefinition{}
-
-	// 	provider,err := NewKubeProvider()
-	// 	certificatesList, err := provider.ClientSet.ApiextensionsV1()
-	// 	if err != nil {
-	// 		logger.Log.Errorf("AllCertificateSigningRequests ERROR: %s", err.Error())
-	// 		return WorkloadResult(nil, err)
-	// 	}
-
-	// 	for _, certificate := range certificatesList.Items {
-	// 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, certificate.ObjectMeta.Namespace) {
-	// 			result = append(result, certificate)
-	// 		}
-	// 	}
-	return WorkloadResult(nil, nil)
+func AllCustomResourceDefinitions(contextId *string) utils.K8sWorkloadResult {
+	provider, err := NewKubeProviderApiExtensions(contextId)
+	if err != nil {
+		return WorkloadResult(nil, err)
+	}
+	crdList, err := provider.ClientSet.ApiextensionsV1().CustomResourceDefinitions().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Log.Errorf("AllCustomResourceDefinitions ERROR: %s", err.Error())
+		return WorkloadResult(nil, err)
+	}
+	return WorkloadResult(crdList.Items, nil)
 }
 
-func UpdateK8sCustomResourceDefinition(data apiExt.CustomResourceDefinition) utils.K8sWorkloadResult {
-	// TODO
-
-	// providererr := NewKubeProvider()
-	// certificateClient := provider.ClientSet.Ex.CertificateRequests(data.Namespace)
-	// _, err := certificateClient.Update(context.TODO(), &data, metav1.UpdateOptions{})
-	// if err != nil {
-	// 	return WorkloadResult(nil, err)
-	// }
-	return WorkloadResult(nil, nil)
+func GetCustomResourceDefinition(name string, contextId *string) (*apiExtV1.CustomResourceDefinition, error) {
+	provider, err := NewKubeProviderApiExtensions(contextId)
+	if err != nil {
+		return nil, err
+	}
+	return provider.ClientSet.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), name, metav1.GetOptions{})
 }
 
-func DeleteK8sCustomResourceDefinition(data apiExt.CustomResourceDefinition) utils.K8sWorkloadResult {
-	// TODO
+func UpdateK8sCustomResourceDefinition(data apiExtV1.CustomResourceDefinition, contextId *string) utils.K8sWorkloadResult {
+	provider, err := NewKubeProviderApiExtensions(contextId)
+	if err != nil {
+		return WorkloadResult(nil, err)
+	}
+	client := provider.ClientSet.ApiextensionsV1().CustomResourceDefinitions()
+	res, err := client.Update(context.TODO(), &data, metav1.UpdateOptions{})
+	if err != nil {
+		return WorkloadResult(nil, err)
+	}
+	return WorkloadResult(res, nil)
+}
 
-	// providererr := NewKubeProvider()
-	// certificateClient := provider.ClientSet.
-	// err := certificateClient.Delete(context.TODO(), data.Name, metav1.DeleteOptions{})
-	// if err != nil {
-	// 	return WorkloadResult(nil, err)
-	// }
+func DeleteK8sCustomResourceDefinition(data apiExtV1.CustomResourceDefinition, contextId *string) utils.K8sWorkloadResult {
+	err := DeleteK8sCustomResourceDefinitionBy(data.Name, contextId)
+	if err != nil {
+		return WorkloadResult(nil, err)
+	}
 	return WorkloadResult(nil, nil)
 }
 
-func DescribeK8sCustomResourceDefinition(name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "crds", name, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
+func DeleteK8sCustomResourceDefinitionBy(name string, contextId *string) error {
+	provider, err := NewKubeProviderApiExtensions(contextId)
 	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
+		return err
 	}
-	return WorkloadResult(string(output), nil)
+	client := provider.ClientSet.ApiextensionsV1().CustomResourceDefinitions()
+	return client.Delete(context.TODO(), name, metav1.DeleteOptions{})
 }
 
-func CreateK8sCustomResourceDefinition(data apiExt.CustomResourceDefinition) utils.K8sWorkloadResult {
-	// TODO
+func DescribeK8sCustomResourceDefinition(name string, contextId *string) utils.K8sWorkloadResult {
+	return DescribeK8s(RES_CUSTOM_RESOURCE_DEFINITION, "", name, contextId)
+}
 
-	// providererr := NewKubeProvider()
-	// client := provider.ClientSet.CoreV1().ConfigMaps(data.Namespace)
-	// _, err := client.Create(context.TODO(), &data, metav1.CreateOptions{})
-	// if err != nil {
-	// 	return WorkloadResult(nil, err)
-	// }
-	return WorkloadResult(nil, nil)
+func CreateK8sCustomResourceDefinition(data apiExtV1.CustomResourceDefinition, contextId *string) utils.K8sWorkloadResult {
+	provider, err := NewKubeProviderApiExtensions(contextId)
+	if err != nil {
+		return WorkloadResult(nil, err)
+	}
+	client := provider.ClientSet.ApiextensionsV1().CustomResourceDefinitions()
+	res, err := client.Create(context.TODO(), &data, metav1.CreateOptions{})
+	if err != nil {
+		return WorkloadResult(nil, err)
+	}
+	return WorkloadResult(res, nil)
 }
 
 func NewK8sCustomResourceDefinition() K8sNewWorkload {
@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/utils"
 
@@ -16,17 +15,13 @@ import (
 func AllCronjobs(namespaceName string, contextId *string) utils.K8sWorkloadResult {
 	result := []v1job.CronJob{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	cronJobList, err := provider.ClientSet.BatchV1().CronJobs(namespaceName).List(context.TODO(), metav1.ListOptions{FieldSelector: "metadata.namespace!=kube-system"})
+	cronJobList, err := cachedList[v1.CronJob](contextId, v1.SchemeGroupVersion.WithResource("cronjobs"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllCronjobs ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	for _, cronJob := range cronJobList.Items {
+	for _, cronJob := range cronJobList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, cronJob.ObjectMeta.Namespace) {
 			result = append(result, cronJob)
 		}
@@ -35,11 +30,7 @@ func AllCronjobs(namespaceName string, contextId *string) utils.K8sWorkloadResul
 }
 
 func GetCronjob(namespaceName string, name string, contextId *string) (*v1job.CronJob, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.BatchV1().CronJobs(namespaceName).Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[v1.CronJob](contextId, v1.SchemeGroupVersion.WithResource("cronjobs"), namespaceName, name)
 }
 
 func UpdateK8sCronJob(data v1.CronJob, contextId *string) utils.K8sWorkloadResult {
@@ -78,15 +69,7 @@ func DeleteK8sCronJobBy(namespace string, name string, contextId *string) error
 }
 
 func DescribeK8sCronJob(namespace string, name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "cronjob", name, "-n", namespace, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_CRON_JOB, namespace, name, contextId)
 }
 
 func CreateK8sCronJob(data v1.CronJob, contextId *string) utils.K8sWorkloadResult {
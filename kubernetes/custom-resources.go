@@ -0,0 +1,150 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mogenius/punq/logger"
+	"github.com/mogenius/punq/utils"
+
+	apiExtV1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiExtClientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// ErrNotCustomResource is returned for resources which are not backed by a CustomResourceDefinition. The generic
+// routes only serve custom resources, built-in kinds have their own routes with their own role rules.
+var ErrNotCustomResource = errors.New("not a custom resource")
+
+// CustomResourceNamespaced resolves the resource against the CustomResourceDefinitions of the cluster and returns
+// whether its objects are namespaced
+func CustomResourceNamespaced(group string, version string, resource string, contextId *string) (bool, error) {
+	provider, err := NewKubeProviderApiExtensions(contextId)
+	if err != nil {
+		return false, err
+	}
+	return customResourceNamespaced(provider.ClientSet, group, version, resource)
+}
+
+func customResourceNamespaced(client apiExtClientset.Interface, group string, version string, resource string) (bool, error) {
+	// crds are named <plural>.<group>, built-in resources have none
+	crd, err := client.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), fmt.Sprintf("%s.%s", resource, group), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, fmt.Errorf("%w: '%s' of group '%s'", ErrNotCustomResource, resource, group)
+	}
+	if err != nil {
+		return false, err
+	}
+	if crd.Spec.Group != group || crd.Spec.Names.Plural != resource {
+		return false, fmt.Errorf("%w: '%s' of group '%s'", ErrNotCustomResource, resource, group)
+	}
+	for _, crdVersion := range crd.Spec.Versions {
+		if crdVersion.Name == version && crdVersion.Served {
+			return crd.Spec.Scope == apiExtV1.NamespaceScoped, nil
+		}
+	}
+	return false, fmt.Errorf("version '%s' of '%s' is not served", version, crd.Name)
+}
+
+// customResourceClient returns a dynamic client for the given custom resource. The namespace is ignored for cluster-scoped resources.
+func customResourceClient(group string, version string, resource string, namespace string, contextId *string) (dynamic.ResourceInterface, error) {
+	namespaced, err := CustomResourceNamespaced(group, version, resource, baseContextId(contextId))
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := NewKubeProvider(contextId)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(&provider.ClientConfig)
+	if err != nil {
+		return nil, err
+	}
+	gvr := schema.GroupVersionResource{Group: group, Version: version, Resource: resource}
+	if namespaced && namespace != "" {
+		return dynamicClient.Resource(gvr).Namespace(namespace), nil
+	}
+	return dynamicClient.Resource(gvr), nil
+}
+
+func AllCustomResources(group string, version string, resource string, namespaceName string, contextId *string) utils.K8sWorkloadResult {
+	result := []unstructured.Unstructured{}
+
+	client, err := customResourceClient(group, version, resource, namespaceName, contextId)
+	if err != nil {
+		return WorkloadResult(nil, err)
+	}
+	list, err := client.List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Log.Errorf("AllCustomResources %s/%s/%s ERROR: %s", group, version, resource, err.Error())
+		return WorkloadResult(nil, err)
+	}
+
+	for _, obj := range list.Items {
+		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, obj.GetNamespace()) {
+			obj.SetManagedFields(nil)
+			result = append(result, obj)
+		}
+	}
+	return WorkloadResult(result, nil)
+}
+
+func GetCustomResource(group string, version string, resource string, namespace string, name string, contextId *string) utils.K8sWorkloadResult {
+	client, err := customResourceClient(group, version, resource, namespace, contextId)
+	if err != nil {
+		return WorkloadResult(nil, err)
+	}
+	res, err := client.Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return WorkloadResult(nil, err)
+	}
+	res.SetManagedFields(nil)
+	return WorkloadResult(res, nil)
+}
+
+// PatchK8sCustomResource applies the object as JSON merge patch, so fields which are missing in it (e.g. the
+// status or fields of a newer schema) are kept. A resourceVersion in the object makes the patch fail on conflicts.
+func PatchK8sCustomResource(group string, version string, resource string, data unstructured.Unstructured, contextId *string) utils.K8sWorkloadResult {
+	client, err := customResourceClient(group, version, resource, data.GetNamespace(), contextId)
+	if err != nil {
+		return WorkloadResult(nil, err)
+	}
+	data.SetManagedFields(nil)
+	patch, err := data.MarshalJSON()
+	if err != nil {
+		return WorkloadResult(nil, err)
+	}
+	res, err := client.Patch(context.TODO(), data.GetName(), types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return WorkloadResult(nil, err)
+	}
+	res.SetManagedFields(nil)
+	return WorkloadResult(res, nil)
+}
+
+func DeleteK8sCustomResourceBy(group string, version string, resource string, namespace string, name string, contextId *string) error {
+	client, err := customResourceClient(group, version, resource, namespace, contextId)
+	if err != nil {
+		return err
+	}
+	return client.Delete(context.TODO(), name, metav1.DeleteOptions{})
+}
+
+func CreateK8sCustomResource(group string, version string, resource string, data unstructured.Unstructured, contextId *string) utils.K8sWorkloadResult {
+	client, err := customResourceClient(group, version, resource, data.GetNamespace(), contextId)
+	if err != nil {
+		return WorkloadResult(nil, err)
+	}
+	res, err := client.Create(context.TODO(), &data, metav1.CreateOptions{})
+	if err != nil {
+		return WorkloadResult(nil, err)
+	}
+	return WorkloadResult(res, nil)
+}
@@ -0,0 +1,66 @@
+package kubernetes
+
+import (
+	"errors"
+	"testing"
+
+	apiExtV1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testCrd(group string, plural string, scope apiExtV1.ResourceScope, versions ...apiExtV1.CustomResourceDefinitionVersion) *apiExtV1.CustomResourceDefinition {
+	return &apiExtV1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: plural + "." + group},
+		Spec: apiExtV1.CustomResourceDefinitionSpec{
+			Group:    group,
+			Names:    apiExtV1.CustomResourceDefinitionNames{Plural: plural},
+			Scope:    scope,
+			Versions: versions,
+		},
+	}
+}
+
+func TestCustomResourceNamespaced(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		testCrd("argoproj.io", "applications", apiExtV1.NamespaceScoped,
+			apiExtV1.CustomResourceDefinitionVersion{Name: "v1alpha1", Served: true},
+			apiExtV1.CustomResourceDefinitionVersion{Name: "v1alpha0", Served: false},
+		),
+		testCrd("cert-manager.io", "clusterissuers", apiExtV1.ClusterScoped,
+			apiExtV1.CustomResourceDefinitionVersion{Name: "v1", Served: true},
+		),
+	)
+
+	tests := []struct {
+		name           string
+		group          string
+		version        string
+		resource       string
+		wantNamespaced bool
+		wantErr        bool
+		wantNotCustom  bool
+	}{
+		{"namespaced crd", "argoproj.io", "v1alpha1", "applications", true, false, false},
+		{"cluster-scoped crd", "cert-manager.io", "v1", "clusterissuers", false, false, false},
+		{"built-in group", "rbac.authorization.k8s.io", "v1", "clusterrolebindings", false, true, true},
+		{"built-in core group", "", "v1", "secrets", false, true, true},
+		{"unknown resource of a crd group", "argoproj.io", "v1alpha1", "secrets", false, true, true},
+		{"version not served", "argoproj.io", "v1alpha0", "applications", false, true, false},
+		{"unknown version", "argoproj.io", "v2", "applications", false, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namespaced, err := customResourceNamespaced(client, tt.group, tt.version, tt.resource)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("customResourceNamespaced() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if err == nil && namespaced != tt.wantNamespaced {
+				t.Errorf("customResourceNamespaced() = %t, want %t", namespaced, tt.wantNamespaced)
+			}
+			if errors.Is(err, ErrNotCustomResource) != tt.wantNotCustom {
+				t.Errorf("customResourceNamespaced() error = %v, want %v", err, ErrNotCustomResource)
+			}
+		})
+	}
+}
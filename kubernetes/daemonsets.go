@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/utils"
 
@@ -15,17 +14,13 @@ import (
 func AllDaemonsets(namespaceName string, contextId *string) []v1.DaemonSet {
 	result := []v1.DaemonSet{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return result
-	}
-	daemonsetList, err := provider.ClientSet.AppsV1().DaemonSets(namespaceName).List(context.TODO(), metav1.ListOptions{FieldSelector: "metadata.namespace!=kube-system"})
+	daemonsetList, err := cachedList[v1.DaemonSet](contextId, v1.SchemeGroupVersion.WithResource("daemonsets"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllDaemonsets ERROR: %s", err.Error())
 		return result
 	}
 
-	for _, daemonset := range daemonsetList.Items {
+	for _, daemonset := range daemonsetList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, daemonset.ObjectMeta.Namespace) {
 			result = append(result, daemonset)
 		}
@@ -36,17 +31,13 @@ func AllDaemonsets(namespaceName string, contextId *string) []v1.DaemonSet {
 func AllK8sDaemonsets(namespaceName string, contextId *string) utils.K8sWorkloadResult {
 	result := []v1.DaemonSet{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	daemonsetList, err := provider.ClientSet.AppsV1().DaemonSets(namespaceName).List(context.TODO(), metav1.ListOptions{FieldSelector: "metadata.namespace!=kube-system"})
+	daemonsetList, err := cachedList[v1.DaemonSet](contextId, v1.SchemeGroupVersion.WithResource("daemonsets"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllDaemonsets ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	for _, daemonset := range daemonsetList.Items {
+	for _, daemonset := range daemonsetList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, daemonset.ObjectMeta.Namespace) {
 			result = append(result, daemonset)
 		}
@@ -55,11 +46,7 @@ func AllK8sDaemonsets(namespaceName string, contextId *string) utils.K8sWorkload
 }
 
 func GetK8sDaemonset(namespaceName string, name string, contextId *string) (*v1.DaemonSet, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.AppsV1().DaemonSets(namespaceName).Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[v1.DaemonSet](contextId, v1.SchemeGroupVersion.WithResource("daemonsets"), namespaceName, name)
 }
 
 func UpdateK8sDaemonSet(data v1.DaemonSet, contextId *string) utils.K8sWorkloadResult {
@@ -98,15 +85,7 @@ func DeleteK8sDaemonSetBy(namespace string, name string, contextId *string) erro
 }
 
 func DescribeK8sDaemonSet(namespace string, name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "daemonset", name, "-n", namespace, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_DAEMON_SET, namespace, name, contextId)
 }
 
 func CreateK8sDaemonSet(data v1.DaemonSet, contextId *string) utils.K8sWorkloadResult {
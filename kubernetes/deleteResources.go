@@ -3,8 +3,6 @@ package kubernetes
 import (
 	"context"
 	"fmt"
-	"os/exec"
-	"strings"
 
 	"github.com/mogenius/punq/utils"
 	"github.com/mogenius/punq/version"
@@ -12,7 +10,9 @@ import (
 	"github.com/mogenius/punq/logger"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 )
 
 func Remove(clusterName string) {
@@ -26,6 +26,14 @@ func Remove(clusterName string) {
 	removeDeployment(provider)
 	removeContextsSecret(provider)
 	removeUsersSecret(provider)
+	removeAuditSecret(provider)
+	removeSessionsSecret(provider)
+	removeApiTokensSecret(provider)
+	removeLoginAttemptsSecret(provider)
+	removeWsTicketsSecret(provider)
+	removeKeysSecret(provider)
+	removeGroupsSecret(provider)
+	removeRolesSecret(provider)
 	removeService(provider)
 	removeIngress(provider)
 
@@ -85,13 +93,14 @@ func removeIngress(provider *KubeProvider) {
 	}
 	if ingressControllerType == TRAEFIK {
 		fmt.Printf("Deleting TRAEFIK middleware ...\n")
-		cmd := exec.Command("kubectl", "delete", "middleware", "mw-backend", "-n", utils.CONFIG.Kubernetes.OwnNamespace)
-
-		output, err := cmd.CombinedOutput()
+		dynamicClient, err := dynamic.NewForConfig(&provider.ClientConfig)
 		if err != nil {
-			if !strings.HasPrefix(string(output), "Error from server (NotFound): ") {
-				utils.FatalError(fmt.Sprintf("failed to execute command (%s): %s\n%s", cmd.String(), err.Error(), string(output)))
-			}
+			utils.FatalError(err.Error())
+		}
+		err = dynamicClient.Resource(TRAEFIK_MIDDLEWARE_RESOURCE).Namespace(utils.CONFIG.Kubernetes.OwnNamespace).Delete(context.TODO(), "mw-backend", metav1.DeleteOptions{})
+		// NoMatch: traefik has been uninstalled together with its crds
+		if err != nil && !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			utils.FatalError(fmt.Sprintf("failed to delete TRAEFIK middleware: %s", err.Error()))
 		}
 		fmt.Printf("Deleted TRAEFIK middleware. ✅\n")
 	}
@@ -154,3 +163,123 @@ func removeContextsSecret(provider *KubeProvider) {
 	}
 	fmt.Printf("Deleted %s/%s secret. ✅\n", utils.CONFIG.Kubernetes.OwnNamespace, utils.CONTEXTSSECRET)
 }
+
+func removeAuditSecret(provider *KubeProvider) {
+	secretClient := provider.ClientSet.CoreV1().Secrets(utils.CONFIG.Kubernetes.OwnNamespace)
+
+	fmt.Printf("Deleting %s/%s secret ...\n", utils.CONFIG.Kubernetes.OwnNamespace, utils.AUDITSECRET)
+	deletePolicy := metav1.DeletePropagationForeground
+	err := secretClient.Delete(context.TODO(), utils.AUDITSECRET, metav1.DeleteOptions{PropagationPolicy: &deletePolicy})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Log.Error(err)
+			return
+		}
+	}
+	fmt.Printf("Deleted %s/%s secret. ✅\n", utils.CONFIG.Kubernetes.OwnNamespace, utils.AUDITSECRET)
+}
+
+func removeSessionsSecret(provider *KubeProvider) {
+	secretClient := provider.ClientSet.CoreV1().Secrets(utils.CONFIG.Kubernetes.OwnNamespace)
+
+	fmt.Printf("Deleting %s/%s secret ...\n", utils.CONFIG.Kubernetes.OwnNamespace, utils.SESSIONSSECRET)
+	deletePolicy := metav1.DeletePropagationForeground
+	err := secretClient.Delete(context.TODO(), utils.SESSIONSSECRET, metav1.DeleteOptions{PropagationPolicy: &deletePolicy})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Log.Error(err)
+			return
+		}
+	}
+	fmt.Printf("Deleted %s/%s secret. ✅\n", utils.CONFIG.Kubernetes.OwnNamespace, utils.SESSIONSSECRET)
+}
+
+func removeApiTokensSecret(provider *KubeProvider) {
+	secretClient := provider.ClientSet.CoreV1().Secrets(utils.CONFIG.Kubernetes.OwnNamespace)
+
+	fmt.Printf("Deleting %s/%s secret ...\n", utils.CONFIG.Kubernetes.OwnNamespace, utils.APITOKENSSECRET)
+	deletePolicy := metav1.DeletePropagationForeground
+	err := secretClient.Delete(context.TODO(), utils.APITOKENSSECRET, metav1.DeleteOptions{PropagationPolicy: &deletePolicy})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Log.Error(err)
+			return
+		}
+	}
+	fmt.Printf("Deleted %s/%s secret. ✅\n", utils.CONFIG.Kubernetes.OwnNamespace, utils.APITOKENSSECRET)
+}
+
+func removeLoginAttemptsSecret(provider *KubeProvider) {
+	secretClient := provider.ClientSet.CoreV1().Secrets(utils.CONFIG.Kubernetes.OwnNamespace)
+
+	fmt.Printf("Deleting %s/%s secret ...\n", utils.CONFIG.Kubernetes.OwnNamespace, utils.LOGINATTEMPTSSECRET)
+	deletePolicy := metav1.DeletePropagationForeground
+	err := secretClient.Delete(context.TODO(), utils.LOGINATTEMPTSSECRET, metav1.DeleteOptions{PropagationPolicy: &deletePolicy})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Log.Error(err)
+			return
+		}
+	}
+	fmt.Printf("Deleted %s/%s secret. ✅\n", utils.CONFIG.Kubernetes.OwnNamespace, utils.LOGINATTEMPTSSECRET)
+}
+
+func removeWsTicketsSecret(provider *KubeProvider) {
+	secretClient := provider.ClientSet.CoreV1().Secrets(utils.CONFIG.Kubernetes.OwnNamespace)
+
+	fmt.Printf("Deleting %s/%s secret ...\n", utils.CONFIG.Kubernetes.OwnNamespace, utils.WSTICKETSSECRET)
+	deletePolicy := metav1.DeletePropagationForeground
+	err := secretClient.Delete(context.TODO(), utils.WSTICKETSSECRET, metav1.DeleteOptions{PropagationPolicy: &deletePolicy})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Log.Error(err)
+			return
+		}
+	}
+	fmt.Printf("Deleted %s/%s secret. ✅\n", utils.CONFIG.Kubernetes.OwnNamespace, utils.WSTICKETSSECRET)
+}
+
+func removeKeysSecret(provider *KubeProvider) {
+	secretClient := provider.ClientSet.CoreV1().Secrets(utils.CONFIG.Kubernetes.OwnNamespace)
+
+	fmt.Printf("Deleting %s/%s secret ...\n", utils.CONFIG.Kubernetes.OwnNamespace, utils.KEYSSECRET)
+	deletePolicy := metav1.DeletePropagationForeground
+	err := secretClient.Delete(context.TODO(), utils.KEYSSECRET, metav1.DeleteOptions{PropagationPolicy: &deletePolicy})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Log.Error(err)
+			return
+		}
+	}
+	fmt.Printf("Deleted %s/%s secret. ✅\n", utils.CONFIG.Kubernetes.OwnNamespace, utils.KEYSSECRET)
+}
+
+func removeGroupsSecret(provider *KubeProvider) {
+	secretClient := provider.ClientSet.CoreV1().Secrets(utils.CONFIG.Kubernetes.OwnNamespace)
+
+	fmt.Printf("Deleting %s/%s secret ...\n", utils.CONFIG.Kubernetes.OwnNamespace, utils.GROUPSSECRET)
+	deletePolicy := metav1.DeletePropagationForeground
+	err := secretClient.Delete(context.TODO(), utils.GROUPSSECRET, metav1.DeleteOptions{PropagationPolicy: &deletePolicy})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Log.Error(err)
+			return
+		}
+	}
+	fmt.Printf("Deleted %s/%s secret. ✅\n", utils.CONFIG.Kubernetes.OwnNamespace, utils.GROUPSSECRET)
+}
+
+func removeRolesSecret(provider *KubeProvider) {
+	secretClient := provider.ClientSet.CoreV1().Secrets(utils.CONFIG.Kubernetes.OwnNamespace)
+
+	fmt.Printf("Deleting %s/%s secret ...\n", utils.CONFIG.Kubernetes.OwnNamespace, utils.ROLESSECRET)
+	deletePolicy := metav1.DeletePropagationForeground
+	err := secretClient.Delete(context.TODO(), utils.ROLESSECRET, metav1.DeleteOptions{PropagationPolicy: &deletePolicy})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Log.Error(err)
+			return
+		}
+	}
+	fmt.Printf("Deleted %s/%s secret. ✅\n", utils.CONFIG.Kubernetes.OwnNamespace, utils.ROLESSECRET)
+}
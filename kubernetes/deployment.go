@@ -2,29 +2,27 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
+	"fmt"
+	"time"
 
 	"github.com/mogenius/punq/logger"
 	"github.com/mogenius/punq/utils"
 
 	v1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func AllDeployments(namespaceName string, contextId *string) []v1.Deployment {
 	result := []v1.Deployment{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return result
-	}
-	deploymentList, err := provider.ClientSet.AppsV1().Deployments(namespaceName).List(context.TODO(), metav1.ListOptions{})
+	deploymentList, err := cachedList[v1.Deployment](contextId, v1.SchemeGroupVersion.WithResource("deployments"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllDeployments ERROR: %s", err.Error())
 		return result
 	}
 
-	for _, deployment := range deploymentList.Items {
+	for _, deployment := range deploymentList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, deployment.ObjectMeta.Namespace) {
 			result = append(result, deployment)
 		}
@@ -33,33 +31,25 @@ func AllDeployments(namespaceName string, contextId *string) []v1.Deployment {
 }
 
 func AllDeploymentsIncludeIgnored(namespaceName string, contextId *string) []v1.Deployment {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return []v1.Deployment{}
-	}
-	deploymentList, err := provider.ClientSet.AppsV1().Deployments(namespaceName).List(context.TODO(), metav1.ListOptions{})
+	deploymentList, err := cachedList[v1.Deployment](contextId, v1.SchemeGroupVersion.WithResource("deployments"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllDeployments ERROR: %s", err.Error())
-		return deploymentList.Items
+		return deploymentList
 	}
 
-	return deploymentList.Items
+	return deploymentList
 }
 
 func AllK8sDeployments(namespaceName string, contextId *string) utils.K8sWorkloadResult {
 	result := []v1.Deployment{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	deploymentList, err := provider.ClientSet.AppsV1().Deployments(namespaceName).List(context.TODO(), metav1.ListOptions{})
+	deploymentList, err := cachedList[v1.Deployment](contextId, v1.SchemeGroupVersion.WithResource("deployments"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllDeployments ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	for _, deployment := range deploymentList.Items {
+	for _, deployment := range deploymentList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, deployment.ObjectMeta.Namespace) {
 			result = append(result, deployment)
 		}
@@ -68,11 +58,7 @@ func AllK8sDeployments(namespaceName string, contextId *string) utils.K8sWorkloa
 }
 
 func GetK8sDeployment(namespaceName string, name string, contextId *string) (*v1.Deployment, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.AppsV1().Deployments(namespaceName).Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[v1.Deployment](contextId, v1.SchemeGroupVersion.WithResource("deployments"), namespaceName, name)
 }
 
 func UpdateK8sDeployment(data v1.Deployment, contextId *string) utils.K8sWorkloadResult {
@@ -110,16 +96,23 @@ func DeleteK8sDeploymentBy(namespace string, name string, contextId *string) err
 	return client.Delete(context.TODO(), name, metav1.DeleteOptions{})
 }
 
-func DescribeK8sDeployment(namespace string, name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "deployment", name, "-n", namespace, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
+// RestartK8sDeployment triggers a rollout like "kubectl rollout restart" by changing an annotation of the pod template
+func RestartK8sDeployment(namespace string, name string, contextId *string) utils.K8sWorkloadResult {
+	provider, err := NewKubeProvider(contextId)
 	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
+		return WorkloadResult(nil, err)
 	}
-	return WorkloadResult(string(output), nil)
+	patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":"%s"}}}}}`, time.Now().Format(time.RFC3339))
+	client := provider.ClientSet.AppsV1().Deployments(namespace)
+	res, err := client.Patch(context.TODO(), name, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+	if err != nil {
+		return WorkloadResult(nil, err)
+	}
+	return WorkloadResult(res, nil)
+}
+
+func DescribeK8sDeployment(namespace string, name string, contextId *string) utils.K8sWorkloadResult {
+	return DescribeK8s(RES_DEPLOYMENT, namespace, name, contextId)
 }
 
 func CreateK8sDeployment(data v1.Deployment, contextId *string) utils.K8sWorkloadResult {
@@ -0,0 +1,318 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/mogenius/punq/logger"
+	"github.com/mogenius/punq/utils"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/kubectl/pkg/describe"
+)
+
+// maximum depth when following ownerReferences (Pod -> ReplicaSet -> Deployment -> ...)
+const maxOwnerChainDepth = 10
+
+var DESCRIBE_GROUP_KINDS = map[string]schema.GroupKind{
+	RES_NAMESPACE:                  {Group: "", Kind: "Namespace"},
+	RES_POD:                        {Group: "", Kind: "Pod"},
+	RES_DEPLOYMENT:                 {Group: "apps", Kind: "Deployment"},
+	RES_SERVICE:                    {Group: "", Kind: "Service"},
+	RES_INGRESS:                    {Group: "networking.k8s.io", Kind: "Ingress"},
+	RES_CONFIG_MAP:                 {Group: "", Kind: "ConfigMap"},
+	RES_SECRET:                     {Group: "", Kind: "Secret"},
+	RES_NODE:                       {Group: "", Kind: "Node"},
+	RES_DAEMON_SET:                 {Group: "apps", Kind: "DaemonSet"},
+	RES_STATEFUL_SET:               {Group: "apps", Kind: "StatefulSet"},
+	RES_JOB:                        {Group: "batch", Kind: "Job"},
+	RES_CRON_JOB:                   {Group: "batch", Kind: "CronJob"},
+	RES_REPLICA_SET:                {Group: "apps", Kind: "ReplicaSet"},
+	RES_PERSISTENT_VOLUME:          {Group: "", Kind: "PersistentVolume"},
+	RES_PERSISTENT_VOLUME_CLAIM:    {Group: "", Kind: "PersistentVolumeClaim"},
+	RES_HORIZONTAL_POD_AUTOSCALER:  {Group: "autoscaling", Kind: "HorizontalPodAutoscaler"},
+	RES_EVENT:                      {Group: "", Kind: "Event"},
+	RES_CERTIFICATE:                {Group: "cert-manager.io", Kind: "Certificate"},
+	RES_CERTIFICATE_REQUEST:        {Group: "cert-manager.io", Kind: "CertificateRequest"},
+	RES_ORDER:                      {Group: "acme.cert-manager.io", Kind: "Order"},
+	RES_ISSUER:                     {Group: "cert-manager.io", Kind: "Issuer"},
+	RES_CLUSTER_ISSUER:             {Group: "cert-manager.io", Kind: "ClusterIssuer"},
+	RES_SERVICE_ACCOUNT:            {Group: "", Kind: "ServiceAccount"},
+	RES_ROLE:                       {Group: "rbac.authorization.k8s.io", Kind: "Role"},
+	RES_ROLE_BINDING:               {Group: "rbac.authorization.k8s.io", Kind: "RoleBinding"},
+	RES_CLUSTER_ROLE:               {Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"},
+	RES_CLUSTER_ROLE_BINDING:       {Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"},
+	RES_VOLUME_ATTACHMENT:          {Group: "storage.k8s.io", Kind: "VolumeAttachment"},
+	RES_NETWORK_POLICY:             {Group: "networking.k8s.io", Kind: "NetworkPolicy"},
+	RES_STORAGE_CLASS:              {Group: "storage.k8s.io", Kind: "StorageClass"},
+	RES_CUSTOM_RESOURCE_DEFINITION: {Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"},
+	RES_ENDPOINT:                   {Group: "", Kind: "Endpoints"},
+	RES_LEASE:                      {Group: "coordination.k8s.io", Kind: "Lease"},
+	RES_PRIORITY_CLASS:             {Group: "scheduling.k8s.io", Kind: "PriorityClass"},
+	RES_VOLUME_SNAPSHOT:            {Group: "snapshot.storage.k8s.io", Kind: "VolumeSnapshot"},
+	RES_RESOURCE_QUOTA:             {Group: "", Kind: "ResourceQuota"},
+	RES_INGRESS_CLASS:              {Group: "networking.k8s.io", Kind: "IngressClass"},
+}
+
+type K8sOwnerReference struct {
+	ApiVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	Found      bool   `json:"found"`
+}
+
+type K8sDescribeResult struct {
+	Object     map[string]interface{} `json:"object"`
+	Events     []v1.Event             `json:"events"`
+	OwnerChain []K8sOwnerReference    `json:"ownerChain"`
+	Pods       []v1.Pod               `json:"pods"`
+}
+
+func restMapperFor(provider *KubeProvider) (meta.RESTMapper, error) {
+	groupResources, err := restmapper.GetAPIGroupResources(provider.ClientSet.Discovery())
+	if err != nil {
+		return nil, err
+	}
+	return restmapper.NewDiscoveryRESTMapper(groupResources), nil
+}
+
+// DescribeK8s renders the same output as "kubectl describe" in-process using the rest.Config of the context.
+// Resources without a built-in describer (e.g. cert-manager or snapshot CRDs) are described generically.
+func DescribeK8s(resource string, namespace string, name string, contextId *string) utils.K8sWorkloadResult {
+	groupKind, ok := DESCRIBE_GROUP_KINDS[resource]
+	if !ok {
+		return WorkloadResult(nil, fmt.Errorf("describe is not supported for '%s'", resource))
+	}
+
+	provider, err := NewKubeProvider(contextId)
+	if err != nil {
+		return WorkloadResult(nil, err)
+	}
+
+	describer, ok := describe.DescriberFor(groupKind, &provider.ClientConfig)
+	if !ok {
+		mapper, err := CachedRESTMapper(contextId)
+		if err != nil {
+			return WorkloadResult(nil, err)
+		}
+		mapping, err := mapper.RESTMapping(groupKind)
+		if err != nil {
+			return WorkloadResult(nil, err)
+		}
+		describer, ok = describe.GenericDescriberFor(mapping, &provider.ClientConfig)
+		if !ok {
+			return WorkloadResult(nil, fmt.Errorf("no describer found for '%s'", groupKind.String()))
+		}
+	}
+
+	output, err := describer.Describe(namespace, name, describe.DescriberSettings{ShowEvents: true, ChunkSize: 500})
+	if err != nil {
+		logger.Log.Errorf("Describe %s %s/%s ERROR: %s", resource, namespace, name, err.Error())
+		return WorkloadResult(nil, err)
+	}
+	return WorkloadResult(output, nil)
+}
+
+// redactSecret replaces the values of a secret with their size (like "kubectl describe"). The last applied
+// configuration is removed as well, it contains the values of secrets created with "kubectl apply".
+func redactSecret(obj *unstructured.Unstructured) {
+	for _, field := range []string{"data", "stringData"} {
+		values, found, err := unstructured.NestedMap(obj.Object, field)
+		if err != nil || !found {
+			continue
+		}
+		for key, value := range values {
+			text, _ := value.(string)
+			size := len(text)
+			if field == "data" {
+				if decoded, err := base64.StdEncoding.DecodeString(text); err == nil {
+					size = len(decoded)
+				}
+			}
+			values[key] = fmt.Sprintf("<redacted, %d bytes>", size)
+		}
+		_ = unstructured.SetNestedMap(obj.Object, values, field)
+	}
+
+	annotations := obj.GetAnnotations()
+	if _, ok := annotations["kubectl.kubernetes.io/last-applied-configuration"]; ok {
+		delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+		obj.SetAnnotations(annotations)
+	}
+}
+
+// DescribeK8sStructured returns the object itself, its related events, the chain of owners and the pods it selects.
+func DescribeK8sStructured(resource string, namespace string, name string, contextId *string) utils.K8sWorkloadResult {
+	groupKind, ok := DESCRIBE_GROUP_KINDS[resource]
+	if !ok {
+		return WorkloadResult(nil, fmt.Errorf("describe is not supported for '%s'", resource))
+	}
+
+	provider, err := NewKubeProvider(contextId)
+	if err != nil {
+		return WorkloadResult(nil, err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(&provider.ClientConfig)
+	if err != nil {
+		return WorkloadResult(nil, err)
+	}
+	mapper, err := CachedRESTMapper(contextId)
+	if err != nil {
+		return WorkloadResult(nil, err)
+	}
+
+	obj, err := getUnstructured(dynamicClient, mapper, groupKind, namespace, name)
+	if err != nil {
+		return WorkloadResult(nil, err)
+	}
+	obj.SetManagedFields(nil)
+	if obj.GroupVersionKind().GroupKind() == DESCRIBE_GROUP_KINDS[RES_SECRET] {
+		redactSecret(obj)
+	}
+
+	result := K8sDescribeResult{
+		Object:     obj.Object,
+		Events:     []v1.Event{},
+		OwnerChain: ownerChain(dynamicClient, mapper, obj),
+		Pods:       []v1.Pod{},
+	}
+
+	// events
+	selector := fields.Set{
+		"involvedObject.name": obj.GetName(),
+		"involvedObject.kind": obj.GetKind(),
+		"involvedObject.uid":  string(obj.GetUID()),
+	}.AsSelector().String()
+	eventList, err := provider.ClientSet.CoreV1().Events(obj.GetNamespace()).List(context.TODO(), metav1.ListOptions{FieldSelector: selector})
+	if err != nil {
+		logger.Log.Errorf("DescribeK8sStructured events ERROR: %s", err.Error())
+	} else {
+		result.Events = eventList.Items
+	}
+
+	// pods
+	if podSelector := podSelectorFor(obj); podSelector != nil {
+		podList, err := provider.ClientSet.CoreV1().Pods(obj.GetNamespace()).List(context.TODO(), metav1.ListOptions{LabelSelector: podSelector.String()})
+		if err != nil {
+			logger.Log.Errorf("DescribeK8sStructured pods ERROR: %s", err.Error())
+		} else {
+			result.Pods = podList.Items
+		}
+	}
+
+	return WorkloadResult(result, nil)
+}
+
+// ResourceFor returns the RES_* constant matching the kind or lowercase route name (e.g. "daemonset") or the name itself
+func ResourceFor(name string) string {
+	for resource := range DESCRIBE_GROUP_KINDS {
+		if strings.EqualFold(resource, name) {
+			return resource
+		}
+	}
+	return name
+}
+
+// GetK8sUnstructured returns the live object of any kind (e.g. to compare it with a requested change)
+func GetK8sUnstructured(groupKind schema.GroupKind, namespace string, name string, contextId *string) (*unstructured.Unstructured, error) {
+	provider, err := NewKubeProvider(contextId)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(&provider.ClientConfig)
+	if err != nil {
+		return nil, err
+	}
+	mapper, err := CachedRESTMapper(contextId)
+	if err != nil {
+		return nil, err
+	}
+	return getUnstructured(dynamicClient, mapper, groupKind, namespace, name)
+}
+
+func getUnstructured(dynamicClient dynamic.Interface, mapper meta.RESTMapper, groupKind schema.GroupKind, namespace string, name string) (*unstructured.Unstructured, error) {
+	mapping, err := mapper.RESTMapping(groupKind)
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameRoot {
+		return dynamicClient.Resource(mapping.Resource).Get(context.TODO(), name, metav1.GetOptions{})
+	}
+	return dynamicClient.Resource(mapping.Resource).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+func ownerChain(dynamicClient dynamic.Interface, mapper meta.RESTMapper, obj *unstructured.Unstructured) []K8sOwnerReference {
+	result := []K8sOwnerReference{}
+	current := obj
+	for i := 0; i < maxOwnerChainDepth && current != nil; i++ {
+		owner := metav1.GetControllerOf(current)
+		if owner == nil {
+			ownerRefs := current.GetOwnerReferences()
+			if len(ownerRefs) == 0 {
+				break
+			}
+			owner = &ownerRefs[0]
+		}
+
+		ref := K8sOwnerReference{
+			ApiVersion: owner.APIVersion,
+			Kind:       owner.Kind,
+			Namespace:  current.GetNamespace(),
+			Name:       owner.Name,
+		}
+
+		gv, err := schema.ParseGroupVersion(owner.APIVersion)
+		if err != nil {
+			result = append(result, ref)
+			break
+		}
+		next, err := getUnstructured(dynamicClient, mapper, schema.GroupKind{Group: gv.Group, Kind: owner.Kind}, current.GetNamespace(), owner.Name)
+		if err != nil {
+			result = append(result, ref)
+			break
+		}
+		ref.Namespace = next.GetNamespace()
+		ref.Found = true
+		result = append(result, ref)
+		current = next
+	}
+	return result
+}
+
+// podSelectorFor returns the label selector of workloads (spec.selector) and services (spec.selector as map)
+func podSelectorFor(obj *unstructured.Unstructured) labels.Selector {
+	if obj.GetKind() == "Service" {
+		selectorMap, found, err := unstructured.NestedStringMap(obj.Object, "spec", "selector")
+		if err != nil || !found || len(selectorMap) == 0 {
+			return nil
+		}
+		return labels.SelectorFromSet(selectorMap)
+	}
+
+	rawSelector, found, err := unstructured.NestedMap(obj.Object, "spec", "selector")
+	if err != nil || !found {
+		return nil
+	}
+	labelSelector := metav1.LabelSelector{}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(rawSelector, &labelSelector)
+	if err != nil {
+		return nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&labelSelector)
+	if err != nil || selector.Empty() {
+		return nil
+	}
+	return selector
+}
@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/logger"
 	"github.com/mogenius/punq/utils"
@@ -14,17 +13,13 @@ import (
 func AllEndpoints(namespaceName string, contextId *string) utils.K8sWorkloadResult {
 	result := []corev1.Endpoints{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	hpaList, err := provider.ClientSet.CoreV1().Endpoints(namespaceName).List(context.TODO(), metav1.ListOptions{FieldSelector: "metadata.namespace!=kube-system"})
+	hpaList, err := cachedList[corev1.Endpoints](contextId, corev1.SchemeGroupVersion.WithResource("endpoints"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllHpas ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	for _, hpa := range hpaList.Items {
+	for _, hpa := range hpaList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, hpa.ObjectMeta.Namespace) {
 			result = append(result, hpa)
 		}
@@ -33,11 +28,7 @@ func AllEndpoints(namespaceName string, contextId *string) utils.K8sWorkloadResu
 }
 
 func GetEndpoint(namespaceName string, name string, contextId *string) (*corev1.Endpoints, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.CoreV1().Endpoints(namespaceName).Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[corev1.Endpoints](contextId, corev1.SchemeGroupVersion.WithResource("endpoints"), namespaceName, name)
 }
 
 func UpdateK8sEndpoint(data corev1.Endpoints, contextId *string) utils.K8sWorkloadResult {
@@ -76,15 +67,7 @@ func DeleteK8sEndpointBy(namespace string, name string, contextId *string) error
 }
 
 func DescribeK8sEndpoint(namespace string, name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "endpoint", name, "-n", namespace, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_ENDPOINT, namespace, name, contextId)
 }
 
 func CreateK8sEndpoint(data corev1.Endpoints, contextId *string) utils.K8sWorkloadResult {
@@ -1,30 +1,22 @@
 package kubernetes
 
 import (
-	"context"
-	"os/exec"
-
 	"github.com/mogenius/punq/logger"
 	"github.com/mogenius/punq/utils"
 
 	v1Core "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func AllEvents(namespaceName string, contextId *string) utils.K8sWorkloadResult {
 	result := []v1Core.Event{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	eventList, err := provider.ClientSet.CoreV1().Events(namespaceName).List(context.TODO(), metav1.ListOptions{FieldSelector: "metadata.namespace!=kube-system"})
+	eventList, err := cachedList[v1Core.Event](contextId, v1Core.SchemeGroupVersion.WithResource("events"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllEvents ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	for _, event := range eventList.Items {
+	for _, event := range eventList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, event.ObjectMeta.Namespace) {
 			result = append(result, event)
 		}
@@ -33,21 +25,9 @@ func AllEvents(namespaceName string, contextId *string) utils.K8sWorkloadResult
 }
 
 func GetEvent(namespaceName string, name string, contextId *string) (*v1Core.Event, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.CoreV1().Events(namespaceName).Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[v1Core.Event](contextId, v1Core.SchemeGroupVersion.WithResource("events"), namespaceName, name)
 }
 
 func DescribeK8sEvent(namespace string, name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "event", name, "-n", namespace, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_EVENT, namespace, name, contextId)
 }
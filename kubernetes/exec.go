@@ -1,131 +1,184 @@
 package kubernetes
 
 import (
+	"context"
 	"fmt"
 	"io"
-	"log"
-	"os/exec"
+	"strings"
+	"time"
 
-	"github.com/gorilla/websocket"
 	"github.com/mogenius/punq/logger"
 	"github.com/mogenius/punq/utils"
-	// corev1 "k8s.io/api/core/v1"
-	//"k8s.io/client-go/tools/remotecommand"
-	//"k8s.io/kubectl/pkg/scheme"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/kubectl/pkg/scheme"
+)
+
+const (
+	EXEC_MODE_EXEC   string = "exec"
+	EXEC_MODE_ATTACH string = "attach"
+	EXEC_MODE_DEBUG  string = "debug"
+
+	DEFAULT_DEBUG_IMAGE string = "busybox:latest"
 )
 
-func ExecTest() error {
-	namespace := "mogenius"
-	podName := "mogenius-k8s-manager-6dcf5df696-8bsf4"
-	container := "mogenius-k8s-manager"
-	execCmd := []string{"exec", "--stdin", "--tty", "-n", namespace, "--container", container, podName, "--", "ifconfig"} // /bin/sh
+// pick the best available shell inside the container with a single exec (instead of probing every shell separately).
+// Namespace, pod and container are passed as positional parameters ($1-$3), so they are never parsed by the shell.
+const shellSelector = `export TERM=xterm-color; for s in bash ash zsh ksh csh sh; do if command -v $s >/dev/null 2>&1; then printf '\033[1;34mConnected to %s/%s/%s using %s. Happy hacking!\033[0m 🚀 🚀 🚀\n' "$1" "$2" "$3" "$s"; exec $s; fi; done; exec sh`
 
-	// Create an *exec.Cmd
-	cmd := exec.Command("kubectl", execCmd...)
-	cmdStdout, _ := cmd.StdoutPipe()
-	cmdStdin, _ := cmd.StdinPipe()
+// shellCommand returns the command starting the shell selector. "--" becomes $0 of the script.
+func shellCommand(namespace string, podName string, container string) []string {
+	return []string{"sh", "-c", shellSelector, "--", namespace, podName, container}
+}
 
-	// Assign os.Stdin, os.Stdout, and os.Stderr
-	// cmd.Stdin = os.Stdin
-	// cmd.Stdout = os.Stdout
-	// cmd.Stderr = os.Stderr
+type ExecOptions struct {
+	Namespace  string
+	PodName    string
+	Container  string
+	Mode       string
+	DebugImage string
+	Command    []string
+	Stdin      io.Reader
+	Stdout     io.Writer
+	SizeQueue  remotecommand.TerminalSizeQueue
+}
 
-	go SendData(cmdStdin, cmdStdout)
+// ExecStream connects stdin/stdout to a container of the pod using the kubeconfig of the given context.
+// EXEC_MODE_EXEC starts a shell (or the given command), EXEC_MODE_ATTACH attaches to the main process of the
+// container and EXEC_MODE_DEBUG starts an ephemeral debug container targeting the selected container.
+func ExecStream(ctx context.Context, opts ExecOptions, contextId *string) error {
+	provider, err := NewKubeProvider(contextId)
+	if err != nil {
+		return err
+	}
 
-	// Run the command
-	err := cmd.Run()
+	subResource := "exec"
+
+	req := provider.ClientSet.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(opts.Namespace).
+		Name(opts.PodName)
+
+	switch opts.Mode {
+	case EXEC_MODE_ATTACH:
+		subResource = "attach"
+		req = req.SubResource(subResource).VersionedParams(&v1.PodAttachOptions{
+			Container: opts.Container,
+			Stdin:     true,
+			Stdout:    true,
+			Stderr:    false,
+			TTY:       true,
+		}, scheme.ParameterCodec)
+	case EXEC_MODE_DEBUG:
+		debugContainer, err := CreateDebugContainer(ctx, opts.Namespace, opts.PodName, opts.Container, opts.DebugImage, contextId)
+		if err != nil {
+			return err
+		}
+		subResource = "attach"
+		req = req.SubResource(subResource).VersionedParams(&v1.PodAttachOptions{
+			Container: debugContainer,
+			Stdin:     true,
+			Stdout:    true,
+			Stderr:    false,
+			TTY:       true,
+		}, scheme.ParameterCodec)
+	default:
+		cmd := opts.Command
+		if len(cmd) == 0 {
+			cmd = shellCommand(opts.Namespace, opts.PodName, opts.Container)
+		}
+		req = req.SubResource(subResource).VersionedParams(&v1.PodExecOptions{
+			Command:   cmd,
+			Container: opts.Container,
+			Stdin:     true,  // DO NOT CHANGE: ONLY WORKING CONFIGURATION
+			Stdout:    true,  // DO NOT CHANGE: ONLY WORKING CONFIGURATION
+			Stderr:    false, // DO NOT CHANGE: ONLY WORKING CONFIGURATION
+			TTY:       true,  // DO NOT CHANGE: ONLY WORKING CONFIGURATION
+		}, scheme.ParameterCodec)
+	}
 
+	executor, err := remotecommand.NewSPDYExecutor(&provider.ClientConfig, "POST", req.URL())
 	if err != nil {
-		logger.Log.Errorf("ExecTest ERR: %s", err.Error())
+		logger.Log.Errorf("ExecStream ERR: %s", err.Error())
+		return err
 	}
 
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:             opts.Stdin,
+		Stdout:            opts.Stdout,
+		Stderr:            nil,
+		Tty:               true,
+		TerminalSizeQueue: opts.SizeQueue,
+	})
+	if err != nil {
+		logger.Log.Errorf("ExecStream (%s) ERR: %s", subResource, err.Error())
+	}
 	return err
 }
 
-// This method is the equivalent of:
-// kubectl exec -n benegeilomat-dev-8umm0v --container nginx nginx-6b64bff7c9-p6vpp -- ls
-// func ExecTest() error {
-// 	provider,err := NewKubeProvider()
-
-// 	namespace := "benegeilomat-dev-8umm0v"
-// 	podName := "nginx-6b64bff7c9-p6vpp"
-// 	container := "nginx"
-// 	cmd := []string{"/bin/bash"} // ls
-
-// 	req := provider.ClientSet.CoreV1().RESTClient().Post().
-// 		Resource("pods").
-// 		Namespace(namespace).
-// 		Name(podName).
-// 		SubResource("exec").
-// 		VersionedParams(&corev1.PodExecOptions{
-// 			Command:   cmd,
-// 			Container: container,
-// 			Stdin:     true,  // DO NOT CHANGE: ONLY WORKING CONFIGURATION
-// 			Stdout:    true,  // DO NOT CHANGE: ONLY WORKING CONFIGURATION
-// 			Stderr:    false, // DO NOT CHANGE: ONLY WORKING CONFIGURATION
-// 			TTY:       true,  // DO NOT CHANGE: ONLY WORKING CONFIGURATION
-// 		}, scheme.ParameterCodec)
-
-// 	exec, err := remotecommand.NewSPDYExecutor(&provider.ClientConfig, "POST", req.URL())
-// 	if err != nil {
-// 		logger.Log.Errorf("ExecTest ERR: %s", err.Error())
-// 	}
-
-// 	err = exec.StreamWithContext(context.TODO(), remotecommand.StreamOptions{
-// 		Stdin:  os.Stdin,  // DO NOT CHANGE: ONLY WORKING CONFIGURATION
-// 		Stdout: os.Stdout, // DO NOT CHANGE: ONLY WORKING CONFIGURATION
-// 		Stderr: nil,       // DO NOT CHANGE: ONLY WORKING CONFIGURATION
-// 		Tty:    true,      // DO NOT CHANGE: ONLY WORKING CONFIGURATION
-// 	})
-
-// 	if err != nil {
-// 		logger.Log.Errorf("ExecTest ERR: %s", err.Error())
-// 	}
-// 	return err
-// }
-
-func SendData(cmdStdin io.WriteCloser, cmdStdout io.ReadCloser) {
-	// Create a dialer
-	dialer := websocket.DefaultDialer
-
-	// Connect to the server
-	conn, _, err := dialer.Dial("ws://127.0.0.1:8080/ws-shell", utils.HttpHeader(""))
+// CreateDebugContainer adds an ephemeral container to the pod and waits until it is running.
+// The name of the new container is returned so it can be attached to.
+func CreateDebugContainer(ctx context.Context, namespace string, podName string, targetContainer string, image string, contextId *string) (string, error) {
+	provider, err := NewKubeProvider(contextId)
 	if err != nil {
-		fmt.Println("Error connecting to WebSocket:", err)
-		return
+		return "", err
 	}
-	defer conn.Close()
+	podClient := provider.ClientSet.CoreV1().Pods(namespace)
 
-	go func() {
-		// Forward stdout to the WebSocket
-		buf := make([]byte, 1024)
-		for {
-			n, err := cmdStdout.Read(buf)
-			if err != nil {
-				log.Println(err)
-				return
-			}
+	pod, err := podClient.Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
 
-			err = conn.WriteMessage(websocket.TextMessage, buf[:n])
-			if err != nil {
-				log.Println(err)
-				return
-			}
-		}
-	}()
+	if image == "" {
+		image = DEFAULT_DEBUG_IMAGE
+	}
 
-	for {
-		// Forward the WebSocket to stdin
-		_, message, err := conn.ReadMessage()
-		if err != nil {
-			log.Println(err)
-			break
-		}
+	debugName := fmt.Sprintf("punq-debug-%s", strings.ToLower(utils.NanoId()[:5]))
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, v1.EphemeralContainer{
+		EphemeralContainerCommon: v1.EphemeralContainerCommon{
+			Name:                     debugName,
+			Image:                    image,
+			Command:                  []string{"sh"},
+			Stdin:                    true,
+			TTY:                      true,
+			TerminationMessagePolicy: v1.TerminationMessageReadFile,
+			ImagePullPolicy:          v1.PullIfNotPresent,
+		},
+		TargetContainerName: targetContainer,
+	})
+
+	_, err = podClient.UpdateEphemeralContainers(ctx, podName, pod, MoUpdateOptions())
+	if err != nil {
+		return "", err
+	}
 
-		_, err = cmdStdin.Write(message)
-		if err != nil {
-			log.Println(err)
-			break
+	// wait for the debug container to come up
+	timeout := time.After(60 * time.Second)
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-timeout:
+			return "", fmt.Errorf("debug container '%s' did not start within 60 seconds", debugName)
+		case <-time.After(1 * time.Second):
+			pod, err := podClient.Get(ctx, podName, metav1.GetOptions{})
+			if err != nil {
+				return "", err
+			}
+			for _, status := range pod.Status.EphemeralContainerStatuses {
+				if status.Name != debugName {
+					continue
+				}
+				if status.State.Running != nil {
+					return debugName, nil
+				}
+				if status.State.Terminated != nil {
+					return "", fmt.Errorf("debug container '%s' terminated: %s", debugName, status.State.Terminated.Reason)
+				}
+			}
 		}
 	}
 }
@@ -0,0 +1,49 @@
+package kubernetes
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// names are passed as positional parameters, so they are printed but never executed
+func TestShellCommand(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	marker := filepath.Join(t.TempDir(), "injected")
+
+	tests := []struct {
+		name      string
+		namespace string
+		podName   string
+		container string
+	}{
+		{"plain", "team-a", "web", "app"},
+		{"command substitution", "$(touch " + marker + ")", "web", "app"},
+		{"quotes", "team-a", "web'; touch " + marker + "; '", "app"},
+		{"separator", "team-a", "web", "app; touch " + marker},
+		{"format verbs and escapes", "%s%n", "\\c", "app"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command := shellCommand(tt.namespace, tt.podName, tt.container)
+			cmd := exec.Command(command[0], command[1:]...)
+			// the selected shell exits immediately without input
+			cmd.Stdin = strings.NewReader("")
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("shellCommand() failed: %s\n%s", err.Error(), output)
+			}
+			if _, err := os.Stat(marker); err == nil {
+				t.Fatalf("shellCommand() executed a part of the names")
+			}
+			want := "Connected to " + tt.namespace + "/" + tt.podName + "/" + tt.container + " using "
+			if !strings.Contains(string(output), want) {
+				t.Errorf("shellCommand() output = %q, want %q", output, want)
+			}
+		})
+	}
+}
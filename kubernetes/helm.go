@@ -1,81 +1,590 @@
 package kubernetes
 
-// func InstallMogeniusNfsStorage(job *structs.Job, clusterProvider string, wg *sync.WaitGroup) []*structs.Command {
-// 	cmds := []*structs.Command{}
-
-// 	addRepoCmd := structs.CreateBashCommand("Install/Update helm repo.", job, "sleep 1", wg)
-// 	cmds = append(cmds, addRepoCmd)
-
-// 	nfsStorageClassStr := ""
-
-// 	// "BRING_YOUR_OWN", "EKS", "AKS", "GKE", "DOCKER_ENTERPRISE", "DOKS", "LINODE", "IBM", "ACK", "OKE", "OTC", "OPEN_SHIFT"
-// 	switch clusterProvider {
-// 	case "EKS":
-// 		nfsStorageClassStr = " --set-string nfsStorageClass.backendStorageClass=gp2"
-// 	case "GKE":
-// 		nfsStorageClassStr = " --set-string nfsStorageClass.backendStorageClass=standard-rwo"
-// 	case "AKS":
-// 		nfsStorageClassStr = " --set-string nfsStorageClass.backendStorageClass=default"
-// 	case "OTC":
-// 		nfsStorageClassStr = " --set-string nfsStorageClass.backendStorageClass=csi-disk"
-// 	default:
-// 		// nothing to do
-// 		errMsg := fmt.Sprintf("CLUSTERPROVIDER '%s' HAS NOT BEEN TESTED YET!", clusterProvider)
-// 		logger.Log.Errorf(errMsg)
-// 		addRepoCmd.Fail(errMsg)
-// 		return cmds
-// 	}
-// 	instRelCmd := structs.CreateBashCommand("Install helm release.", job, fmt.Sprintf("helm repo add mo-openebs-nfs https://openebs.github.io/dynamic-nfs-provisioner; helm repo update; helm install mogenius-nfs-storage mo-openebs-nfs/nfs-provisioner -n %s --set analytics.enabled=false%s", utils.CONFIG.Kubernetes.OwnNamespace, nfsStorageClassStr), wg)
-// 	cmds = append(cmds, instRelCmd)
-
-// 	return cmds
-// }
-
-// func UninstallMogeniusNfsStorage(job *structs.Job, wg *sync.WaitGroup) []*structs.Command {
-// 	cmds := []*structs.Command{}
-
-// 	uninstRelCmd := structs.CreateBashCommand("Uninstall helm release.", job, fmt.Sprintf("helm uninstall mogenius-nfs-storage -n %s", utils.CONFIG.Kubernetes.OwnNamespace), wg)
-// 	cmds = append(cmds, uninstRelCmd)
-// 	// storageClassCmd := DeleteMogeniusNfsStorageClass(job, c, wg)
-// 	// cmds = append(cmds, storageClassCmd)
-
-// 	return cmds
-// }
-
-// func AllHelmCharts(namespaceName string) []cmapi.CertificateRequest {
-// 	result := []cmapi.CertificateRequest{}
-
-// 	provider,err := NewKubeProvider()
-// 	resources, err := provider.ClientSet.Discovery().ServerPreferredResources()
-// 	if err != nil {
-// 		logger.Log.Errorf("ServerPreferredResources Error: %s", err.Error())
-// 		return result
-// 	}
-
-// 	for _, certificate := range certificatesList.Items {
-// 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, certificate.ObjectMeta.Namespace) {
-// 			result = append(result, certificate)
-// 		}
-// 	}
-// 	return result
-// }
-
-// func UpdateHelmChart(data cmapi.CertificateRequest) K8sWorkloadResult {
-// 	providererr := NewKubeProviderCertManager()
-// 	certificateClient := provider.ClientSet.CertmanagerV1().CertificateRequests(data.Namespace)
-// 	_, err := certificateClient.Update(context.TODO(), &data, metav1.UpdateOptions{})
-// 	if err != nil {
-// 		return WorkloadResult(nil, err)
-// 	}
-// 	return WorkloadResult(nil, nil)
-// }
-
-// func DeleteK8sHelmChart(data cmapi.CertificateRequest) K8sWorkloadResult {
-// 	providererr := NewKubeProviderCertManager()
-// 	certificateClient := provider.ClientSet.CertmanagerV1().CertificateRequests(data.Namespace)
-// 	err := certificateClient.Delete(context.TODO(), data.Name, metav1.DeleteOptions{})
-// 	if err != nil {
-// 		return WorkloadResult(nil, err)
-// 	}
-// 	return WorkloadResult(nil, nil)
-// }
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/mogenius/punq/logger"
+	"github.com/mogenius/punq/structs"
+	"github.com/mogenius/punq/utils"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	HELM_RELEASE_SECRET_TYPE v1.SecretType = "helm.sh/release.v1"
+	HELM_STATUS_DEPLOYED     string        = "deployed"
+	HELM_STATUS_SUPERSEDED   string        = "superseded"
+	HELM_FIELD_MANAGER       string        = "punq"
+)
+
+var helmGzipMagic = []byte{0x1f, 0x8b, 0x08}
+var helmManifestSeparator = regexp.MustCompile(`(?m)^---\s*$`)
+
+// a release secret together with its decoded content. raw is kept to write releases back without losing fields.
+type helmReleaseRecord struct {
+	secret  v1.Secret
+	raw     map[string]interface{}
+	release structs.HelmRelease
+}
+
+// helmReleaseRecords returns all revisions of the release (or of all releases if name is empty) sorted by namespace, name and revision
+func helmReleaseRecords(namespace string, name string, contextId *string) ([]helmReleaseRecord, error) {
+	result := []helmReleaseRecord{}
+
+	provider, err := NewKubeProvider(contextId)
+	if err != nil {
+		return result, err
+	}
+
+	selector := "owner=helm"
+	if name != "" {
+		selector = fmt.Sprintf("%s,name=%s", selector, name)
+	}
+	secretList, err := provider.ClientSet.CoreV1().Secrets(namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: selector,
+		FieldSelector: fields.OneTermEqualSelector("type", string(HELM_RELEASE_SECRET_TYPE)).String(),
+	})
+	if err != nil {
+		logger.Log.Errorf("helmReleaseRecords ERROR: %s", err.Error())
+		return result, err
+	}
+
+	for _, secret := range secretList.Items {
+		raw, release, err := decodeHelmRelease(secret.Data["release"])
+		if err != nil {
+			logger.Log.Errorf("Decoding helm release %s/%s failed: %s", secret.Namespace, secret.Name, err.Error())
+			continue
+		}
+		result = append(result, helmReleaseRecord{secret: secret, raw: raw, release: *release})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].release, result[j].release
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Version < b.Version
+	})
+	return result, nil
+}
+
+func helmReleaseRecordsFor(namespace string, name string, contextId *string) ([]helmReleaseRecord, error) {
+	records, err := helmReleaseRecords(namespace, name, contextId)
+	if err != nil {
+		return records, err
+	}
+	if len(records) == 0 {
+		return records, fmt.Errorf("helm release '%s/%s' not found", namespace, name)
+	}
+	return records, nil
+}
+
+// helm stores the release as base64(gzip(json)) inside of the (already base64 encoded) secret data
+func decodeHelmRelease(data []byte) (map[string]interface{}, *structs.HelmRelease, error) {
+	decoded, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	if bytes.HasPrefix(decoded, helmGzipMagic) {
+		reader, err := gzip.NewReader(bytes.NewReader(decoded))
+		if err != nil {
+			return nil, nil, err
+		}
+		defer reader.Close()
+		decoded, err = io.ReadAll(reader)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	raw := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(decoded))
+	decoder.UseNumber()
+	err = decoder.Decode(&raw)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	release := structs.HelmRelease{}
+	err = json.Unmarshal(decoded, &release)
+	if err != nil {
+		return nil, nil, err
+	}
+	return raw, &release, nil
+}
+
+func encodeHelmRelease(raw map[string]interface{}) ([]byte, error) {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	writer, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	_, err = writer.Write(data)
+	if err != nil {
+		return nil, err
+	}
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(base64.StdEncoding.EncodeToString(buf.Bytes())), nil
+}
+
+// AllHelmReleases lists the latest revision of every release
+func AllHelmReleases(namespace string, contextId *string) ([]structs.HelmReleaseSummary, error) {
+	result := []structs.HelmReleaseSummary{}
+
+	records, err := helmReleaseRecords(namespace, "", contextId)
+	if err != nil {
+		return result, err
+	}
+
+	for i, record := range records {
+		if utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, record.release.Namespace) {
+			continue
+		}
+		// records are sorted by revision, so the last one of every release is the latest
+		if i+1 < len(records) && records[i+1].release.Namespace == record.release.Namespace && records[i+1].release.Name == record.release.Name {
+			continue
+		}
+		result = append(result, record.release.Summary())
+	}
+	return result, nil
+}
+
+func HelmReleaseHistory(namespace string, name string, contextId *string) ([]structs.HelmReleaseSummary, error) {
+	result := []structs.HelmReleaseSummary{}
+
+	records, err := helmReleaseRecordsFor(namespace, name, contextId)
+	if err != nil {
+		return result, err
+	}
+	for _, record := range records {
+		result = append(result, record.release.Summary())
+	}
+	return result, nil
+}
+
+// GetHelmRelease returns the given revision of the release. A revision <= 0 returns the latest revision.
+func GetHelmRelease(namespace string, name string, revision int, contextId *string) (*structs.HelmRelease, error) {
+	records, err := helmReleaseRecordsFor(namespace, name, contextId)
+	if err != nil {
+		return nil, err
+	}
+	if revision <= 0 {
+		return &records[len(records)-1].release, nil
+	}
+	for _, record := range records {
+		if record.release.Version == revision {
+			return &record.release, nil
+		}
+	}
+	return nil, fmt.Errorf("revision %d of helm release '%s/%s' not found", revision, namespace, name)
+}
+
+// HelmReleaseValues returns the user supplied values of the revision or, if allValues is set, the values merged with the chart defaults
+func HelmReleaseValues(namespace string, name string, revision int, allValues bool, contextId *string) (map[string]interface{}, error) {
+	release, err := GetHelmRelease(namespace, name, revision, contextId)
+	if err != nil {
+		return nil, err
+	}
+	if allValues {
+		return mergeHelmValues(release.Chart.Values, release.Config), nil
+	}
+	if release.Config == nil {
+		return map[string]interface{}{}, nil
+	}
+	return release.Config, nil
+}
+
+func HelmReleaseManifest(namespace string, name string, revision int, contextId *string) (string, error) {
+	release, err := GetHelmRelease(namespace, name, revision, contextId)
+	if err != nil {
+		return "", err
+	}
+	return release.Manifest, nil
+}
+
+// HelmReleaseDiff returns a unified diff of values and manifest between two revisions.
+// toRevision <= 0 means the latest revision, fromRevision <= 0 the revision before toRevision. The first revision
+// (e.g. of a release with a single revision) is compared with an empty release.
+func HelmReleaseDiff(namespace string, name string, fromRevision int, toRevision int, contextId *string) (*structs.HelmReleaseDiff, error) {
+	to, err := GetHelmRelease(namespace, name, toRevision, contextId)
+	if err != nil {
+		return nil, err
+	}
+	if fromRevision <= 0 {
+		fromRevision = to.Version - 1
+	}
+	if fromRevision >= to.Version {
+		return nil, fmt.Errorf("revision 'from' (%d) must be lower than 'to' (%d)", fromRevision, to.Version)
+	}
+
+	// the first revision is compared with an empty release
+	from := &structs.HelmRelease{}
+	if fromRevision > 0 {
+		from, err = GetHelmRelease(namespace, name, fromRevision, contextId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fromValues, err := helmValuesYaml(from.Config)
+	if err != nil {
+		return nil, err
+	}
+	toValues, err := helmValuesYaml(to.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	valuesDiff, err := UnifiedDiff(string(fromValues), string(toValues), fmt.Sprintf("%s.v%d/values.yaml", name, from.Version), fmt.Sprintf("%s.v%d/values.yaml", name, to.Version))
+	if err != nil {
+		return nil, err
+	}
+	manifestDiff, err := UnifiedDiff(from.Manifest, to.Manifest, fmt.Sprintf("%s.v%d/manifest.yaml", name, from.Version), fmt.Sprintf("%s.v%d/manifest.yaml", name, to.Version))
+	if err != nil {
+		return nil, err
+	}
+
+	return &structs.HelmReleaseDiff{
+		Name:         name,
+		Namespace:    namespace,
+		FromRevision: from.Version,
+		ToRevision:   to.Version,
+		Values:       valuesDiff,
+		Manifest:     manifestDiff,
+	}, nil
+}
+
+func helmValuesYaml(values map[string]interface{}) ([]byte, error) {
+	if len(values) == 0 {
+		return []byte{}, nil
+	}
+	return yaml.Marshal(values)
+}
+
+// HelmRollback re-applies the manifest of the given revision, removes objects which are not part of it anymore and
+// records a new revision just like "helm rollback" does. Hooks of the chart are not executed.
+func HelmRollback(namespace string, name string, revision int, contextId *string) (*structs.HelmRelease, error) {
+	records, err := helmReleaseRecordsFor(namespace, name, contextId)
+	if err != nil {
+		return nil, err
+	}
+
+	var target *helmReleaseRecord
+	for i := range records {
+		if records[i].release.Version == revision {
+			target = &records[i]
+		}
+	}
+	if target == nil {
+		return nil, fmt.Errorf("revision %d of helm release '%s/%s' not found", revision, namespace, name)
+	}
+	latest := records[len(records)-1]
+	current := latest
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].release.Info.Status == HELM_STATUS_DEPLOYED {
+			current = records[i]
+			break
+		}
+	}
+
+	provider, err := NewKubeProvider(contextId)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(&provider.ClientConfig)
+	if err != nil {
+		return nil, err
+	}
+	mapper, err := CachedRESTMapper(contextId)
+	if err != nil {
+		return nil, err
+	}
+
+	targetObjects, err := helmManifestObjects(target.release.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	currentObjects, err := helmManifestObjects(current.release.Manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	keep := map[string]bool{}
+	for _, obj := range targetObjects {
+		client, err := helmResourceClient(dynamicClient, mapper, obj, namespace)
+		if err != nil {
+			return nil, err
+		}
+		data, err := obj.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		_, err = client.Patch(context.TODO(), obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{FieldManager: HELM_FIELD_MANAGER, Force: utils.Pointer(true)})
+		if err != nil {
+			return nil, fmt.Errorf("applying %s %s failed: %s", obj.GetKind(), obj.GetName(), err.Error())
+		}
+		keep[helmObjectKey(obj, namespace)] = true
+	}
+	for _, obj := range currentObjects {
+		if keep[helmObjectKey(obj, namespace)] {
+			continue
+		}
+		deleteHelmObject(dynamicClient, mapper, obj, namespace)
+	}
+
+	for i := range records {
+		if records[i].release.Info.Status == HELM_STATUS_DEPLOYED {
+			err = updateHelmReleaseStatus(provider, &records[i], HELM_STATUS_SUPERSEDED)
+			if err != nil {
+				logger.Log.Errorf("Superseding helm release %s failed: %s", records[i].secret.Name, err.Error())
+			}
+		}
+	}
+
+	// decode again so the new revision does not share maps with the (possibly updated) target record
+	raw, _, err := decodeHelmRelease(target.secret.Data["release"])
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	newRevision := latest.release.Version + 1
+	raw["version"] = newRevision
+	info, ok := raw["info"].(map[string]interface{})
+	if !ok {
+		info = map[string]interface{}{}
+		raw["info"] = info
+	}
+	info["status"] = HELM_STATUS_DEPLOYED
+	info["description"] = fmt.Sprintf("Rollback to %d", revision)
+	info["last_deployed"] = now.Format(time.RFC3339Nano)
+	delete(info, "deleted")
+
+	encoded, err := encodeHelmRelease(raw)
+	if err != nil {
+		return nil, err
+	}
+	secret := v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("sh.helm.release.v1.%s.v%d", name, newRevision),
+			Namespace: namespace,
+			Labels: map[string]string{
+				"name":       name,
+				"owner":      "helm",
+				"status":     HELM_STATUS_DEPLOYED,
+				"version":    strconv.Itoa(newRevision),
+				"modifiedAt": strconv.FormatInt(now.Unix(), 10),
+			},
+		},
+		Type: HELM_RELEASE_SECRET_TYPE,
+		Data: map[string][]byte{"release": encoded},
+	}
+	_, err = provider.ClientSet.CoreV1().Secrets(namespace).Create(context.TODO(), &secret, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return GetHelmRelease(namespace, name, newRevision, contextId)
+}
+
+// HelmUninstall deletes all objects of the latest revision and the release history
+func HelmUninstall(namespace string, name string, contextId *string) error {
+	records, err := helmReleaseRecordsFor(namespace, name, contextId)
+	if err != nil {
+		return err
+	}
+
+	provider, err := NewKubeProvider(contextId)
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := dynamic.NewForConfig(&provider.ClientConfig)
+	if err != nil {
+		return err
+	}
+	mapper, err := CachedRESTMapper(contextId)
+	if err != nil {
+		return err
+	}
+
+	objects, err := helmManifestObjects(records[len(records)-1].release.Manifest)
+	if err != nil {
+		return err
+	}
+	for i := len(objects) - 1; i >= 0; i-- {
+		deleteHelmObject(dynamicClient, mapper, objects[i], namespace)
+	}
+
+	secretClient := provider.ClientSet.CoreV1().Secrets(namespace)
+	for _, record := range records {
+		err = secretClient.Delete(context.TODO(), record.secret.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func updateHelmReleaseStatus(provider *KubeProvider, record *helmReleaseRecord, status string) error {
+	info, ok := record.raw["info"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("release has no info")
+	}
+	info["status"] = status
+	encoded, err := encodeHelmRelease(record.raw)
+	if err != nil {
+		return err
+	}
+	record.secret.Data["release"] = encoded
+	if record.secret.Labels == nil {
+		record.secret.Labels = map[string]string{}
+	}
+	record.secret.Labels["status"] = status
+	record.release.Info.Status = status
+	_, err = provider.ClientSet.CoreV1().Secrets(record.secret.Namespace).Update(context.TODO(), &record.secret, MoUpdateOptions())
+	return err
+}
+
+func helmManifestObjects(manifest string) ([]*unstructured.Unstructured, error) {
+	result := []*unstructured.Unstructured{}
+	for _, doc := range helmManifestSeparator.Split(manifest, -1) {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		jsonData, err := yaml.YAMLToJSON([]byte(doc))
+		if err != nil {
+			return result, err
+		}
+		// documents only containing comments (e.g. "# Source: ...")
+		if string(jsonData) == "null" {
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		err = obj.UnmarshalJSON(jsonData)
+		if err != nil {
+			return result, err
+		}
+		result = append(result, obj)
+	}
+	return result, nil
+}
+
+// helmResourceClient also sets the release namespace on namespaced objects without namespace (like helm does)
+func helmResourceClient(dynamicClient dynamic.Interface, mapper meta.RESTMapper, obj *unstructured.Unstructured, namespace string) (dynamic.ResourceInterface, error) {
+	gvk := obj.GroupVersionKind()
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameRoot {
+		return dynamicClient.Resource(mapping.Resource), nil
+	}
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(namespace)
+	}
+	return dynamicClient.Resource(mapping.Resource).Namespace(obj.GetNamespace()), nil
+}
+
+func deleteHelmObject(dynamicClient dynamic.Interface, mapper meta.RESTMapper, obj *unstructured.Unstructured, namespace string) {
+	client, err := helmResourceClient(dynamicClient, mapper, obj, namespace)
+	if err != nil {
+		logger.Log.Errorf("Deleting %s %s failed: %s", obj.GetKind(), obj.GetName(), err.Error())
+		return
+	}
+	propagation := metav1.DeletePropagationBackground
+	err = client.Delete(context.TODO(), obj.GetName(), metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil && !apierrors.IsNotFound(err) {
+		logger.Log.Errorf("Deleting %s %s failed: %s", obj.GetKind(), obj.GetName(), err.Error())
+	}
+}
+
+// the api version is not part of the key because it may change between revisions of the same object
+func helmObjectKey(obj *unstructured.Unstructured, namespace string) string {
+	if obj.GetNamespace() != "" {
+		namespace = obj.GetNamespace()
+	}
+	return fmt.Sprintf("%s/%s/%s", obj.GroupVersionKind().GroupKind().String(), namespace, obj.GetName())
+}
+
+func mergeHelmValues(base map[string]interface{}, override map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for key, value := range base {
+		result[key] = value
+	}
+	for key, value := range override {
+		// null removes a default value
+		if value == nil {
+			delete(result, key)
+			continue
+		}
+		if overrideMap, ok := value.(map[string]interface{}); ok {
+			if baseMap, ok := result[key].(map[string]interface{}); ok {
+				result[key] = mergeHelmValues(baseMap, overrideMap)
+				continue
+			}
+		}
+		result[key] = value
+	}
+	return result
+}
+
+func ListHelmReleasesTerminal(namespace string, contextId *string) {
+	releases, err := AllHelmReleases(namespace, contextId)
+	if err != nil {
+		utils.FatalError(err.Error())
+	}
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"#", "Namespace", "Name", "Revision", "Status", "Chart", "App Version", "Updated"})
+	for index, release := range releases {
+		t.AppendRow(
+			table.Row{index + 1, release.Namespace, release.Name, release.Revision, release.Status, fmt.Sprintf("%s-%s", release.Chart, release.ChartVersion), release.AppVersion, utils.JsonStringToHumanDuration(release.Updated.Format(time.RFC3339))},
+		)
+	}
+	t.Render()
+}
+
+func ListHelmReleaseHistoryTerminal(namespace string, name string, contextId *string) {
+	history, err := HelmReleaseHistory(namespace, name, contextId)
+	if err != nil {
+		utils.FatalError(err.Error())
+	}
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Revision", "Updated", "Status", "Chart", "App Version", "Description"})
+	for _, release := range history {
+		t.AppendRow(
+			table.Row{release.Revision, release.Updated.Format(time.RFC1123), release.Status, fmt.Sprintf("%s-%s", release.Chart, release.ChartVersion), release.AppVersion, release.Description},
+		)
+	}
+	t.Render()
+}
@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/utils"
 
@@ -15,17 +14,13 @@ import (
 func AllHpas(namespaceName string, contextId *string) utils.K8sWorkloadResult {
 	result := []v2.HorizontalPodAutoscaler{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	hpaList, err := provider.ClientSet.AutoscalingV2().HorizontalPodAutoscalers(namespaceName).List(context.TODO(), metav1.ListOptions{FieldSelector: "metadata.namespace!=kube-system"})
+	hpaList, err := cachedList[v2.HorizontalPodAutoscaler](contextId, v2.SchemeGroupVersion.WithResource("horizontalpodautoscalers"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllHpas ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	for _, hpa := range hpaList.Items {
+	for _, hpa := range hpaList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, hpa.ObjectMeta.Namespace) {
 			result = append(result, hpa)
 		}
@@ -34,11 +29,7 @@ func AllHpas(namespaceName string, contextId *string) utils.K8sWorkloadResult {
 }
 
 func GetHpa(namespaceName string, name string, contextId *string) (*v2.HorizontalPodAutoscaler, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.AutoscalingV2().HorizontalPodAutoscalers(namespaceName).Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[v2.HorizontalPodAutoscaler](contextId, v2.SchemeGroupVersion.WithResource("horizontalpodautoscalers"), namespaceName, name)
 }
 
 func UpdateK8sHpa(data v2.HorizontalPodAutoscaler, contextId *string) utils.K8sWorkloadResult {
@@ -77,15 +68,7 @@ func DeleteK8sHpaBy(namespace string, name string, contextId *string) error {
 }
 
 func DescribeK8sHpa(namespace string, name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "hpa", name, "-n", namespace, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_HORIZONTAL_POD_AUTOSCALER, namespace, name, contextId)
 }
 
 func CreateK8sHpa(data v2.HorizontalPodAutoscaler, contextId *string) utils.K8sWorkloadResult {
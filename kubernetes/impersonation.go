@@ -0,0 +1,84 @@
+package kubernetes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/client-go/rest"
+)
+
+// impersonated context ids look like <contextId>/as/<hash of username and groups>
+const IMPERSONATION_ID_SEPARATOR = "/as/"
+
+type impersonation struct {
+	contextId string
+	config    rest.ImpersonationConfig
+}
+
+var impersonations = map[string]impersonation{}
+var impersonationsMutex sync.RWMutex
+
+// ImpersonatedContextId returns an id which can be used like the id of the context, but all requests made with it
+// impersonate the username and groups. Its clients are cached separately and never use the shared informers,
+// because the informers of a context are filled with the rights of its kubeconfig.
+func ImpersonatedContextId(contextId string, username string, groups []string) string {
+	sortedGroups := append([]string{}, groups...)
+	sort.Strings(sortedGroups)
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\n%s", username, strings.Join(sortedGroups, "\n"))))
+	id := fmt.Sprintf("%s%s%s", contextId, IMPERSONATION_ID_SEPARATOR, hex.EncodeToString(sum[:])[:16])
+
+	impersonationsMutex.Lock()
+	defer impersonationsMutex.Unlock()
+	impersonations[id] = impersonation{
+		contextId: contextId,
+		config: rest.ImpersonationConfig{
+			UserName: username,
+			Groups:   sortedGroups,
+		},
+	}
+	return id
+}
+
+func impersonationFor(contextId *string) *impersonation {
+	if contextId == nil || !strings.Contains(*contextId, IMPERSONATION_ID_SEPARATOR) {
+		return nil
+	}
+	impersonationsMutex.RLock()
+	defer impersonationsMutex.RUnlock()
+	if entry, ok := impersonations[*contextId]; ok {
+		return &entry
+	}
+	return nil
+}
+
+// baseContextId returns the id of the context an impersonated id belongs to, for lookups which must not depend on
+// the rights of the impersonated user. Other ids (also unknown impersonated ones) are returned unchanged.
+func baseContextId(contextId *string) *string {
+	if entry := impersonationFor(contextId); entry != nil {
+		return &entry.contextId
+	}
+	return contextId
+}
+
+// isImpersonated is also true for unknown impersonated ids, so they are never served with the rights of the kubeconfig
+func isImpersonated(contextId *string) bool {
+	return contextId != nil && strings.Contains(*contextId, IMPERSONATION_ID_SEPARATOR)
+}
+
+// removeImpersonations forgets all impersonated ids of the context and returns them
+func removeImpersonations(contextId string) []string {
+	impersonationsMutex.Lock()
+	defer impersonationsMutex.Unlock()
+	result := []string{}
+	for id, entry := range impersonations {
+		if entry.contextId == contextId {
+			delete(impersonations, id)
+			result = append(result, id)
+		}
+	}
+	return result
+}
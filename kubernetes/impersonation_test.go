@@ -0,0 +1,87 @@
+package kubernetes
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mogenius/punq/dtos"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://127.0.0.1:6443
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+current-context: test
+users:
+- name: test
+  user:
+    token: secret
+`
+
+func TestImpersonatedContextId(t *testing.T) {
+	t.Cleanup(func() { removeImpersonations("ctx") })
+
+	id := ImpersonatedContextId("ctx", "jane", []string{"ops", "dev"})
+	if id != ImpersonatedContextId("ctx", "jane", []string{"dev", "ops"}) {
+		t.Errorf("ImpersonatedContextId() depends on the order of the groups")
+	}
+	if id == ImpersonatedContextId("ctx", "john", []string{"dev", "ops"}) || id == ImpersonatedContextId("ctx", "jane", []string{"dev"}) {
+		t.Errorf("ImpersonatedContextId() returned the same id for another identity")
+	}
+
+	entry := impersonationFor(&id)
+	if entry == nil || entry.contextId != "ctx" || entry.config.UserName != "jane" || !reflect.DeepEqual(entry.config.Groups, []string{"dev", "ops"}) {
+		t.Fatalf("impersonationFor() = %+v, want jane with the sorted groups", entry)
+	}
+	if base := baseContextId(&id); *base != "ctx" {
+		t.Errorf("baseContextId() = %s, want ctx", *base)
+	}
+
+	removed := removeImpersonations("ctx")
+	if len(removed) != 3 || impersonationFor(&id) != nil {
+		t.Errorf("removeImpersonations() = %v, want all 3 ids to be forgotten", removed)
+	}
+	// forgotten ids must never fall back to the rights of the kubeconfig
+	if !isImpersonated(&id) || *baseContextId(&id) != id {
+		t.Errorf("unknown impersonated id is not treated as impersonated")
+	}
+}
+
+func TestContextConfigLoaderImpersonation(t *testing.T) {
+	previous := ContextList()
+	t.Cleanup(func() {
+		ContextSync(previous)
+		removeImpersonations("ctx")
+	})
+	ContextSync([]dtos.PunqContext{{Id: "ctx", Context: testKubeconfig}})
+
+	plainId := "ctx"
+	config, err := ContextConfigLoader(&plainId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Impersonate.UserName != "" {
+		t.Errorf("ContextConfigLoader() impersonates %s without impersonated id", config.Impersonate.UserName)
+	}
+
+	id := ImpersonatedContextId("ctx", "jane", []string{"dev"})
+	config, err = ContextConfigLoader(&id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Impersonate.UserName != "jane" || !reflect.DeepEqual(config.Impersonate.Groups, []string{"dev"}) {
+		t.Errorf("ContextConfigLoader() impersonate = %+v, want jane in dev", config.Impersonate)
+	}
+
+	unknownId := "ctx" + IMPERSONATION_ID_SEPARATOR + "unknown"
+	if _, err := ContextConfigLoader(&unknownId); err == nil {
+		t.Errorf("ContextConfigLoader() of an unknown impersonated id error = nil, want an error")
+	}
+}
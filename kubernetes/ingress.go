@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/logger"
 	"github.com/mogenius/punq/utils"
@@ -18,17 +17,13 @@ const (
 func AllIngresses(namespaceName string, contextId *string) []v1.Ingress {
 	result := []v1.Ingress{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return result
-	}
-	ingressList, err := provider.ClientSet.NetworkingV1().Ingresses(namespaceName).List(context.TODO(), metav1.ListOptions{FieldSelector: "metadata.namespace!=kube-system"})
+	ingressList, err := cachedList[v1.Ingress](contextId, v1.SchemeGroupVersion.WithResource("ingresses"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllIngresses ERROR: %s", err.Error())
 		return result
 	}
 
-	for _, ingress := range ingressList.Items {
+	for _, ingress := range ingressList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, ingress.ObjectMeta.Namespace) {
 			result = append(result, ingress)
 		}
@@ -39,17 +34,13 @@ func AllIngresses(namespaceName string, contextId *string) []v1.Ingress {
 func AllK8sIngresses(namespaceName string, contextId *string) utils.K8sWorkloadResult {
 	result := []v1.Ingress{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	ingressList, err := provider.ClientSet.NetworkingV1().Ingresses(namespaceName).List(context.TODO(), metav1.ListOptions{FieldSelector: "metadata.namespace!=kube-system"})
+	ingressList, err := cachedList[v1.Ingress](contextId, v1.SchemeGroupVersion.WithResource("ingresses"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllIngresses ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	for _, ingress := range ingressList.Items {
+	for _, ingress := range ingressList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, ingress.ObjectMeta.Namespace) {
 			result = append(result, ingress)
 		}
@@ -58,11 +49,7 @@ func AllK8sIngresses(namespaceName string, contextId *string) utils.K8sWorkloadR
 }
 
 func GetK8sIngress(namespaceName string, name string, contextId *string) (*v1.Ingress, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.NetworkingV1().Ingresses(namespaceName).Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[v1.Ingress](contextId, v1.SchemeGroupVersion.WithResource("ingresses"), namespaceName, name)
 }
 
 func UpdateK8sIngress(data v1.Ingress, contextId *string) utils.K8sWorkloadResult {
@@ -101,15 +88,7 @@ func DeleteK8sIngressBy(namespace string, name string, contextId *string) error
 }
 
 func DescribeK8sIngress(namespace string, name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "ingress", name, "-n", namespace, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_INGRESS, namespace, name, contextId)
 }
 
 func CreateK8sIngress(data v1.Ingress, contextId *string) utils.K8sWorkloadResult {
@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/logger"
 	"github.com/mogenius/punq/utils"
@@ -14,17 +13,13 @@ import (
 func AllIngressClasses(contextId *string) []v1.IngressClass {
 	result := []v1.IngressClass{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return result
-	}
-	ingressList, err := provider.ClientSet.NetworkingV1().IngressClasses().List(context.TODO(), metav1.ListOptions{})
+	ingressList, err := cachedList[v1.IngressClass](contextId, v1.SchemeGroupVersion.WithResource("ingressclasses"), "")
 	if err != nil {
 		logger.Log.Errorf("AllIngressClasses ERROR: %s", err.Error())
 		return result
 	}
 
-	result = append(result, ingressList.Items...)
+	result = append(result, ingressList...)
 
 	return result
 }
@@ -32,27 +27,19 @@ func AllIngressClasses(contextId *string) []v1.IngressClass {
 func AllK8sIngressClasses(contextId *string) utils.K8sWorkloadResult {
 	result := []v1.IngressClass{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	ingressList, err := provider.ClientSet.NetworkingV1().IngressClasses().List(context.TODO(), metav1.ListOptions{})
+	ingressList, err := cachedList[v1.IngressClass](contextId, v1.SchemeGroupVersion.WithResource("ingressclasses"), "")
 	if err != nil {
 		logger.Log.Errorf("AllK8sIngressClasses ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	result = append(result, ingressList.Items...)
+	result = append(result, ingressList...)
 
 	return WorkloadResult(result, nil)
 }
 
 func GetK8sIngressClass(name string, contextId *string) (*v1.IngressClass, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.NetworkingV1().IngressClasses().Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[v1.IngressClass](contextId, v1.SchemeGroupVersion.WithResource("ingressclasses"), "", name)
 }
 
 func UpdateK8sIngressClass(data v1.IngressClass, contextId *string) utils.K8sWorkloadResult {
@@ -91,15 +78,7 @@ func DeleteK8sIngressClassBy(name string, contextId *string) error {
 }
 
 func DescribeK8sIngressClass(name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "ingressclass", name, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_INGRESS_CLASS, "", name, contextId)
 }
 
 func CreateK8sIngressClass(data v1.IngressClass, contextId *string) utils.K8sWorkloadResult {
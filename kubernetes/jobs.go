@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/utils"
 
@@ -15,17 +14,13 @@ import (
 func AllJobs(namespaceName string, contextId *string) utils.K8sWorkloadResult {
 	result := []v1job.Job{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	jobList, err := provider.ClientSet.BatchV1().Jobs(namespaceName).List(context.TODO(), metav1.ListOptions{FieldSelector: "metadata.namespace!=kube-system"})
+	jobList, err := cachedList[v1job.Job](contextId, v1job.SchemeGroupVersion.WithResource("jobs"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllJobs ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	for _, job := range jobList.Items {
+	for _, job := range jobList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, job.ObjectMeta.Namespace) {
 			result = append(result, job)
 		}
@@ -34,11 +29,7 @@ func AllJobs(namespaceName string, contextId *string) utils.K8sWorkloadResult {
 }
 
 func GetJob(namespaceName string, name string, contextId *string) (*v1job.Job, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.BatchV1().Jobs(namespaceName).Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[v1job.Job](contextId, v1job.SchemeGroupVersion.WithResource("jobs"), namespaceName, name)
 }
 
 func UpdateK8sJob(data v1job.Job, contextId *string) utils.K8sWorkloadResult {
@@ -77,15 +68,7 @@ func DeleteK8sJobBy(namespace string, name string, contextId *string) error {
 }
 
 func DescribeK8sJob(namespace string, name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "job", name, "-n", namespace, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_JOB, namespace, name, contextId)
 }
 
 func CreateK8sJob(data v1job.Job, contextId *string) utils.K8sWorkloadResult {
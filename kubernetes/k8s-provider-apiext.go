@@ -0,0 +1,69 @@
+package kubernetes
+
+import (
+	"github.com/mogenius/punq/logger"
+
+	apiExtClientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/client-go/rest"
+)
+
+type KubeProviderApiExtensions struct {
+	ClientSet    *apiExtClientset.Clientset
+	ClientConfig rest.Config
+}
+
+func NewKubeProviderApiExtensions(contextId *string) (*KubeProviderApiExtensions, error) {
+	var provider *KubeProviderApiExtensions
+	var err error
+	if RunsInCluster {
+		provider, err = newKubeProviderApiExtensionsInCluster(contextId)
+	} else {
+		provider, err = newKubeProviderApiExtensionsLocal(contextId)
+	}
+
+	if err != nil {
+		logger.Log.Errorf("ERROR: %s", err.Error())
+	}
+	return provider, err
+}
+
+func newKubeProviderApiExtensionsLocal(contextId *string) (*KubeProviderApiExtensions, error) {
+	config, err := ContextSwitcher(contextId)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := apiExtClientset.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KubeProviderApiExtensions{
+		ClientSet:    clientset,
+		ClientConfig: *config,
+	}, nil
+}
+
+func newKubeProviderApiExtensionsInCluster(contextId *string) (*KubeProviderApiExtensions, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if contextId != nil {
+		config, err = ContextSwitcher(contextId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	clientset, err := apiExtClientset.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KubeProviderApiExtensions{
+		ClientSet:    clientset,
+		ClientConfig: *config,
+	}, nil
+}
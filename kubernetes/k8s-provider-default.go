@@ -16,7 +16,17 @@ type KubeProvider struct {
 	ClientConfig rest.Config
 }
 
+// NewKubeProvider returns the cached provider of the context (see context-cache.go)
 func NewKubeProvider(contextId *string) (*KubeProvider, error) {
+	entry, err := contextCacheEntryFor(contextId)
+	if err != nil {
+		logger.Log.Errorf("ERROR: %s", err.Error())
+		return nil, err
+	}
+	return entry.provider, nil
+}
+
+func newKubeProvider(contextId *string) (*KubeProvider, error) {
 	var provider *KubeProvider
 	var err error
 	if RunsInCluster {
@@ -25,9 +35,6 @@ func NewKubeProvider(contextId *string) (*KubeProvider, error) {
 		provider, err = newKubeProviderLocal(contextId)
 	}
 
-	if err != nil {
-		logger.Log.Errorf("ERROR: %s", err.Error())
-	}
 	return provider, err
 }
 
@@ -85,7 +92,17 @@ func ContextConfigLoader(contextId *string) (*rest.Config, error) {
 	}
 
 	config, err := configFromString.ClientConfig()
-	return config, err
+	if err != nil {
+		return nil, err
+	}
+	if isImpersonated(contextId) {
+		entry := impersonationFor(contextId)
+		if entry == nil {
+			return nil, fmt.Errorf("unknown impersonated context: %s", *contextId)
+		}
+		config.Impersonate = entry.config
+	}
+	return config, nil
 }
 
 func ContextSwitcher(contextId *string) (*rest.Config, error) {
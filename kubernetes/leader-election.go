@@ -0,0 +1,65 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mogenius/punq/logger"
+	"github.com/mogenius/punq/utils"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const (
+	LEADER_LEASE_DURATION = 30 * time.Second
+	LEADER_RENEW_DEADLINE = 20 * time.Second
+	LEADER_RETRY_PERIOD   = 5 * time.Second
+)
+
+// RunLeaderElection competes for the lease with the given name in the own namespace in the background, so background
+// jobs run on one replica only. onChange is called whenever this process becomes or stops being the leader.
+func RunLeaderElection(name string, onChange func(leading bool)) error {
+	provider, err := NewKubeProvider(nil)
+	if err != nil {
+		return err
+	}
+	hostname, _ := os.Hostname()
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: utils.CONFIG.Kubernetes.OwnNamespace,
+		},
+		Client: provider.ClientSet.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: fmt.Sprintf("%s-%s", hostname, utils.NanoId()),
+		},
+	}
+
+	go func() {
+		// RunOrDie returns after the leadership has been lost, the next round competes again
+		for {
+			leaderelection.RunOrDie(context.Background(), leaderelection.LeaderElectionConfig{
+				Lock:            lock,
+				LeaseDuration:   LEADER_LEASE_DURATION,
+				RenewDeadline:   LEADER_RENEW_DEADLINE,
+				RetryPeriod:     LEADER_RETRY_PERIOD,
+				ReleaseOnCancel: true,
+				Callbacks: leaderelection.LeaderCallbacks{
+					OnStartedLeading: func(ctx context.Context) {
+						logger.Log.Noticef("Acquired lease '%s'.", name)
+						onChange(true)
+					},
+					OnStoppedLeading: func() {
+						logger.Log.Noticef("Lost lease '%s'.", name)
+						onChange(false)
+					},
+				},
+			})
+		}
+	}()
+	return nil
+}
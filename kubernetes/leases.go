@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/utils"
 
@@ -16,17 +15,13 @@ import (
 func AllLeases(namespaceName string, contextId *string) utils.K8sWorkloadResult {
 	result := []v1.Lease{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	rolesList, err := provider.ClientSet.CoordinationV1().Leases(namespaceName).List(context.TODO(), metav1.ListOptions{})
+	rolesList, err := cachedList[v1.Lease](contextId, v1.SchemeGroupVersion.WithResource("leases"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllLeases ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	for _, role := range rolesList.Items {
+	for _, role := range rolesList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, role.ObjectMeta.Namespace) {
 			result = append(result, role)
 		}
@@ -35,11 +30,7 @@ func AllLeases(namespaceName string, contextId *string) utils.K8sWorkloadResult
 }
 
 func GetLeas(namespaceName string, name string, contextId *string) (*v1.Lease, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.CoordinationV1().Leases(namespaceName).Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[v1.Lease](contextId, v1.SchemeGroupVersion.WithResource("leases"), namespaceName, name)
 }
 
 func UpdateK8sLease(data v1.Lease, contextId *string) utils.K8sWorkloadResult {
@@ -78,15 +69,7 @@ func DeleteK8sLeaseBy(namespace string, name string, contextId *string) error {
 }
 
 func DescribeK8sLease(namespace string, name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "lease", name, "-n", namespace, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_LEASE, namespace, name, contextId)
 }
 
 func CreateK8sLease(data v1.Lease, contextId *string) utils.K8sWorkloadResult {
@@ -0,0 +1,367 @@
+package kubernetes
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mogenius/punq/logger"
+	"github.com/mogenius/punq/utils"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
+)
+
+// lines of all containers are buffered this long before they are sorted by timestamp and emitted
+const LOG_AGGREGATION_WINDOW = 500 * time.Millisecond
+
+// how often the pod list is checked for new pods/containers while following
+const LOG_POD_RESYNC_INTERVAL = 5 * time.Second
+
+const LOG_MAX_LINE_SIZE = 1024 * 1024
+
+// at most this many pods are streamed at the same time, further pods are ignored until others end
+const LOG_STREAM_MAX_PODS = 50
+
+var LOG_OWNER_KINDS = []string{RES_DEPLOYMENT, RES_STATEFUL_SET, RES_DAEMON_SET, RES_REPLICA_SET, RES_JOB}
+
+type K8sLogStreamOptions struct {
+	Namespace     string   `json:"namespace"`
+	LabelSelector string   `json:"labelSelector,omitempty"`
+	OwnerKind     string   `json:"ownerKind,omitempty"`
+	OwnerName     string   `json:"ownerName,omitempty"`
+	Containers    []string `json:"containers,omitempty"` // empty = all containers
+	Include       string   `json:"include,omitempty"`    // regex
+	Exclude       string   `json:"exclude,omitempty"`    // regex
+	SinceSeconds  int64    `json:"sinceSeconds,omitempty"`
+	TailLines     int64    `json:"tailLines,omitempty"`
+	Follow        bool     `json:"follow"`
+}
+
+type K8sLogLine struct {
+	Timestamp time.Time `json:"timestamp"`
+	Namespace string    `json:"namespace"`
+	Pod       string    `json:"pod"`
+	Container string    `json:"container"`
+	Line      string    `json:"line"`
+}
+
+func (l K8sLogLine) Prefix() string {
+	return fmt.Sprintf("%s/%s", l.Pod, l.Container)
+}
+
+type logTarget struct {
+	namespace string
+	pod       string
+	container string
+}
+
+func (t logTarget) key() string {
+	return fmt.Sprintf("%s/%s/%s", t.namespace, t.pod, t.container)
+}
+
+// AggregatedLogStream streams the logs of all containers of all pods matching a label selector or owner
+type AggregatedLogStream struct {
+	options   K8sLogStreamOptions
+	contextId *string
+	provider  *KubeProvider
+	selector  labels.Selector
+	include   *regexp.Regexp
+	exclude   *regexp.Regexp
+
+	mutex        sync.Mutex
+	active       map[string]bool
+	lastSeen     map[string]time.Time
+	limitReached bool
+}
+
+func NewAggregatedLogStream(opts K8sLogStreamOptions, contextId *string) (*AggregatedLogStream, error) {
+	if opts.Namespace == "" && opts.LabelSelector == "" && opts.OwnerKind == "" {
+		return nil, fmt.Errorf("namespace, label selector or owner is required to stream logs")
+	}
+	stream := &AggregatedLogStream{
+		options:   opts,
+		contextId: contextId,
+		active:    map[string]bool{},
+		lastSeen:  map[string]time.Time{},
+	}
+
+	var err error
+	if opts.Include != "" {
+		if stream.include, err = regexp.Compile(opts.Include); err != nil {
+			return nil, fmt.Errorf("invalid include pattern: %s", err.Error())
+		}
+	}
+	if opts.Exclude != "" {
+		if stream.exclude, err = regexp.Compile(opts.Exclude); err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern: %s", err.Error())
+		}
+	}
+
+	stream.provider, err = NewKubeProvider(contextId)
+	if err != nil {
+		return nil, err
+	}
+	stream.selector, err = logPodSelector(stream.provider, opts, contextId)
+	if err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+// logPodSelector returns the pod selector of the owner (if set) or the parsed label selector
+func logPodSelector(provider *KubeProvider, opts K8sLogStreamOptions, contextId *string) (labels.Selector, error) {
+	if opts.OwnerKind == "" {
+		if opts.LabelSelector == "" {
+			return labels.Everything(), nil
+		}
+		return labels.Parse(opts.LabelSelector)
+	}
+
+	kind := ""
+	for _, ownerKind := range LOG_OWNER_KINDS {
+		if strings.EqualFold(ownerKind, opts.OwnerKind) {
+			kind = ownerKind
+		}
+	}
+	if kind == "" {
+		return nil, fmt.Errorf("logs for '%s' are not supported (supported: %s)", opts.OwnerKind, strings.Join(LOG_OWNER_KINDS, ", "))
+	}
+	if opts.Namespace == "" || opts.OwnerName == "" {
+		return nil, fmt.Errorf("namespace and name are required to get the logs of a %s", kind)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(&provider.ClientConfig)
+	if err != nil {
+		return nil, err
+	}
+	mapper, err := CachedRESTMapper(contextId)
+	if err != nil {
+		return nil, err
+	}
+	owner, err := getUnstructured(dynamicClient, mapper, DESCRIBE_GROUP_KINDS[kind], opts.Namespace, opts.OwnerName)
+	if err != nil {
+		return nil, err
+	}
+	selector := podSelectorFor(owner)
+	if selector == nil {
+		return nil, fmt.Errorf("%s '%s/%s' has no pod selector", kind, opts.Namespace, opts.OwnerName)
+	}
+	return selector, nil
+}
+
+func (s *AggregatedLogStream) targets() ([]logTarget, error) {
+	result := []logTarget{}
+
+	pods, err := cachedList[v1.Pod](s.contextId, v1.SchemeGroupVersion.WithResource("pods"), s.options.Namespace)
+	if err != nil {
+		return result, err
+	}
+	for _, pod := range pods {
+		if utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, pod.Namespace) {
+			continue
+		}
+		if !s.selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		containers := append(append([]v1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+		for _, container := range containers {
+			if len(s.options.Containers) > 0 && !utils.Contains(s.options.Containers, container.Name) {
+				continue
+			}
+			result = append(result, logTarget{namespace: pod.Namespace, pod: pod.Name, container: container.Name})
+		}
+	}
+	return result, nil
+}
+
+func (s *AggregatedLogStream) matches(line string) bool {
+	if s.include != nil && !s.include.MatchString(line) {
+		return false
+	}
+	if s.exclude != nil && s.exclude.MatchString(line) {
+		return false
+	}
+	return true
+}
+
+// startTargets starts a log stream for every container which is not streamed yet
+func (s *AggregatedLogStream) startTargets(ctx context.Context, lines chan<- K8sLogLine, wg *sync.WaitGroup) error {
+	targets, err := s.targets()
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	pods := map[string]bool{}
+	for key := range s.active {
+		pods[key[:strings.LastIndex(key, "/")]] = true
+	}
+	for _, target := range targets {
+		if s.active[target.key()] {
+			continue
+		}
+		pod := fmt.Sprintf("%s/%s", target.namespace, target.pod)
+		if !pods[pod] && len(pods) >= LOG_STREAM_MAX_PODS {
+			if !s.limitReached {
+				logger.Log.Warningf("Log stream of namespace '%s' follows only %d pods, further pods are ignored.", s.options.Namespace, LOG_STREAM_MAX_PODS)
+				s.limitReached = true
+			}
+			continue
+		}
+		pods[pod] = true
+		s.active[target.key()] = true
+		wg.Add(1)
+		go func(target logTarget, since time.Time) {
+			defer wg.Done()
+			s.streamTarget(ctx, target, since, lines)
+			s.mutex.Lock()
+			delete(s.active, target.key())
+			s.mutex.Unlock()
+		}(target, s.lastSeen[target.key()])
+	}
+	return nil
+}
+
+// streamTarget reads the log of one container. If the container was streamed before (since is set)
+// only newer lines are requested to avoid duplicates after a reconnect.
+func (s *AggregatedLogStream) streamTarget(ctx context.Context, target logTarget, since time.Time, lines chan<- K8sLogLine) {
+	opts := v1.PodLogOptions{
+		Container:  target.container,
+		Follow:     s.options.Follow,
+		Timestamps: true,
+	}
+	if !since.IsZero() {
+		opts.SinceTime = utils.Pointer(metav1.NewTime(since.Add(time.Nanosecond)))
+	} else {
+		if s.options.TailLines > 0 {
+			opts.TailLines = utils.Pointer(s.options.TailLines)
+		}
+		if s.options.SinceSeconds > 0 {
+			opts.SinceSeconds = utils.Pointer(s.options.SinceSeconds)
+		}
+	}
+
+	stream, err := s.provider.ClientSet.CoreV1().Pods(target.namespace).GetLogs(target.pod, &opts).Stream(ctx)
+	if err != nil {
+		// e.g. container is still waiting to start; it is retried on the next resync
+		if ctx.Err() == nil {
+			logger.Log.Infof("Log stream for '%s' not available: %s", target.key(), err.Error())
+		}
+		return
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), LOG_MAX_LINE_SIZE)
+	for scanner.Scan() {
+		timestamp, text := splitLogTimestamp(scanner.Text())
+
+		s.mutex.Lock()
+		s.lastSeen[target.key()] = timestamp
+		s.mutex.Unlock()
+
+		if !s.matches(text) {
+			continue
+		}
+		select {
+		case lines <- K8sLogLine{Timestamp: timestamp, Namespace: target.namespace, Pod: target.pod, Container: target.container, Line: text}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Run streams the matching lines (interleaved by timestamp) to the handler. Without follow it returns after all
+// logs have been read, otherwise when the context is done.
+func (s *AggregatedLogStream) Run(ctx context.Context, handler func(line K8sLogLine)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	lines := make(chan K8sLogLine, 1000)
+	wg := sync.WaitGroup{}
+	if err := s.startTargets(ctx, lines, &wg); err != nil {
+		return err
+	}
+
+	if !s.options.Follow {
+		go func() {
+			wg.Wait()
+			close(lines)
+		}()
+		all := []K8sLogLine{}
+		for line := range lines {
+			all = append(all, line)
+		}
+		sortLogLines(all)
+		for _, line := range all {
+			handler(line)
+		}
+		return nil
+	}
+
+	type bufferedLine struct {
+		line       K8sLogLine
+		receivedAt time.Time
+	}
+	buffer := []bufferedLine{}
+
+	flushTicker := time.NewTicker(LOG_AGGREGATION_WINDOW)
+	defer flushTicker.Stop()
+	resyncTicker := time.NewTicker(LOG_POD_RESYNC_INTERVAL)
+	defer resyncTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case line := <-lines:
+			buffer = append(buffer, bufferedLine{line: line, receivedAt: time.Now()})
+		case <-flushTicker.C:
+			cutoff := time.Now().Add(-LOG_AGGREGATION_WINDOW)
+			ready := []K8sLogLine{}
+			pending := []bufferedLine{}
+			for _, entry := range buffer {
+				if entry.receivedAt.Before(cutoff) {
+					ready = append(ready, entry.line)
+				} else {
+					pending = append(pending, entry)
+				}
+			}
+			buffer = pending
+			sortLogLines(ready)
+			for _, line := range ready {
+				handler(line)
+			}
+		case <-resyncTicker.C:
+			if err := s.startTargets(ctx, lines, &wg); err != nil {
+				logger.Log.Errorf("AggregatedLogStream resync ERROR: %s", err.Error())
+			}
+		}
+	}
+}
+
+func sortLogLines(lines []K8sLogLine) {
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].Timestamp.Before(lines[j].Timestamp)
+	})
+}
+
+// splitLogTimestamp splits the RFC3339 timestamp kubernetes prepends if Timestamps is requested
+func splitLogTimestamp(line string) (time.Time, string) {
+	parts := strings.SplitN(line, " ", 2)
+	if len(parts) == 2 {
+		if timestamp, err := time.Parse(time.RFC3339Nano, parts[0]); err == nil {
+			return timestamp, parts[1]
+		}
+	}
+	return time.Now(), line
+}
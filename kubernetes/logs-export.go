@@ -0,0 +1,271 @@
+package kubernetes
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mogenius/punq/logger"
+	"github.com/mogenius/punq/utils"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+type K8sLogExportOptions struct {
+	Namespace     string    `json:"namespace"`
+	LabelSelector string    `json:"labelSelector,omitempty"`
+	OwnerKind     string    `json:"ownerKind,omitempty"`
+	OwnerName     string    `json:"ownerName,omitempty"`
+	Since         time.Time `json:"since,omitempty"` // zero = complete log
+	Until         time.Time `json:"until,omitempty"` // zero = now
+}
+
+var logExportNameCleaner = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// LogExportFileName returns a descriptive file name for the archive of the export
+func LogExportFileName(opts K8sLogExportOptions) string {
+	subject := opts.LabelSelector
+	if opts.OwnerKind != "" {
+		subject = fmt.Sprintf("%s-%s", strings.ToLower(opts.OwnerKind), opts.OwnerName)
+	}
+	if subject == "" {
+		subject = "all"
+	}
+	name := fmt.Sprintf("%s-%s-%s", opts.Namespace, subject, time.Now().Format("20060102-150405"))
+	return logExportNameCleaner.ReplaceAllString(name, "_") + ".tar.gz"
+}
+
+// limits of an export, the archive is streamed but every file is read into memory before it is added
+const (
+	LOG_EXPORT_MAX_PODS      = 50
+	LOG_EXPORT_MAX_FILE_SIZE = 10 * 1024 * 1024  // per log, longer logs are truncated
+	LOG_EXPORT_MAX_SIZE      = 200 * 1024 * 1024 // of all logs, further logs are skipped
+)
+
+// LogExport is a validated export whose pods are known, so errors can be reported before the archive is written
+type LogExport struct {
+	opts      K8sLogExportOptions
+	contextId *string
+	logStream *AggregatedLogStream
+	targets   []logTarget
+}
+
+// ExportLogs writes a tar.gz support bundle to w (see PrepareLogExport and LogExport.Write)
+func ExportLogs(opts K8sLogExportOptions, contextId *string, w io.Writer) error {
+	export, err := PrepareLogExport(opts, contextId)
+	if err != nil {
+		return err
+	}
+	return export.Write(w)
+}
+
+// PrepareLogExport validates the options and resolves the containers. At most LOG_EXPORT_MAX_PODS pods can be exported.
+func PrepareLogExport(opts K8sLogExportOptions, contextId *string) (*LogExport, error) {
+	if opts.Namespace == "" {
+		return nil, fmt.Errorf("namespace is required to export logs")
+	}
+	if !opts.Until.IsZero() && !opts.Since.IsZero() && opts.Until.Before(opts.Since) {
+		return nil, fmt.Errorf("until must be after since")
+	}
+
+	logStream, err := NewAggregatedLogStream(K8sLogStreamOptions{
+		Namespace:     opts.Namespace,
+		LabelSelector: opts.LabelSelector,
+		OwnerKind:     opts.OwnerKind,
+		OwnerName:     opts.OwnerName,
+	}, contextId)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := logStream.targets()
+	if err != nil {
+		return nil, err
+	}
+	if pods := countLogTargetPods(targets); pods > LOG_EXPORT_MAX_PODS {
+		return nil, fmt.Errorf("%d pods match, at most %d can be exported. Please use a selector or an owner", pods, LOG_EXPORT_MAX_PODS)
+	}
+	return &LogExport{opts: opts, contextId: contextId, logStream: logStream, targets: targets}, nil
+}
+
+// Write writes the archive to w. Per pod it contains the describe output and the current and previous log of every
+// container. Events of the pods/owner and the describe output of the owner are added at the top level.
+func (e *LogExport) Write(w io.Writer) error {
+	opts := e.opts
+	contextId := e.contextId
+
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+	root := strings.TrimSuffix(LogExportFileName(opts), ".tar.gz")
+	addFile := func(name string, data []byte) error {
+		err := tarWriter.WriteHeader(&tar.Header{
+			Name:    fmt.Sprintf("%s/%s", root, name),
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		_, err = tarWriter.Write(data)
+		return err
+	}
+	totalSize := 0
+	addLog := func(name string, data []byte) error {
+		if totalSize >= LOG_EXPORT_MAX_SIZE {
+			data = []byte(fmt.Sprintf("skipped: the export reached its limit of %d MiB\n", LOG_EXPORT_MAX_SIZE/1024/1024))
+		}
+		totalSize += len(data)
+		return addFile(name, data)
+	}
+
+	podNames := []string{}
+	for _, target := range e.targets {
+		if !utils.Contains(podNames, target.pod) {
+			podNames = append(podNames, target.pod)
+			describe := DescribeK8s(RES_POD, target.namespace, target.pod, contextId)
+			if err := addFile(fmt.Sprintf("%s/describe.txt", target.pod), []byte(exportResultText(describe.Result, describe.Error))); err != nil {
+				return err
+			}
+		}
+
+		currentOpts := v1.PodLogOptions{Container: target.container, Timestamps: true}
+		if !opts.Since.IsZero() {
+			currentOpts.SinceTime = &metav1.Time{Time: opts.Since}
+		}
+		current, err := exportLog(e.logStream.provider.ClientSet.CoreV1().Pods(target.namespace).GetLogs(target.pod, &currentOpts), opts)
+		if err != nil {
+			current = []byte(err.Error())
+		}
+		if err := addLog(fmt.Sprintf("%s/%s.log", target.pod, target.container), current); err != nil {
+			return err
+		}
+
+		// previous logs only exist after a restart
+		previousReq, err := StreamPreviousLog(target.namespace, target.pod, target.container, contextId)
+		if err != nil {
+			return err
+		}
+		previous, err := exportLog(previousReq, opts)
+		if err == nil && len(previous) > 0 {
+			if err := addLog(fmt.Sprintf("%s/%s.previous.log", target.pod, target.container), previous); err != nil {
+				return err
+			}
+		}
+	}
+
+	if opts.OwnerKind != "" {
+		describe := DescribeK8s(ResourceFor(opts.OwnerKind), opts.Namespace, opts.OwnerName, contextId)
+		if err := addFile("owner-describe.txt", []byte(exportResultText(describe.Result, describe.Error))); err != nil {
+			return err
+		}
+	}
+
+	events := exportEvents(opts, podNames, contextId)
+	eventsJson, err := json.MarshalIndent(events, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := addFile("events.json", eventsJson); err != nil {
+		return err
+	}
+
+	optsJson, err := json.MarshalIndent(opts, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := addFile("export.json", optsJson); err != nil {
+		return err
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+func countLogTargetPods(targets []logTarget) int {
+	pods := map[string]bool{}
+	for _, target := range targets {
+		pods[target.namespace+"/"+target.pod] = true
+	}
+	return len(pods)
+}
+
+// exportLog reads the log and drops lines outside of the time range (since is already applied for current logs).
+// Logs are truncated after LOG_EXPORT_MAX_FILE_SIZE.
+func exportLog(req *rest.Request, opts K8sLogExportOptions) ([]byte, error) {
+	stream, err := req.Stream(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	result := bytes.Buffer{}
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), LOG_MAX_LINE_SIZE)
+	for scanner.Scan() {
+		timestamp, _ := splitLogTimestamp(scanner.Text())
+		if !opts.Since.IsZero() && timestamp.Before(opts.Since) {
+			continue
+		}
+		if !opts.Until.IsZero() && timestamp.After(opts.Until) {
+			break
+		}
+		if result.Len()+len(scanner.Text()) >= LOG_EXPORT_MAX_FILE_SIZE {
+			result.WriteString(fmt.Sprintf("... truncated after %d MiB\n", LOG_EXPORT_MAX_FILE_SIZE/1024/1024))
+			break
+		}
+		result.WriteString(scanner.Text())
+		result.WriteString("\n")
+	}
+	return result.Bytes(), scanner.Err()
+}
+
+// exportEvents returns the events of the exported pods, the owner and objects named after the owner (e.g. ReplicaSets) in the time range
+func exportEvents(opts K8sLogExportOptions, podNames []string, contextId *string) []v1.Event {
+	result := []v1.Event{}
+
+	eventsResult := AllEvents(opts.Namespace, contextId)
+	events, ok := eventsResult.Result.([]v1.Event)
+	if !ok {
+		logger.Log.Errorf("ExportLogs events ERROR: %v", eventsResult.Error)
+		return result
+	}
+
+	for _, event := range events {
+		name := event.InvolvedObject.Name
+		related := utils.Contains(podNames, name) || (opts.OwnerName != "" && strings.HasPrefix(name, opts.OwnerName))
+		if !related {
+			continue
+		}
+		lastSeen := event.LastTimestamp.Time
+		if lastSeen.IsZero() {
+			lastSeen = event.EventTime.Time
+		}
+		if !opts.Since.IsZero() && lastSeen.Before(opts.Since) {
+			continue
+		}
+		if !opts.Until.IsZero() && lastSeen.After(opts.Until) {
+			continue
+		}
+		result = append(result, event)
+	}
+	return result
+}
+
+func exportResultText(result interface{}, err interface{}) string {
+	if err != nil {
+		return fmt.Sprintf("%v", err)
+	}
+	return fmt.Sprintf("%v", result)
+}
@@ -18,6 +18,7 @@ import (
 type ServiceGetLogErrorResult struct {
 	Namespace string `json:"namespace"`
 	PodId     string `json:"podId"`
+	Container string `json:"container"`
 	Restarts  int32  `json:"restarts"`
 	Log       string `json:"log"`
 }
@@ -100,8 +101,12 @@ func GetLogError(namespace string, podId string, contextId *string) ServiceGetLo
 		result.Log = err.Error()
 		return result
 	}
-	if len(pod.Status.ContainerStatuses) > 0 {
-		result.Restarts = pod.Status.ContainerStatuses[0].RestartCount
+	// report the container with the most restarts
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.RestartCount > result.Restarts {
+			result.Restarts = status.RestartCount
+			result.Container = status.Name
+		}
 	}
 
 	// show empty message if no restart have occoured
@@ -110,6 +115,7 @@ func GetLogError(namespace string, podId string, contextId *string) ServiceGetLo
 	}
 
 	restReq := podClient.GetLogs(podId, &v1.PodLogOptions{
+		Container: result.Container,
 		TailLines: utils.Pointer[int64](2000),
 	})
 	stream, err := restReq.Stream(context.TODO())
@@ -129,7 +135,8 @@ func GetLogError(namespace string, podId string, contextId *string) ServiceGetLo
 	return result
 }
 
-func StreamLog(namespace string, podId string, sinceSeconds int64, contextId *string) (*rest.Request, error) {
+// StreamLog follows the log of a container of the pod (container may be empty for single-container pods)
+func StreamLog(namespace string, podId string, container string, sinceSeconds int64, contextId *string) (*rest.Request, error) {
 	provider, err := NewKubeProvider(contextId)
 	if err != nil {
 		return nil, err
@@ -137,6 +144,7 @@ func StreamLog(namespace string, podId string, sinceSeconds int64, contextId *st
 	podClient := provider.ClientSet.CoreV1().Pods(namespace)
 
 	opts := v1.PodLogOptions{
+		Container:  container,
 		Follow:     true,
 		TailLines:  utils.Pointer[int64](2000),
 		Timestamps: true,
@@ -150,7 +158,7 @@ func StreamLog(namespace string, podId string, sinceSeconds int64, contextId *st
 	return restReq, nil
 }
 
-func StreamPreviousLog(namespace string, podId string, contextId *string) (*rest.Request, error) {
+func StreamPreviousLog(namespace string, podId string, container string, contextId *string) (*rest.Request, error) {
 	provider, err := NewKubeProvider(contextId)
 	if err != nil {
 		return nil, err
@@ -158,6 +166,7 @@ func StreamPreviousLog(namespace string, podId string, contextId *string) (*rest
 	podClient := provider.ClientSet.CoreV1().Pods(namespace)
 
 	opts := v1.PodLogOptions{
+		Container:  container,
 		Previous:   true,
 		Timestamps: true,
 	}
@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 	"strings"
 
 	"github.com/mogenius/punq/utils"
@@ -16,19 +15,13 @@ import (
 func ListAllNamespaceNames(contextId *string) []string {
 	result := []string{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return result
-	}
-	namespaceClient := provider.ClientSet.CoreV1().Namespaces()
-
-	namespaceList, err := namespaceClient.List(context.TODO(), metav1.ListOptions{})
+	namespaceList, err := cachedList[v1.Namespace](contextId, v1.SchemeGroupVersion.WithResource("namespaces"), "")
 	if err != nil {
 		logger.Log.Errorf("ListAll ERROR: %s", err.Error())
 		return result
 	}
 
-	for _, ns := range namespaceList.Items {
+	for _, ns := range namespaceList {
 		result = append(result, ns.Name)
 	}
 
@@ -38,48 +31,31 @@ func ListAllNamespaceNames(contextId *string) []string {
 func ListAllNamespace(contextId *string) []v1.Namespace {
 	result := []v1.Namespace{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return result
-	}
-	namespaceClient := provider.ClientSet.CoreV1().Namespaces()
-
-	namespaceList, err := namespaceClient.List(context.TODO(), metav1.ListOptions{})
+	namespaceList, err := cachedList[v1.Namespace](contextId, v1.SchemeGroupVersion.WithResource("namespaces"), "")
 	if err != nil {
 		logger.Log.Errorf("ListAllNamespace ERROR: %s", err.Error())
 		return result
 	}
 
-	result = append(result, namespaceList.Items...)
+	result = append(result, namespaceList...)
 
 	return result
 }
 
 func GetNamespace(name string, contextId *string) (*v1.Namespace, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	namespaceClient := provider.ClientSet.CoreV1().Namespaces()
-	return namespaceClient.Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[v1.Namespace](contextId, v1.SchemeGroupVersion.WithResource("namespaces"), "", name)
 }
 
 func ListK8sNamespaces(namespaceName string, contextId *string) utils.K8sWorkloadResult {
 	result := []v1.Namespace{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	namespaceClient := provider.ClientSet.CoreV1().Namespaces()
-
-	namespaceList, err := namespaceClient.List(context.TODO(), metav1.ListOptions{})
+	namespaceList, err := cachedList[v1.Namespace](contextId, v1.SchemeGroupVersion.WithResource("namespaces"), "")
 	if err != nil {
 		logger.Log.Errorf("ListAllNamespace ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	for _, ns := range namespaceList.Items {
+	for _, ns := range namespaceList {
 		if namespaceName == "" {
 			result = append(result, ns)
 		} else {
@@ -115,15 +91,7 @@ func DeleteK8sNamespaceBy(name string, contextId *string) error {
 }
 
 func DescribeK8sNamespace(name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "namespace", name, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_NAMESPACE, "", name, contextId)
 }
 
 func NamespaceExists(namespaceName string, contextId *string) (bool, error) {
@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/utils"
 
@@ -15,17 +14,13 @@ import (
 func AllNetworkPolicies(namespaceName string, contextId *string) utils.K8sWorkloadResult {
 	result := []v1.NetworkPolicy{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	netPolist, err := provider.ClientSet.NetworkingV1().NetworkPolicies(namespaceName).List(context.TODO(), metav1.ListOptions{FieldSelector: "metadata.namespace!=kube-system"})
+	netPolist, err := cachedList[v1.NetworkPolicy](contextId, v1.SchemeGroupVersion.WithResource("networkpolicies"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllNetworkPolicies ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	for _, netpol := range netPolist.Items {
+	for _, netpol := range netPolist {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, netpol.ObjectMeta.Namespace) {
 			result = append(result, netpol)
 		}
@@ -34,11 +29,7 @@ func AllNetworkPolicies(namespaceName string, contextId *string) utils.K8sWorklo
 }
 
 func GetNetworkPolicy(namespaceName string, name string, contextId *string) (*v1.NetworkPolicy, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.NetworkingV1().NetworkPolicies(namespaceName).Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[v1.NetworkPolicy](contextId, v1.SchemeGroupVersion.WithResource("networkpolicies"), namespaceName, name)
 }
 
 func UpdateK8sNetworkPolicy(data v1.NetworkPolicy, contextId *string) utils.K8sWorkloadResult {
@@ -77,15 +68,7 @@ func DeleteK8sNetworkPolicyBy(namespace string, name string, contextId *string)
 }
 
 func DescribeK8sNetworkPolicy(namespace string, name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "netpol", name, "-n", namespace, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_NETWORK_POLICY, namespace, name, contextId)
 }
 
 func CreateK8sNetworkpolicy(data v1.NetworkPolicy, contextId *string) utils.K8sWorkloadResult {
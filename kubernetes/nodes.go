@@ -3,7 +3,6 @@ package kubernetes
 import (
 	"context"
 	"fmt"
-	"os/exec"
 
 	"github.com/mogenius/punq/utils"
 
@@ -78,27 +77,16 @@ func GetNodeStats(contextId *string) []dtos.NodeStat {
 }
 
 func ListK8sNodes(contextId *string) utils.K8sWorkloadResult {
-	provider, err := NewKubeProvider(contextId)
-	if provider == nil || err != nil {
-		err := fmt.Errorf("failed to load provider")
-		logger.Log.Errorf(err.Error())
-		return WorkloadResult(nil, err)
-	}
-
-	nodeMetricsList, err := provider.ClientSet.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	nodeMetricsList, err := cachedList[v1.Node](contextId, v1.SchemeGroupVersion.WithResource("nodes"), "")
 	if err != nil {
 		logger.Log.Errorf("ListNodeMetrics ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
-	return WorkloadResult(nodeMetricsList.Items, nil)
+	return WorkloadResult(nodeMetricsList, nil)
 }
 
 func GetK8sNode(name string, contextId *string) (*v1.Node, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.CoreV1().Nodes().Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[v1.Node](contextId, v1.SchemeGroupVersion.WithResource("nodes"), "", name)
 }
 
 func DeleteK8sNode(name string, contextId *string) error {
@@ -110,13 +98,5 @@ func DeleteK8sNode(name string, contextId *string) error {
 }
 
 func DescribeK8sNode(name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "node", name, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_NODE, "", name, contextId)
 }
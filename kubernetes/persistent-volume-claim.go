@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/utils"
 
@@ -15,42 +14,30 @@ import (
 func AllPersistentVolumeClaims(namespaceName string, contextId *string) []core.PersistentVolumeClaim {
 	result := []core.PersistentVolumeClaim{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return result
-	}
-	pvList, err := provider.ClientSet.CoreV1().PersistentVolumeClaims(namespaceName).List(context.TODO(), metav1.ListOptions{})
+	pvList, err := cachedList[core.PersistentVolumeClaim](contextId, core.SchemeGroupVersion.WithResource("persistentvolumeclaims"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllPersistentVolumeClaims ERROR: %s", err.Error())
 		return result
 	}
-	result = append(result, pvList.Items...)
+	result = append(result, pvList...)
 
 	return result
 }
 
 func GetPersistentVolumeClaim(namespaceName string, name string, contextId *string) (*core.PersistentVolumeClaim, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.CoreV1().PersistentVolumeClaims(namespaceName).Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[core.PersistentVolumeClaim](contextId, core.SchemeGroupVersion.WithResource("persistentvolumeclaims"), namespaceName, name)
 }
 
 func AllK8sPersistentVolumeClaims(namespaceName string, contextId *string) utils.K8sWorkloadResult {
 	result := []core.PersistentVolumeClaim{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	pvList, err := provider.ClientSet.CoreV1().PersistentVolumeClaims(namespaceName).List(context.TODO(), metav1.ListOptions{})
+	pvList, err := cachedList[core.PersistentVolumeClaim](contextId, core.SchemeGroupVersion.WithResource("persistentvolumeclaims"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllPersistentVolumeClaims ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	result = append(result, pvList.Items...)
+	result = append(result, pvList...)
 	return WorkloadResult(result, nil)
 }
 
@@ -90,15 +77,7 @@ func DeleteK8sPersistentVolumeClaimBy(namespace string, name string, contextId *
 }
 
 func DescribeK8sPersistentVolumeClaim(namespace string, name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "persistentvolumeclaim", name, "-n", namespace, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_PERSISTENT_VOLUME_CLAIM, namespace, name, contextId)
 }
 
 func CreateK8sPersistentVolumeClaim(data core.PersistentVolumeClaim, contextId *string) utils.K8sWorkloadResult {
@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/utils"
 
@@ -15,26 +14,18 @@ import (
 func AllPersistentVolumes(contextId *string) utils.K8sWorkloadResult {
 	result := []core.PersistentVolume{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	pvList, err := provider.ClientSet.CoreV1().PersistentVolumes().List(context.TODO(), metav1.ListOptions{})
+	pvList, err := cachedList[core.PersistentVolume](contextId, core.SchemeGroupVersion.WithResource("persistentvolumes"), "")
 	if err != nil {
 		logger.Log.Errorf("AllPersistentVolumes ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	result = append(result, pvList.Items...)
+	result = append(result, pvList...)
 	return WorkloadResult(result, nil)
 }
 
 func GetPersistentVolume(name string, contextId *string) (*core.PersistentVolume, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.CoreV1().PersistentVolumes().Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[core.PersistentVolume](contextId, core.SchemeGroupVersion.WithResource("persistentvolumes"), "", name)
 }
 
 func UpdateK8sPersistentVolume(data core.PersistentVolume, contextId *string) utils.K8sWorkloadResult {
@@ -73,15 +64,7 @@ func DeleteK8sPersistentVolumeBy(name string, contextId *string) error {
 }
 
 func DescribeK8sPersistentVolume(name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "persistentvolume", name, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_PERSISTENT_VOLUME, "", name, contextId)
 }
 
 func CreateK8sPersistentVolume(data core.PersistentVolume, contextId *string) utils.K8sWorkloadResult {
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"os"
-	"os/exec"
 	"sort"
 	"strings"
 	"text/template"
@@ -135,13 +134,7 @@ func GetFirstPodForLabelName(namespace string, labelName string, contextId *stri
 }
 
 func GetPod(namespace string, podName string, contextId *string) *v1.Pod {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil
-	}
-
-	client := provider.ClientSet.CoreV1().Pods(namespace)
-	pod, err := client.Get(context.TODO(), podName, metav1.GetOptions{})
+	pod, err := cachedGet[v1.Pod](contextId, v1.SchemeGroupVersion.WithResource("pods"), namespace, podName)
 	if err != nil {
 		logger.Log.Errorf("GetPod Error: %s", err.Error())
 		return nil
@@ -150,12 +143,7 @@ func GetPod(namespace string, podName string, contextId *string) *v1.Pod {
 }
 
 func GetPodBy(namespace string, podName string, contextId *string) (*v1.Pod, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	client := provider.ClientSet.CoreV1().Pods(namespace)
-	return client.Get(context.TODO(), podName, metav1.GetOptions{})
+	return cachedGet[v1.Pod](contextId, v1.SchemeGroupVersion.WithResource("pods"), namespace, podName)
 }
 
 func PodExists(namespace string, name string, contextId *string) ServicePodExistsResult {
@@ -179,17 +167,13 @@ func PodExists(namespace string, name string, contextId *string) ServicePodExist
 func AllPods(namespaceName string, contextId *string) []v1.Pod {
 	result := []v1.Pod{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return result
-	}
-	podsList, err := provider.ClientSet.CoreV1().Pods(namespaceName).List(context.TODO(), metav1.ListOptions{FieldSelector: "metadata.namespace!=kube-system"})
+	podsList, err := cachedList[v1.Pod](contextId, v1.SchemeGroupVersion.WithResource("pods"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllPods podMetricsList ERROR: %s", err.Error())
 		return result
 	}
 
-	for _, pod := range podsList.Items {
+	for _, pod := range podsList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, pod.ObjectMeta.Namespace) {
 			result = append(result, pod)
 		}
@@ -288,15 +272,7 @@ func DeleteK8sPodBy(namespace string, name string, contextId *string) error {
 }
 
 func DescribeK8sPod(namespace string, name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "pod", name, "-n", namespace, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_POD, namespace, name, contextId)
 }
 
 func CreateK8sPod(data v1.Pod, contextId *string) utils.K8sWorkloadResult {
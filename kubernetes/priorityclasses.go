@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/utils"
 
@@ -16,17 +15,13 @@ import (
 func AllPriorityClasses(contextId *string) utils.K8sWorkloadResult {
 	result := []v1.PriorityClass{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	pcList, err := provider.ClientSet.SchedulingV1().PriorityClasses().List(context.TODO(), metav1.ListOptions{})
+	pcList, err := cachedList[v1.PriorityClass](contextId, v1.SchemeGroupVersion.WithResource("priorityclasses"), "")
 	if err != nil {
 		logger.Log.Errorf("AllPriorityClasses ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	for _, roleBinding := range pcList.Items {
+	for _, roleBinding := range pcList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, roleBinding.ObjectMeta.Namespace) {
 			result = append(result, roleBinding)
 		}
@@ -35,11 +30,7 @@ func AllPriorityClasses(contextId *string) utils.K8sWorkloadResult {
 }
 
 func GetPriorityClass(name string, contextId *string) (*v1.PriorityClass, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.SchedulingV1().PriorityClasses().Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[v1.PriorityClass](contextId, v1.SchemeGroupVersion.WithResource("priorityclasses"), "", name)
 }
 
 func UpdateK8sPriorityClass(data v1.PriorityClass, contextId *string) utils.K8sWorkloadResult {
@@ -78,15 +69,7 @@ func DeleteK8sPriorityClassBy(name string, contextId *string) error {
 }
 
 func DescribeK8sPriorityClass(name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "priorityclasses", name, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_PRIORITY_CLASS, "", name, contextId)
 }
 
 func CreateK8sPriorityClass(data v1.PriorityClass, contextId *string) utils.K8sWorkloadResult {
@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/utils"
 
@@ -15,17 +14,13 @@ import (
 func AllReplicasets(namespaceName string, contextId *string) []v1.ReplicaSet {
 	result := []v1.ReplicaSet{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return result
-	}
-	replicaSetList, err := provider.ClientSet.AppsV1().ReplicaSets(namespaceName).List(context.TODO(), metav1.ListOptions{FieldSelector: "metadata.namespace!=kube-system"})
+	replicaSetList, err := cachedList[v1.ReplicaSet](contextId, v1.SchemeGroupVersion.WithResource("replicasets"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllReplicasets ERROR: %s", err.Error())
 		return result
 	}
 
-	for _, replicaSet := range replicaSetList.Items {
+	for _, replicaSet := range replicaSetList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, replicaSet.ObjectMeta.Namespace) {
 			result = append(result, replicaSet)
 		}
@@ -34,27 +29,19 @@ func AllReplicasets(namespaceName string, contextId *string) []v1.ReplicaSet {
 }
 
 func GetReplicaset(namespaceName string, name string, contextId *string) (*v1.ReplicaSet, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.AppsV1().ReplicaSets(namespaceName).Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[v1.ReplicaSet](contextId, v1.SchemeGroupVersion.WithResource("replicasets"), namespaceName, name)
 }
 
 func AllK8sReplicasets(namespaceName string, contextId *string) utils.K8sWorkloadResult {
 	result := []v1.ReplicaSet{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	replicaSetList, err := provider.ClientSet.AppsV1().ReplicaSets(namespaceName).List(context.TODO(), metav1.ListOptions{FieldSelector: "metadata.namespace!=kube-system"})
+	replicaSetList, err := cachedList[v1.ReplicaSet](contextId, v1.SchemeGroupVersion.WithResource("replicasets"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllReplicasets ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	for _, replicaSet := range replicaSetList.Items {
+	for _, replicaSet := range replicaSetList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, replicaSet.ObjectMeta.Namespace) {
 			result = append(result, replicaSet)
 		}
@@ -98,15 +85,7 @@ func DeleteK8sReplicasetBy(namespace string, name string, contextId *string) err
 }
 
 func DescribeK8sReplicaset(namespace string, name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "replicaset", name, "-n", namespace, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_REPLICA_SET, namespace, name, contextId)
 }
 
 func CreateK8sReplicaSet(data v1.ReplicaSet, contextId *string) utils.K8sWorkloadResult {
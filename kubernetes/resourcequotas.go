@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/utils"
 
@@ -16,17 +15,13 @@ import (
 func AllResourceQuotas(namespaceName string, contextId *string) utils.K8sWorkloadResult {
 	result := []core.ResourceQuota{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	rqList, err := provider.ClientSet.CoreV1().ResourceQuotas(namespaceName).List(context.TODO(), metav1.ListOptions{})
+	rqList, err := cachedList[core.ResourceQuota](contextId, core.SchemeGroupVersion.WithResource("resourcequotas"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllResourceQuotas ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	for _, rq := range rqList.Items {
+	for _, rq := range rqList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, rq.ObjectMeta.Namespace) {
 			result = append(result, rq)
 		}
@@ -35,11 +30,7 @@ func AllResourceQuotas(namespaceName string, contextId *string) utils.K8sWorkloa
 }
 
 func GetResourceQuota(namespaceName string, name string, contextId *string) (*core.ResourceQuota, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.CoreV1().ResourceQuotas(namespaceName).Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[core.ResourceQuota](contextId, core.SchemeGroupVersion.WithResource("resourcequotas"), namespaceName, name)
 }
 
 func UpdateK8sResourceQuota(data core.ResourceQuota, contextId *string) utils.K8sWorkloadResult {
@@ -78,15 +69,7 @@ func DeleteK8sResourceQuotaBy(namespace string, name string, contextId *string)
 }
 
 func DescribeK8sResourceQuota(namespace string, name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "resourcequotas", name, "-n", namespace, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_RESOURCE_QUOTA, namespace, name, contextId)
 }
 
 func CreateK8sResourceQuota(data core.ResourceQuota, contextId *string) utils.K8sWorkloadResult {
@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/logger"
 	"github.com/mogenius/punq/utils"
@@ -15,17 +14,13 @@ import (
 func AllRoleBindings(namespaceName string, contextId *string) utils.K8sWorkloadResult {
 	result := []v1.RoleBinding{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	rolesList, err := provider.ClientSet.RbacV1().RoleBindings(namespaceName).List(context.TODO(), metav1.ListOptions{})
+	rolesList, err := cachedList[v1.RoleBinding](contextId, v1.SchemeGroupVersion.WithResource("rolebindings"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllBindings ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	for _, roleBinding := range rolesList.Items {
+	for _, roleBinding := range rolesList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, roleBinding.ObjectMeta.Namespace) {
 			result = append(result, roleBinding)
 		}
@@ -34,11 +29,7 @@ func AllRoleBindings(namespaceName string, contextId *string) utils.K8sWorkloadR
 }
 
 func GetRoleBinding(namespaceName string, name string, contextId *string) (*v1.RoleBinding, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.RbacV1().RoleBindings(namespaceName).Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[v1.RoleBinding](contextId, v1.SchemeGroupVersion.WithResource("rolebindings"), namespaceName, name)
 }
 
 func UpdateK8sRoleBinding(data v1.RoleBinding, contextId *string) utils.K8sWorkloadResult {
@@ -77,15 +68,7 @@ func DeleteK8sRoleBindingBy(namespace string, name string, contextId *string) er
 }
 
 func DescribeK8sRoleBinding(namespace string, name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "rolebinding", name, "-n", namespace, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_ROLE_BINDING, namespace, name, contextId)
 }
 
 func CreateK8sRoleBinding(data v1.RoleBinding, contextId *string) utils.K8sWorkloadResult {
@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/logger"
 	"github.com/mogenius/punq/utils"
@@ -15,17 +14,13 @@ import (
 func AllRoles(namespaceName string, contextId *string) utils.K8sWorkloadResult {
 	result := []v1.Role{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	rolesList, err := provider.ClientSet.RbacV1().Roles(namespaceName).List(context.TODO(), metav1.ListOptions{})
+	rolesList, err := cachedList[v1.Role](contextId, v1.SchemeGroupVersion.WithResource("roles"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllRoles ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	for _, role := range rolesList.Items {
+	for _, role := range rolesList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, role.ObjectMeta.Namespace) {
 			result = append(result, role)
 		}
@@ -34,11 +29,7 @@ func AllRoles(namespaceName string, contextId *string) utils.K8sWorkloadResult {
 }
 
 func GetRole(namespaceName string, name string, contextId *string) (*v1.Role, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.RbacV1().Roles(namespaceName).Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[v1.Role](contextId, v1.SchemeGroupVersion.WithResource("roles"), namespaceName, name)
 }
 
 func UpdateK8sRole(data v1.Role, contextId *string) utils.K8sWorkloadResult {
@@ -77,15 +68,7 @@ func DeleteK8sRoleBy(namespace string, name string, contextId *string) error {
 }
 
 func DescribeK8sRole(namespace string, name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "role", name, "-n", namespace, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_ROLE, namespace, name, contextId)
 }
 
 func CreateK8sRole(data v1.Role, contextId *string) utils.K8sWorkloadResult {
@@ -3,7 +3,6 @@ package kubernetes
 import (
 	"context"
 	"encoding/json"
-	"os/exec"
 	"sort"
 
 	"github.com/mogenius/punq/dtos"
@@ -17,17 +16,13 @@ import (
 func AllSecrets(namespaceName string, contextId *string) []v1.Secret {
 	result := []v1.Secret{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return result
-	}
-	secretList, err := provider.ClientSet.CoreV1().Secrets(namespaceName).List(context.TODO(), metav1.ListOptions{FieldSelector: "metadata.namespace!=kube-system"})
+	secretList, err := cachedList[v1.Secret](contextId, v1.SchemeGroupVersion.WithResource("secrets"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllSecrets ERROR: %s", err.Error())
 		return result
 	}
 
-	for _, secret := range secretList.Items {
+	for _, secret := range secretList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, secret.ObjectMeta.Namespace) {
 			result = append(result, secret)
 		}
@@ -49,12 +44,7 @@ func SecretFor(namespace string, name string, contextId *string) *v1.Secret {
 	return secret
 }
 func GetSecret(namespace string, name string, contextId *string) (*v1.Secret, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	secretClient := provider.ClientSet.CoreV1().Secrets(namespace)
-	return secretClient.Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[v1.Secret](contextId, v1.SchemeGroupVersion.WithResource("secrets"), namespace, name)
 }
 
 func ListAllContexts() []dtos.PunqContext {
@@ -84,17 +74,13 @@ func ListAllContexts() []dtos.PunqContext {
 func AllK8sSecrets(namespaceName string, contextId *string) utils.K8sWorkloadResult {
 	result := []v1.Secret{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	secretList, err := provider.ClientSet.CoreV1().Secrets(namespaceName).List(context.TODO(), metav1.ListOptions{FieldSelector: "metadata.namespace!=kube-system"})
+	secretList, err := cachedList[v1.Secret](contextId, v1.SchemeGroupVersion.WithResource("secrets"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllSecrets ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	for _, secret := range secretList.Items {
+	for _, secret := range secretList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, secret.ObjectMeta.Namespace) {
 			result = append(result, secret)
 		}
@@ -138,15 +124,7 @@ func DeleteK8sSecretBy(namespace string, name string, contextId *string) error {
 }
 
 func DescribeK8sSecret(namespace string, name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "secret", name, "-n", namespace, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_SECRET, namespace, name, contextId)
 }
 
 func CreateK8sSecret(data v1.Secret, contextId *string) utils.K8sWorkloadResult {
@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/utils"
 
@@ -16,17 +15,13 @@ import (
 func AllServiceAccounts(namespaceName string, contextId *string) utils.K8sWorkloadResult {
 	result := []v1.ServiceAccount{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	rolesList, err := provider.ClientSet.CoreV1().ServiceAccounts(namespaceName).List(context.TODO(), metav1.ListOptions{})
+	rolesList, err := cachedList[v1.ServiceAccount](contextId, v1.SchemeGroupVersion.WithResource("serviceaccounts"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllServiceAccounts ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	for _, role := range rolesList.Items {
+	for _, role := range rolesList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, role.ObjectMeta.Namespace) {
 			result = append(result, role)
 		}
@@ -35,11 +30,7 @@ func AllServiceAccounts(namespaceName string, contextId *string) utils.K8sWorklo
 }
 
 func GetServiceAccount(namespaceName string, name string, contextId *string) (*v1.ServiceAccount, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.CoreV1().ServiceAccounts(namespaceName).Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[v1.ServiceAccount](contextId, v1.SchemeGroupVersion.WithResource("serviceaccounts"), namespaceName, name)
 }
 
 func UpdateK8sServiceAccount(data v1.ServiceAccount, contextId *string) utils.K8sWorkloadResult {
@@ -78,15 +69,7 @@ func DeleteK8sServiceAccountBy(namespace string, name string, contextId *string)
 }
 
 func DescribeK8sServiceAccount(namespace string, name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "serviceaccount", name, "-n", namespace, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_SERVICE_ACCOUNT, namespace, name, contextId)
 }
 
 func CreateK8sServiceAccount(data v1.ServiceAccount, contextId *string) utils.K8sWorkloadResult {
@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/utils"
 
@@ -39,28 +38,19 @@ func ServiceFor(namespace string, serviceName string, contextId *string) *v1.Ser
 }
 
 func GetService(namespace string, serviceName string, contextId *string) (*v1.Service, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	serviceClient := provider.ClientSet.CoreV1().Services(namespace)
-	return serviceClient.Get(context.TODO(), serviceName, metav1.GetOptions{})
+	return cachedGet[v1.Service](contextId, v1.SchemeGroupVersion.WithResource("services"), namespace, serviceName)
 }
 
 func AllServices(namespaceName string, contextId *string) []v1.Service {
 	result := []v1.Service{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return result
-	}
-	serviceList, err := provider.ClientSet.CoreV1().Services(namespaceName).List(context.TODO(), metav1.ListOptions{FieldSelector: "metadata.namespace!=kube-system"})
+	serviceList, err := cachedList[v1.Service](contextId, v1.SchemeGroupVersion.WithResource("services"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllServices ERROR: %s", err.Error())
 		return result
 	}
 
-	for _, service := range serviceList.Items {
+	for _, service := range serviceList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, service.ObjectMeta.Namespace) {
 			result = append(result, service)
 		}
@@ -109,15 +99,7 @@ func DeleteK8sServiceBy(namespace string, name string, contextId *string) error
 }
 
 func DescribeK8sService(namespace string, name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "service", name, "-n", namespace, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_SERVICE, namespace, name, contextId)
 }
 
 func CreateK8sService(data v1.Service, contextId *string) utils.K8sWorkloadResult {
@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/utils"
 
@@ -15,17 +14,13 @@ import (
 func AllStatefulSets(namespaceName string, contextId *string) utils.K8sWorkloadResult {
 	result := []v1.StatefulSet{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	statefulSetList, err := provider.ClientSet.AppsV1().StatefulSets(namespaceName).List(context.TODO(), metav1.ListOptions{FieldSelector: "metadata.namespace!=kube-system"})
+	statefulSetList, err := cachedList[v1.StatefulSet](contextId, v1.SchemeGroupVersion.WithResource("statefulsets"), namespaceName)
 	if err != nil {
 		logger.Log.Errorf("AllStatefulSets ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	for _, statefulSet := range statefulSetList.Items {
+	for _, statefulSet := range statefulSetList {
 		if !utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, statefulSet.ObjectMeta.Namespace) {
 			result = append(result, statefulSet)
 		}
@@ -34,11 +29,7 @@ func AllStatefulSets(namespaceName string, contextId *string) utils.K8sWorkloadR
 }
 
 func GetStatefulSet(namespaceName string, name string, contextId *string) (*v1.StatefulSet, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.AppsV1().StatefulSets(namespaceName).Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[v1.StatefulSet](contextId, v1.SchemeGroupVersion.WithResource("statefulsets"), namespaceName, name)
 }
 
 func UpdateK8sStatefulset(data v1.StatefulSet, contextId *string) utils.K8sWorkloadResult {
@@ -77,15 +68,7 @@ func DeleteK8sStatefulsetBy(namespace string, name string, contextId *string) er
 }
 
 func DescribeK8sStatefulset(namespace string, name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "statefulset", name, "-n", namespace, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_STATEFUL_SET, namespace, name, contextId)
 }
 
 func CreateK8sStatefulset(data v1.StatefulSet, contextId *string) utils.K8sWorkloadResult {
@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/utils"
 
@@ -15,26 +14,18 @@ import (
 func AllStorageClasses(contextId *string) utils.K8sWorkloadResult {
 	result := []storage.StorageClass{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	scList, err := provider.ClientSet.StorageV1().StorageClasses().List(context.TODO(), metav1.ListOptions{})
+	scList, err := cachedList[storage.StorageClass](contextId, storage.SchemeGroupVersion.WithResource("storageclasses"), "")
 	if err != nil {
 		logger.Log.Errorf("AllStorageClasses ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	result = append(result, scList.Items...)
+	result = append(result, scList...)
 	return WorkloadResult(result, nil)
 }
 
 func GetStorageClass(name string, contextId *string) (*storage.StorageClass, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.StorageV1().StorageClasses().Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[storage.StorageClass](contextId, storage.SchemeGroupVersion.WithResource("storageclasses"), "", name)
 }
 
 func UpdateK8sStorageClass(data storage.StorageClass, contextId *string) utils.K8sWorkloadResult {
@@ -73,15 +64,7 @@ func DeleteK8sStorageClassBy(name string, contextId *string) error {
 }
 
 func DescribeK8sStorageClass(name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "storageclass", name, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_STORAGE_CLASS, "", name, contextId)
 }
 
 func CreateK8sStorageClass(data storage.StorageClass, contextId *string) utils.K8sWorkloadResult {
@@ -16,6 +16,7 @@ import (
 
 	"github.com/jedib0t/go-pretty/table"
 	"github.com/mogenius/punq/version"
+	"github.com/pmezard/go-difflib/difflib"
 
 	"github.com/mogenius/punq/utils"
 
@@ -73,6 +74,12 @@ const (
 	RES_INGRESS_CLASS              string = "IngressClass"
 )
 
+// kinds of workload routes which are no single kubernetes resource (used by role rules)
+const (
+	RES_HELM_RELEASE    string = "HelmRelease"
+	RES_CUSTOM_RESOURCE string = "CustomResource"
+)
+
 var ALL_RESOURCES []string = []string{
 	RES_NAMESPACE,
 	RES_POD,
@@ -113,44 +120,6 @@ var ALL_RESOURCES []string = []string{
 	RES_INGRESS_CLASS,
 }
 
-var ALL_RESOURCES_USER []string = []string{
-	RES_NAMESPACE,
-	RES_POD,
-	RES_DEPLOYMENT,
-	RES_SERVICE,
-	RES_INGRESS,
-	RES_CONFIG_MAP,
-	RES_NODE,
-	RES_DAEMON_SET,
-	RES_STATEFUL_SET,
-	RES_JOB,
-	RES_CRON_JOB,
-	RES_REPLICA_SET,
-	RES_PERSISTENT_VOLUME_CLAIM,
-	RES_EVENT,
-	RES_NETWORK_POLICY,
-	RES_ENDPOINT,
-}
-
-var ALL_RESOURCES_READER []string = []string{
-	RES_NAMESPACE,
-	RES_POD,
-	RES_DEPLOYMENT,
-	RES_SERVICE,
-	RES_INGRESS,
-	RES_CONFIG_MAP,
-	RES_NODE,
-	RES_DAEMON_SET,
-	RES_STATEFUL_SET,
-	RES_JOB,
-	RES_CRON_JOB,
-	RES_REPLICA_SET,
-	RES_PERSISTENT_VOLUME_CLAIM,
-	RES_EVENT,
-	RES_NETWORK_POLICY,
-	RES_ENDPOINT,
-}
-
 type IngressType int
 
 const (
@@ -189,13 +158,12 @@ func InitKubernetes(runsInCluster bool) {
 	RunsInCluster = runsInCluster
 }
 
-func ListWorkloadsOnTerminal(access dtos.AccessLevel) {
+func ListWorkloadsOnTerminal() {
 	t := table.NewWriter()
 	t.SetOutputMirror(os.Stdout)
 	t.AppendHeader(table.Row{"#", "Name"})
 
-	resources := WorkloadsForAccesslevel(access)
-	for index, resource := range resources {
+	for index, resource := range ALL_RESOURCES {
 		t.AppendRow(
 			table.Row{index + 1, resource},
 		)
@@ -203,19 +171,6 @@ func ListWorkloadsOnTerminal(access dtos.AccessLevel) {
 	t.Render()
 }
 
-func WorkloadsForAccesslevel(access dtos.AccessLevel) []string {
-	resources := []string{}
-	switch access {
-	case dtos.READER:
-		resources = ALL_RESOURCES_READER
-	case dtos.USER:
-		resources = ALL_RESOURCES_USER
-	case dtos.ADMIN:
-		resources = ALL_RESOURCES
-	}
-	return resources
-}
-
 func WorkloadResult(result interface{}, err interface{}) utils.K8sWorkloadResult {
 	if fmt.Sprint(reflect.TypeOf(err)) == "*errors.errorString" {
 		err = err.(error).Error()
@@ -685,7 +640,6 @@ func GuessCluserProviderFromNodeList(nodes *v1.NodeList) (dtos.KubernetesProvide
 		} else if LabelsContain(labels, "rke.cattle.io") {
 			return dtos.RKE, nil
 		} else {
-			fmt.Println("This cluster's provider is unknown or it might be self-managed.")
 			return dtos.UNKNOWN, nil
 		}
 	}
@@ -800,3 +754,14 @@ func AllResourcesFromToCombinedYaml(namespace string, resourcesToLookFor []strin
 	}
 	return result, err
 }
+
+// UnifiedDiff returns a unified diff (3 lines of context) of two texts
+func UnifiedDiff(a string, b string, fromFile string, toFile string) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(a),
+		B:        difflib.SplitLines(b),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  3,
+	})
+}
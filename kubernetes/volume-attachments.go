@@ -2,7 +2,6 @@ package kubernetes
 
 import (
 	"context"
-	"os/exec"
 
 	"github.com/mogenius/punq/logger"
 	"github.com/mogenius/punq/utils"
@@ -14,26 +13,18 @@ import (
 func AllVolumeAttachments(contextId *string) utils.K8sWorkloadResult {
 	result := []storage.VolumeAttachment{}
 
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return WorkloadResult(nil, err)
-	}
-	volAttachList, err := provider.ClientSet.StorageV1().VolumeAttachments().List(context.TODO(), metav1.ListOptions{})
+	volAttachList, err := cachedList[storage.VolumeAttachment](contextId, storage.SchemeGroupVersion.WithResource("volumeattachments"), "")
 	if err != nil {
 		logger.Log.Errorf("AllCertificateSigningRequests ERROR: %s", err.Error())
 		return WorkloadResult(nil, err)
 	}
 
-	result = append(result, volAttachList.Items...)
+	result = append(result, volAttachList...)
 	return WorkloadResult(result, nil)
 }
 
 func GetVolumeAttachment(name string, contextId *string) (*storage.VolumeAttachment, error) {
-	provider, err := NewKubeProvider(contextId)
-	if err != nil {
-		return nil, err
-	}
-	return provider.ClientSet.StorageV1().VolumeAttachments().Get(context.TODO(), name, metav1.GetOptions{})
+	return cachedGet[storage.VolumeAttachment](contextId, storage.SchemeGroupVersion.WithResource("volumeattachments"), "", name)
 }
 
 func UpdateK8sVolumeAttachment(data storage.VolumeAttachment, contextId *string) utils.K8sWorkloadResult {
@@ -72,15 +63,7 @@ func DeleteK8sVolumeAttachmentBy(name string, contextId *string) error {
 }
 
 func DescribeK8sVolumeAttachment(name string, contextId *string) utils.K8sWorkloadResult {
-	cmd := exec.Command("kubectl", "describe", "volumeattachment", name, ContextFlag(contextId))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Log.Errorf("Failed to execute command (%s): %v", cmd.String(), err)
-		logger.Log.Errorf("Error: %s", string(output))
-		return WorkloadResult(nil, string(output))
-	}
-	return WorkloadResult(string(output), nil)
+	return DescribeK8s(RES_VOLUME_ATTACHMENT, "", name, contextId)
 }
 
 func CreateK8sVolumeAttachment(data storage.VolumeAttachment, contextId *string) utils.K8sWorkloadResult {
@@ -0,0 +1,110 @@
+package kubernetes
+
+import (
+	"fmt"
+
+	"github.com/mogenius/punq/utils"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	WATCH_EVENT_ADDED    string = "ADDED"
+	WATCH_EVENT_MODIFIED string = "MODIFIED"
+	WATCH_EVENT_DELETED  string = "DELETED"
+)
+
+type K8sWatchEvent struct {
+	Type      string                 `json:"type"`
+	ContextId string                 `json:"contextId"`
+	Resource  string                 `json:"resource"`
+	Namespace string                 `json:"namespace"`
+	Object    map[string]interface{} `json:"object"`
+}
+
+func contextIdString(contextId *string) string {
+	if contextId == nil {
+		return ""
+	}
+	return *contextId
+}
+
+// WatchK8sResource calls the handler for every change of the resource (starting with ADDED for all existing objects)
+// until the returned stop function is called. Cluster-scoped resources ignore the namespace. The informer is shared
+// with other watchers of the same resource and namespace and stops with the last of them.
+func WatchK8sResource(resource string, namespace string, contextId *string, handler func(event K8sWatchEvent)) (func(), error) {
+	groupKind, ok := DESCRIBE_GROUP_KINDS[resource]
+	if !ok {
+		return nil, fmt.Errorf("watching '%s' is not supported", resource)
+	}
+
+	mapper, err := CachedRESTMapper(contextId)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := mapper.RESTMapping(groupKind)
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameRoot {
+		namespace = ""
+	}
+
+	informer, release, err := acquireWatchInformer(contextId, mapping.Resource, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	send := func(eventType string, obj interface{}) {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			tombstone, isTombstone := obj.(cache.DeletedFinalStateUnknown)
+			if !isTombstone {
+				return
+			}
+			if u, ok = tombstone.Obj.(*unstructured.Unstructured); !ok {
+				return
+			}
+		}
+		if u.GetNamespace() == HIDDEN_NAMESPACE || utils.Contains(utils.CONFIG.Misc.IgnoreNamespaces, u.GetNamespace()) {
+			return
+		}
+		u = u.DeepCopy()
+		u.SetManagedFields(nil)
+		handler(K8sWatchEvent{
+			Type:      eventType,
+			ContextId: contextIdString(contextId),
+			Resource:  resource,
+			Namespace: u.GetNamespace(),
+			Object:    u.Object,
+		})
+	}
+
+	registration, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			send(WATCH_EVENT_ADDED, obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldU, oldOk := oldObj.(*unstructured.Unstructured)
+			newU, newOk := newObj.(*unstructured.Unstructured)
+			if oldOk && newOk && oldU.GetResourceVersion() == newU.GetResourceVersion() {
+				return
+			}
+			send(WATCH_EVENT_MODIFIED, newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			send(WATCH_EVENT_DELETED, obj)
+		},
+	})
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	return func() {
+		_ = informer.RemoveEventHandler(registration)
+		release()
+	}, nil
+}
@@ -6,6 +6,7 @@ import (
 	"io/fs"
 	"net/http"
 	"path"
+	"strings"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -23,6 +24,7 @@ var HtmlDirFs embed.FS
 func InitFrontend() {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
+	setTrustedProxies(router)
 	config := cors.DefaultConfig()
 	config.AllowAllOrigins = true
 	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "authorization", "x-context-id"}
@@ -44,12 +46,14 @@ func InitFrontend() {
 func InitBackend() {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
+	setTrustedProxies(router)
 	config := cors.DefaultConfig()
 	config.AllowAllOrigins = true
 	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "authorization", "x-context-id"}
 
 	router.Use(cors.New(config))
 	router.Use(CreateLogger("BACKEND"))
+	router.Use(Audit())
 
 	docs.SwaggerInfo.BasePath = "/"
 	docs.SwaggerInfo.Title = "punq API documentation"
@@ -60,8 +64,13 @@ func InitBackend() {
 	InitContextRoutes(router)
 	InitAuthRoutes(router)
 	InitUserRoutes(router)
+	InitMeRoutes(router)
+	InitGroupRoutes(router)
+	InitRoleRoutes(router)
+	InitScimRoutes(router)
 	InitGeneralRoutes(router)
 	InitWorkloadRoutes(router)
+	InitAuditRoutes(router)
 
 	utils.PrintInfo(fmt.Sprintf("Backend started:   http://%s:%d", utils.CONFIG.Backend.Host, utils.CONFIG.Backend.Port))
 	err := router.Run(fmt.Sprintf(":%d", utils.CONFIG.Backend.Port))
@@ -71,6 +80,7 @@ func InitBackend() {
 func InitWebsocket() {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
+	setTrustedProxies(router)
 	config := cors.DefaultConfig()
 	config.AllowAllOrigins = true
 
@@ -84,6 +94,17 @@ func InitWebsocket() {
 	logger.Log.Errorf("Websocket (gin) stopped with error: %s", err.Error())
 }
 
+// setTrustedProxies makes c.ClientIP() ignore X-Forwarded-For unless the connection comes from a configured proxy,
+// otherwise clients could choose their own address and escape the login throttling
+func setTrustedProxies(router *gin.Engine) {
+	proxies := utils.CONFIG.Backend.TrustedProxies
+	err := router.SetTrustedProxies(proxies)
+	if err != nil {
+		logger.Log.Errorf("Invalid trusted proxies (%s), no proxy is trusted: %s", strings.Join(proxies, ", "), err.Error())
+		_ = router.SetTrustedProxies(nil)
+	}
+}
+
 func embedFs() http.FileSystem {
 	sub, err := fs.Sub(HtmlDirFs, "ui/dist")
 	if err != nil {
@@ -0,0 +1,159 @@
+package operator
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/kubernetes"
+	"github.com/mogenius/punq/services"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+const AUDIT_MAX_MESSAGE_SIZE = 1024
+
+// routes which change nothing although they are not GET requests
+var AUDIT_IGNORED_PATHS = []string{"/auth/login", "/auth/login/2fa", "/auth/refresh", "/auth/ws-ticket", "/auth/password/forgot", "/context/validate-config"}
+
+// keeps the beginning of the response to record error messages
+type auditResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditResponseWriter) Write(data []byte) (int, error) {
+	if w.body.Len() < AUDIT_MAX_MESSAGE_SIZE {
+		w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Audit records every mutating request (POST, PUT, PATCH, DELETE) with user, context, resource and the diff
+// between the current state and the request body.
+func Audit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead || c.Request.Method == http.MethodOptions ||
+			c.FullPath() == "" || isAuditIgnored(c.FullPath()) {
+			c.Next()
+			return
+		}
+
+		// nothing is changed by rejected bodies, so they are not recorded
+		body, err := readRequestBody(c)
+		if err != nil {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"err": fmt.Sprintf("Request body could not be read: %s", err.Error())})
+			c.Abort()
+			return
+		}
+
+		requested := map[string]interface{}{}
+		if len(body) > 0 {
+			if err := yaml.Unmarshal(body, &requested); err != nil {
+				requested = map[string]interface{}{"body": string(body)}
+			}
+		}
+
+		kind, namespace, name := auditResourceFor(c, requested)
+		contextId := services.GetGinContextId(c)
+
+		// the state before the request (only kubernetes objects can be compared)
+		var current map[string]interface{}
+		if c.Request.Method != http.MethodPost && strings.HasPrefix(c.FullPath(), "/workload/") && name != "" {
+			if groupKind, ok := auditGroupKindFor(requested, kind); ok {
+				if obj, err := kubernetes.GetK8sUnstructured(groupKind, namespace, name, contextId); err == nil {
+					current = obj.Object
+				}
+			}
+		}
+
+		writer := &auditResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		entry := dtos.PunqAuditEntry{
+			Action:     c.Request.Method,
+			Path:       c.Request.URL.Path,
+			Kind:       kind,
+			Namespace:  namespace,
+			Name:       name,
+			StatusCode: writer.Status(),
+			Result:     dtos.AUDIT_RESULT_SUCCESS,
+		}
+		if contextId != nil {
+			entry.ContextId = *contextId
+		}
+		if user := services.GetGinContextUser(c); user != nil {
+			entry.UserId = user.Id
+			entry.UserEmail = user.Email
+		} else if actor := c.GetString("auditActor"); actor != "" {
+			// requests without punq user, e.g. of the identity provider via SCIM
+			entry.UserEmail = actor
+		}
+		if c.Request.Method == http.MethodDelete {
+			entry.Diff = services.AuditDiff(current, nil)
+		} else if len(requested) > 0 {
+			entry.Diff = services.AuditDiff(current, requested)
+		}
+		if writer.Status() >= http.StatusBadRequest {
+			entry.Result = dtos.AUDIT_RESULT_FAILURE
+			entry.Message = writer.body.String()
+		}
+		services.RecordAudit(entry)
+	}
+}
+
+func isAuditIgnored(path string) bool {
+	for _, ignored := range AUDIT_IGNORED_PATHS {
+		if path == ignored {
+			return true
+		}
+	}
+	return false
+}
+
+// auditResourceFor takes kind/namespace/name from the kubernetes object in the body or from the route
+func auditResourceFor(c *gin.Context, requested map[string]interface{}) (string, string, string) {
+	kind, _ := requested["kind"].(string)
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	if name == "" {
+		name = c.Param("id")
+	}
+	if metadata, ok := requested["metadata"].(map[string]interface{}); ok {
+		if value, ok := metadata["namespace"].(string); ok && value != "" {
+			namespace = value
+		}
+		if value, ok := metadata["name"].(string); ok && value != "" {
+			name = value
+		}
+	}
+
+	if kind == "" {
+		// "/workload/<resource>/..." or "/<group>/..."
+		segments := strings.Split(strings.Trim(c.FullPath(), "/"), "/")
+		if len(segments) > 1 && segments[0] == "workload" {
+			kind = kubernetes.ResourceFor(segments[1])
+			if segments[1] == "custom" {
+				kind = c.Param("resource")
+			}
+		} else if len(segments) > 2 && segments[0] == "scim" {
+			// "/scim/v2/Users/:id"
+			kind = fmt.Sprintf("scim/%s", segments[2])
+		} else if len(segments) > 0 {
+			kind = segments[0]
+		}
+	}
+	return kind, namespace, name
+}
+
+func auditGroupKindFor(requested map[string]interface{}, kind string) (schema.GroupKind, bool) {
+	if apiVersion, ok := requested["apiVersion"].(string); ok && apiVersion != "" {
+		return schema.FromAPIVersionAndKind(apiVersion, kind).GroupKind(), true
+	}
+	groupKind, ok := kubernetes.DESCRIBE_GROUP_KINDS[kind]
+	return groupKind, ok
+}
@@ -0,0 +1,28 @@
+package operator
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAuditBodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handled := false
+	router := gin.New()
+	router.Use(Audit())
+	router.POST("/workload/pod", func(c *gin.Context) {
+		handled = true
+	})
+	body := `{"data":"` + strings.Repeat("a", REQUEST_MAX_BODY_SIZE) + `"}`
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/workload/pod", strings.NewReader(body)))
+
+	if recorder.Code != http.StatusRequestEntityTooLarge || handled {
+		t.Errorf("Audit() status = %d, handled = %t, want %d and not handled", recorder.Code, handled, http.StatusRequestEntityTooLarge)
+	}
+}
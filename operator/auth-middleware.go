@@ -1,15 +1,22 @@
 package operator
 
 import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"regexp"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/kubernetes"
 	"github.com/mogenius/punq/logger"
 	"github.com/mogenius/punq/services"
 	"github.com/mogenius/punq/utils"
+	"sigs.k8s.io/yaml"
 )
 
 // var users []dtos.PunqUser = []dtos.PunqUser{}
@@ -42,8 +49,7 @@ func Auth(requiredAccessLevel dtos.AccessLevel) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		isAuthorized, err := HasSufficientAccess(c, requiredAccessLevel)
 		if err != nil {
-			utils.Unauthorized(c, err.Error())
-			c.Abort()
+			abortForAuthError(c, err)
 			return
 		}
 
@@ -53,9 +59,10 @@ func Auth(requiredAccessLevel dtos.AccessLevel) gin.HandlerFunc {
 	}
 }
 
-func AuthByParameter(requiredAccessLevel dtos.AccessLevel) gin.HandlerFunc {
+// AuthByTicket authenticates websocket requests with the one-time ?ticket= of POST /auth/ws-ticket
+func AuthByTicket(requiredAccessLevel dtos.AccessLevel) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		isAuthorized, err := HasSufficientAccessByParameter(c, requiredAccessLevel)
+		isAuthorized, err := HasSufficientAccessByTicket(c, requiredAccessLevel)
 		if err != nil {
 			utils.Unauthorized(c, err.Error())
 			c.Abort()
@@ -68,6 +75,129 @@ func AuthByParameter(requiredAccessLevel dtos.AccessLevel) gin.HandlerFunc {
 	}
 }
 
+// AuthorizeExec checks the "exec" verb for pods in the namespace of the shell session (after AuthByTicket)
+func AuthorizeExec() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := services.GetGinContextUser(c)
+		if user == nil {
+			utils.Unauthorized(c, "user not found")
+			c.Abort()
+			return
+		}
+		_, err := services.Authorize(user, services.GetGinContextId(c), dtos.ROLE_VERB_EXEC, kubernetes.RES_POD, c.Query("namespace"))
+		if err != nil {
+			utils.Unauthorized(c, err.Error())
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// ScimAuth accepts the bearer token of the identity provider (scim.token) on the /scim/v2 routes. Its requests are
+// audited as "scim".
+func ScimAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !utils.CONFIG.Scim.Enabled || utils.CONFIG.Scim.Token == "" {
+			scimRespondError(c, &services.ScimError{Status: http.StatusNotFound, Detail: "SCIM is disabled"})
+			c.Abort()
+			return
+		}
+		authorization, err := parseAuthHeader(c.GetHeader("authorization"))
+		if err != nil || !strings.EqualFold(authorization.Scheme, "bearer") ||
+			subtle.ConstantTimeCompare([]byte(authorization.Value), []byte(utils.CONFIG.Scim.Token)) != 1 {
+			scimRespondError(c, &services.ScimError{Status: http.StatusUnauthorized, Detail: "invalid SCIM token"})
+			c.Abort()
+			return
+		}
+		c.Set("auditActor", "scim")
+		c.Next()
+	}
+}
+
+// Authorize checks the request against the roles of the user (see services.Authorize). Every workload route group
+// uses it with the kind it manages; verb and namespace are taken from the request.
+func Authorize(kind string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, err := CheckUserAuthorization(c)
+		if err != nil {
+			abortForAuthError(c, err)
+			return
+		}
+
+		namespace, err := requestNamespace(c)
+		if err != nil {
+			abortForAuthError(c, err)
+			return
+		}
+		accessLevel, err := services.Authorize(user, services.GetGinContextId(c), workloadVerbFor(c), kind, namespace)
+		if err != nil {
+			utils.Unauthorized(c, err.Error())
+			c.Abort()
+			return
+		}
+		c.Set("user", *user)
+		c.Set("accessLevel", accessLevel)
+		c.Next()
+	}
+}
+
+// AuthorizeCustomResource only lets custom resources through the generic /workload/custom routes (after Authorize),
+// otherwise they would bypass the rules of every built-in kind. Cluster-scoped custom resources are checked again
+// without namespace, because namespace patterns of roles and api tokens do not apply to them.
+func AuthorizeCustomResource() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := services.GetGinContextUser(c)
+		if user == nil {
+			utils.Unauthorized(c, "user not found")
+			c.Abort()
+			return
+		}
+		namespaced, err := kubernetes.CustomResourceNamespaced(c.Param("group"), c.Param("version"), c.Param("resource"), services.GetGinContextId(c))
+		if err != nil {
+			utils.MalformedMessage(c, err.Error())
+			c.Abort()
+			return
+		}
+		if !namespaced {
+			_, err = services.Authorize(user, services.GetGinContextId(c), workloadVerbFor(c), kubernetes.RES_CUSTOM_RESOURCE, "")
+			if err == nil {
+				if token := getGinContextApiToken(c); token != nil {
+					err = token.Allows(apiTokenVerbFor(c), ginContextIdString(c), "")
+				}
+			}
+			if err != nil {
+				utils.Unauthorized(c, err.Error())
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+func workloadVerbFor(c *gin.Context) string {
+	path := c.FullPath()
+	switch {
+	case strings.Contains(path, "/logs"):
+		return dtos.ROLE_VERB_LOGS
+	case strings.Contains(path, "/restart/"):
+		return dtos.ROLE_VERB_RESTART
+	case strings.Contains(path, "/rollback/"):
+		return dtos.ROLE_VERB_UPDATE
+	}
+	switch c.Request.Method {
+	case http.MethodPost:
+		return dtos.ROLE_VERB_CREATE
+	case http.MethodPatch, http.MethodPut:
+		return dtos.ROLE_VERB_UPDATE
+	case http.MethodDelete:
+		return dtos.ROLE_VERB_DELETE
+	default:
+		return dtos.ROLE_VERB_GET
+	}
+}
+
 // func updateLocalUserStore() {
 // 	if time.Now().After(nextUpdate) {
 // 		users = services.ListUsers()
@@ -87,11 +217,16 @@ func CheckUserAuthorization(c *gin.Context) (*dtos.PunqUser, error) {
 		return nil, err
 	}
 
+	if strings.HasPrefix(authorization.Value, dtos.API_TOKEN_PREFIX) {
+		return checkApiTokenAuthorization(c, authorization.Value)
+	}
+
 	claims, err := services.ValidationToken(authorization.Value)
 	if err != nil {
 		return nil, err
 	}
 	userId := claims.UserID
+	c.Set("sessionId", claims.SessionID)
 
 	// updateLocalUserStore()
 
@@ -104,59 +239,201 @@ func CheckUserAuthorization(c *gin.Context) (*dtos.PunqUser, error) {
 	if err != nil {
 		return nil, err
 	}
+	if user.Disabled {
+		return nil, services.ErrUserDisabled
+	}
 	return user, nil
 }
 
-func CheckUserAuthorizationByParameter(c *gin.Context) (*dtos.PunqUser, error) {
-	token, tokenOk := c.GetQuery("token")
-	if !tokenOk || token == "" {
-		return nil, fmt.Errorf("missing header 'authorization'")
+func CheckUserAuthorizationByTicket(c *gin.Context) (*dtos.PunqUser, error) {
+	ticket, ticketOk := c.GetQuery("ticket")
+	if !ticketOk || ticket == "" {
+		return nil, fmt.Errorf("missing query parameter 'ticket'")
 	}
 
-	claims, err := services.ValidationToken(token)
+	contextId := ginContextIdString(c)
+	grant, err := services.RedeemWsTicket(ticket, contextId, dtos.PunqWsTicketInput{
+		Namespace: c.Query("namespace"),
+		PodName:   c.Query("podname"),
+		Container: c.Query("container"),
+		Mode:      c.Query("mode"),
+		Image:     c.Query("image"),
+	})
 	if err != nil {
 		return nil, err
 	}
-	userId := claims.UserID
+	if grant.SessionId != "" {
+		c.Set("sessionId", grant.SessionId)
+	}
+	if grant.ApiToken != nil {
+		c.Set("apiTokenId", grant.ApiToken.Id)
+		c.Set("apiToken", *grant.ApiToken)
+	}
+	return grant.User, nil
+}
 
-	getGinContextUser := services.GetGinContextUser(c)
-	if getGinContextUser != nil && getGinContextUser.Id == userId {
-		return getGinContextUser, nil
+// getGinContextApiToken returns the api token of the request or the one a websocket ticket was issued with (nil =
+// session of the user)
+func getGinContextApiToken(c *gin.Context) *dtos.PunqApiToken {
+	if value, exists := c.Get("apiToken"); exists {
+		if token, ok := value.(dtos.PunqApiToken); ok {
+			return &token
+		}
 	}
+	return nil
+}
 
-	user, err := services.GetUser(userId)
+// checkApiTokenAuthorization resolves the user of a personal api token and checks the scope of the token for the request
+func checkApiTokenAuthorization(c *gin.Context, value string) (*dtos.PunqUser, error) {
+	token, user, err := services.ValidateApiToken(value)
 	if err != nil {
 		return nil, err
 	}
+	if !apiTokenRouteAllowed(c.FullPath()) {
+		return nil, fmt.Errorf("api tokens can only be used for workloads, everything else requires a login")
+	}
+
+	contextId := ginContextIdString(c)
+	namespace, err := requestNamespace(c)
+	if err != nil {
+		return nil, err
+	}
+	err = token.Allows(apiTokenVerbFor(c), contextId, namespace)
+	if err != nil {
+		return nil, err
+	}
+	c.Set("apiTokenId", token.Id)
+	c.Set("apiToken", *token)
 	return user, nil
 }
 
+// apiTokenRouteAllowed is true for the routes api tokens can be used for. Their scope only covers workloads, so a
+// token restricted to one context or namespace must not reach users, roles, groups, contexts, the own password or
+// other tokens and 2fa.
+func apiTokenRouteAllowed(path string) bool {
+	return strings.HasPrefix(path, "/workload/") || path == "/auth/ws-ticket"
+}
+
+// ginContextIdString returns the requested context id ("" = none)
+func ginContextIdString(c *gin.Context) string {
+	if id := services.GetGinContextId(c); id != nil {
+		return *id
+	}
+	return ""
+}
+
+func apiTokenVerbFor(c *gin.Context) string {
+	// a ticket grants nothing by itself, the scope of the token is checked when it is redeemed
+	if c.FullPath() == "/auth/ws-ticket" {
+		return dtos.API_TOKEN_VERB_GET
+	}
+	switch c.Request.Method {
+	case http.MethodPost:
+		return dtos.API_TOKEN_VERB_CREATE
+	case http.MethodPatch, http.MethodPut:
+		return dtos.API_TOKEN_VERB_UPDATE
+	case http.MethodDelete:
+		return dtos.API_TOKEN_VERB_DELETE
+	default:
+		return dtos.API_TOKEN_VERB_GET
+	}
+}
+
+// errInvalidRequestNamespace is answered with 400, the request is not unauthorized but ambiguous
+var errInvalidRequestNamespace = errors.New("invalid request namespace")
+
+// requestNamespace takes the namespace from the route, the query or the kubernetes object in the body ("" = none).
+// Handlers with a body act on the namespace of the object, so a different route or query namespace is rejected.
+func requestNamespace(c *gin.Context) (string, error) {
+	isNamespaceRoute := strings.HasPrefix(c.FullPath(), "/workload/namespace")
+	namespace := c.Param("namespace")
+	if namespace == "" && isNamespaceRoute {
+		namespace = c.Param("name")
+	}
+	if query := c.Query("namespace"); query != "" {
+		if namespace != "" && namespace != query {
+			return "", fmt.Errorf("%w: namespace '%s' of the route differs from '%s' of the query", errInvalidRequestNamespace, namespace, query)
+		}
+		namespace = query
+	}
+
+	body, err := readRequestBody(c)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", errInvalidRequestNamespace, err.Error())
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return namespace, nil
+	}
+
+	requested := struct {
+		Metadata struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+	}{}
+	if err := decodeBody(body, &requested); err != nil {
+		return "", fmt.Errorf("%w: %s", errInvalidRequestNamespace, err.Error())
+	}
+	bodyNamespace := requested.Metadata.Namespace
+	if isNamespaceRoute {
+		bodyNamespace = requested.Metadata.Name
+	}
+	if namespace != "" && namespace != bodyNamespace {
+		return "", fmt.Errorf("%w: namespace '%s' of the request differs from '%s' of the object", errInvalidRequestNamespace, namespace, bodyNamespace)
+	}
+	return bodyNamespace, nil
+}
+
+// decodeBody parses json bodies like binding.JSON and yaml bodies like k8sYamlBinding, so authorization and handler
+// always see the same object
+func decodeBody(body []byte, obj interface{}) error {
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '{' {
+		return json.Unmarshal(body, obj)
+	}
+	return yaml.Unmarshal(body, obj)
+}
+
+// abortForAuthError answers ambiguous requests with 400 and everything else with 401
+func abortForAuthError(c *gin.Context, err error) {
+	if errors.Is(err, errInvalidRequestNamespace) {
+		utils.MalformedMessage(c, err.Error())
+	} else {
+		utils.Unauthorized(c, err.Error())
+	}
+	c.Abort()
+}
+
 func HasSufficientAccess(c *gin.Context, requiredAccessLevel dtos.AccessLevel) (bool, error) {
 	user, err := CheckUserAuthorization(c)
 	if err != nil {
 		return false, err
 	}
-	if user != nil {
-		if user.AccessLevel >= requiredAccessLevel {
-			c.Set("user", *user)
-			return true, err
-		}
+	return hasSufficientAccessForContext(c, user, requiredAccessLevel)
+}
+
+func HasSufficientAccessByTicket(c *gin.Context, requiredAccessLevel dtos.AccessLevel) (bool, error) {
+	user, err := CheckUserAuthorizationByTicket(c)
+	if err != nil {
+		return false, err
 	}
-	errStr := fmt.Sprintf("AccessLevel is insufficient (Current:%d - Required:%d).", user.AccessLevel, requiredAccessLevel)
-	return false, fmt.Errorf(errStr)
+	return hasSufficientAccessForContext(c, user, requiredAccessLevel)
 }
 
-func HasSufficientAccessByParameter(c *gin.Context, requiredAccessLevel dtos.AccessLevel) (bool, error) {
-	user, err := CheckUserAuthorizationByParameter(c)
+// the effective access level is the combination of the global user level and the access list of the requested context
+func hasSufficientAccessForContext(c *gin.Context, user *dtos.PunqUser, requiredAccessLevel dtos.AccessLevel) (bool, error) {
+	if user == nil {
+		return false, fmt.Errorf("user not found")
+	}
+
+	accessLevel, err := services.AccessLevelForContext(user, services.GetGinContextId(c))
 	if err != nil {
 		return false, err
 	}
-	if user != nil {
-		if user.AccessLevel >= requiredAccessLevel {
-			c.Set("user", *user)
-			return true, err
-		}
+
+	if accessLevel >= requiredAccessLevel {
+		c.Set("user", *user)
+		c.Set("accessLevel", accessLevel)
+		return true, nil
 	}
-	errStr := fmt.Sprintf("AccessLevel is insufficient (Current:%d - Required:%d).", user.AccessLevel, requiredAccessLevel)
-	return false, fmt.Errorf(errStr)
+	return false, fmt.Errorf("AccessLevel is insufficient (Current:%d - Required:%d).", accessLevel, requiredAccessLevel)
 }
@@ -0,0 +1,155 @@
+package operator
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestNamespace(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		route   string
+		method  string
+		url     string
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{"route", "/workload/pod/describe/:namespace/:name", http.MethodGet, "/workload/pod/describe/team-a/web", "", "team-a", false},
+		{"query", "/workload/pod", http.MethodGet, "/workload/pod?namespace=team-a", "", "team-a", false},
+		{"none", "/workload/node", http.MethodGet, "/workload/node", "", "", false},
+		{"route and query agree", "/workload/pod/describe/:namespace/:name", http.MethodGet, "/workload/pod/describe/team-a/web?namespace=team-a", "", "team-a", false},
+		{"route and query differ", "/workload/pod/describe/:namespace/:name", http.MethodGet, "/workload/pod/describe/team-a/web?namespace=team-b", "", "", true},
+		{"json body", "/workload/pod", http.MethodPost, "/workload/pod", `{"metadata":{"name":"web","namespace":"team-a"}}`, "team-a", false},
+		{"yaml body", "/workload/pod", http.MethodPost, "/workload/pod", "metadata:\n  name: web\n  namespace: team-a\n", "team-a", false},
+		{"empty body", "/workload/pod", http.MethodPost, "/workload/pod?namespace=team-a", "  \n", "team-a", false},
+		{"query and body agree", "/workload/pod", http.MethodPatch, "/workload/pod?namespace=team-a", `{"metadata":{"name":"web","namespace":"team-a"}}`, "team-a", false},
+		{"query and body differ", "/workload/pod", http.MethodPatch, "/workload/pod?namespace=team-a", `{"metadata":{"name":"web","namespace":"team-b"}}`, "", true},
+		{"body without namespace", "/workload/pod", http.MethodPatch, "/workload/pod?namespace=team-a", `{"metadata":{"name":"web"}}`, "", true},
+		{"invalid body", "/workload/pod", http.MethodPost, "/workload/pod", "{not json", "", true},
+		{"namespace route", "/workload/namespace/:name", http.MethodDelete, "/workload/namespace/team-a", "", "team-a", false},
+		{"namespace route body", "/workload/namespace", http.MethodPost, "/workload/namespace", `{"metadata":{"name":"team-a"}}`, "team-a", false},
+		{"namespace route body differs", "/workload/namespace/:name", http.MethodPatch, "/workload/namespace/team-a", "metadata:\n  name: team-b\n", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			var gotErr error
+			var gotBody string
+			router := gin.New()
+			router.Handle(tt.method, tt.route, func(c *gin.Context) {
+				got, gotErr = requestNamespace(c)
+				body, _ := io.ReadAll(c.Request.Body)
+				gotBody = string(body)
+			})
+
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			if tt.wantErr {
+				if !errors.Is(gotErr, errInvalidRequestNamespace) {
+					t.Fatalf("requestNamespace() error = %v, want %v", gotErr, errInvalidRequestNamespace)
+				}
+				return
+			}
+			if gotErr != nil {
+				t.Fatalf("requestNamespace() error = %v", gotErr)
+			}
+			if got != tt.want {
+				t.Errorf("requestNamespace() = '%s', want '%s'", got, tt.want)
+			}
+			if gotBody != tt.body {
+				t.Errorf("handler body = '%s', want '%s'", gotBody, tt.body)
+			}
+		})
+	}
+}
+
+func TestDecodeBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{"json", `{"metadata":{"namespace":"team-a"}}`, "team-a", false},
+		{"json with leading whitespace", "\n  {\"metadata\":{\"namespace\":\"team-a\"}}", "team-a", false},
+		{"yaml", "metadata:\n  namespace: team-a\n", "team-a", false},
+		{"yaml uses json tags", "metadata: {namespace: team-a}", "team-a", false},
+		{"invalid json", `{"metadata":`, "", true},
+		{"invalid yaml", "metadata: [", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := struct {
+				Metadata struct {
+					Namespace string `json:"namespace"`
+				} `json:"metadata"`
+			}{}
+			err := decodeBody([]byte(tt.body), &obj)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeBody() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if err == nil && obj.Metadata.Namespace != tt.want {
+				t.Errorf("decodeBody() namespace = '%s', want '%s'", obj.Metadata.Namespace, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestNamespaceBodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var gotErr error
+	router := gin.New()
+	router.POST("/workload/pod", func(c *gin.Context) {
+		_, gotErr = requestNamespace(c)
+	})
+	body := `{"metadata":{"namespace":"team-a"},"data":"` + strings.Repeat("a", REQUEST_MAX_BODY_SIZE) + `"}`
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/workload/pod", strings.NewReader(body)))
+
+	if !errors.Is(gotErr, errInvalidRequestNamespace) {
+		t.Errorf("requestNamespace() error = %v, want %v", gotErr, errInvalidRequestNamespace)
+	}
+}
+
+func TestApiTokenRouteAllowed(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/workload/pod", true},
+		{"/workload/pod/describe/:namespace/:name", true},
+		{"/workload/custom/:group/:version/:resource", true},
+		{"/auth/ws-ticket", true},
+		{"/workload", false},
+		{"/user/", false},
+		{"/user/invite", false},
+		{"/user/:id/deactivate", false},
+		{"/user/tokens", false},
+		{"/user/2fa/enroll", false},
+		{"/role/", false},
+		{"/group/", false},
+		{"/context", false},
+		{"/context/all", false},
+		{"/me/password", false},
+		{"/auth/logout", false},
+		{"/audit", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := apiTokenRouteAllowed(tt.path); got != tt.want {
+				t.Errorf("apiTokenRouteAllowed(%s) = %t, want %t", tt.path, got, tt.want)
+			}
+		})
+	}
+}
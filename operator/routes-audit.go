@@ -0,0 +1,35 @@
+package operator
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/services"
+)
+
+func InitAuditRoutes(router *gin.Engine) {
+	router.GET("/audit", Auth(dtos.ADMIN), auditList)
+}
+
+// @Tags Audit
+// @Produce json
+// @Success 200 {array} dtos.PunqAuditEntry
+// @Router /backend/audit [get]
+// @Param user query string false "user id or email"
+// @Param context query string false "context id"
+// @Param kind query string false "resource kind"
+// @Param namespace query string false "namespace"
+// @Param limit query int false "max number of entries (newest first)"
+// @Security Bearer
+func auditList(c *gin.Context) {
+	filter := dtos.PunqAuditFilter{
+		User:      c.Query("user"),
+		ContextId: c.Query("context"),
+		Kind:      c.Query("kind"),
+		Namespace: c.Query("namespace"),
+	}
+	filter.Limit, _ = strconv.Atoi(c.Query("limit"))
+	c.JSON(http.StatusOK, services.ListAuditEntries(filter))
+}
@@ -1,7 +1,13 @@
 package operator
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
@@ -10,7 +16,21 @@ import (
 	"github.com/mogenius/punq/utils"
 )
 
+// holds the encrypted pending OIDC login (state, PKCE verifier and nonce) in the browser which started it
+const OIDC_STATE_COOKIE = "punq_oidc_state"
+
+type RefreshInput struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+type TwoFactorLoginInput struct {
+	Challenge string `json:"challenge" binding:"required"`
+	// code of the authenticator app or recovery code
+	Code string `json:"code" binding:"required"`
+}
+
 type LoginInput struct {
+	// email of local users, LDAP users can also use their login name (see ldap.user_filter)
 	Email    string `json:"email" binding:"required"`
 	Password string `json:"password" binding:"required"`
 }
@@ -20,14 +40,24 @@ func InitAuthRoutes(router *gin.Engine) {
 	authRoutes := router.Group("/auth")
 	{
 		authRoutes.POST("/login", login)
+		authRoutes.POST("/login/2fa", loginTwoFactor)
 		authRoutes.GET("/authenticate", Auth(dtos.READER), authenticate)
+		authRoutes.POST("/refresh", refresh)
+		authRoutes.POST("/logout", Auth(dtos.READER), logout)
+		authRoutes.POST("/ws-ticket", Auth(dtos.READER), wsTicket)
+		authRoutes.POST("/password/forgot", passwordForgot)
+		authRoutes.POST("/password/reset", passwordReset)
+		authRoutes.GET("/oidc/login", oidcLogin)
+		authRoutes.GET("/oidc/callback", oidcCallback)
+		authRoutes.GET("/jwks", jwks)
 	}
 
 }
 
 // @Tags Auth
 // @Produce json
-// @Success 200 {object} dtos.PunqToken
+// @Success 200 {object} dtos.PunqToken "or dtos.PunqTwoFactorChallenge if 2FA is required"
+// @Failure 429 "too many failed login attempts (see Retry-After header)"
 // @Router /backend/auth/login [post]
 // @Param body body LoginInput true "LoginInput"
 func login(c *gin.Context) {
@@ -39,20 +69,93 @@ func login(c *gin.Context) {
 		return
 	}
 
-	user, err := services.GetUserByEmail(input.Email)
+	// unknown users and wrong passwords get the same response and are throttled the same way
+	err = services.CheckLogin(input.Email, c.ClientIP())
+	if err != nil {
+		loginThrottled(c, err)
+		return
+	}
+	defer services.FinishLogin(input.Email, c.ClientIP())
 
+	// local users and users of the enabled providers (e.g. LDAP) can sign in
+	user, err := services.Authenticate(input.Email, input.Password)
 	if err != nil {
-		utils.NotFound(c, err.Error())
+		services.RecordFailedLogin(input.Email, c.ClientIP())
+		utils.Unauthorized(c, err.Error())
 		return
 	}
 
-	_, err = user.PasswordCheck(input.Password)
+	// failures are reset after the second factor, otherwise the password could be used to reset the backoff for guessing codes
+	if services.TwoFactorRequired(user) {
+		challenge, err := services.CreateTwoFactorChallenge(user)
+		if err != nil {
+			utils.Unauthorized(c, err.Error())
+			return
+		}
+		c.JSON(http.StatusOK, challenge)
+		return
+	}
+	services.RecordSuccessfulLogin(input.Email)
+
+	token, err := services.CreateSession(user, sessionClient(c))
+	if err != nil {
+		utils.Unauthorized(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, token)
+}
+
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} dtos.PunqToken
+// @Failure 429 "too many failed login attempts (see Retry-After header)"
+// @Router /backend/auth/login/2fa [post]
+// @Param body body TwoFactorLoginInput true "TwoFactorLoginInput"
+func loginTwoFactor(c *gin.Context) {
+	input := TwoFactorLoginInput{}
+
+	err := c.MustBindWith(&input, binding.JSON)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+		return
+	}
+
+	token, err := services.CompleteTwoFactorChallenge(input.Challenge, input.Code, sessionClient(c))
+	if err != nil {
+		var throttled *services.LoginThrottledError
+		if errors.As(err, &throttled) {
+			loginThrottled(c, err)
+			return
+		}
+		utils.Unauthorized(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, token)
+}
+
+func loginThrottled(c *gin.Context, err error) {
+	var throttled *services.LoginThrottledError
+	if errors.As(err, &throttled) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+	}
+	c.JSON(http.StatusTooManyRequests, gin.H{"err": err.Error()})
+}
+
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} dtos.PunqToken
+// @Router /backend/auth/refresh [post]
+// @Param body body RefreshInput true "RefreshInput"
+func refresh(c *gin.Context) {
+	input := RefreshInput{}
+
+	err := c.MustBindWith(&input, binding.JSON)
 	if err != nil {
-		utils.Unauthorized(c, "username or password is incorrect")
+		c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
 		return
 	}
 
-	token, err := services.GenerateToken(user)
+	token, err := services.RefreshSession(input.RefreshToken)
 	if err != nil {
 		utils.Unauthorized(c, err.Error())
 		return
@@ -60,6 +163,106 @@ func login(c *gin.Context) {
 	c.JSON(http.StatusOK, token)
 }
 
+// @Tags Auth
+// @Produce json
+// @Success 200 "always, so accounts cannot be enumerated"
+// @Router /backend/auth/password/forgot [post]
+// @Param body body dtos.PunqPasswordForgotInput true "PunqPasswordForgotInput"
+func passwordForgot(c *gin.Context) {
+	input := dtos.PunqPasswordForgotInput{}
+
+	err := c.MustBindWith(&input, binding.JSON)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+		return
+	}
+
+	err = services.RequestPasswordReset(input.Email)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"err": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "If the account exists, a link to reset the password has been sent."})
+}
+
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} dtos.PunqUser
+// @Router /backend/auth/password/reset [post]
+// @Param body body dtos.PunqPasswordResetInput true "PunqPasswordResetInput"
+func passwordReset(c *gin.Context) {
+	input := dtos.PunqPasswordResetInput{}
+
+	err := c.MustBindWith(&input, binding.JSON)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+		return
+	}
+
+	user, err := services.ResetPassword(input.Token, input.Password)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPasswordToken) {
+			utils.Unauthorized(c, err.Error())
+			return
+		}
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// @Tags Auth
+// @Produce json
+// @Success 200
+// @Router /backend/auth/logout [post]
+// @Security Bearer
+func logout(c *gin.Context) {
+	user := services.GetGinContextUser(c)
+	sessionId := services.GetGinContextSessionId(c)
+	if user == nil || sessionId == "" {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	err := services.RevokeSession(user.Id, sessionId)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out."})
+}
+
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} dtos.PunqWsTicket
+// @Router /backend/auth/ws-ticket [post]
+// @Param body body dtos.PunqWsTicketInput true "PunqWsTicketInput"
+// @Security Bearer
+func wsTicket(c *gin.Context) {
+	user := services.GetGinContextUser(c)
+	if user == nil {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	input := dtos.PunqWsTicketInput{}
+	err := c.MustBindWith(&input, binding.JSON)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+
+	contextId := ""
+	if id := services.GetGinContextId(c); id != nil {
+		contextId = *id
+	}
+	ticket, err := services.CreateWsTicket(user.Id, services.GetGinContextSessionId(c), c.GetString("apiTokenId"), contextId, input)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, ticket)
+}
+
 // @Tags Auth
 // @Produce json
 // @Success 200 {object} dtos.PunqToken
@@ -68,8 +271,102 @@ func login(c *gin.Context) {
 func authenticate(c *gin.Context) {
 	user := services.GetGinContextUser(c)
 	if user != nil {
-		c.JSON(http.StatusOK, user)
+		c.JSON(http.StatusOK, user.Redacted())
 		return
 	}
 	utils.Unauthorized(c, "Unauthorized")
 }
+
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} dtos.PunqJwks
+// @Router /backend/auth/jwks [get]
+func jwks(c *gin.Context) {
+	keySet, err := services.JwtKeySet()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"err": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, keySet)
+}
+
+// @Tags Auth
+// @Produce json
+// @Success 302
+// @Router /backend/auth/oidc/login [get]
+func oidcLogin(c *gin.Context) {
+	if !services.OidcEnabled() {
+		utils.NotFound(c, "OIDC login is not enabled.")
+		return
+	}
+
+	loginUrl, sealedLogin, err := services.OidcLoginUrl()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"err": err.Error()})
+		return
+	}
+	// binds the login to this browser, otherwise an attacker could make a victim complete the attacker's login
+	setOidcStateCookie(c, sealedLogin, int(services.OIDC_LOGIN_TIMEOUT.Seconds()))
+	c.Redirect(http.StatusFound, loginUrl)
+}
+
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} dtos.PunqToken
+// @Router /backend/auth/oidc/callback [get]
+// @Param state query string true "state of the login"
+// @Param code query string true "authorization code"
+func oidcCallback(c *gin.Context) {
+	if !services.OidcEnabled() {
+		utils.NotFound(c, "OIDC login is not enabled.")
+		return
+	}
+
+	if providerError := c.Query("error"); providerError != "" {
+		utils.Unauthorized(c, fmt.Sprintf("%s %s", providerError, c.Query("error_description")))
+		return
+	}
+	state := c.Query("state")
+	code := c.Query("code")
+	if state == "" || code == "" {
+		utils.MalformedMessage(c, "Missing query parameter 'state' or 'code'.")
+		return
+	}
+	// the cookie is dropped and the provider accepts every code only once, so a login cannot be completed twice
+	sealedLogin, err := c.Cookie(OIDC_STATE_COOKIE)
+	setOidcStateCookie(c, "", -1)
+	if err != nil || sealedLogin == "" {
+		utils.Unauthorized(c, "The login was started in another browser. Please try again.")
+		return
+	}
+
+	token, err := services.OidcLogin(c.Request.Context(), state, code, sealedLogin, sessionClient(c))
+	if err != nil {
+		utils.Unauthorized(c, err.Error())
+		return
+	}
+
+	// the token is passed in the fragment so it is not sent to any server or written to access logs
+	if utils.CONFIG.Oidc.FrontendUrl != "" {
+		fragment := url.Values{}
+		fragment.Set("token", token.Token)
+		fragment.Set("refreshToken", token.RefreshToken)
+		fragment.Set("expiresIn", fmt.Sprint(token.ExpiresIn))
+		c.Redirect(http.StatusFound, fmt.Sprintf("%s#%s", utils.CONFIG.Oidc.FrontendUrl, fragment.Encode()))
+		return
+	}
+	c.JSON(http.StatusOK, token)
+}
+
+// the cookie has to be sent with the top-level redirect of the provider, so it uses SameSite=Lax
+func setOidcStateCookie(c *gin.Context, state string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(OIDC_STATE_COOKIE, state, maxAge, "/", "", strings.HasPrefix(utils.CONFIG.Oidc.RedirectUrl, "https://"), true)
+}
+
+func sessionClient(c *gin.Context) dtos.PunqSessionClient {
+	return dtos.PunqSessionClient{
+		UserAgent: c.Request.UserAgent(),
+		ClientIp:  c.ClientIP(),
+	}
+}
@@ -10,21 +10,24 @@ import (
 	"github.com/mogenius/punq/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/mogenius/punq/dtos"
 	"github.com/mogenius/punq/services"
 )
 
 func InitContextRoutes(router *gin.Engine) {
 
-	contextRoutes := router.Group("/context", Auth(dtos.ADMIN))
+	contextRoutes := router.Group("/context")
 	{
-		contextRoutes.GET("/all", Auth(dtos.ADMIN), allContexts)
+		contextRoutes.GET("/all", Auth(dtos.READER), allContexts)
+		contextRoutes.GET("/health", Auth(dtos.READER), contextHealth)
 		contextRoutes.GET("/info", Auth(dtos.ADMIN), RequireContextId(), getInfoContexts)
 		contextRoutes.GET("", Auth(dtos.ADMIN), RequireContextId(), getContext)
 		contextRoutes.DELETE("", Auth(dtos.ADMIN), RequireContextId(), deleteContext)
 		contextRoutes.POST("/validate-config", Auth(dtos.ADMIN), validateConfig)
 		contextRoutes.POST("", Auth(dtos.ADMIN), addContext)
 		contextRoutes.PATCH("", Auth(dtos.ADMIN), updateContext)
+		contextRoutes.PUT("/impersonation", Auth(dtos.ADMIN), RequireContextId(), setContextImpersonation)
 	}
 }
 
@@ -34,7 +37,26 @@ func InitContextRoutes(router *gin.Engine) {
 // @Router /backend/context/all [get]
 // @Security Bearer
 func allContexts(c *gin.Context) {
-	c.JSON(http.StatusOK, services.ListContexts())
+	user := services.GetGinContextUser(c)
+	if user == nil {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	c.JSON(http.StatusOK, services.ListContextsForUser(user))
+}
+
+// @Tags Context
+// @Produce json
+// @Success 200 {array} dtos.PunqContextHealth
+// @Router /backend/context/health [get]
+// @Security Bearer
+func contextHealth(c *gin.Context) {
+	user := services.GetGinContextUser(c)
+	if user == nil {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	c.JSON(http.StatusOK, services.ListContextHealth(user))
 }
 
 // @Tags Context
@@ -44,7 +66,7 @@ func allContexts(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 // @Security Bearer
 func getInfoContexts(c *gin.Context) {
-	c.JSON(http.StatusOK, kubernetes.ClusterInfo(services.GetGinContextId(c)))
+	c.JSON(http.StatusOK, kubernetes.ClusterInfo(services.GetGinKubeContextId(c)))
 }
 
 // @Tags Context
@@ -187,3 +209,25 @@ func updateContext(c *gin.Context) {
 
 	c.JSON(200, updateContext)
 }
+
+// @Tags Context
+// @Produce json
+// @Success 200 {object} dtos.PunqContext
+// @Router /backend/context/impersonation [put]
+// @Param X-Context-Id header string true "X-Context-Id"
+// @Param body body dtos.PunqImpersonation true "PunqImpersonation"
+// @Security Bearer
+func setContextImpersonation(c *gin.Context) {
+	var data dtos.PunqImpersonation
+	err := c.MustBindWith(&data, binding.JSON)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	ctx, err := services.SetContextImpersonation(*services.GetGinContextId(c), &data)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, ctx)
+}
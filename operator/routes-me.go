@@ -0,0 +1,165 @@
+package operator
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/kubernetes"
+	"github.com/mogenius/punq/services"
+	"github.com/mogenius/punq/utils"
+)
+
+// InitMeRoutes registers the self-service routes of the signed in user, which need no admin rights
+func InitMeRoutes(router *gin.Engine) {
+
+	meRoutes := router.Group("/me", Auth(dtos.READER))
+	{
+		meRoutes.GET("", meGet)
+		meRoutes.PATCH("", meUpdate)
+		meRoutes.PUT("/password", mePasswordChange)
+		meRoutes.GET("/sessions", meSessionList)
+		meRoutes.DELETE("/sessions/:sessionId", validateParam("sessionId"), meSessionDelete)
+		meRoutes.GET("/tokens", meTokenList)
+		meRoutes.GET("/contexts", meContextList)
+	}
+
+}
+
+// @Tags Me
+// @Produce json
+// @Success 200 {object} dtos.PunqUser
+// @Router /backend/me [get]
+// @Security Bearer
+func meGet(c *gin.Context) {
+	user := services.GetGinContextUser(c)
+	if user == nil {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	c.JSON(http.StatusOK, user.Redacted())
+}
+
+// @Tags Me
+// @Produce json
+// @Success 200 {object} dtos.PunqUser
+// @Router /backend/me [patch]
+// @Param body body dtos.PunqProfileUpdateInput true "PunqProfileUpdateInput"
+// @Security Bearer
+func meUpdate(c *gin.Context) {
+	user := services.GetGinContextUser(c)
+	if user == nil {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	var data dtos.PunqProfileUpdateInput
+	err := c.MustBindWith(&data, binding.JSON)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	updated, err := services.UpdateProfile(user.Id, data)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, updated.Redacted())
+}
+
+// @Tags Me
+// @Produce json
+// @Success 200 {object} dtos.PunqUser
+// @Failure 429 "too many wrong current passwords (see Retry-After header)"
+// @Router /backend/me/password [put]
+// @Param body body dtos.PunqPasswordChangeInput true "PunqPasswordChangeInput"
+// @Security Bearer
+func mePasswordChange(c *gin.Context) {
+	user := services.GetGinContextUser(c)
+	if user == nil {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	var data dtos.PunqPasswordChangeInput
+	err := c.MustBindWith(&data, binding.JSON)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	updated, err := services.ChangePassword(user.Id, data, c.ClientIP(), services.GetGinContextSessionId(c))
+	if err != nil {
+		var throttled *services.LoginThrottledError
+		if errors.As(err, &throttled) {
+			loginThrottled(c, err)
+			return
+		}
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, updated.Redacted())
+}
+
+// @Tags Me
+// @Produce json
+// @Success 200 {array} dtos.PunqSession
+// @Router /backend/me/sessions [get]
+// @Security Bearer
+func meSessionList(c *gin.Context) {
+	user := services.GetGinContextUser(c)
+	if user == nil {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	sessions, err := services.ListSessions(user.Id)
+	utils.HttpRespondForWorkloadResult(c, kubernetes.WorkloadResult(sessions, err))
+}
+
+// @Tags Me
+// @Produce json
+// @Success 200
+// @Router /backend/me/sessions/{sessionId} [delete]
+// @Param sessionId path string true "ID of the session"
+// @Security Bearer
+func meSessionDelete(c *gin.Context) {
+	user := services.GetGinContextUser(c)
+	if user == nil {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	err := services.RevokeSession(user.Id, c.Param("sessionId"))
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked."})
+}
+
+// @Tags Me
+// @Produce json
+// @Success 200 {array} dtos.PunqApiToken
+// @Router /backend/me/tokens [get]
+// @Security Bearer
+func meTokenList(c *gin.Context) {
+	user := services.GetGinContextUser(c)
+	if user == nil {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	tokens, err := services.ListApiTokens(user.Id)
+	utils.HttpRespondForWorkloadResult(c, kubernetes.WorkloadResult(tokens, err))
+}
+
+// @Tags Me
+// @Produce json
+// @Success 200 {array} dtos.PunqContext
+// @Router /backend/me/contexts [get]
+// @Security Bearer
+func meContextList(c *gin.Context) {
+	user := services.GetGinContextUser(c)
+	if user == nil {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	c.JSON(http.StatusOK, services.ListContextsForUser(user))
+}
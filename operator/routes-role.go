@@ -0,0 +1,117 @@
+package operator
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/kubernetes"
+	"github.com/mogenius/punq/services"
+	"github.com/mogenius/punq/utils"
+)
+
+func InitRoleRoutes(router *gin.Engine) {
+
+	roleRoutes := router.Group("/role", Auth(dtos.ADMIN))
+	{
+		roleRoutes.GET("/all", roleList)
+		roleRoutes.GET("/kinds", roleKinds)
+		roleRoutes.GET("/:id", validateParam("id"), roleGet)
+		roleRoutes.DELETE("/:id", validateParam("id"), roleDelete)
+		roleRoutes.PATCH("/", roleUpdate)
+		roleRoutes.POST("/", roleAdd)
+	}
+
+}
+
+// @Tags Role
+// @Produce json
+// @Success 200 {array} dtos.PunqRole
+// @Router /backend/role/all [get]
+// @Security Bearer
+func roleList(c *gin.Context) {
+	roles, err := services.ListRoles()
+	utils.HttpRespondForWorkloadResult(c, kubernetes.WorkloadResult(roles, err))
+}
+
+// @Tags Role
+// @Produce json
+// @Success 200 {array} string
+// @Router /backend/role/kinds [get]
+// @Security Bearer
+func roleKinds(c *gin.Context) {
+	c.JSON(http.StatusOK, services.RoleKinds())
+}
+
+// @Tags Role
+// @Produce json
+// @Success 200 {object} dtos.PunqRole
+// @Router /backend/role/{id} [get]
+// @Param id path string true "ID of the role"
+// @Security Bearer
+func roleGet(c *gin.Context) {
+	role, err := services.GetRole(c.Param("id"))
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, role)
+}
+
+// @Tags Role
+// @Produce json
+// @Success 200
+// @Router /backend/role/{id} [delete]
+// @Param id path string true "ID of the role"
+// @Security Bearer
+func roleDelete(c *gin.Context) {
+	err := services.DeleteRole(c.Param("id"))
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Role deleted."})
+}
+
+// @Tags Role
+// @Produce json
+// @Success 200 {object} dtos.PunqRole
+// @Router /backend/role [patch]
+// @Param body body dtos.PunqRole true "PunqRole"
+// @Security Bearer
+func roleUpdate(c *gin.Context) {
+	var data dtos.PunqRole
+	err := c.MustBindWith(&data, binding.JSON)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	role, err := services.UpdateRole(data)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, role)
+}
+
+// @Tags Role
+// @Produce json
+// @Success 200 {object} dtos.PunqRole
+// @Router /backend/role [post]
+// @Param body body dtos.PunqRoleCreateInput true "PunqRoleCreateInput"
+// @Security Bearer
+func roleAdd(c *gin.Context) {
+	var data dtos.PunqRoleCreateInput
+	err := c.MustBindWith(&data, binding.JSON)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	role, err := services.AddRole(data)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, role)
+}
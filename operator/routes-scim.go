@@ -0,0 +1,355 @@
+package operator
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/services"
+)
+
+// InitScimRoutes registers the SCIM 2.0 server for identity providers (see scim in the config)
+func InitScimRoutes(router *gin.Engine) {
+
+	scimRoutes := router.Group("/scim/v2", ScimAuth())
+	{
+		scimRoutes.GET("/ServiceProviderConfig", scimServiceProviderConfig)
+		scimRoutes.GET("/ResourceTypes", scimResourceTypes)
+
+		scimRoutes.GET("/Users", scimUserList)
+		scimRoutes.GET("/Users/:id", validateParam("id"), scimUserGet)
+		scimRoutes.POST("/Users", scimUserCreate)
+		scimRoutes.PUT("/Users/:id", validateParam("id"), scimUserReplace)
+		scimRoutes.PATCH("/Users/:id", validateParam("id"), scimUserPatch)
+		scimRoutes.DELETE("/Users/:id", validateParam("id"), scimUserDelete)
+
+		scimRoutes.GET("/Groups", scimGroupList)
+		scimRoutes.GET("/Groups/:id", validateParam("id"), scimGroupGet)
+		scimRoutes.POST("/Groups", scimGroupCreate)
+		scimRoutes.PUT("/Groups/:id", validateParam("id"), scimGroupReplace)
+		scimRoutes.PATCH("/Groups/:id", validateParam("id"), scimGroupPatch)
+		scimRoutes.DELETE("/Groups/:id", validateParam("id"), scimGroupDelete)
+	}
+
+}
+
+// @Tags SCIM
+// @Produce json
+// @Success 200 {object} dtos.ScimServiceProviderConfig
+// @Router /backend/scim/v2/ServiceProviderConfig [get]
+// @Security Bearer
+func scimServiceProviderConfig(c *gin.Context) {
+	scimRespond(c, http.StatusOK, dtos.ScimServiceProviderConfig{
+		Schemas:        []string{dtos.SCIM_SCHEMA_SERVICE_PROVIDER_CONFIG},
+		Patch:          dtos.ScimSupported{Supported: true},
+		Filter:         dtos.ScimSupported{Supported: true, MaxResults: dtos.SCIM_MAX_RESULTS},
+		Bulk:           dtos.ScimSupported{},
+		ChangePassword: dtos.ScimSupported{},
+		Sort:           dtos.ScimSupported{},
+		Etag:           dtos.ScimSupported{},
+		AuthenticationSchemes: []dtos.ScimAuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "Bearer token",
+			Description: "Token configured in scim.token",
+		}},
+	})
+}
+
+// @Tags SCIM
+// @Produce json
+// @Success 200 {object} dtos.ScimListResponse
+// @Router /backend/scim/v2/ResourceTypes [get]
+// @Security Bearer
+func scimResourceTypes(c *gin.Context) {
+	resourceTypes := []interface{}{
+		dtos.ScimResourceType{
+			Schemas:  []string{dtos.SCIM_SCHEMA_RESOURCE_TYPE},
+			Id:       dtos.SCIM_RESOURCE_USER,
+			Name:     dtos.SCIM_RESOURCE_USER,
+			Endpoint: "/Users",
+			Schema:   dtos.SCIM_SCHEMA_USER,
+		},
+		dtos.ScimResourceType{
+			Schemas:  []string{dtos.SCIM_SCHEMA_RESOURCE_TYPE},
+			Id:       dtos.SCIM_RESOURCE_GROUP,
+			Name:     dtos.SCIM_RESOURCE_GROUP,
+			Endpoint: "/Groups",
+			Schema:   dtos.SCIM_SCHEMA_GROUP,
+		},
+	}
+	scimRespond(c, http.StatusOK, dtos.ScimListResponse{
+		Schemas:      []string{dtos.SCIM_SCHEMA_LIST_RESPONSE},
+		TotalResults: len(resourceTypes),
+		StartIndex:   1,
+		ItemsPerPage: len(resourceTypes),
+		Resources:    resourceTypes,
+	})
+}
+
+// @Tags SCIM
+// @Produce json
+// @Success 200 {object} dtos.ScimListResponse
+// @Router /backend/scim/v2/Users [get]
+// @Param filter query string false "e.g. userName eq \"jane@example.com\""
+// @Param startIndex query int false "1-based index of the first result"
+// @Param count query int false "maximum number of results"
+// @Security Bearer
+func scimUserList(c *gin.Context) {
+	startIndex, count := scimPage(c)
+	list, err := services.ScimListUsers(c.Query("filter"), startIndex, count, scimBaseUrl(c))
+	if err != nil {
+		scimRespondError(c, err)
+		return
+	}
+	scimRespond(c, http.StatusOK, list)
+}
+
+// @Tags SCIM
+// @Produce json
+// @Success 200 {object} dtos.ScimUser
+// @Router /backend/scim/v2/Users/{id} [get]
+// @Param id path string true "ID of the user"
+// @Security Bearer
+func scimUserGet(c *gin.Context) {
+	user, err := services.ScimGetUser(c.Param("id"), scimBaseUrl(c))
+	if err != nil {
+		scimRespondError(c, err)
+		return
+	}
+	scimRespond(c, http.StatusOK, user)
+}
+
+// @Tags SCIM
+// @Produce json
+// @Success 201 {object} dtos.ScimUser
+// @Router /backend/scim/v2/Users [post]
+// @Param body body dtos.ScimUser true "ScimUser"
+// @Security Bearer
+func scimUserCreate(c *gin.Context) {
+	var data dtos.ScimUser
+	if err := c.ShouldBindWith(&data, binding.JSON); err != nil {
+		scimRespondError(c, &services.ScimError{Status: http.StatusBadRequest, ScimType: "invalidSyntax", Detail: err.Error()})
+		return
+	}
+	user, err := services.ScimCreateUser(data, scimBaseUrl(c))
+	if err != nil {
+		scimRespondError(c, err)
+		return
+	}
+	scimRespond(c, http.StatusCreated, user)
+}
+
+// @Tags SCIM
+// @Produce json
+// @Success 200 {object} dtos.ScimUser
+// @Router /backend/scim/v2/Users/{id} [put]
+// @Param id path string true "ID of the user"
+// @Param body body dtos.ScimUser true "ScimUser"
+// @Security Bearer
+func scimUserReplace(c *gin.Context) {
+	var data dtos.ScimUser
+	if err := c.ShouldBindWith(&data, binding.JSON); err != nil {
+		scimRespondError(c, &services.ScimError{Status: http.StatusBadRequest, ScimType: "invalidSyntax", Detail: err.Error()})
+		return
+	}
+	user, err := services.ScimReplaceUser(c.Param("id"), data, scimBaseUrl(c))
+	if err != nil {
+		scimRespondError(c, err)
+		return
+	}
+	scimRespond(c, http.StatusOK, user)
+}
+
+// @Tags SCIM
+// @Produce json
+// @Success 200 {object} dtos.ScimUser
+// @Router /backend/scim/v2/Users/{id} [patch]
+// @Param id path string true "ID of the user"
+// @Param body body dtos.ScimPatchRequest true "ScimPatchRequest"
+// @Security Bearer
+func scimUserPatch(c *gin.Context) {
+	var data dtos.ScimPatchRequest
+	if err := c.ShouldBindWith(&data, binding.JSON); err != nil {
+		scimRespondError(c, &services.ScimError{Status: http.StatusBadRequest, ScimType: "invalidSyntax", Detail: err.Error()})
+		return
+	}
+	user, err := services.ScimPatchUser(c.Param("id"), data, scimBaseUrl(c))
+	if err != nil {
+		scimRespondError(c, err)
+		return
+	}
+	scimRespond(c, http.StatusOK, user)
+}
+
+// @Tags SCIM
+// @Produce json
+// @Success 204 "the user is deactivated (deleted if scim.delete_users is set)"
+// @Router /backend/scim/v2/Users/{id} [delete]
+// @Param id path string true "ID of the user"
+// @Security Bearer
+func scimUserDelete(c *gin.Context) {
+	err := services.ScimDeleteUser(c.Param("id"))
+	if err != nil {
+		scimRespondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Tags SCIM
+// @Produce json
+// @Success 200 {object} dtos.ScimListResponse
+// @Router /backend/scim/v2/Groups [get]
+// @Param filter query string false "e.g. displayName eq \"developers\""
+// @Param startIndex query int false "1-based index of the first result"
+// @Param count query int false "maximum number of results"
+// @Security Bearer
+func scimGroupList(c *gin.Context) {
+	startIndex, count := scimPage(c)
+	list, err := services.ScimListGroups(c.Query("filter"), startIndex, count, scimBaseUrl(c))
+	if err != nil {
+		scimRespondError(c, err)
+		return
+	}
+	scimRespond(c, http.StatusOK, list)
+}
+
+// @Tags SCIM
+// @Produce json
+// @Success 200 {object} dtos.ScimGroup
+// @Router /backend/scim/v2/Groups/{id} [get]
+// @Param id path string true "ID of the group"
+// @Security Bearer
+func scimGroupGet(c *gin.Context) {
+	group, err := services.ScimGetGroup(c.Param("id"), scimBaseUrl(c))
+	if err != nil {
+		scimRespondError(c, err)
+		return
+	}
+	scimRespond(c, http.StatusOK, group)
+}
+
+// @Tags SCIM
+// @Produce json
+// @Success 201 {object} dtos.ScimGroup
+// @Router /backend/scim/v2/Groups [post]
+// @Param body body dtos.ScimGroup true "ScimGroup"
+// @Security Bearer
+func scimGroupCreate(c *gin.Context) {
+	var data dtos.ScimGroup
+	if err := c.ShouldBindWith(&data, binding.JSON); err != nil {
+		scimRespondError(c, &services.ScimError{Status: http.StatusBadRequest, ScimType: "invalidSyntax", Detail: err.Error()})
+		return
+	}
+	group, err := services.ScimCreateGroup(data, scimBaseUrl(c))
+	if err != nil {
+		scimRespondError(c, err)
+		return
+	}
+	scimRespond(c, http.StatusCreated, group)
+}
+
+// @Tags SCIM
+// @Produce json
+// @Success 200 {object} dtos.ScimGroup
+// @Router /backend/scim/v2/Groups/{id} [put]
+// @Param id path string true "ID of the group"
+// @Param body body dtos.ScimGroup true "ScimGroup"
+// @Security Bearer
+func scimGroupReplace(c *gin.Context) {
+	var data dtos.ScimGroup
+	if err := c.ShouldBindWith(&data, binding.JSON); err != nil {
+		scimRespondError(c, &services.ScimError{Status: http.StatusBadRequest, ScimType: "invalidSyntax", Detail: err.Error()})
+		return
+	}
+	group, err := services.ScimReplaceGroup(c.Param("id"), data, scimBaseUrl(c))
+	if err != nil {
+		scimRespondError(c, err)
+		return
+	}
+	scimRespond(c, http.StatusOK, group)
+}
+
+// @Tags SCIM
+// @Produce json
+// @Success 200 {object} dtos.ScimGroup
+// @Router /backend/scim/v2/Groups/{id} [patch]
+// @Param id path string true "ID of the group"
+// @Param body body dtos.ScimPatchRequest true "ScimPatchRequest"
+// @Security Bearer
+func scimGroupPatch(c *gin.Context) {
+	var data dtos.ScimPatchRequest
+	if err := c.ShouldBindWith(&data, binding.JSON); err != nil {
+		scimRespondError(c, &services.ScimError{Status: http.StatusBadRequest, ScimType: "invalidSyntax", Detail: err.Error()})
+		return
+	}
+	group, err := services.ScimPatchGroup(c.Param("id"), data, scimBaseUrl(c))
+	if err != nil {
+		scimRespondError(c, err)
+		return
+	}
+	scimRespond(c, http.StatusOK, group)
+}
+
+// @Tags SCIM
+// @Produce json
+// @Success 204
+// @Router /backend/scim/v2/Groups/{id} [delete]
+// @Param id path string true "ID of the group"
+// @Security Bearer
+func scimGroupDelete(c *gin.Context) {
+	err := services.ScimDeleteGroup(c.Param("id"))
+	if err != nil {
+		scimRespondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func scimRespond(c *gin.Context, status int, obj interface{}) {
+	// c.JSON keeps an existing content type
+	c.Header("Content-Type", dtos.SCIM_CONTENT_TYPE)
+	c.JSON(status, obj)
+}
+
+func scimRespondError(c *gin.Context, err error) {
+	scimErr := services.ScimErrorFor(err)
+	scimRespond(c, scimErr.Status, dtos.ScimError{
+		Schemas:  []string{dtos.SCIM_SCHEMA_ERROR},
+		Status:   strconv.Itoa(scimErr.Status),
+		ScimType: scimErr.ScimType,
+		Detail:   scimErr.Detail,
+	})
+}
+
+// scimPage returns startIndex and count of the query (-1 = no limit)
+func scimPage(c *gin.Context) (int, int) {
+	startIndex, err := strconv.Atoi(c.Query("startIndex"))
+	if err != nil {
+		startIndex = 1
+	}
+	count, err := strconv.Atoi(c.Query("count"))
+	if err != nil {
+		count = -1
+	}
+	return startIndex, count
+}
+
+// scimBaseUrl is used for meta.location, a reverse proxy can pass its path prefix as X-Forwarded-Prefix
+func scimBaseUrl(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	host := c.Request.Host
+	if forwardedHost := c.GetHeader("X-Forwarded-Host"); forwardedHost != "" {
+		host = forwardedHost
+	}
+	return fmt.Sprintf("%s://%s%s/scim/v2", scheme, host, c.GetHeader("X-Forwarded-Prefix"))
+}
@@ -1,6 +1,7 @@
 package operator
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -19,8 +20,34 @@ func InitUserRoutes(router *gin.Engine) {
 		userRoutes.GET("/", currentUserGet)
 		userRoutes.GET("/:id", validateParam("id"), userGet)
 		userRoutes.DELETE("/:id", validateParam("id"), userDelete)
+		userRoutes.GET("/:id/sessions", validateParam("id"), userSessionList)
+		userRoutes.DELETE("/:id/sessions", validateParam("id"), userSessionDeleteAll)
+		userRoutes.DELETE("/:id/sessions/:sessionId", validateParam("id", "sessionId"), userSessionDelete)
+		userRoutes.DELETE("/:id/2fa", validateParam("id"), userTwoFactorReset)
+		userRoutes.POST("/:id/deactivate", validateParam("id"), userDeactivate)
+		userRoutes.POST("/:id/activate", validateParam("id"), userActivate)
+		userRoutes.GET("/login-attempts", userLoginAttemptList)
+		userRoutes.DELETE("/login-attempts", userLoginAttemptUnlock)
 		userRoutes.PATCH("/", userUpdate)
 		userRoutes.POST("/", userAdd)
+		userRoutes.POST("/invite", userInvite)
+	}
+
+	// every user manages its own api tokens
+	tokenRoutes := router.Group("/user/tokens", Auth(dtos.READER))
+	{
+		tokenRoutes.GET("", userTokenList)
+		tokenRoutes.POST("", userTokenCreate)
+		tokenRoutes.DELETE("/:tokenId", validateParam("tokenId"), userTokenRevoke)
+	}
+
+	// every user manages its own second factor
+	twoFactorRoutes := router.Group("/user/2fa", Auth(dtos.READER))
+	{
+		twoFactorRoutes.POST("/enroll", userTwoFactorEnroll)
+		twoFactorRoutes.POST("/confirm", userTwoFactorConfirm)
+		twoFactorRoutes.POST("/recovery-codes", userTwoFactorRecoveryCodes)
+		twoFactorRoutes.DELETE("", userTwoFactorDisable)
 	}
 
 }
@@ -32,7 +59,7 @@ func InitUserRoutes(router *gin.Engine) {
 // @Security Bearer
 func userList(c *gin.Context) {
 	users := services.ListUsers()
-	utils.HttpRespondForWorkloadResult(c, kubernetes.WorkloadResult(users, nil))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.WorkloadResult(dtos.RedactedUsers(users), nil))
 }
 
 // @Tags User
@@ -54,7 +81,7 @@ func userDelete(c *gin.Context) {
 func currentUserGet(c *gin.Context) {
 	user := services.GetGinContextUser(c)
 	if user != nil {
-		c.JSON(http.StatusOK, user)
+		c.JSON(http.StatusOK, user.Redacted())
 		return
 	}
 	utils.Unauthorized(c, "Unauthorized")
@@ -75,7 +102,7 @@ func userGet(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, user.Redacted())
 }
 
 // @Tags User
@@ -96,7 +123,7 @@ func userUpdate(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, user.Redacted())
 }
 
 // @Tags User
@@ -117,5 +144,310 @@ func userAdd(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, user.Redacted())
+}
+
+// @Tags User
+// @Produce json
+// @Success 200 {object} dtos.PunqInvitation "link is only set if the invitation could not be mailed"
+// @Router /backend/user/invite [post]
+// @Param body body dtos.PunqInvitationInput true "PunqInvitationInput"
+// @Security Bearer
+func userInvite(c *gin.Context) {
+	var data dtos.PunqInvitationInput
+	err := c.MustBindWith(&data, binding.JSON)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	invitation, err := services.InviteUser(data)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, invitation)
+}
+
+// @Tags User
+// @Produce json
+// @Success 200 {array} dtos.PunqSession
+// @Router /backend/user/{id}/sessions [get]
+// @Param id path string true "ID of the user"
+// @Security Bearer
+func userSessionList(c *gin.Context) {
+	sessions, err := services.ListSessions(c.Param("id"))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.WorkloadResult(sessions, err))
+}
+
+// @Tags User
+// @Produce json
+// @Success 200
+// @Router /backend/user/{id}/sessions [delete]
+// @Param id path string true "ID of the user"
+// @Security Bearer
+func userSessionDeleteAll(c *gin.Context) {
+	err := services.RevokeUserSessions(c.Param("id"))
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "All sessions revoked."})
+}
+
+// @Tags User
+// @Produce json
+// @Success 200
+// @Router /backend/user/{id}/sessions/{sessionId} [delete]
+// @Param id path string true "ID of the user"
+// @Param sessionId path string true "ID of the session"
+// @Security Bearer
+func userSessionDelete(c *gin.Context) {
+	err := services.RevokeSession(c.Param("id"), c.Param("sessionId"))
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked."})
+}
+
+// @Tags User
+// @Produce json
+// @Success 200 {array} dtos.PunqLoginAttempt
+// @Router /backend/user/login-attempts [get]
+// @Security Bearer
+func userLoginAttemptList(c *gin.Context) {
+	attempts, err := services.ListLoginAttempts()
+	utils.HttpRespondForWorkloadResult(c, kubernetes.WorkloadResult(attempts, err))
+}
+
+// @Tags User
+// @Produce json
+// @Success 200
+// @Router /backend/user/login-attempts [delete]
+// @Param kind query string true "account or ip"
+// @Param subject query string true "login (e.g. email) or IP address"
+// @Security Bearer
+func userLoginAttemptUnlock(c *gin.Context) {
+	kind := c.Query("kind")
+	subject := c.Query("subject")
+	if subject == "" {
+		utils.MalformedMessage(c, "Query parameter 'subject' is required.")
+		return
+	}
+	err := services.UnlockLogin(kind, subject)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Unlocked %s '%s'.", kind, subject)})
+}
+
+// @Tags User
+// @Produce json
+// @Success 200 {array} dtos.PunqApiToken
+// @Router /backend/user/tokens [get]
+// @Security Bearer
+func userTokenList(c *gin.Context) {
+	user := services.GetGinContextUser(c)
+	if user == nil {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	tokens, err := services.ListApiTokens(user.Id)
+	utils.HttpRespondForWorkloadResult(c, kubernetes.WorkloadResult(tokens, err))
+}
+
+// @Tags User
+// @Produce json
+// @Success 200 {object} dtos.PunqApiTokenCreated
+// @Router /backend/user/tokens [post]
+// @Param body body dtos.PunqApiTokenCreateInput true "PunqApiTokenCreateInput"
+// @Security Bearer
+func userTokenCreate(c *gin.Context) {
+	user := services.GetGinContextUser(c)
+	if user == nil {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	var input dtos.PunqApiTokenCreateInput
+	err := c.MustBindWith(&input, binding.JSON)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+
+	token, err := services.CreateApiToken(user.Id, input)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, token)
+}
+
+// @Tags User
+// @Produce json
+// @Success 200
+// @Router /backend/user/tokens/{tokenId} [delete]
+// @Param tokenId path string true "ID of the api token"
+// @Security Bearer
+func userTokenRevoke(c *gin.Context) {
+	user := services.GetGinContextUser(c)
+	if user == nil {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	err := services.RevokeApiToken(user.Id, c.Param("tokenId"))
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Api token revoked."})
+}
+
+type TwoFactorCodeInput struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// @Tags User
+// @Produce json
+// @Success 200 {object} dtos.PunqTwoFactorEnrollment
+// @Router /backend/user/2fa/enroll [post]
+// @Security Bearer
+func userTwoFactorEnroll(c *gin.Context) {
+	user := services.GetGinContextUser(c)
+	if user == nil {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	enrollment, err := services.StartTwoFactorEnrollment(user.Id)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// @Tags User
+// @Produce json
+// @Success 200 {object} dtos.PunqTwoFactorRecoveryCodes
+// @Router /backend/user/2fa/confirm [post]
+// @Param body body TwoFactorCodeInput true "code of the authenticator app"
+// @Security Bearer
+func userTwoFactorConfirm(c *gin.Context) {
+	user := services.GetGinContextUser(c)
+	if user == nil {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	var data TwoFactorCodeInput
+	err := c.MustBindWith(&data, binding.JSON)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	recoveryCodes, err := services.ConfirmTwoFactorEnrollment(user.Id, data.Code)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, dtos.PunqTwoFactorRecoveryCodes{RecoveryCodes: recoveryCodes})
+}
+
+// @Tags User
+// @Produce json
+// @Success 200 {object} dtos.PunqTwoFactorRecoveryCodes
+// @Router /backend/user/2fa/recovery-codes [post]
+// @Param body body TwoFactorCodeInput true "code of the authenticator app"
+// @Security Bearer
+func userTwoFactorRecoveryCodes(c *gin.Context) {
+	user := services.GetGinContextUser(c)
+	if user == nil {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	var data TwoFactorCodeInput
+	err := c.MustBindWith(&data, binding.JSON)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	recoveryCodes, err := services.RegenerateRecoveryCodes(user.Id, data.Code)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, dtos.PunqTwoFactorRecoveryCodes{RecoveryCodes: recoveryCodes})
+}
+
+// @Tags User
+// @Produce json
+// @Success 200
+// @Router /backend/user/2fa [delete]
+// @Param body body TwoFactorCodeInput true "code of the authenticator app or recovery code"
+// @Security Bearer
+func userTwoFactorDisable(c *gin.Context) {
+	user := services.GetGinContextUser(c)
+	if user == nil {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	var data TwoFactorCodeInput
+	err := c.MustBindWith(&data, binding.JSON)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	err = services.DisableTwoFactor(user.Id, data.Code)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "2FA disabled."})
+}
+
+// @Tags User
+// @Produce json
+// @Success 200
+// @Router /backend/user/{id}/2fa [delete]
+// @Param id path string true "ID of the user"
+// @Security Bearer
+func userTwoFactorReset(c *gin.Context) {
+	err := services.ResetTwoFactor(c.Param("id"))
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "2FA reset."})
+}
+
+// @Tags User
+// @Produce json
+// @Success 200 {object} dtos.PunqUser
+// @Router /backend/user/{id}/deactivate [post]
+// @Param id path string true "ID of the user"
+// @Security Bearer
+func userDeactivate(c *gin.Context) {
+	user, err := services.SetUserDisabled(c.Param("id"), true)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, user.Redacted())
+}
+
+// @Tags User
+// @Produce json
+// @Success 200 {object} dtos.PunqUser
+// @Router /backend/user/{id}/activate [post]
+// @Param id path string true "ID of the user"
+// @Security Bearer
+func userActivate(c *gin.Context) {
+	user, err := services.SetUserDisabled(c.Param("id"), false)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, user.Redacted())
 }
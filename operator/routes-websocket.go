@@ -1,19 +1,26 @@
 package operator
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"io"
 	"net/http"
-	"os"
-	"os/exec"
+	"net/url"
 	"strings"
+	"sync"
+	"time"
 
-	"github.com/creack/pty"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/kubernetes"
+	"github.com/mogenius/punq/logger"
+	"github.com/mogenius/punq/services"
+	"github.com/mogenius/punq/structs"
 	"github.com/mogenius/punq/utils"
+	"k8s.io/client-go/tools/remotecommand"
 )
 
 type windowSize struct {
@@ -21,18 +28,98 @@ type windowSize struct {
 	Cols uint16 `json:"cols"`
 }
 
+// implements remotecommand.TerminalSizeQueue for the "\x04{rows,cols}" resize messages of the client
+type wsSizeQueue struct {
+	resize chan remotecommand.TerminalSize
+}
+
+func (q *wsSizeQueue) Next() *remotecommand.TerminalSize {
+	size, ok := <-q.resize
+	if !ok {
+		return nil
+	}
+	return &size
+}
+
+// websocket.Conn does not support concurrent writers
+type wsWriter struct {
+	conn *websocket.Conn
+	mu   sync.Mutex
+}
+
+func (w *wsWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	err := w.conn.WriteMessage(websocket.BinaryMessage, p)
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *wsWriter) WriteText(msg string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.conn.WriteMessage(websocket.TextMessage, []byte(msg))
+}
+
+func (w *wsWriter) WriteJSON(v interface{}) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.conn.WriteJSON(v)
+}
+
+const (
+	WATCH_PATTERN_SUBSCRIBE   string = "subscribe"
+	WATCH_PATTERN_UNSUBSCRIBE string = "unsubscribe"
+	WATCH_PATTERN_EVENT       string = "watch-event"
+)
+
+type watchSubscription struct {
+	ContextId string `json:"contextId"`
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace"`
+}
+
 func InitWebsocketRoutes(router *gin.Engine) {
-	router.GET("/exec-sh", AuthByParameter(dtos.ADMIN), connectWs)
+	router.GET("/exec-sh", AuthByTicket(dtos.READER), AuthorizeExec(), connectWs)
+	router.GET("/watch", AuthByTicket(dtos.READER), connectWatchWs)
 }
 
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		return true // adjust to implement your origin validation logic
-	},
+	CheckOrigin:     checkOrigin,
+}
+
+// checkOrigin accepts clients without Origin header, same-origin requests and the pages of websocket.allowed_origins
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	originUrl, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(originUrl.Host, r.Host) {
+		return true
+	}
+
+	allowedOrigins := utils.CONFIG.Websocket.AllowedOrigins
+	if len(allowedOrigins) == 0 {
+		allowedOrigins = []string{fmt.Sprintf("http://%s:%d", utils.CONFIG.Frontend.Host, utils.CONFIG.Frontend.Port)}
+	}
+	for _, allowed := range allowedOrigins {
+		if allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	logger.Log.Warningf("Rejected websocket from origin '%s'", origin)
+	return false
 }
 
+// QUERY: namespace, podname, container, mode (exec|attach|debug), image (debug only), context-id, ticket (see POST /auth/ws-ticket)
 func connectWs(c *gin.Context) {
 	namespace, namespaceOk := c.GetQuery("namespace")
 	if !namespaceOk || namespace == "" {
@@ -52,91 +139,288 @@ func connectWs(c *gin.Context) {
 		return
 	}
 
-	log.Printf("exec-sh: %s %s %s\n", namespace, container, podName)
-
-	ws, err := upgrader.Upgrade(c.Writer, c.Request, nil)
-	if err != nil {
-		log.Printf("Failed to upgrade ws: %s", err.Error())
+	mode := c.DefaultQuery("mode", kubernetes.EXEC_MODE_EXEC)
+	if mode != kubernetes.EXEC_MODE_EXEC && mode != kubernetes.EXEC_MODE_ATTACH && mode != kubernetes.EXEC_MODE_DEBUG {
+		utils.MalformedMessage(c, "mode must be one of: exec, attach, debug")
 		return
 	}
-	defer func() {
-		ws.Close()
-	}()
+	debugImage := c.Query("image")
+	contextId := services.GetGinKubeContextId(c)
+
+	logger.Log.Infof("exec-sh (%s): %s %s %s", mode, namespace, container, podName)
+
+	ws, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
-		log.Printf("Unable to upgrade connection: %s", err.Error())
+		logger.Log.Errorf("Failed to upgrade ws: %s", err.Error())
 		return
 	}
+	defer ws.Close()
+	defer closeOnRevokedCredentials(c, ws)()
+
+	sessionStart := time.Now()
+	auditExecSession(c, mode, namespace, podName, fmt.Sprintf("Session started (container: %s).", container), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stdinReader, stdinWriter := io.Pipe()
+	defer stdinReader.Close()
+	sizeQueue := &wsSizeQueue{resize: make(chan remotecommand.TerminalSize, 1)}
+	writer := &wsWriter{conn: ws}
+
+	go func() {
+		defer stdinWriter.Close()
+		defer close(sizeQueue.resize)
+		defer cancel()
+		for {
+			_, reader, err := ws.ReadMessage()
+			if err != nil {
+				logger.Log.Infof("Unable to grab next reader: %s", err.Error())
+				return
+			}
 
-	selectedShell := FindValidShell(container, namespace, podName)
-	fmt.Println("Selected shell: " + selectedShell)
+			if strings.HasPrefix(string(reader), "\x04") {
+				str := strings.TrimPrefix(string(reader), "\x04")
+
+				var resizeMessage windowSize
+				err := json.Unmarshal([]byte(str), &resizeMessage)
+				if err != nil {
+					logger.Log.Errorf("Invalid resize message: %s", err.Error())
+					continue
+				}
+
+				// drop outdated sizes if the executor did not pick them up yet
+				select {
+				case <-sizeQueue.resize:
+				default:
+				}
+				sizeQueue.resize <- remotecommand.TerminalSize{Width: resizeMessage.Cols, Height: resizeMessage.Rows}
+				continue
+			}
 
-	cmd := exec.Command("sh", "-c", fmt.Sprintf("kubectl exec -it -c %s -n %s %s -- %s -c 'echo -e \"\033[1;34mConnected to %s/%s/%s using \"$(echo $0)\". Happy hacking!\033[0m 🚀 🚀 🚀\"; %s'", container, namespace, podName, selectedShell, namespace, podName, container, selectedShell))
-	fmt.Println(cmd.String())
-	cmd.Env = append(os.Environ(), "TERM=xterm-color")
+			if _, err := stdinWriter.Write(reader); err != nil {
+				logger.Log.Errorf("Unable to write to stdin: %s", err.Error())
+				return
+			}
+		}
+	}()
 
-	tty, err := pty.Start(cmd)
+	err = kubernetes.ExecStream(ctx, kubernetes.ExecOptions{
+		Namespace:  namespace,
+		PodName:    podName,
+		Container:  container,
+		Mode:       mode,
+		DebugImage: debugImage,
+		Stdin:      stdinReader,
+		Stdout:     writer,
+		SizeQueue:  sizeQueue,
+	}, contextId)
 	if err != nil {
-		log.Printf("Unable to start pty/cmd: %s", err.Error())
-		ws.WriteMessage(websocket.TextMessage, []byte(err.Error()))
-		return
+		writer.WriteText(err.Error())
 	}
+	auditExecSession(c, mode, namespace, podName, fmt.Sprintf("Session ended after %s (container: %s).", time.Since(sessionStart).Round(time.Second), container), err)
+}
 
-	defer func() {
-		cmd.Process.Kill()
-		cmd.Process.Wait()
-		tty.Close()
-		ws.Close()
-	}()
+// closeOnRevokedCredentials closes the connection once the session or api token its ticket was issued with is
+// revoked or the user is disabled or deleted. The returned function stops the check.
+func closeOnRevokedCredentials(c *gin.Context, ws *websocket.Conn) func() {
+	user := services.GetGinContextUser(c)
+	sessionId := services.GetGinContextSessionId(c)
+	apiTokenId := c.GetString("apiTokenId")
+	done := make(chan struct{})
+	if user == nil {
+		return func() {}
+	}
 
 	go func() {
+		ticker := time.NewTicker(services.SESSION_REVOCATION_SYNC)
+		defer ticker.Stop()
 		for {
-			buf := make([]byte, 1024)
-			read, err := tty.Read(buf)
-			if err != nil {
-				ws.WriteMessage(websocket.TextMessage, []byte(err.Error()))
-				log.Printf("Unable to read from pty/cmd: %s", err.Error())
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				_, err := services.CheckWsCredentials(user.Id, sessionId, apiTokenId)
+				if err == nil || !isRevokedCredential(err) {
+					continue
+				}
+				logger.Log.Warningf("Closing websocket of user '%s': %s", user.Id, err.Error())
+				deadline := time.Now().Add(time.Second)
+				ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, err.Error()), deadline)
+				ws.Close()
 				return
 			}
-			ws.WriteMessage(websocket.BinaryMessage, buf[:read])
+		}
+	}()
+	return func() {
+		close(done)
+	}
+}
+
+// errors of the credentials check which are not caused by an unavailable cluster
+func isRevokedCredential(err error) bool {
+	return errors.Is(err, services.ErrInvalidRefreshToken) || errors.Is(err, services.ErrInvalidApiToken) || errors.Is(err, services.ErrUserDisabled) || errors.Is(err, services.ErrUserNotFound)
+}
+
+func auditExecSession(c *gin.Context, mode string, namespace string, podName string, message string, err error) {
+	entry := dtos.PunqAuditEntry{
+		Action:     mode,
+		Path:       c.Request.URL.Path,
+		Kind:       kubernetes.RES_POD,
+		Namespace:  namespace,
+		Name:       podName,
+		StatusCode: http.StatusSwitchingProtocols,
+		Result:     dtos.AUDIT_RESULT_SUCCESS,
+		Message:    message,
+	}
+	if contextId := services.GetGinContextId(c); contextId != nil {
+		entry.ContextId = *contextId
+	}
+	if user := services.GetGinContextUser(c); user != nil {
+		entry.UserId = user.Id
+		entry.UserEmail = user.Email
+	}
+	if err != nil {
+		entry.Result = dtos.AUDIT_RESULT_FAILURE
+		entry.Message = fmt.Sprintf("%s %s", message, err.Error())
+	}
+	services.RecordAudit(entry)
+}
+
+// QUERY: ticket (see POST /auth/ws-ticket)
+// Client -> Server: Datagram{id, pattern: "subscribe", payload: {contextId, resource, namespace}} or Datagram{id, pattern: "unsubscribe"}
+// Server -> Client: the request datagram as ack (err set on failure) and Datagram{id, pattern: "watch-event", payload: kubernetes.K8sWatchEvent}
+func connectWatchWs(c *gin.Context) {
+	user := services.GetGinContextUser(c)
+	if user == nil {
+		return
+	}
+	apiToken := getGinContextApiToken(c)
+
+	ws, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.Log.Errorf("Failed to upgrade ws: %s", err.Error())
+		return
+	}
+	defer ws.Close()
+	defer closeOnRevokedCredentials(c, ws)()
+
+	writer := &wsWriter{conn: ws}
+	subscriptions := map[string]func(){}
+	defer func() {
+		for _, stop := range subscriptions {
+			stop()
 		}
 	}()
 
 	for {
-		_, reader, err := ws.ReadMessage()
+		request := structs.Datagram{}
+		err := ws.ReadJSON(&request)
 		if err != nil {
-			log.Printf("Unable to grab next reader: %s", err.Error())
+			logger.Log.Infof("Unable to read watch request: %s", err.Error())
 			return
 		}
 
-		if strings.HasPrefix(string(reader), "\x04") {
-			str := strings.TrimPrefix(string(reader), "\x04")
-
-			var resizeMessage windowSize
-			err := json.Unmarshal([]byte(str), &resizeMessage)
+		response := structs.CreateDatagramRequest(request, nil)
+		switch request.Pattern {
+		case WATCH_PATTERN_SUBSCRIBE:
+			if stop, exists := subscriptions[request.Id]; exists {
+				stop()
+				delete(subscriptions, request.Id)
+			}
+			stop, err := subscribeWatch(user, apiToken, request, writer)
 			if err != nil {
-				log.Printf("%s", err.Error())
-				continue
+				response.Err = err.Error()
+			} else {
+				subscriptions[request.Id] = stop
 			}
-
-			if err := pty.Setsize(tty, &pty.Winsize{Rows: uint16(resizeMessage.Rows), Cols: uint16(resizeMessage.Cols)}); err != nil {
-				log.Printf("Unable to resize: %s", err.Error())
-				continue
+		case WATCH_PATTERN_UNSUBSCRIBE:
+			if stop, exists := subscriptions[request.Id]; exists {
+				stop()
+				delete(subscriptions, request.Id)
 			}
-			continue
+		default:
+			response.Err = fmt.Sprintf("unknown pattern '%s'", request.Pattern)
+		}
+
+		if err := writer.WriteJSON(response); err != nil {
+			return
 		}
+	}
+}
+
+// number of watch subscriptions per user id over all connections (see websocket.max_watches_per_user)
+var watchesPerUser = map[string]int{}
+var watchesPerUserMutex sync.Mutex
 
-		tty.Write(reader)
+// acquireWatchSlot counts a subscription of the user. The returned function gives the slot back.
+func acquireWatchSlot(userId string) (func(), error) {
+	watchesPerUserMutex.Lock()
+	defer watchesPerUserMutex.Unlock()
+	max := utils.CONFIG.Websocket.MaxWatchesPerUser
+	if max > 0 && watchesPerUser[userId] >= max {
+		return nil, fmt.Errorf("too many watch subscriptions (maximum %d per user)", max)
 	}
+	watchesPerUser[userId]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			watchesPerUserMutex.Lock()
+			defer watchesPerUserMutex.Unlock()
+			watchesPerUser[userId]--
+			if watchesPerUser[userId] <= 0 {
+				delete(watchesPerUser, userId)
+			}
+		})
+	}, nil
 }
 
-func FindValidShell(container string, namespace string, podName string) string {
-	availableShells := []string{"bash", "ash", "zsh", "sh", "ksh", "csh"}
-	for _, shell := range availableShells {
-		cmd := exec.Command("sh", "-c", fmt.Sprintf("kubectl exec -it -c %s -n %s %s -- sh -c '%s'", container, namespace, podName, shell))
-		err := cmd.Run()
-		if err == nil {
-			return shell
+// apiToken is the token the ticket of the connection was issued with (nil = session), its scope applies to every
+// subscription
+func subscribeWatch(user *dtos.PunqUser, apiToken *dtos.PunqApiToken, request structs.Datagram, writer *wsWriter) (func(), error) {
+	payload, err := json.Marshal(request.Payload)
+	if err != nil {
+		return nil, err
+	}
+	subscription := watchSubscription{}
+	err = json.Unmarshal(payload, &subscription)
+	if err != nil {
+		return nil, err
+	}
+	if subscription.ContextId == "" || subscription.Resource == "" {
+		return nil, fmt.Errorf("contextId and resource are required")
+	}
+
+	if apiToken != nil {
+		err = apiToken.Allows(dtos.API_TOKEN_VERB_GET, subscription.ContextId, subscription.Namespace)
+		if err != nil {
+			return nil, err
+		}
+	}
+	_, err = services.Authorize(user, &subscription.ContextId, dtos.ROLE_VERB_GET, subscription.Resource, subscription.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	releaseSlot, err := acquireWatchSlot(user.Id)
+	if err != nil {
+		return nil, err
+	}
+	kubeContextId := services.KubeContextId(user, subscription.ContextId)
+	stop, err := kubernetes.WatchK8sResource(subscription.Resource, subscription.Namespace, &kubeContextId, func(event kubernetes.K8sWatchEvent) {
+		datagram := structs.CreateDatagramFrom(WATCH_PATTERN_EVENT, event)
+		datagram.Id = request.Id
+		if err := writer.WriteJSON(datagram); err != nil {
+			logger.Log.Errorf("Unable to send watch event: %s", err.Error())
 		}
+	})
+	if err != nil {
+		releaseSlot()
+		return nil, err
 	}
-	return "sh"
+	return func() {
+		stop()
+		releaseSlot()
+	}, nil
 }
@@ -0,0 +1,30 @@
+package operator
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mogenius/punq/services"
+)
+
+func TestIsRevokedCredential(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"revoked session", services.ErrInvalidRefreshToken, true},
+		{"deleted api token", services.ErrInvalidApiToken, true},
+		{"disabled user", services.ErrUserDisabled, true},
+		{"deleted user", fmt.Errorf("check: %w", services.ErrUserNotFound), true},
+		{"cluster unavailable", errors.New("Failed to get 'punq/punq-users' secret."), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRevokedCredential(tt.err); got != tt.want {
+				t.Errorf("isRevokedCredential(%v) = %t, want %t", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/mogenius/punq/logger"
 	"github.com/mogenius/punq/services"
 	"github.com/mogenius/punq/utils"
 
@@ -27,7 +29,9 @@ import (
 	v1Rbac "k8s.io/api/rbac/v1"
 	v1Scheduling "k8s.io/api/scheduling/v1"
 	v1Storage "k8s.io/api/storage/v1"
-	apiExt "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiExtV1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
 )
 
 func InitWorkloadRoutes(router *gin.Engine) {
@@ -36,19 +40,21 @@ func InitWorkloadRoutes(router *gin.Engine) {
 	{
 		workloadRoutes.GET("/templates", Auth(dtos.READER), allWorkloadTemplates)
 		workloadRoutes.GET("/available-resources", Auth(dtos.READER), allKubernetesResources)
+		workloadRoutes.GET("/logs/:namespace", Authorize(kubernetes.RES_POD), RequireContextId(), validateParam("namespace"), logsAggregated)    // PARAM: namespace
+		workloadRoutes.GET("/logs/:namespace/export", Authorize(kubernetes.RES_POD), RequireContextId(), validateParam("namespace"), logsExport) // PARAM: namespace
 
 		// namespace
-		namespaceWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_NAMESPACE)), Auth(dtos.USER), RequireContextId())
+		namespaceWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_NAMESPACE)), Authorize(kubernetes.RES_NAMESPACE), RequireContextId())
 		{
-			namespaceWorkloadRoutes.GET("/", allNamespaces)                                                    // PARAM: -
-			namespaceWorkloadRoutes.GET("/describe/:name", validateParam("name"), describeNamespaces)          // PARAM: name
-			namespaceWorkloadRoutes.DELETE("/:name", Auth(dtos.ADMIN), validateParam("name"), deleteNamespace) // PARAM: name
-			namespaceWorkloadRoutes.PATCH("/", patchNamespace)                                                 // BODY: json-object
-			namespaceWorkloadRoutes.POST("/", createNamespace)                                                 // BODY: yaml-object
+			namespaceWorkloadRoutes.GET("/", allNamespaces)                                           // PARAM: -
+			namespaceWorkloadRoutes.GET("/describe/:name", validateParam("name"), describeNamespaces) // PARAM: name
+			namespaceWorkloadRoutes.DELETE("/:name", validateParam("name"), deleteNamespace)          // PARAM: name
+			namespaceWorkloadRoutes.PATCH("/", patchNamespace)                                        // BODY: json-object
+			namespaceWorkloadRoutes.POST("/", createNamespace)                                        // BODY: yaml-object
 		}
 
 		// pod
-		podWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_POD)), Auth(dtos.USER), RequireContextId())
+		podWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_POD)), Authorize(kubernetes.RES_POD), RequireContextId())
 		{
 			podWorkloadRoutes.GET("/", allPods)
 			podWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describePod) // PARAM: namespace
@@ -59,17 +65,18 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// deployment
-		deploymentWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_DEPLOYMENT)), Auth(dtos.USER), RequireContextId())
+		deploymentWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_DEPLOYMENT)), Authorize(kubernetes.RES_DEPLOYMENT), RequireContextId())
 		{
 			deploymentWorkloadRoutes.GET("/", allDeployments)                                                                  // PARAM: namespace
 			deploymentWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeDeployment) // PARAM: namespace, name
 			deploymentWorkloadRoutes.DELETE("/:namespace/:name", validateParam("namespace", "name"), deleteDeployment)         // PARAM: namespace, name
+			deploymentWorkloadRoutes.POST("/restart/:namespace/:name", validateParam("namespace", "name"), restartDeployment)  // PARAM: namespace, name
 			deploymentWorkloadRoutes.PATCH("/", patchDeployment)                                                               // BODY: json-object
 			deploymentWorkloadRoutes.POST("/´", createDeployment)                                                              // BODY: yaml-object
 		}
 
 		// service
-		serviceWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_SERVICE)), Auth(dtos.USER), RequireContextId())
+		serviceWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_SERVICE)), Authorize(kubernetes.RES_SERVICE), RequireContextId())
 		{
 			serviceWorkloadRoutes.GET("/", allServices)                                                                  // PARAM: namespace
 			serviceWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeService) // PARAM: namespace, name
@@ -79,7 +86,7 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// ingress
-		ingressWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_INGRESS)), Auth(dtos.USER), RequireContextId())
+		ingressWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_INGRESS)), Authorize(kubernetes.RES_INGRESS), RequireContextId())
 		{
 			ingressWorkloadRoutes.GET("/", allIngresses)                                                                 // PARAM: namespace
 			ingressWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeIngress) // PARAM: namespace, name
@@ -89,17 +96,17 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// configmap
-		configmapWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_CONFIG_MAP)), Auth(dtos.USER), RequireContextId())
+		configmapWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_CONFIG_MAP)), Authorize(kubernetes.RES_CONFIG_MAP), RequireContextId())
 		{
 			configmapWorkloadRoutes.GET("/", allConfigmaps)                                                                  // PARAM: namespace
 			configmapWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeConfigmap) // PARAM: namespace, name
 			configmapWorkloadRoutes.DELETE("/:namespace/:name", validateParam("namespace", "name"), deleteConfigmap)         // PARAM: namespace, name
 			configmapWorkloadRoutes.PATCH("/", patchConfigmap)                                                               // BODY: json-object
-			workloadRoutes.POST("/´", createConfigmap)                                                                       // BODY: yaml-object
+			configmapWorkloadRoutes.POST("/", createConfigmap)                                                               // BODY: yaml-object
 		}
 
 		// secret
-		secretWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_SECRET)), Auth(dtos.ADMIN), RequireContextId())
+		secretWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_SECRET)), Authorize(kubernetes.RES_SECRET), RequireContextId())
 		{
 			secretWorkloadRoutes.GET("/", allSecrets)                                                                  // PARAM: namespace
 			secretWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeSecret) // PARAM: namespace, name
@@ -109,14 +116,14 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// node
-		nodeWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_NODE)), Auth(dtos.USER), RequireContextId())
+		nodeWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_NODE)), Authorize(kubernetes.RES_NODE), RequireContextId())
 		{
 			nodeWorkloadRoutes.GET("/", allNodes)                                          // -
 			nodeWorkloadRoutes.GET("/describe/:name", validateParam("name"), describeNode) // PARAM: namespace
 		}
 
 		// daemon-set
-		daemonSetWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_DAEMON_SET)), Auth(dtos.USER), RequireContextId())
+		daemonSetWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_DAEMON_SET)), Authorize(kubernetes.RES_DAEMON_SET), RequireContextId())
 		{
 			daemonSetWorkloadRoutes.GET("/", allDaemonSets)                                                                  // PARAM: namespace
 			daemonSetWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeDaemonSet) // PARAM: namespace, name
@@ -127,7 +134,7 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// stateful-set
-		statefulSetWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_STATEFUL_SET)), Auth(dtos.USER), RequireContextId())
+		statefulSetWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_STATEFUL_SET)), Authorize(kubernetes.RES_STATEFUL_SET), RequireContextId())
 		{
 			statefulSetWorkloadRoutes.GET("/", allStatefulSets)                                                                  // PARAM: namespace
 			statefulSetWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeStatefulSet) // PARAM: namespace, name
@@ -137,7 +144,7 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// job
-		jobWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_JOB)), Auth(dtos.USER), RequireContextId())
+		jobWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_JOB)), Authorize(kubernetes.RES_JOB), RequireContextId())
 		{
 			jobWorkloadRoutes.GET("/", allJobs)                                                                  // PARAM: namespace
 			jobWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeJob) // PARAM: namespace, name
@@ -147,7 +154,7 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// cron-job
-		cronJobWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_CRON_JOB)), Auth(dtos.USER), RequireContextId())
+		cronJobWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_CRON_JOB)), Authorize(kubernetes.RES_CRON_JOB), RequireContextId())
 		{
 			cronJobWorkloadRoutes.GET("/", allCronJobs)                                                                  // PARAM: namespace
 			cronJobWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeCronJob) // PARAM: namespace, name
@@ -157,7 +164,7 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// replicaset
-		replicaSetWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_REPLICA_SET)), Auth(dtos.USER), RequireContextId())
+		replicaSetWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_REPLICA_SET)), Authorize(kubernetes.RES_REPLICA_SET), RequireContextId())
 		{
 			replicaSetWorkloadRoutes.GET("/", allReplicasets)                                                                  // PARAM: namespace
 			replicaSetWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeReplicaset) // PARAM: namespace, name
@@ -167,7 +174,7 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// persistent-volume
-		persistentVolumeWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_PERSISTENT_VOLUME)), Auth(dtos.ADMIN), RequireContextId())
+		persistentVolumeWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_PERSISTENT_VOLUME)), Authorize(kubernetes.RES_PERSISTENT_VOLUME), RequireContextId())
 		{
 			persistentVolumeWorkloadRoutes.GET("/", allPersistentVolumes)                                          // PARAM: -
 			persistentVolumeWorkloadRoutes.GET("/describe/:name", validateParam("name"), describePersistentVolume) // PARAM: name
@@ -177,34 +184,34 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// persistent-volume-claim
-		persistentVolumeClaimWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_PERSISTENT_VOLUME_CLAIM)), RequireContextId())
+		persistentVolumeClaimWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_PERSISTENT_VOLUME_CLAIM)), Authorize(kubernetes.RES_PERSISTENT_VOLUME_CLAIM), RequireContextId())
 		{
-			persistentVolumeClaimWorkloadRoutes.GET("/", Auth(dtos.USER), allPersistentVolumeClaims)                                                                  // PARAM: namespace
-			persistentVolumeClaimWorkloadRoutes.GET("/describe/:namespace/:name", Auth(dtos.USER), validateParam("namespace", "name"), describePersistentVolumeClaim) // PARAM: namespace, name
-			persistentVolumeClaimWorkloadRoutes.DELETE("/:namespace/:name", Auth(dtos.ADMIN), validateParam("namespace", "name"), deletePersistentVolumeClaim)        // PARAM: namespace, name
-			persistentVolumeClaimWorkloadRoutes.PATCH("/", Auth(dtos.ADMIN), patchPersistentVolumeClaim)                                                              // BODY: json-object
-			persistentVolumeClaimWorkloadRoutes.POST("/", Auth(dtos.ADMIN), createPersistentVolumeClaim)                                                              // BODY: yaml-object
+			persistentVolumeClaimWorkloadRoutes.GET("/", allPersistentVolumeClaims)                                                                  // PARAM: namespace
+			persistentVolumeClaimWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describePersistentVolumeClaim) // PARAM: namespace, name
+			persistentVolumeClaimWorkloadRoutes.DELETE("/:namespace/:name", validateParam("namespace", "name"), deletePersistentVolumeClaim)         // PARAM: namespace, name
+			persistentVolumeClaimWorkloadRoutes.PATCH("/", patchPersistentVolumeClaim)                                                               // BODY: json-object
+			persistentVolumeClaimWorkloadRoutes.POST("/", createPersistentVolumeClaim)                                                               // BODY: yaml-object
 		}
 
 		// horizontal-pod-autoscaler
-		horizontalPodAutoscalerWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_HORIZONTAL_POD_AUTOSCALER)), RequireContextId())
+		horizontalPodAutoscalerWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_HORIZONTAL_POD_AUTOSCALER)), Authorize(kubernetes.RES_HORIZONTAL_POD_AUTOSCALER), RequireContextId())
 		{
-			horizontalPodAutoscalerWorkloadRoutes.GET("/", Auth(dtos.USER), allHpas)                                                                  // PARAM: namespace
-			horizontalPodAutoscalerWorkloadRoutes.GET("/describe/:namespace/:name", Auth(dtos.USER), validateParam("namespace", "name"), describeHpa) // PARAM: namespace, name
-			horizontalPodAutoscalerWorkloadRoutes.DELETE("/:namespace/:name", Auth(dtos.ADMIN), validateParam("namespace", "name"), deleteHpa)        // PARAM: namespace, name
-			horizontalPodAutoscalerWorkloadRoutes.PATCH("/", Auth(dtos.ADMIN), patchHpa)                                                              // BODY: json-object
-			horizontalPodAutoscalerWorkloadRoutes.POST("/", Auth(dtos.ADMIN), createHpa)                                                              // BODY: yaml-object
+			horizontalPodAutoscalerWorkloadRoutes.GET("/", allHpas)                                                                  // PARAM: namespace
+			horizontalPodAutoscalerWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeHpa) // PARAM: namespace, name
+			horizontalPodAutoscalerWorkloadRoutes.DELETE("/:namespace/:name", validateParam("namespace", "name"), deleteHpa)         // PARAM: namespace, name
+			horizontalPodAutoscalerWorkloadRoutes.PATCH("/", patchHpa)                                                               // BODY: json-object
+			horizontalPodAutoscalerWorkloadRoutes.POST("/", createHpa)                                                               // BODY: yaml-object
 		}
 
 		// event
-		eventWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_EVENT)), Auth(dtos.USER), RequireContextId())
+		eventWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_EVENT)), Authorize(kubernetes.RES_EVENT), RequireContextId())
 		{
 			eventWorkloadRoutes.GET("/", allEvents)                                                                  // PARAM: namespace
 			eventWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeEvent) // PARAM: namespace, name
 		}
 
 		// certificate
-		certificateWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_CERTIFICATE)), Auth(dtos.USER), RequireContextId())
+		certificateWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_CERTIFICATE)), Authorize(kubernetes.RES_CERTIFICATE), RequireContextId())
 		{
 			certificateWorkloadRoutes.GET("/", allCertificates)                                                                  // PARAM: namespace
 			certificateWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeCertificate) // PARAM: namespace, name
@@ -214,7 +221,7 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// certificate-request
-		certificateRequestWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_CERTIFICATE_REQUEST)), Auth(dtos.USER), RequireContextId())
+		certificateRequestWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_CERTIFICATE_REQUEST)), Authorize(kubernetes.RES_CERTIFICATE_REQUEST), RequireContextId())
 		{
 			certificateRequestWorkloadRoutes.GET("/", allCertificateRequests)                                                                  // PARAM: namespace
 			certificateRequestWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeCertificateRequest) // PARAM: namespace, name
@@ -224,7 +231,7 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// orders
-		ordersWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_ORDER)), Auth(dtos.USER), RequireContextId())
+		ordersWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_ORDER)), Authorize(kubernetes.RES_ORDER), RequireContextId())
 		{
 			ordersWorkloadRoutes.GET("/", allOrders)                                                                  // PARAM: namespace
 			ordersWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeOrder) // PARAM: namespace, name
@@ -234,7 +241,7 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// issuer
-		issuerWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_ISSUER)), Auth(dtos.USER), RequireContextId())
+		issuerWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_ISSUER)), Authorize(kubernetes.RES_ISSUER), RequireContextId())
 		{
 			issuerWorkloadRoutes.GET("/", allIssuers)                                                                  // PARAM: namespace
 			issuerWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeIssuer) // PARAM: namespace, name
@@ -244,7 +251,7 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// cluster-issuer
-		clusterIssuerWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_CLUSTER_ISSUER)), Auth(dtos.ADMIN), RequireContextId())
+		clusterIssuerWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_CLUSTER_ISSUER)), Authorize(kubernetes.RES_CLUSTER_ISSUER), RequireContextId())
 		{
 			clusterIssuerWorkloadRoutes.GET("/", allClusterIssuers)                                          // PARAM: -
 			clusterIssuerWorkloadRoutes.GET("/describe/:name", validateParam("name"), describeClusterIssuer) // PARAM: name
@@ -254,7 +261,7 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// service-account
-		serviceAccountWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_SERVICE_ACCOUNT)), Auth(dtos.ADMIN), RequireContextId())
+		serviceAccountWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_SERVICE_ACCOUNT)), Authorize(kubernetes.RES_SERVICE_ACCOUNT), RequireContextId())
 		{
 			serviceAccountWorkloadRoutes.GET("/", allServiceAccounts)                                                                  // PARAM: namespace
 			serviceAccountWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeServiceAccount) // PARAM: namespace, name
@@ -264,27 +271,27 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// role
-		roleWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_ROLE)), RequireContextId())
+		roleWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_ROLE)), Authorize(kubernetes.RES_ROLE), RequireContextId())
 		{
-			roleWorkloadRoutes.GET("/", Auth(dtos.USER), allRoles)                                                                  // PARAM: namespace
-			roleWorkloadRoutes.GET("/describe/:namespace/:name", Auth(dtos.USER), validateParam("namespace", "name"), describeRole) // PARAM: namespace, name
-			roleWorkloadRoutes.DELETE("/:namespace/:name", Auth(dtos.ADMIN), validateParam("namespace", "name"), deleteRole)        // PARAM: namespace, name
-			roleWorkloadRoutes.PATCH("/", Auth(dtos.ADMIN), patchRole)                                                              // BODY: json-object
-			roleWorkloadRoutes.POST("/", Auth(dtos.ADMIN), createRole)                                                              // BODY: yaml-object
+			roleWorkloadRoutes.GET("/", allRoles)                                                                  // PARAM: namespace
+			roleWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeRole) // PARAM: namespace, name
+			roleWorkloadRoutes.DELETE("/:namespace/:name", validateParam("namespace", "name"), deleteRole)         // PARAM: namespace, name
+			roleWorkloadRoutes.PATCH("/", patchRole)                                                               // BODY: json-object
+			roleWorkloadRoutes.POST("/", createRole)                                                               // BODY: yaml-object
 		}
 
 		// role-binding
-		roleBindingWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_ROLE_BINDING)), RequireContextId())
+		roleBindingWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_ROLE_BINDING)), Authorize(kubernetes.RES_ROLE_BINDING), RequireContextId())
 		{
-			roleBindingWorkloadRoutes.GET("/", Auth(dtos.USER), allRoleBindings)                                                                  // PARAM: namespace
-			roleBindingWorkloadRoutes.GET("/describe/:namespace/:name", Auth(dtos.USER), validateParam("namespace", "name"), describeRoleBinding) // PARAM: namespace, name
-			roleBindingWorkloadRoutes.DELETE("/:namespace/:name", Auth(dtos.ADMIN), validateParam("namespace", "name"), deleteRoleBinding)        // PARAM: namespace, name
-			roleBindingWorkloadRoutes.PATCH("/", Auth(dtos.ADMIN), patchRoleBinding)                                                              // BODY: json-object
-			roleBindingWorkloadRoutes.POST("/", Auth(dtos.ADMIN), createRoleBinding)                                                              // BODY: yaml-object
+			roleBindingWorkloadRoutes.GET("/", allRoleBindings)                                                                  // PARAM: namespace
+			roleBindingWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeRoleBinding) // PARAM: namespace, name
+			roleBindingWorkloadRoutes.DELETE("/:namespace/:name", validateParam("namespace", "name"), deleteRoleBinding)         // PARAM: namespace, name
+			roleBindingWorkloadRoutes.PATCH("/", patchRoleBinding)                                                               // BODY: json-object
+			roleBindingWorkloadRoutes.POST("/", createRoleBinding)                                                               // BODY: yaml-object
 		}
 
 		// cluster-role
-		clusterRoleWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_CLUSTER_ROLE)), Auth(dtos.ADMIN), RequireContextId())
+		clusterRoleWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_CLUSTER_ROLE)), Authorize(kubernetes.RES_CLUSTER_ROLE), RequireContextId())
 		{
 			clusterRoleWorkloadRoutes.GET("/", allClusterRoles)                                          // PARAM: -
 			clusterRoleWorkloadRoutes.GET("/describe/:name", validateParam("name"), describeClusterRole) // PARAM: name
@@ -294,7 +301,7 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// cluster-role-binding
-		clusterRoleBindingWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_CLUSTER_ROLE_BINDING)), Auth(dtos.ADMIN), RequireContextId())
+		clusterRoleBindingWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_CLUSTER_ROLE_BINDING)), Authorize(kubernetes.RES_CLUSTER_ROLE_BINDING), RequireContextId())
 		{
 			clusterRoleBindingWorkloadRoutes.GET("/", allClusterRoleBindings)                                          // PARAM: -
 			clusterRoleBindingWorkloadRoutes.GET("/describe/:name", validateParam("name"), describeClusterRoleBinding) // PARAM: name
@@ -304,7 +311,7 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// volume-attachment
-		volumeAttachmentWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_VOLUME_ATTACHMENT)), Auth(dtos.ADMIN), RequireContextId())
+		volumeAttachmentWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_VOLUME_ATTACHMENT)), Authorize(kubernetes.RES_VOLUME_ATTACHMENT), RequireContextId())
 		{
 			volumeAttachmentWorkloadRoutes.GET("/", allVolumeAttachments)                                          // PARAM: -
 			volumeAttachmentWorkloadRoutes.GET("/describe/:name", validateParam("name"), describeVolumeAttachment) // PARAM: name
@@ -314,37 +321,37 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// network-policy
-		networkPolicyWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_NETWORK_POLICY)), RequireContextId())
+		networkPolicyWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_NETWORK_POLICY)), Authorize(kubernetes.RES_NETWORK_POLICY), RequireContextId())
 		{
-			networkPolicyWorkloadRoutes.GET("/", Auth(dtos.USER), allNetworkPolicies)                                                                 // PARAM: namespace
-			networkPolicyWorkloadRoutes.GET("/describe/:namespace/:name", Auth(dtos.USER), validateParam("namespace", "name"), describeNetworkPolicy) // PARAM: namespace, name
-			networkPolicyWorkloadRoutes.DELETE("/:namespace/:name", Auth(dtos.ADMIN), validateParam("namespace", "name"), deleteNetworkPolicy)        // PARAM: namespace, name
-			networkPolicyWorkloadRoutes.PATCH("/", Auth(dtos.ADMIN), patchNetworkPolicy)                                                              // BODY: json-object
-			networkPolicyWorkloadRoutes.POST("/", Auth(dtos.ADMIN), createNetworkPolicy)                                                              // BODY: yaml-object
+			networkPolicyWorkloadRoutes.GET("/", allNetworkPolicies)                                                                 // PARAM: namespace
+			networkPolicyWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeNetworkPolicy) // PARAM: namespace, name
+			networkPolicyWorkloadRoutes.DELETE("/:namespace/:name", validateParam("namespace", "name"), deleteNetworkPolicy)         // PARAM: namespace, name
+			networkPolicyWorkloadRoutes.PATCH("/", patchNetworkPolicy)                                                               // BODY: json-object
+			networkPolicyWorkloadRoutes.POST("/", createNetworkPolicy)                                                               // BODY: yaml-object
 		}
 
 		// storage-class
-		storageClassWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_STORAGE_CLASS)), RequireContextId())
+		storageClassWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_STORAGE_CLASS)), Authorize(kubernetes.RES_STORAGE_CLASS), RequireContextId())
 		{
-			storageClassWorkloadRoutes.GET("/", Auth(dtos.USER), allStorageClasses)                                         // PARAM: namespace
-			storageClassWorkloadRoutes.GET("/describe/:name", Auth(dtos.USER), validateParam("name"), describeStorageClass) // PARAM: namespace, name
-			storageClassWorkloadRoutes.DELETE("/:name", Auth(dtos.ADMIN), validateParam("name"), deleteStorageClass)        // PARAM: namespace, name
-			storageClassWorkloadRoutes.PATCH("/", Auth(dtos.ADMIN), patchStorageClass)                                      // BODY: json-object
-			storageClassWorkloadRoutes.POST("/", Auth(dtos.ADMIN), createStorageClass)                                      // BODY: yaml-object
+			storageClassWorkloadRoutes.GET("/", allStorageClasses)                                         // PARAM: namespace
+			storageClassWorkloadRoutes.GET("/describe/:name", validateParam("name"), describeStorageClass) // PARAM: namespace, name
+			storageClassWorkloadRoutes.DELETE("/:name", validateParam("name"), deleteStorageClass)         // PARAM: namespace, name
+			storageClassWorkloadRoutes.PATCH("/", patchStorageClass)                                       // BODY: json-object
+			storageClassWorkloadRoutes.POST("/", createStorageClass)                                       // BODY: yaml-object
 		}
 
 		// crds
-		crdsWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_CUSTOM_RESOURCE_DEFINITION)), Auth(dtos.ADMIN), RequireContextId())
+		crdsWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_CUSTOM_RESOURCE_DEFINITION)), Authorize(kubernetes.RES_CUSTOM_RESOURCE_DEFINITION), RequireContextId())
 		{
-			crdsWorkloadRoutes.GET("/", allCrds)                                                            // PARAM: -
-			crdsWorkloadRoutes.GET("/describe/:name", validateParam("name"), Auth(dtos.ADMIN), describeCrd) // PARAM: name
-			crdsWorkloadRoutes.DELETE("/:name", validateParam("name"), Auth(dtos.ADMIN), deleteCrd)         // PARAM: name
-			crdsWorkloadRoutes.PATCH("/", Auth(dtos.ADMIN), patchCrd)                                       // BODY: json-object
-			crdsWorkloadRoutes.POST("/", Auth(dtos.ADMIN), createCrd)                                       // BODY: yaml-object
+			crdsWorkloadRoutes.GET("/", allCrds)                                          // PARAM: -
+			crdsWorkloadRoutes.GET("/describe/:name", validateParam("name"), describeCrd) // PARAM: name
+			crdsWorkloadRoutes.DELETE("/:name", validateParam("name"), deleteCrd)         // PARAM: name
+			crdsWorkloadRoutes.PATCH("/", patchCrd)                                       // BODY: json-object
+			crdsWorkloadRoutes.POST("/", createCrd)                                       // BODY: yaml-object
 		}
 
 		// endpoints
-		endpointsWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_ENDPOINT)), Auth(dtos.USER), RequireContextId())
+		endpointsWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_ENDPOINT)), Authorize(kubernetes.RES_ENDPOINT), RequireContextId())
 		{
 			endpointsWorkloadRoutes.GET("/", allEndpoints)                                                                  // PARAM: namespace
 			endpointsWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeEndpoint) // PARAM: namespace, name
@@ -354,7 +361,7 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// leases
-		leasesWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_LEASE)), Auth(dtos.USER), RequireContextId())
+		leasesWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_LEASE)), Authorize(kubernetes.RES_LEASE), RequireContextId())
 		{
 			leasesWorkloadRoutes.GET("/", allLeases)                                                                  // PARAM: namespace
 			leasesWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeLease) // PARAM: namespace, name
@@ -364,7 +371,7 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// priority-classes
-		priorityClassesWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_PRIORITY_CLASS)), Auth(dtos.ADMIN), RequireContextId())
+		priorityClassesWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_PRIORITY_CLASS)), Authorize(kubernetes.RES_PRIORITY_CLASS), RequireContextId())
 		{
 			priorityClassesWorkloadRoutes.GET("/", allPriorityClasses)                                         // PARAM: -
 			priorityClassesWorkloadRoutes.GET("/describe/:name", validateParam("name"), describePriorityClass) // PARAM: name
@@ -374,7 +381,7 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// volume-snapshots
-		volumeSnapshotsWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_VOLUME_SNAPSHOT)), Auth(dtos.USER), RequireContextId())
+		volumeSnapshotsWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_VOLUME_SNAPSHOT)), Authorize(kubernetes.RES_VOLUME_SNAPSHOT), RequireContextId())
 		{
 			volumeSnapshotsWorkloadRoutes.GET("/", allVolumeSnapshots)                                                                  // PARAM: namespace
 			volumeSnapshotsWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeVolumeSnapshot) // PARAM: namespace, name
@@ -384,7 +391,7 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// resource-quota
-		resourceQuotaWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_RESOURCE_QUOTA)), Auth(dtos.ADMIN), RequireContextId())
+		resourceQuotaWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_RESOURCE_QUOTA)), Authorize(kubernetes.RES_RESOURCE_QUOTA), RequireContextId())
 		{
 			resourceQuotaWorkloadRoutes.GET("/", allResourceQuotas)                                                                  // PARAM: namespace
 			resourceQuotaWorkloadRoutes.GET("/describe/:namespace/:name", validateParam("namespace", "name"), describeResourceQuota) // PARAM: namespace, name
@@ -394,7 +401,7 @@ func InitWorkloadRoutes(router *gin.Engine) {
 		}
 
 		// ingress-classes
-		ingressClassesWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_INGRESS_CLASS)), Auth(dtos.ADMIN), RequireContextId())
+		ingressClassesWorkloadRoutes := workloadRoutes.Group(fmt.Sprintf("/%s", strings.ToLower(kubernetes.RES_INGRESS_CLASS)), Authorize(kubernetes.RES_INGRESS_CLASS), RequireContextId())
 		{
 			ingressClassesWorkloadRoutes.GET("/", allIngressClasses)                                         // PARAM: -
 			ingressClassesWorkloadRoutes.GET("/describe/:name", validateParam("name"), describeIngressClass) // PARAM: name
@@ -402,6 +409,29 @@ func InitWorkloadRoutes(router *gin.Engine) {
 			ingressClassesWorkloadRoutes.PATCH("/", patchIngressClass)                                       // BODY: json-object
 			ingressClassesWorkloadRoutes.POST("/", createIngressClass)                                       // BODY: yaml-object
 		}
+
+		// helm releases
+		helmReleaseWorkloadRoutes := workloadRoutes.Group("/helm-release", Authorize(kubernetes.RES_HELM_RELEASE), RequireContextId())
+		{
+			helmReleaseWorkloadRoutes.GET("/", allHelmReleases)                                                                                  // PARAM: namespace
+			helmReleaseWorkloadRoutes.GET("/:namespace/:name", validateParam("namespace", "name"), getHelmRelease)                               // PARAM: revision
+			helmReleaseWorkloadRoutes.GET("/:namespace/:name/values", validateParam("namespace", "name"), helmReleaseValues)                     // PARAM: revision, all
+			helmReleaseWorkloadRoutes.GET("/:namespace/:name/manifest", validateParam("namespace", "name"), helmReleaseManifest)                 // PARAM: revision
+			helmReleaseWorkloadRoutes.GET("/:namespace/:name/history", validateParam("namespace", "name"), helmReleaseHistory)                   // PARAM: -
+			helmReleaseWorkloadRoutes.GET("/:namespace/:name/diff", validateParam("namespace", "name"), helmReleaseDiff)                         // PARAM: from, to
+			helmReleaseWorkloadRoutes.POST("/:namespace/:name/rollback/:revision", validateParam("namespace", "name", "revision"), helmRollback) // PARAM: -
+			helmReleaseWorkloadRoutes.DELETE("/:namespace/:name", validateParam("namespace", "name"), helmUninstall)                             // PARAM: -
+		}
+
+		// custom resources (instances of crds only, built-in kinds are rejected)
+		customWorkloadRoutes := workloadRoutes.Group("/custom", Authorize(kubernetes.RES_CUSTOM_RESOURCE), RequireContextId(), AuthorizeCustomResource())
+		{
+			customWorkloadRoutes.GET("/:group/:version/:resource", validateParam("group", "version", "resource"), allCustomResources)                    // PARAM: namespace
+			customWorkloadRoutes.GET("/:group/:version/:resource/:name", validateParam("group", "version", "resource", "name"), getCustomResource)       // PARAM: namespace
+			customWorkloadRoutes.DELETE("/:group/:version/:resource/:name", validateParam("group", "version", "resource", "name"), deleteCustomResource) // PARAM: namespace
+			customWorkloadRoutes.PATCH("/:group/:version/:resource", validateParam("group", "version", "resource"), patchCustomResource)                 // BODY: json-object
+			customWorkloadRoutes.POST("/:group/:version/:resource", validateParam("group", "version", "resource"), createCustomResource)                 // BODY: yaml-object
+		}
 	}
 }
 
@@ -422,12 +452,20 @@ func allWorkloadTemplates(c *gin.Context) {
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func allKubernetesResources(c *gin.Context) {
-	user, err := CheckUserAuthorization(c)
-	if err != nil || user == nil {
-		utils.MalformedMessage(c, "User not found.")
+	user := services.GetGinContextUser(c)
+	if user == nil {
+		utils.Unauthorized(c, "user not found")
 		return
 	}
-	c.JSON(http.StatusOK, kubernetes.WorkloadsForAccesslevel(user.AccessLevel))
+	c.JSON(http.StatusOK, services.WorkloadsForUser(user, services.GetGinContextId(c)))
+}
+
+// "?format=json" returns object, events, owner chain and pods instead of the kubectl describe text
+func describeResource(c *gin.Context, resource string, namespace string, name string) utils.K8sWorkloadResult {
+	if c.Query("format") == "json" {
+		return kubernetes.DescribeK8sStructured(resource, namespace, name, services.GetGinKubeContextId(c))
+	}
+	return kubernetes.DescribeK8s(resource, namespace, name, services.GetGinKubeContextId(c))
 }
 
 // ---------------------- NAMESPACES ----------------------
@@ -441,7 +479,7 @@ func allKubernetesResources(c *gin.Context) {
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func allNamespaces(c *gin.Context) {
-	c.JSON(http.StatusOK, kubernetes.ListK8sNamespaces("", services.GetGinContextId(c)))
+	c.JSON(http.StatusOK, kubernetes.ListK8sNamespaces("", services.GetGinKubeContextId(c)))
 }
 
 // NAMESPACES
@@ -449,12 +487,13 @@ func allNamespaces(c *gin.Context) {
 // @Produce json
 // @Success 200 {array} v1.Namespace
 // @Router /backend/workload/namespace/describe/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param name path string true "name of the namespace"
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func describeNamespaces(c *gin.Context) {
 	name := c.Param("name")
-	c.JSON(http.StatusOK, kubernetes.DescribeK8sNamespace(name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_NAMESPACE, "", name))
 }
 
 // NAMESPACES
@@ -467,11 +506,11 @@ func describeNamespaces(c *gin.Context) {
 // @Body {"type": "object"}
 func createNamespace(c *gin.Context) {
 	var data v1.Namespace
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		return
 	}
-	c.JSON(http.StatusCreated, kubernetes.CreateK8sNamespace(data, services.GetGinContextId(c)))
+	c.JSON(http.StatusCreated, kubernetes.CreateK8sNamespace(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -487,7 +526,7 @@ func patchNamespace(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sNamespace(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sNamespace(data, services.GetGinKubeContextId(c)))
 }
 
 // NAMESPACES
@@ -500,7 +539,7 @@ func patchNamespace(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func deleteNamespace(c *gin.Context) {
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sNamespaceBy(name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sNamespaceBy(name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -519,13 +558,14 @@ func deleteNamespace(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func allPods(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllK8sPods(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllK8sPods(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/pod/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true  "namespace name"
 // @Param name path string true  "pod name"
 // @Security Bearer
@@ -533,7 +573,7 @@ func allPods(c *gin.Context) {
 func describePod(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sPod(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_POD, namespace, name))
 }
 
 // @Tags Workloads
@@ -543,6 +583,7 @@ func describePod(c *gin.Context) {
 // @Param namespace path string true  "namespace name"
 // @Param name path string true  "pod name"
 // @Param since-seconds query string false  "since-seconds"
+// @Param container query string false  "container name (required for multi-container pods)"
 // @Security Bearer
 // @Param X-Context-Id header string true "X-Context-Id"
 func logsPod(c *gin.Context) {
@@ -554,7 +595,7 @@ func logsPod(c *gin.Context) {
 		i = -1
 	}
 
-	req, err := kubernetes.StreamLog(namespace, name, i, services.GetGinContextId(c))
+	req, err := kubernetes.StreamLog(namespace, name, c.Query("container"), i, services.GetGinKubeContextId(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -594,6 +635,123 @@ func logsPod(c *gin.Context) {
 	})
 }
 
+// @Tags Workloads
+// @Produce text/event-stream
+// @Success 200 {string} string "streaming data (one kubernetes.K8sLogLine json per message)"
+// @Router /backend/workload/logs/{namespace} [get]
+// @Param namespace path string true  "namespace name"
+// @Param selector query string false  "label selector (e.g. app=web,tier!=cache)"
+// @Param owner-kind query string false  "Deployment, StatefulSet, DaemonSet, ReplicaSet or Job"
+// @Param owner-name query string false  "name of the owner"
+// @Param container query []string false  "container names (default: all)"
+// @Param include query string false  "only lines matching this regex"
+// @Param exclude query string false  "drop lines matching this regex"
+// @Param since-seconds query string false  "since-seconds"
+// @Param tail query string false  "lines per container"
+// @Param follow query bool false  "keep streaming"
+// @Security Bearer
+// @Param X-Context-Id header string true "X-Context-Id"
+func logsAggregated(c *gin.Context) {
+	opts := kubernetes.K8sLogStreamOptions{
+		Namespace:     c.Param("namespace"),
+		LabelSelector: c.Query("selector"),
+		OwnerKind:     c.Query("owner-kind"),
+		OwnerName:     c.Query("owner-name"),
+		Containers:    c.QueryArray("container"),
+		Include:       c.Query("include"),
+		Exclude:       c.Query("exclude"),
+		Follow:        c.Query("follow") == "true",
+	}
+	opts.SinceSeconds, _ = strconv.ParseInt(c.Query("since-seconds"), 10, 64)
+	opts.TailLines, _ = strconv.ParseInt(c.Query("tail"), 10, 64)
+
+	logStream, err := kubernetes.NewAggregatedLogStream(opts, services.GetGinKubeContextId(c))
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+
+	// set header
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+	c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+
+	lines := make(chan kubernetes.K8sLogLine)
+	done := make(chan error, 1)
+	go func() {
+		done <- logStream.Run(c.Request.Context(), func(line kubernetes.K8sLogLine) {
+			select {
+			case lines <- line:
+			case <-c.Request.Context().Done():
+			}
+		})
+	}()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case line := <-lines:
+			c.SSEvent("message", line)
+			return true
+		case err := <-done:
+			if err != nil {
+				c.SSEvent("error", err.Error())
+			}
+			return false
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// @Tags Workloads
+// @Produce application/gzip
+// @Success 200 {file} file "tar.gz with logs, previous logs, events and describe output"
+// @Router /backend/workload/logs/{namespace}/export [get]
+// @Param namespace path string true  "namespace name"
+// @Param selector query string false  "label selector (e.g. app=web,tier!=cache)"
+// @Param owner-kind query string false  "Deployment, StatefulSet, DaemonSet, ReplicaSet or Job"
+// @Param owner-name query string false  "name of the owner"
+// @Param since query string false  "RFC3339 timestamp"
+// @Param until query string false  "RFC3339 timestamp"
+// @Security Bearer
+// @Param X-Context-Id header string true "X-Context-Id"
+func logsExport(c *gin.Context) {
+	opts := kubernetes.K8sLogExportOptions{
+		Namespace:     c.Param("namespace"),
+		LabelSelector: c.Query("selector"),
+		OwnerKind:     c.Query("owner-kind"),
+		OwnerName:     c.Query("owner-name"),
+	}
+	for key, target := range map[string]*time.Time{"since": &opts.Since, "until": &opts.Until} {
+		if c.Query(key) == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, c.Query(key))
+		if err != nil {
+			utils.MalformedMessage(c, fmt.Sprintf("%s: %s", key, err.Error()))
+			return
+		}
+		*target = parsed
+	}
+
+	export, err := kubernetes.PrepareLogExport(opts, services.GetGinKubeContextId(c))
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+
+	// the archive is streamed, errors after this point can only abort the download
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", kubernetes.LogExportFileName(opts)))
+	c.Header("Content-Type", "application/gzip")
+	c.Status(http.StatusOK)
+	err = export.Write(c.Writer)
+	if err != nil {
+		logger.Log.Errorf("Log export of namespace '%s' failed: %s", opts.Namespace, err.Error())
+		_ = c.Error(err)
+	}
+}
+
 // @Tags Workloads
 // @Produce json
 // @Success 200
@@ -605,7 +763,7 @@ func logsPod(c *gin.Context) {
 func deletePod(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sPodBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sPodBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -626,7 +784,7 @@ func patchPod(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sPod(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sPod(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -637,12 +795,12 @@ func patchPod(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createPod(c *gin.Context) {
 	var data v1.Pod
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sPod(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sPod(data, services.GetGinKubeContextId(c)))
 
 }
 
@@ -657,13 +815,14 @@ func createPod(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func allDeployments(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllK8sDeployments(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllK8sDeployments(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/deployment/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true  "namespace name"
 // @Param name path string true  "deployment name"
 // @Security Bearer
@@ -671,7 +830,7 @@ func allDeployments(c *gin.Context) {
 func describeDeployment(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sDeployment(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_DEPLOYMENT, namespace, name))
 }
 
 // @Tags Workloads
@@ -685,7 +844,7 @@ func describeDeployment(c *gin.Context) {
 func deleteDeployment(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sDeploymentBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sDeploymentBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -693,6 +852,20 @@ func deleteDeployment(c *gin.Context) {
 	c.Status(http.StatusOK)
 }
 
+// @Tags Workloads
+// @Produce json
+// @Success 200 {object} utils.K8sWorkloadResult
+// @Router /backend/workload/deployment/restart/{namespace}/{name} [post]
+// @Param namespace path string true  "namespace name"
+// @Param name path string true  "deployment name"
+// @Security Bearer
+// @Param string header string true "X-Context-Id"
+func restartDeployment(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	utils.HttpRespondForWorkloadResult(c, kubernetes.RestartK8sDeployment(namespace, name, services.GetGinKubeContextId(c)))
+}
+
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
@@ -706,7 +879,7 @@ func patchDeployment(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sDeployment(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sDeployment(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -717,12 +890,12 @@ func patchDeployment(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createDeployment(c *gin.Context) {
 	var data v1Apps.Deployment
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sDeployment(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sDeployment(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- SERVICES ----------------------
@@ -736,13 +909,14 @@ func createDeployment(c *gin.Context) {
 // @Param namespace query string false  "namespace name"
 func allServices(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllK8sServices(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllK8sServices(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/service/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true  "namespace name"
 // @Param name path string true  "service name"
 // @Security Bearer
@@ -750,7 +924,7 @@ func allServices(c *gin.Context) {
 func describeService(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sService(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_SERVICE, namespace, name))
 }
 
 // @Tags Workloads
@@ -764,7 +938,7 @@ func describeService(c *gin.Context) {
 func deleteService(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sServiceBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sServiceBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -785,7 +959,7 @@ func patchService(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sService(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sService(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -796,12 +970,12 @@ func patchService(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createService(c *gin.Context) {
 	var data v1.Service
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sService(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sService(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- INGRESSES ----------------------
@@ -815,13 +989,14 @@ func createService(c *gin.Context) {
 // @Param namespace query string false  "namespace name"
 func allIngresses(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllK8sIngresses(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllK8sIngresses(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/ingress/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true  "namespace name"
 // @Param name path string true  "ingress name"
 // @Security Bearer
@@ -829,7 +1004,7 @@ func allIngresses(c *gin.Context) {
 func describeIngress(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sIngress(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_INGRESS, namespace, name))
 }
 
 // @Tags Workloads
@@ -843,7 +1018,7 @@ func describeIngress(c *gin.Context) {
 func deleteIngress(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sIngressBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sIngressBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -864,7 +1039,7 @@ func patchIngress(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sIngress(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sIngress(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -875,12 +1050,12 @@ func patchIngress(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createIngress(c *gin.Context) {
 	var data v1Networking.Ingress
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sIngress(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sIngress(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- CONFIGMAPS ----------------------
@@ -894,13 +1069,14 @@ func createIngress(c *gin.Context) {
 // @Param namespace query string false  "namespace name"
 func allConfigmaps(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllK8sConfigmaps(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllK8sConfigmaps(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/configmap/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true "namespace"
 // @Param name path string true "configmap name"
 // @Security Bearer
@@ -908,7 +1084,7 @@ func allConfigmaps(c *gin.Context) {
 func describeConfigmap(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sConfigmap(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_CONFIG_MAP, namespace, name))
 }
 
 // @Tags Workloads
@@ -922,7 +1098,7 @@ func describeConfigmap(c *gin.Context) {
 func deleteConfigmap(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sConfigmapBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sConfigmapBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -943,7 +1119,7 @@ func patchConfigmap(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sConfigMap(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sConfigMap(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -954,12 +1130,12 @@ func patchConfigmap(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createConfigmap(c *gin.Context) {
 	var data v1.ConfigMap
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sConfigMap(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sConfigMap(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- SECRETS ----------------------
@@ -973,13 +1149,14 @@ func createConfigmap(c *gin.Context) {
 // @Param namespace query string false  "namespace name"
 func allSecrets(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllK8sSecrets(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllK8sSecrets(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/secret/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true  "namespace name"
 // @Param name path string true  "secret name"
 // @Security Bearer
@@ -987,7 +1164,7 @@ func allSecrets(c *gin.Context) {
 func describeSecret(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sSecret(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_SECRET, namespace, name))
 }
 
 // @Tags Workloads
@@ -1001,7 +1178,7 @@ func describeSecret(c *gin.Context) {
 func deleteSecret(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sSecretBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sSecretBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -1022,7 +1199,7 @@ func patchSecret(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sSecret(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sSecret(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -1034,12 +1211,12 @@ func patchSecret(c *gin.Context) {
 // @Body {"type": "object"}
 func createSecret(c *gin.Context) {
 	var data v1.Secret
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sSecret(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sSecret(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- NODES ----------------------
@@ -1051,19 +1228,20 @@ func createSecret(c *gin.Context) {
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func allNodes(c *gin.Context) {
-	utils.HttpRespondForWorkloadResult(c, kubernetes.ListK8sNodes(services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.ListK8sNodes(services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/node/describe/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param name path string true  "node name"
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func describeNode(c *gin.Context) {
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sNode(name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_NODE, "", name))
 }
 
 // ---------------------- DEAMONSETS ----------------------
@@ -1077,13 +1255,14 @@ func describeNode(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func allDaemonSets(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllK8sDaemonsets(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllK8sDaemonsets(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/daemon-set/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true "namespace"
 // @Param namespace path string true "name"
 // @Security Bearer
@@ -1091,7 +1270,7 @@ func allDaemonSets(c *gin.Context) {
 func describeDaemonSet(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sDaemonSet(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_DAEMON_SET, namespace, name))
 }
 
 // @Tags Workloads
@@ -1105,7 +1284,7 @@ func describeDaemonSet(c *gin.Context) {
 func deleteDaemonSet(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sDaemonSetBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sDaemonSetBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -1126,7 +1305,7 @@ func patchDaemonSet(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sDaemonSet(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sDaemonSet(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -1137,12 +1316,12 @@ func patchDaemonSet(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createDaemonSet(c *gin.Context) {
 	var data v1Apps.DaemonSet
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sDaemonSet(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sDaemonSet(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- STATEFULSETS ----------------------
@@ -1156,13 +1335,14 @@ func createDaemonSet(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func allStatefulSets(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllStatefulSets(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllStatefulSets(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/stateful-set/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true "namespace"
 // @Param name path string true "stateful-set name"
 // @Security Bearer
@@ -1170,7 +1350,7 @@ func allStatefulSets(c *gin.Context) {
 func describeStatefulSet(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sStatefulset(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_STATEFUL_SET, namespace, name))
 }
 
 // @Tags Workloads
@@ -1184,7 +1364,7 @@ func describeStatefulSet(c *gin.Context) {
 func deleteStatefulSet(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sStatefulsetBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sStatefulsetBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -1205,7 +1385,7 @@ func patchStatefulSet(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sStatefulset(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sStatefulset(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -1216,12 +1396,12 @@ func patchStatefulSet(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createStatefulSet(c *gin.Context) {
 	var data v1Apps.StatefulSet
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sStatefulset(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sStatefulset(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- JOBS ----------------------------
@@ -1235,13 +1415,14 @@ func createStatefulSet(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func allJobs(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllJobs(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllJobs(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/job/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true "namespace"
 // @Param name path string true "job name"
 // @Security Bearer
@@ -1249,7 +1430,7 @@ func allJobs(c *gin.Context) {
 func describeJob(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sJob(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_JOB, namespace, name))
 }
 
 // @Tags Workloads
@@ -1263,7 +1444,7 @@ func describeJob(c *gin.Context) {
 func deleteJob(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sJobBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sJobBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -1284,7 +1465,7 @@ func patchJob(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sJob(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sJob(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -1295,12 +1476,12 @@ func patchJob(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createJob(c *gin.Context) {
 	var data v1Job.Job
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sJob(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sJob(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- CRONJOBS ----------------------------
@@ -1314,13 +1495,14 @@ func createJob(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func allCronJobs(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllCronjobs(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllCronjobs(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/cron-job/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true "namespace"
 // @Param name path string true "cronjob name"
 // @Security Bearer
@@ -1328,7 +1510,7 @@ func allCronJobs(c *gin.Context) {
 func describeCronJob(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sCronJob(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_CRON_JOB, namespace, name))
 }
 
 // @Tags Workloads
@@ -1342,7 +1524,7 @@ func describeCronJob(c *gin.Context) {
 func deleteCronJob(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sCronJobBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sCronJobBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -1363,7 +1545,7 @@ func patchCronJob(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sCronJob(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sCronJob(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -1374,12 +1556,12 @@ func patchCronJob(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createCronJob(c *gin.Context) {
 	var data v1Job.CronJob
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sCronJob(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sCronJob(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- REPLICASETS ----------------------------
@@ -1393,13 +1575,14 @@ func createCronJob(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func allReplicasets(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllK8sReplicasets(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllK8sReplicasets(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/replica-set/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true "namespace"
 // @Param name path string true "replica-set name"
 // @Security Bearer
@@ -1407,7 +1590,7 @@ func allReplicasets(c *gin.Context) {
 func describeReplicaset(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sReplicaset(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_REPLICA_SET, namespace, name))
 }
 
 // @Tags Workloads
@@ -1421,7 +1604,7 @@ func describeReplicaset(c *gin.Context) {
 func deleteReplicaset(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sReplicasetBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sReplicasetBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -1442,7 +1625,7 @@ func patchReplicaset(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sReplicaset(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sReplicaset(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -1453,12 +1636,12 @@ func patchReplicaset(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createReplicaset(c *gin.Context) {
 	var data v1Apps.ReplicaSet
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sReplicaSet(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sReplicaSet(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- PERSISTENT VOLUMES ----------------------------
@@ -1470,19 +1653,20 @@ func createReplicaset(c *gin.Context) {
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func allPersistentVolumes(c *gin.Context) {
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllPersistentVolumes(services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllPersistentVolumes(services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/persistent-volume/describe/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param name path string true "persistent-volume name"
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func describePersistentVolume(c *gin.Context) {
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sPersistentVolume(name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_PERSISTENT_VOLUME, "", name))
 }
 
 // @Tags Workloads
@@ -1494,7 +1678,7 @@ func describePersistentVolume(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func deletePersistentVolume(c *gin.Context) {
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sPersistentVolumeBy(name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sPersistentVolumeBy(name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -1515,7 +1699,7 @@ func patchPersistentVolume(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sPersistentVolume(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sPersistentVolume(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -1526,12 +1710,12 @@ func patchPersistentVolume(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createPersistentVolume(c *gin.Context) {
 	var data v1.PersistentVolume
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sPersistentVolume(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sPersistentVolume(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- PERSISTENT VOLUME CLAIMS ----------------------------
@@ -1545,13 +1729,14 @@ func createPersistentVolume(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func allPersistentVolumeClaims(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllK8sPersistentVolumeClaims(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllK8sPersistentVolumeClaims(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/persistent-volume-claim/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true "namespace"
 // @Param name path string true "persistent-volume-claim name"
 // @Security Bearer
@@ -1559,7 +1744,7 @@ func allPersistentVolumeClaims(c *gin.Context) {
 func describePersistentVolumeClaim(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sPersistentVolumeClaim(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_PERSISTENT_VOLUME_CLAIM, namespace, name))
 }
 
 // @Tags Workloads
@@ -1573,7 +1758,7 @@ func describePersistentVolumeClaim(c *gin.Context) {
 func deletePersistentVolumeClaim(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sPersistentVolumeClaimBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sPersistentVolumeClaimBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -1594,7 +1779,7 @@ func patchPersistentVolumeClaim(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sPersistentVolumeClaim(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sPersistentVolumeClaim(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -1605,12 +1790,12 @@ func patchPersistentVolumeClaim(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createPersistentVolumeClaim(c *gin.Context) {
 	var data v1.PersistentVolumeClaim
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sPersistentVolumeClaim(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sPersistentVolumeClaim(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- HORIZONTAL POD AUTOSCALER ----------------------------
@@ -1624,13 +1809,14 @@ func createPersistentVolumeClaim(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func allHpas(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllHpas(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllHpas(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/horizontal-pod-autoscaler/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true "namespace"
 // @Param name path string true "hpa name"
 // @Security Bearer
@@ -1638,7 +1824,7 @@ func allHpas(c *gin.Context) {
 func describeHpa(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sHpa(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_HORIZONTAL_POD_AUTOSCALER, namespace, name))
 }
 
 // @Tags Workloads
@@ -1652,7 +1838,7 @@ func describeHpa(c *gin.Context) {
 func deleteHpa(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sHpaBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sHpaBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -1673,7 +1859,7 @@ func patchHpa(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sHpa(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sHpa(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -1684,12 +1870,12 @@ func patchHpa(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createHpa(c *gin.Context) {
 	var data v2Scale.HorizontalPodAutoscaler
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sHpa(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sHpa(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- EVENTS ----------------------------
@@ -1703,13 +1889,14 @@ func createHpa(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func allEvents(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllEvents(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllEvents(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/event/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true "namespace"
 // @Param name path string true "event name"
 // @Security Bearer
@@ -1717,7 +1904,7 @@ func allEvents(c *gin.Context) {
 func describeEvent(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sEvent(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_EVENT, namespace, name))
 }
 
 // ---------------------- CERTIFICATES ----------------------------
@@ -1731,13 +1918,14 @@ func describeEvent(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func allCertificates(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllK8sCertificates(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllK8sCertificates(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/certificate/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true "namespace"
 // @Param name path string true "certificate name"
 // @Security Bearer
@@ -1745,7 +1933,7 @@ func allCertificates(c *gin.Context) {
 func describeCertificate(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sCertificate(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_CERTIFICATE, namespace, name))
 }
 
 // @Tags Workloads
@@ -1759,7 +1947,7 @@ func describeCertificate(c *gin.Context) {
 func deleteCertificate(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sCertificateBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sCertificateBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -1780,7 +1968,7 @@ func patchCertificate(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sCertificate(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sCertificate(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -1791,12 +1979,12 @@ func patchCertificate(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createCertificate(c *gin.Context) {
 	var data cmapi.Certificate
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sCertificate(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sCertificate(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- CERTIFICATE REQUESTS ----------------------------
@@ -1810,13 +1998,14 @@ func createCertificate(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func allCertificateRequests(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllCertificateSigningRequests(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllCertificateSigningRequests(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/certificate-request/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true "namespace"
 // @Param name path string true "certificate request name"
 // @Security Bearer
@@ -1824,7 +2013,7 @@ func allCertificateRequests(c *gin.Context) {
 func describeCertificateRequest(c *gin.Context) {
 	name := c.Param("name")
 	namespace := c.Param("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sCertificateSigningRequest(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_CERTIFICATE_REQUEST, namespace, name))
 }
 
 // @Tags Workloads
@@ -1839,7 +2028,7 @@ func deleteCertificateRequest(c *gin.Context) {
 
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sCertificateSigningRequestBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sCertificateSigningRequestBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -1860,7 +2049,7 @@ func patchCertificateRequest(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sCertificateSigningRequest(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sCertificateSigningRequest(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -1871,12 +2060,12 @@ func patchCertificateRequest(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createCertificateRequest(c *gin.Context) {
 	var data cmapi.CertificateRequest
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sCertificateSigningRequest(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sCertificateSigningRequest(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- ORDERS ----------------------------
@@ -1890,13 +2079,14 @@ func createCertificateRequest(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func allOrders(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllOrders(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllOrders(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/orders/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true "namespace"
 // @Param name path string true "order name"
 // @Security Bearer
@@ -1904,7 +2094,7 @@ func allOrders(c *gin.Context) {
 func describeOrder(c *gin.Context) {
 	name := c.Param("name")
 	namespace := c.Param("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sOrder(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_ORDER, namespace, name))
 }
 
 // @Tags Workloads
@@ -1918,7 +2108,7 @@ func describeOrder(c *gin.Context) {
 func deleteOrder(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sOrderBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sOrderBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -1939,7 +2129,7 @@ func patchOrder(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sOrder(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sOrder(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -1950,12 +2140,12 @@ func patchOrder(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createOrder(c *gin.Context) {
 	var data v1Cert.Order
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sOrder(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sOrder(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- ISSUERS ----------------------------
@@ -1969,13 +2159,14 @@ func createOrder(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func allIssuers(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllIssuer(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllIssuer(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/issuer/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true "namespace"
 // @Param name path string true "issuer name"
 // @Security Bearer
@@ -1983,7 +2174,7 @@ func allIssuers(c *gin.Context) {
 func describeIssuer(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sIssuer(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_ISSUER, namespace, name))
 }
 
 // @Tags Workloads
@@ -1998,7 +2189,7 @@ func deleteIssuer(c *gin.Context) {
 
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sIssuerBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sIssuerBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -2019,7 +2210,7 @@ func patchIssuer(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sIssuer(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sIssuer(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -2030,12 +2221,12 @@ func patchIssuer(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createIssuer(c *gin.Context) {
 	var data cmapi.Issuer
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sIssuer(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sIssuer(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- CLUSTER ISSUERS ----------------------------
@@ -2047,19 +2238,20 @@ func createIssuer(c *gin.Context) {
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func allClusterIssuers(c *gin.Context) {
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllClusterIssuers(services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllClusterIssuers(services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/cluster-issuer/describe/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param name path string true "cluster-issuer name"
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func describeClusterIssuer(c *gin.Context) {
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sClusterIssuer(name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_CLUSTER_ISSUER, "", name))
 }
 
 // @Tags Workloads
@@ -2071,7 +2263,7 @@ func describeClusterIssuer(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func deleteClusterIssuer(c *gin.Context) {
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sClusterIssuerBy(name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sClusterIssuerBy(name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -2092,7 +2284,7 @@ func patchClusterIssuer(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sClusterIssuer(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sClusterIssuer(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -2103,12 +2295,12 @@ func patchClusterIssuer(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createClusterIssuer(c *gin.Context) {
 	var data cmapi.ClusterIssuer
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sClusterIssuer(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sClusterIssuer(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- SERVICE ACCOUNTS ----------------------------
@@ -2122,13 +2314,14 @@ func createClusterIssuer(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func allServiceAccounts(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllServiceAccounts(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllServiceAccounts(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/service-account/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true "namespace"
 // @Param name path string true "service-account name"
 // @Security Bearer
@@ -2136,7 +2329,7 @@ func allServiceAccounts(c *gin.Context) {
 func describeServiceAccount(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sServiceAccount(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_SERVICE_ACCOUNT, namespace, name))
 }
 
 // @Tags Workloads
@@ -2150,7 +2343,7 @@ func describeServiceAccount(c *gin.Context) {
 func deleteServiceAccount(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sServiceAccountBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sServiceAccountBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -2171,7 +2364,7 @@ func patchServiceAccount(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sServiceAccount(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sServiceAccount(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -2182,12 +2375,12 @@ func patchServiceAccount(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createServiceAccount(c *gin.Context) {
 	var data v1.ServiceAccount
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sServiceAccount(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sServiceAccount(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- ROLES ----------------------------
@@ -2201,13 +2394,14 @@ func createServiceAccount(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func allRoles(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllRoles(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllRoles(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/role/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true "namespace"
 // @Param name path string true "role name"
 // @Security Bearer
@@ -2215,7 +2409,7 @@ func allRoles(c *gin.Context) {
 func describeRole(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sRole(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_ROLE, namespace, name))
 }
 
 // @Tags Workloads
@@ -2229,7 +2423,7 @@ func describeRole(c *gin.Context) {
 func deleteRole(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sRoleBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sRoleBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -2250,7 +2444,7 @@ func patchRole(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sRole(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sRole(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -2261,12 +2455,12 @@ func patchRole(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createRole(c *gin.Context) {
 	var data v1Rbac.Role
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sRole(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sRole(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- ROLE BINDINGS ----------------------------
@@ -2280,13 +2474,14 @@ func createRole(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func allRoleBindings(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllRoleBindings(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllRoleBindings(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/role-binding/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true "namespace"
 // @Param name path string true "role-binding name"
 // @Security Bearer
@@ -2294,7 +2489,7 @@ func allRoleBindings(c *gin.Context) {
 func describeRoleBinding(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sRoleBinding(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_ROLE_BINDING, namespace, name))
 }
 
 // @Tags Workloads
@@ -2308,7 +2503,7 @@ func describeRoleBinding(c *gin.Context) {
 func deleteRoleBinding(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sRoleBindingBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sRoleBindingBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -2329,7 +2524,7 @@ func patchRoleBinding(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sRoleBinding(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sRoleBinding(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -2340,12 +2535,12 @@ func patchRoleBinding(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createRoleBinding(c *gin.Context) {
 	var data v1Rbac.RoleBinding
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sRoleBinding(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sRoleBinding(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- CLUSTER ROLES ----------------------------
@@ -2357,19 +2552,20 @@ func createRoleBinding(c *gin.Context) {
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func allClusterRoles(c *gin.Context) {
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllClusterRoles(services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllClusterRoles(services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/cluster-role/describe/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param name path string true "cluster-role name"
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func describeClusterRole(c *gin.Context) {
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sClusterRole(name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_CLUSTER_ROLE, "", name))
 }
 
 // @Tags Workloads
@@ -2382,7 +2578,7 @@ func describeClusterRole(c *gin.Context) {
 func deleteClusterRole(c *gin.Context) {
 
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sClusterRoleBy(name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sClusterRoleBy(name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -2403,7 +2599,7 @@ func patchClusterRole(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sClusterRole(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sClusterRole(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -2414,12 +2610,12 @@ func patchClusterRole(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createClusterRole(c *gin.Context) {
 	var data v1Rbac.ClusterRole
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sClusterRole(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sClusterRole(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- CLUSTER ROLE BINDINGS ----------------------------
@@ -2431,19 +2627,20 @@ func createClusterRole(c *gin.Context) {
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func allClusterRoleBindings(c *gin.Context) {
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllClusterRoleBindings(services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllClusterRoleBindings(services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/cluster-role-binding/describe/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param name path string true "cluster-role-binding name"
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func describeClusterRoleBinding(c *gin.Context) {
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sClusterRoleBinding(name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_CLUSTER_ROLE_BINDING, "", name))
 }
 
 // @Tags Workloads
@@ -2455,7 +2652,7 @@ func describeClusterRoleBinding(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func deleteClusterRoleBinding(c *gin.Context) {
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sClusterRoleBindingBy(name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sClusterRoleBindingBy(name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -2476,7 +2673,7 @@ func patchClusterRoleBinding(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sClusterRoleBinding(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sClusterRoleBinding(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -2487,12 +2684,12 @@ func patchClusterRoleBinding(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createClusterRoleBinding(c *gin.Context) {
 	var data v1Rbac.ClusterRoleBinding
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sClusterRoleBinding(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sClusterRoleBinding(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- VOLUME ATTACHMENTS ----------------------------
@@ -2504,19 +2701,20 @@ func createClusterRoleBinding(c *gin.Context) {
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func allVolumeAttachments(c *gin.Context) {
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllVolumeAttachments(services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllVolumeAttachments(services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/volume-attachment/describe/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param name path string true "volume-attachment name"
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func describeVolumeAttachment(c *gin.Context) {
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sVolumeAttachment(name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_VOLUME_ATTACHMENT, "", name))
 }
 
 // @Tags Workloads
@@ -2528,7 +2726,7 @@ func describeVolumeAttachment(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func deleteVolumeAttachment(c *gin.Context) {
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sVolumeAttachmentBy(name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sVolumeAttachmentBy(name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -2549,7 +2747,7 @@ func patchVolumeAttachment(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sVolumeAttachment(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sVolumeAttachment(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -2560,12 +2758,12 @@ func patchVolumeAttachment(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createVolumeAttachment(c *gin.Context) {
 	var data v1Storage.VolumeAttachment
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sVolumeAttachment(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sVolumeAttachment(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- NETWORK POLICIES ----------------------------
@@ -2579,13 +2777,14 @@ func createVolumeAttachment(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func allNetworkPolicies(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllNetworkPolicies(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllNetworkPolicies(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/network-policy/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true "namespace"
 // @Param name path string true "network-policy name"
 // @Security Bearer
@@ -2593,7 +2792,7 @@ func allNetworkPolicies(c *gin.Context) {
 func describeNetworkPolicy(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sNetworkPolicy(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_NETWORK_POLICY, namespace, name))
 }
 
 // @Tags Workloads
@@ -2607,7 +2806,7 @@ func describeNetworkPolicy(c *gin.Context) {
 func deleteNetworkPolicy(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sNetworkPolicyBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sNetworkPolicyBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -2628,7 +2827,7 @@ func patchNetworkPolicy(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sNetworkPolicy(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sNetworkPolicy(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -2639,12 +2838,12 @@ func patchNetworkPolicy(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createNetworkPolicy(c *gin.Context) {
 	var data v1Networking.NetworkPolicy
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sNetworkpolicy(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sNetworkpolicy(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- STORAGECLASSES ----------------------------
@@ -2656,19 +2855,20 @@ func createNetworkPolicy(c *gin.Context) {
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func allStorageClasses(c *gin.Context) {
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllStorageClasses(services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllStorageClasses(services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/storage-class/describe/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param name path string true "storage-class name"
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func describeStorageClass(c *gin.Context) {
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sStorageClass(name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_STORAGE_CLASS, "", name))
 }
 
 // @Tags Workloads
@@ -2680,7 +2880,7 @@ func describeStorageClass(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func deleteStorageClass(c *gin.Context) {
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sStorageClassBy(name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sStorageClassBy(name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -2701,7 +2901,7 @@ func patchStorageClass(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sStorageClass(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sStorageClass(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -2712,12 +2912,12 @@ func patchStorageClass(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createStorageClass(c *gin.Context) {
 	var data v1Storage.StorageClass
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sStorageClass(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sStorageClass(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- CUSTOM RESSOURCE DEFINITIONS ----------------------------
@@ -2729,19 +2929,20 @@ func createStorageClass(c *gin.Context) {
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func allCrds(c *gin.Context) {
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllCustomResourceDefinitions())
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllCustomResourceDefinitions(services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/crds/describe/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param name path string true "crds name"
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func describeCrd(c *gin.Context) {
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sCustomResourceDefinition(name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_CUSTOM_RESOURCE_DEFINITION, "", name))
 }
 
 // @Tags Workloads
@@ -2752,13 +2953,12 @@ func describeCrd(c *gin.Context) {
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func deleteCrd(c *gin.Context) {
-	// name := c.Param("name")
-	// TODO
-	// err := kubernetes.DeleteK8sCustomResourceDefinition(name)
-	// if err != nil {
-	// 	utils.MalformedMessage(c, err.Error())
-	// 	return
-	// }
+	name := c.Param("name")
+	err := kubernetes.DeleteK8sCustomResourceDefinitionBy(name, services.GetGinKubeContextId(c))
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
 	c.Status(http.StatusOK)
 }
 
@@ -2769,13 +2969,13 @@ func deleteCrd(c *gin.Context) {
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func patchCrd(c *gin.Context) {
-	var data apiExt.CustomResourceDefinition
+	var data apiExtV1.CustomResourceDefinition
 	err := c.MustBindWith(&data, binding.JSON)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sCustomResourceDefinition(data))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sCustomResourceDefinition(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -2785,13 +2985,13 @@ func patchCrd(c *gin.Context) {
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func createCrd(c *gin.Context) {
-	var data apiExt.CustomResourceDefinition
-	err := c.MustBindWith(&data, binding.YAML)
+	var data apiExtV1.CustomResourceDefinition
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sCustomResourceDefinition(data))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sCustomResourceDefinition(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- ENDPOINTS ----------------------------
@@ -2805,13 +3005,14 @@ func createCrd(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func allEndpoints(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllEndpoints(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllEndpoints(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/endpoints/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true "namespace"
 // @Param name path string true "endpoint name"
 // @Security Bearer
@@ -2819,7 +3020,7 @@ func allEndpoints(c *gin.Context) {
 func describeEndpoint(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sEndpoint(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_ENDPOINT, namespace, name))
 }
 
 // @Tags Workloads
@@ -2833,7 +3034,7 @@ func describeEndpoint(c *gin.Context) {
 func deleteEndpoint(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sEndpointBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sEndpointBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -2854,7 +3055,7 @@ func patchEndpoint(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sEndpoint(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sEndpoint(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -2865,12 +3066,12 @@ func patchEndpoint(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createEndpoint(c *gin.Context) {
 	var data v1.Endpoints
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sEndpoint(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sEndpoint(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- LEASES ----------------------------
@@ -2884,13 +3085,14 @@ func createEndpoint(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func allLeases(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllLeases(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllLeases(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/leases/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true "namespace"
 // @Param name path string true "lease name"
 // @Security Bearer
@@ -2898,7 +3100,7 @@ func allLeases(c *gin.Context) {
 func describeLease(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sLease(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_LEASE, namespace, name))
 }
 
 // @Tags Workloads
@@ -2912,7 +3114,7 @@ func describeLease(c *gin.Context) {
 func deleteLease(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sLeaseBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sLeaseBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -2933,7 +3135,7 @@ func patchLease(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sLease(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sLease(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -2944,12 +3146,12 @@ func patchLease(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createLease(c *gin.Context) {
 	var data v1Coordination.Lease
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sLease(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sLease(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- PRIORITY CLASSES ----------------------------
@@ -2961,19 +3163,20 @@ func createLease(c *gin.Context) {
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func allPriorityClasses(c *gin.Context) {
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllPriorityClasses(services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllPriorityClasses(services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/priority-classes/describe/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param name path string true "priority-classes name"
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func describePriorityClass(c *gin.Context) {
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sPriorityClass(name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_PRIORITY_CLASS, "", name))
 }
 
 // @Tags Workloads
@@ -2985,7 +3188,7 @@ func describePriorityClass(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func deletePriorityClass(c *gin.Context) {
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sPriorityClassBy(name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sPriorityClassBy(name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -3006,7 +3209,7 @@ func patchPriorityClass(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sPriorityClass(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sPriorityClass(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -3017,12 +3220,12 @@ func patchPriorityClass(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createPriorityClass(c *gin.Context) {
 	var data v1Scheduling.PriorityClass
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sPriorityClass(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sPriorityClass(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- VOLUME SNAPSHOTS ----------------------------
@@ -3036,13 +3239,14 @@ func createPriorityClass(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func allVolumeSnapshots(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllVolumeSnapshots(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllVolumeSnapshots(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/volume-snapshots/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true "namespace"
 // @Param name path string true "volume-snapshot name"
 // @Security Bearer
@@ -3050,7 +3254,7 @@ func allVolumeSnapshots(c *gin.Context) {
 func describeVolumeSnapshot(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sVolumeSnapshot(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_VOLUME_SNAPSHOT, namespace, name))
 }
 
 // @Tags Workloads
@@ -3064,7 +3268,7 @@ func describeVolumeSnapshot(c *gin.Context) {
 func deleteVolumeSnapshot(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sVolumeSnapshotBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sVolumeSnapshotBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -3096,12 +3300,12 @@ func patchVolumeSnapshot(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createVolumeSnapshot(c *gin.Context) {
 	var data v6Snap.VolumeSnapshot
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sVolumeSnapshot(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sVolumeSnapshot(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- RESOURCE QUOTAS ----------------------------
@@ -3115,13 +3319,14 @@ func createVolumeSnapshot(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func allResourceQuotas(c *gin.Context) {
 	namespace := c.Query("namespace")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllResourceQuotas(namespace, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllResourceQuotas(namespace, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/resource-quota/describe/{namespace}/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param namespace path string true "namespace"
 // @Param name path string true "resource-quota name"
 // @Security Bearer
@@ -3129,7 +3334,7 @@ func allResourceQuotas(c *gin.Context) {
 func describeResourceQuota(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sResourceQuota(namespace, name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_RESOURCE_QUOTA, namespace, name))
 }
 
 // @Tags Workloads
@@ -3144,7 +3349,7 @@ func deleteResourceQuota(c *gin.Context) {
 
 	namespace := c.Param("namespace")
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sResourceQuotaBy(namespace, name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sResourceQuotaBy(namespace, name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -3165,7 +3370,7 @@ func patchResourceQuota(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sResourceQuota(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sResourceQuota(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -3176,12 +3381,12 @@ func patchResourceQuota(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createResourceQuota(c *gin.Context) {
 	var data v1.ResourceQuota
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sResourceQuota(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sResourceQuota(data, services.GetGinKubeContextId(c)))
 }
 
 // ---------------------- INGRESS CLASSES ----------------------------
@@ -3193,19 +3398,20 @@ func createResourceQuota(c *gin.Context) {
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func allIngressClasses(c *gin.Context) {
-	utils.HttpRespondForWorkloadResult(c, kubernetes.AllK8sIngressClasses(services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllK8sIngressClasses(services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
 // @Produce json
 // @Success 200 {object} utils.K8sWorkloadResult
 // @Router /backend/workload/ingress-classes/describe/{name}/ [get]
+// @Param format query string false "text (default) or json"
 // @Param name path string true "ingress-class name"
 // @Security Bearer
 // @Param string header string true "X-Context-Id"
 func describeIngressClass(c *gin.Context) {
 	name := c.Param("name")
-	utils.HttpRespondForWorkloadResult(c, kubernetes.DescribeK8sIngressClass(name, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, describeResource(c, kubernetes.RES_INGRESS_CLASS, "", name))
 }
 
 // @Tags Workloads
@@ -3217,7 +3423,7 @@ func describeIngressClass(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func deleteIngressClass(c *gin.Context) {
 	name := c.Param("name")
-	err := kubernetes.DeleteK8sIngressClassBy(name, services.GetGinContextId(c))
+	err := kubernetes.DeleteK8sIngressClassBy(name, services.GetGinKubeContextId(c))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
@@ -3238,7 +3444,7 @@ func patchIngressClass(c *gin.Context) {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sIngressClass(data, services.GetGinContextId(c)))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.UpdateK8sIngressClass(data, services.GetGinKubeContextId(c)))
 }
 
 // @Tags Workloads
@@ -3249,10 +3455,293 @@ func patchIngressClass(c *gin.Context) {
 // @Param string header string true "X-Context-Id"
 func createIngressClass(c *gin.Context) {
 	var data v1Networking.IngressClass
-	err := c.MustBindWith(&data, binding.YAML)
+	err := c.MustBindWith(&data, k8sYaml)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sIngressClass(data, services.GetGinKubeContextId(c)))
+}
+
+// ---------------------- CUSTOM RESOURCES ----------------------------
+
+// unstructured objects can't be bound by gin because of the int/int64 handling of yaml, so the body is converted to json first
+// k8sYamlBinding decodes kubernetes objects by their json tags like the authorization does (binding.YAML ignores
+// them and would e.g. not see metadata)
+type k8sYamlBinding struct{}
+
+var k8sYaml = k8sYamlBinding{}
+
+func (k8sYamlBinding) Name() string {
+	return "yaml"
+}
+
+func (b k8sYamlBinding) Bind(req *http.Request, obj interface{}) error {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	return decodeBody(body, obj)
+}
+
+func bindUnstructured(c *gin.Context) (*unstructured.Unstructured, error) {
+	body, err := c.GetRawData()
+	if err != nil {
+		return nil, err
+	}
+	jsonData, err := yaml.YAMLToJSON(body)
+	if err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{}
+	err = obj.UnmarshalJSON(jsonData)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// @Tags Workloads
+// @Produce json
+// @Success 200 {object} utils.K8sWorkloadResult
+// @Router /backend/workload/custom/{group}/{version}/{resource} [get]
+// @Param group path string true "api group (e.g. argoproj.io)"
+// @Param version path string true "api version (e.g. v1alpha1)"
+// @Param resource path string true "plural resource name (e.g. applications)"
+// @Param namespace query string false "namespace name"
+// @Security Bearer
+// @Param string header string true "X-Context-Id"
+func allCustomResources(c *gin.Context) {
+	namespace := c.Query("namespace")
+	utils.HttpRespondForWorkloadResult(c, kubernetes.AllCustomResources(c.Param("group"), c.Param("version"), c.Param("resource"), namespace, services.GetGinKubeContextId(c)))
+}
+
+// @Tags Workloads
+// @Produce json
+// @Success 200 {object} utils.K8sWorkloadResult
+// @Router /backend/workload/custom/{group}/{version}/{resource}/{name} [get]
+// @Param group path string true "api group (e.g. argoproj.io)"
+// @Param version path string true "api version (e.g. v1alpha1)"
+// @Param resource path string true "plural resource name (e.g. applications)"
+// @Param name path string true "resource name"
+// @Param namespace query string false "namespace name (namespaced resources only)"
+// @Security Bearer
+// @Param string header string true "X-Context-Id"
+func getCustomResource(c *gin.Context) {
+	namespace := c.Query("namespace")
+	utils.HttpRespondForWorkloadResult(c, kubernetes.GetCustomResource(c.Param("group"), c.Param("version"), c.Param("resource"), namespace, c.Param("name"), services.GetGinKubeContextId(c)))
+}
+
+// @Tags Workloads
+// @Produce json
+// @Success 200
+// @Router /backend/workload/custom/{group}/{version}/{resource}/{name} [delete]
+// @Param group path string true "api group (e.g. argoproj.io)"
+// @Param version path string true "api version (e.g. v1alpha1)"
+// @Param resource path string true "plural resource name (e.g. applications)"
+// @Param name path string true "resource name"
+// @Param namespace query string false "namespace name (namespaced resources only)"
+// @Security Bearer
+// @Param string header string true "X-Context-Id"
+func deleteCustomResource(c *gin.Context) {
+	namespace := c.Query("namespace")
+	err := kubernetes.DeleteK8sCustomResourceBy(c.Param("group"), c.Param("version"), c.Param("resource"), namespace, c.Param("name"), services.GetGinKubeContextId(c))
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// @Tags Workloads
+// @Produce json
+// @Success 200 {object} utils.K8sWorkloadResult
+// @Router /backend/workload/custom/{group}/{version}/{resource} [patch]
+// @Param group path string true "api group (e.g. argoproj.io)"
+// @Param version path string true "api version (e.g. v1alpha1)"
+// @Param resource path string true "plural resource name (e.g. applications)"
+// @Security Bearer
+// @Param string header string true "X-Context-Id"
+func patchCustomResource(c *gin.Context) {
+	data, err := bindUnstructured(c)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	utils.HttpRespondForWorkloadResult(c, kubernetes.PatchK8sCustomResource(c.Param("group"), c.Param("version"), c.Param("resource"), *data, services.GetGinKubeContextId(c)))
+}
+
+// @Tags Workloads
+// @Produce json
+// @Success 200 {object} utils.K8sWorkloadResult
+// @Router /backend/workload/custom/{group}/{version}/{resource} [post]
+// @Param group path string true "api group (e.g. argoproj.io)"
+// @Param version path string true "api version (e.g. v1alpha1)"
+// @Param resource path string true "plural resource name (e.g. applications)"
+// @Security Bearer
+// @Param string header string true "X-Context-Id"
+func createCustomResource(c *gin.Context) {
+	data, err := bindUnstructured(c)
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sCustomResource(c.Param("group"), c.Param("version"), c.Param("resource"), *data, services.GetGinKubeContextId(c)))
+}
+
+// ---------------------- HELM RELEASES ----------------------------
+
+// optional revision query parameter, 0 means latest
+func revisionQuery(c *gin.Context, key string) (int, error) {
+	value := c.Query(key)
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// @Tags Workloads
+// @Produce json
+// @Success 200 {object} utils.K8sWorkloadResult
+// @Router /backend/workload/helm-release/ [get]
+// @Param namespace query string false "namespace name"
+// @Security Bearer
+// @Param string header string true "X-Context-Id"
+func allHelmReleases(c *gin.Context) {
+	namespace := c.Query("namespace")
+	releases, err := kubernetes.AllHelmReleases(namespace, services.GetGinKubeContextId(c))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.WorkloadResult(releases, err))
+}
+
+// @Tags Workloads
+// @Produce json
+// @Success 200 {object} utils.K8sWorkloadResult
+// @Router /backend/workload/helm-release/{namespace}/{name} [get]
+// @Param namespace path string true "namespace name"
+// @Param name path string true "release name"
+// @Param revision query int false "revision (default: latest)"
+// @Security Bearer
+// @Param string header string true "X-Context-Id"
+func getHelmRelease(c *gin.Context) {
+	revision, err := revisionQuery(c, "revision")
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	release, err := kubernetes.GetHelmRelease(c.Param("namespace"), c.Param("name"), revision, services.GetGinKubeContextId(c))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.WorkloadResult(release, err))
+}
+
+// @Tags Workloads
+// @Produce json
+// @Success 200 {object} utils.K8sWorkloadResult
+// @Router /backend/workload/helm-release/{namespace}/{name}/values [get]
+// @Param namespace path string true "namespace name"
+// @Param name path string true "release name"
+// @Param revision query int false "revision (default: latest)"
+// @Param all query bool false "merge with the default values of the chart"
+// @Security Bearer
+// @Param string header string true "X-Context-Id"
+func helmReleaseValues(c *gin.Context) {
+	revision, err := revisionQuery(c, "revision")
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	allValues := c.Query("all") == "true"
+	values, err := kubernetes.HelmReleaseValues(c.Param("namespace"), c.Param("name"), revision, allValues, services.GetGinKubeContextId(c))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.WorkloadResult(values, err))
+}
+
+// @Tags Workloads
+// @Produce json
+// @Success 200 {object} utils.K8sWorkloadResult
+// @Router /backend/workload/helm-release/{namespace}/{name}/manifest [get]
+// @Param namespace path string true "namespace name"
+// @Param name path string true "release name"
+// @Param revision query int false "revision (default: latest)"
+// @Security Bearer
+// @Param string header string true "X-Context-Id"
+func helmReleaseManifest(c *gin.Context) {
+	revision, err := revisionQuery(c, "revision")
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	manifest, err := kubernetes.HelmReleaseManifest(c.Param("namespace"), c.Param("name"), revision, services.GetGinKubeContextId(c))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.WorkloadResult(manifest, err))
+}
+
+// @Tags Workloads
+// @Produce json
+// @Success 200 {object} utils.K8sWorkloadResult
+// @Router /backend/workload/helm-release/{namespace}/{name}/history [get]
+// @Param namespace path string true "namespace name"
+// @Param name path string true "release name"
+// @Security Bearer
+// @Param string header string true "X-Context-Id"
+func helmReleaseHistory(c *gin.Context) {
+	history, err := kubernetes.HelmReleaseHistory(c.Param("namespace"), c.Param("name"), services.GetGinKubeContextId(c))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.WorkloadResult(history, err))
+}
+
+// @Tags Workloads
+// @Produce json
+// @Success 200 {object} utils.K8sWorkloadResult
+// @Router /backend/workload/helm-release/{namespace}/{name}/diff [get]
+// @Param namespace path string true "namespace name"
+// @Param name path string true "release name"
+// @Param from query int false "revision (default: the revision before 'to')"
+// @Param to query int false "revision (default: latest)"
+// @Security Bearer
+// @Param string header string true "X-Context-Id"
+func helmReleaseDiff(c *gin.Context) {
+	from, err := revisionQuery(c, "from")
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	to, err := revisionQuery(c, "to")
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	diff, err := kubernetes.HelmReleaseDiff(c.Param("namespace"), c.Param("name"), from, to, services.GetGinKubeContextId(c))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.WorkloadResult(diff, err))
+}
+
+// @Tags Workloads
+// @Produce json
+// @Success 200 {object} utils.K8sWorkloadResult
+// @Router /backend/workload/helm-release/{namespace}/{name}/rollback/{revision} [post]
+// @Param namespace path string true "namespace name"
+// @Param name path string true "release name"
+// @Param revision path int true "revision to roll back to"
+// @Security Bearer
+// @Param string header string true "X-Context-Id"
+func helmRollback(c *gin.Context) {
+	revision, err := strconv.Atoi(c.Param("revision"))
 	if err != nil {
 		utils.MalformedMessage(c, err.Error())
 		return
 	}
-	utils.HttpRespondForWorkloadResult(c, kubernetes.CreateK8sIngressClass(data, services.GetGinContextId(c)))
+	release, err := kubernetes.HelmRollback(c.Param("namespace"), c.Param("name"), revision, services.GetGinKubeContextId(c))
+	utils.HttpRespondForWorkloadResult(c, kubernetes.WorkloadResult(release, err))
+}
+
+// @Tags Workloads
+// @Produce json
+// @Success 200
+// @Router /backend/workload/helm-release/{namespace}/{name} [delete]
+// @Param namespace path string true "namespace name"
+// @Param name path string true "release name"
+// @Security Bearer
+// @Param string header string true "X-Context-Id"
+func helmUninstall(c *gin.Context) {
+	err := kubernetes.HelmUninstall(c.Param("namespace"), c.Param("name"), services.GetGinKubeContextId(c))
+	if err != nil {
+		utils.MalformedMessage(c, err.Error())
+		return
+	}
+	c.Status(http.StatusOK)
 }
@@ -1,12 +1,31 @@
 package operator
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mogenius/punq/utils"
 )
 
+// bodies read by middlewares before the handler (kubernetes objects are limited to roughly 1.5 MiB by etcd)
+const REQUEST_MAX_BODY_SIZE = 10 * 1024 * 1024
+
+// readRequestBody reads at most REQUEST_MAX_BODY_SIZE bytes of the body and puts them back for the next handler
+func readRequestBody(c *gin.Context) ([]byte, error) {
+	if c.Request.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, REQUEST_MAX_BODY_SIZE))
+	if err != nil {
+		return nil, err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
 func CreateLogger(loggerPrefix string) gin.HandlerFunc {
 	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
 		var statusColor, methodColor, resetColor string
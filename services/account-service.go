@@ -0,0 +1,65 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/logger"
+)
+
+// UpdateProfile changes the fields of PunqProfileUpdateInput, which users can change themselves
+func UpdateProfile(userId string, input dtos.PunqProfileUpdateInput) (*dtos.PunqUser, error) {
+	if input.DisplayName == "" {
+		return nil, errors.New("displayName is required")
+	}
+	user, err := GetUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	user.DisplayName = input.DisplayName
+	return UpdateUser(*user)
+}
+
+// ChangePassword sets the password of a local user after the current password has been checked. Wrong current
+// passwords count as failed logins. All other sessions of the user end.
+func ChangePassword(userId string, input dtos.PunqPasswordChangeInput, clientIp string, currentSessionId string) (*dtos.PunqUser, error) {
+	user, err := GetUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	if user.ProviderName() != dtos.USER_PROVIDER_LOCAL {
+		return nil, errors.New("the password of external users is managed by their provider")
+	}
+
+	err = CheckLogin(user.Email, clientIp)
+	if err != nil {
+		return nil, err
+	}
+	defer FinishLogin(user.Email, clientIp)
+	valid, _ := user.PasswordCheck(input.CurrentPassword)
+	if !valid {
+		RecordFailedLogin(user.Email, clientIp)
+		return nil, errors.New("current password is wrong")
+	}
+
+	user.Password = input.NewPassword
+	user, err = UpdateUser(*user)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := ListSessions(userId)
+	if err != nil {
+		logger.Log.Errorf("Failed to list sessions of user '%s': %s", userId, err.Error())
+	}
+	for _, session := range sessions {
+		if session.Id == currentSessionId {
+			continue
+		}
+		err = RevokeSession(userId, session.Id)
+		if err != nil {
+			logger.Log.Errorf("Failed to revoke session '%s' of user '%s': %s", session.Id, userId, err.Error())
+		}
+	}
+	return user, nil
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/kubernetes"
+	"github.com/mogenius/punq/logger"
+	"github.com/mogenius/punq/utils"
+	v1 "k8s.io/api/core/v1"
+)
+
+// last usage is only written if the previous one is older to avoid a secret update on every request
+const API_TOKEN_LAST_USED_INTERVAL = time.Minute
+
+var ErrInvalidApiToken = errors.New("invalid, expired or revoked api token")
+
+var apiTokensMutex sync.Mutex
+
+// CreateApiToken creates a personal api token for the user. The returned token is not stored and cannot be shown again.
+func CreateApiToken(userId string, input dtos.PunqApiTokenCreateInput) (*dtos.PunqApiTokenCreated, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return nil, errors.New("name of the api token is required")
+	}
+	if len(input.Verbs) == 0 {
+		return nil, fmt.Errorf("at least one verb is required (%s)", strings.Join(dtos.API_TOKEN_VERBS, ", "))
+	}
+	for _, verb := range input.Verbs {
+		if !utils.ContainsEqual(dtos.API_TOKEN_VERBS, verb) {
+			return nil, fmt.Errorf("unknown verb '%s' (%s)", verb, strings.Join(dtos.API_TOKEN_VERBS, ", "))
+		}
+	}
+	if !input.Expires.IsZero() && input.Expires.Before(time.Now()) {
+		return nil, errors.New("expiry date must be in the future")
+	}
+	if _, err := GetUser(userId); err != nil {
+		return nil, err
+	}
+
+	apiTokensMutex.Lock()
+	defer apiTokensMutex.Unlock()
+
+	secret, err := apiTokensSecret()
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range allApiTokens(secret) {
+		if token.UserId == userId && token.Name == input.Name {
+			return nil, fmt.Errorf("api token '%s' already exists", input.Name)
+		}
+	}
+
+	secretPart := utils.NanoId() + utils.NanoId()
+	token := dtos.PunqApiToken{
+		Id:         utils.NanoId(),
+		UserId:     userId,
+		Name:       input.Name,
+		Contexts:   input.Contexts,
+		Namespaces: input.Namespaces,
+		Verbs:      input.Verbs,
+		Created:    time.Now(),
+		Expires:    input.Expires,
+		TokenHash:  hashToken(secretPart),
+	}
+	err = saveApiToken(secret, token)
+	if err != nil {
+		return nil, err
+	}
+
+	token.TokenHash = ""
+	return &dtos.PunqApiTokenCreated{
+		PunqApiToken: token,
+		Token:        fmt.Sprintf("%s%s_%s", dtos.API_TOKEN_PREFIX, token.Id, secretPart),
+	}, nil
+}
+
+// ListApiTokens returns the tokens of the user ("" = all users) without their hashes
+func ListApiTokens(userId string) ([]dtos.PunqApiToken, error) {
+	secret, err := apiTokensSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []dtos.PunqApiToken{}
+	for _, token := range allApiTokens(secret) {
+		if userId != "" && token.UserId != userId {
+			continue
+		}
+		token.TokenHash = ""
+		result = append(result, token)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Created.Before(result[j].Created)
+	})
+	return result, nil
+}
+
+// RevokeApiToken deletes the token of the user ("" = any user)
+func RevokeApiToken(userId string, tokenId string) error {
+	apiTokensMutex.Lock()
+	defer apiTokensMutex.Unlock()
+
+	secret, err := apiTokensSecret()
+	if err != nil {
+		return err
+	}
+	token, err := apiTokenFrom(secret, tokenId)
+	if err != nil || (userId != "" && token.UserId != userId) {
+		return fmt.Errorf("api token '%s' not found", tokenId)
+	}
+	delete(secret.Data, tokenId)
+	return updateApiTokensSecret(secret)
+}
+
+// RevokeUserApiTokens deletes all tokens of the user
+func RevokeUserApiTokens(userId string) error {
+	apiTokensMutex.Lock()
+	defer apiTokensMutex.Unlock()
+
+	secret, err := apiTokensSecret()
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, token := range allApiTokens(secret) {
+		if token.UserId == userId {
+			delete(secret.Data, token.Id)
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+	return updateApiTokensSecret(secret)
+}
+
+// ValidateApiToken returns the token and its user for a punq_<id>_<secret> string
+func ValidateApiToken(value string) (*dtos.PunqApiToken, *dtos.PunqUser, error) {
+	tokenId, secretPart, found := strings.Cut(strings.TrimPrefix(value, dtos.API_TOKEN_PREFIX), "_")
+	if !strings.HasPrefix(value, dtos.API_TOKEN_PREFIX) || !found || tokenId == "" || secretPart == "" {
+		return nil, nil, ErrInvalidApiToken
+	}
+
+	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.APITOKENSSECRET, nil)
+	if secret == nil {
+		return nil, nil, ErrInvalidApiToken
+	}
+	token, err := apiTokenFrom(secret, tokenId)
+	if err != nil || token.IsExpired() {
+		return nil, nil, ErrInvalidApiToken
+	}
+	if subtle.ConstantTimeCompare([]byte(hashToken(secretPart)), []byte(token.TokenHash)) != 1 {
+		return nil, nil, ErrInvalidApiToken
+	}
+	user, err := GetUser(token.UserId)
+	if err != nil || user.Disabled {
+		return nil, nil, ErrInvalidApiToken
+	}
+
+	if time.Since(token.LastUsed) > API_TOKEN_LAST_USED_INTERVAL {
+		go touchApiToken(token.Id)
+	}
+	return token, user, nil
+}
+
+func touchApiToken(tokenId string) {
+	apiTokensMutex.Lock()
+	defer apiTokensMutex.Unlock()
+
+	secret, err := apiTokensSecret()
+	if err != nil {
+		return
+	}
+	token, err := apiTokenFrom(secret, tokenId)
+	if err != nil {
+		return
+	}
+	token.LastUsed = time.Now()
+	err = saveApiToken(secret, *token)
+	if err != nil {
+		logger.Log.Errorf("Failed to update last usage of api token '%s': %s", tokenId, err.Error())
+	}
+}
+
+func apiTokensSecret() (*v1.Secret, error) {
+	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.APITOKENSSECRET, nil)
+	if secret == nil {
+		provider, err := kubernetes.NewKubeProvider(nil)
+		if err != nil {
+			return nil, err
+		}
+		newSecret := utils.InitSecret()
+		newSecret.ObjectMeta.Name = utils.APITOKENSSECRET
+		newSecret.ObjectMeta.Namespace = utils.CONFIG.Kubernetes.OwnNamespace
+		newSecret.StringData = map[string]string{}
+		secret, err = provider.ClientSet.CoreV1().Secrets(utils.CONFIG.Kubernetes.OwnNamespace).Create(context.TODO(), &newSecret, kubernetes.MoCreateOptions())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	return secret, nil
+}
+
+func saveApiToken(secret *v1.Secret, token dtos.PunqApiToken) error {
+	rawToken, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	secret.Data[token.Id] = rawToken
+	return updateApiTokensSecret(secret)
+}
+
+func updateApiTokensSecret(secret *v1.Secret) error {
+	workloadResult := kubernetes.UpdateK8sSecret(*secret, nil)
+	if workloadResult.Error != nil {
+		return fmt.Errorf("%v", workloadResult.Error)
+	}
+	return nil
+}
+
+func apiTokenFrom(secret *v1.Secret, tokenId string) (*dtos.PunqApiToken, error) {
+	rawToken, ok := secret.Data[tokenId]
+	if !ok {
+		return nil, fmt.Errorf("api token '%s' not found", tokenId)
+	}
+	token := dtos.PunqApiToken{}
+	err := json.Unmarshal(rawToken, &token)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func allApiTokens(secret *v1.Secret) []dtos.PunqApiToken {
+	result := []dtos.PunqApiToken{}
+	for key := range secret.Data {
+		token, err := apiTokenFrom(secret, key)
+		if err != nil {
+			logger.Log.Errorf("Failed to unmarshal api token '%s': %s", key, err.Error())
+			continue
+		}
+		result = append(result, *token)
+	}
+	return result
+}
@@ -0,0 +1,325 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/kubernetes"
+	"github.com/mogenius/punq/logger"
+	"github.com/mogenius/punq/utils"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	AuditEntriesKey = "entries"
+
+	AUDIT_MAX_ENTRIES     = 1000
+	AUDIT_MAX_SECRET_SIZE = 900 * 1024 // secrets are limited to 1MiB
+	AUDIT_MAX_DIFF_SIZE   = 8 * 1024
+	AUDIT_FLUSH_INTERVAL  = 2 * time.Second
+	// entries waiting for the next write. If the secret cannot be written the oldest ones are dropped, more than
+	// AUDIT_MAX_ENTRIES would not fit into the secret anyway.
+	AUDIT_MAX_PENDING = AUDIT_MAX_ENTRIES
+	// time to write the pending entries on shutdown
+	AUDIT_SHUTDOWN_TIMEOUT = 5 * time.Second
+)
+
+// values of these keys are never written to the audit log (compared case-insensitively)
+var AUDIT_REDACTED_KEYS = []string{"password", "currentpassword", "newpassword", "passwordhistory", "token", "refreshtoken", "context", "privatekey", "secret", "code", "challenge"}
+
+// secret values are replaced by a keyed hash so changes are visible in a diff without allowing to guess the values.
+// The key is kept in the punq-keys secret, so hashes stay comparable across restarts and replicas.
+const AUDIT_HASH_KEY = "audit-hash"
+
+var auditQueue = make(chan dtos.PunqAuditEntry, 1000)
+var auditFlushRequests = make(chan chan struct{})
+var auditWriterOnce sync.Once
+
+// RecordAudit queues the entry. Entries are written in batches to the audit secret by a background writer.
+func RecordAudit(entry dtos.PunqAuditEntry) {
+	auditWriterOnce.Do(func() {
+		go auditWriter()
+	})
+
+	entry.Id = utils.NanoId()
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+	if len(entry.Diff) > AUDIT_MAX_DIFF_SIZE {
+		entry.Diff = entry.Diff[:AUDIT_MAX_DIFF_SIZE] + "\n... (truncated)"
+	}
+	auditQueue <- entry
+}
+
+func auditWriter() {
+	pending := []dtos.PunqAuditEntry{}
+	dropped := 0
+	ticker := time.NewTicker(AUDIT_FLUSH_INTERVAL)
+	defer ticker.Stop()
+
+	add := func(entry dtos.PunqAuditEntry) {
+		pending = append(pending, entry)
+		if len(pending) > AUDIT_MAX_PENDING {
+			dropped += len(pending) - AUDIT_MAX_PENDING
+			pending = pending[len(pending)-AUDIT_MAX_PENDING:]
+		}
+	}
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		batch := pending
+		if dropped > 0 {
+			batch = append(batch, dtos.PunqAuditEntry{
+				Id:        utils.NanoId(),
+				Timestamp: time.Now(),
+				UserEmail: "audit",
+				Action:    "dropped",
+				Result:    dtos.AUDIT_RESULT_FAILURE,
+				Message:   fmt.Sprintf("%d audit entries were dropped because the audit log could not be written.", dropped),
+			})
+		}
+		// failed batches (e.g. update conflicts) are retried with the next tick
+		err := appendAuditEntries(batch)
+		if err != nil {
+			logger.Log.Errorf("Failed to write %d audit entries (%d dropped so far): %s", len(pending), dropped, err.Error())
+			return
+		}
+		pending = []dtos.PunqAuditEntry{}
+		dropped = 0
+	}
+
+	for {
+		select {
+		case entry := <-auditQueue:
+			add(entry)
+		case <-ticker.C:
+			flush()
+		case done := <-auditFlushRequests:
+			for queued := len(auditQueue); queued > 0; queued-- {
+				add(<-auditQueue)
+			}
+			flush()
+			close(done)
+		}
+	}
+}
+
+// FlushAudit writes the queued entries immediately and waits at most timeout for it
+func FlushAudit(timeout time.Duration) {
+	auditWriterOnce.Do(func() {
+		go auditWriter()
+	})
+
+	done := make(chan struct{})
+	select {
+	case auditFlushRequests <- done:
+	case <-time.After(timeout):
+		logger.Log.Error("Timeout while flushing the audit log.")
+		return
+	}
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		logger.Log.Error("Timeout while flushing the audit log.")
+	}
+}
+
+// FlushAuditOnShutdown writes the queued entries before the process exits on SIGINT or SIGTERM
+func FlushAuditOnShutdown() {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-quit
+		FlushAudit(AUDIT_SHUTDOWN_TIMEOUT)
+		os.Exit(0)
+	}()
+}
+
+func appendAuditEntries(entries []dtos.PunqAuditEntry) error {
+	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.AUDITSECRET, nil)
+	if secret == nil {
+		provider, err := kubernetes.NewKubeProvider(nil)
+		if err != nil {
+			return err
+		}
+		newSecret := utils.InitSecret()
+		newSecret.ObjectMeta.Name = utils.AUDITSECRET
+		newSecret.ObjectMeta.Namespace = utils.CONFIG.Kubernetes.OwnNamespace
+		newSecret.StringData = map[string]string{}
+		secret, err = provider.ClientSet.CoreV1().Secrets(utils.CONFIG.Kubernetes.OwnNamespace).Create(context.TODO(), &newSecret, kubernetes.MoCreateOptions())
+		if err != nil {
+			return err
+		}
+	}
+
+	all := []dtos.PunqAuditEntry{}
+	if raw, ok := secret.Data[AuditEntriesKey]; ok {
+		err := json.Unmarshal(raw, &all)
+		if err != nil {
+			logger.Log.Errorf("Failed to unmarshal audit entries (starting a new log): %s", err.Error())
+			all = []dtos.PunqAuditEntry{}
+		}
+	}
+	all = append(all, entries...)
+
+	// ring: drop the oldest entries until count and size fit
+	if len(all) > AUDIT_MAX_ENTRIES {
+		all = all[len(all)-AUDIT_MAX_ENTRIES:]
+	}
+	data, err := json.Marshal(all)
+	for err == nil && len(data) > AUDIT_MAX_SECRET_SIZE && len(all) > 1 {
+		all = all[len(all)/10+1:]
+		data, err = json.Marshal(all)
+	}
+	if err != nil {
+		return err
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[AuditEntriesKey] = data
+	workloadResult := kubernetes.UpdateK8sSecret(*secret, nil)
+	if workloadResult.Error != nil {
+		return fmt.Errorf("%v", workloadResult.Error)
+	}
+	return nil
+}
+
+// ListAuditEntries returns the matching entries, newest first
+func ListAuditEntries(filter dtos.PunqAuditFilter) []dtos.PunqAuditEntry {
+	result := []dtos.PunqAuditEntry{}
+
+	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.AUDITSECRET, nil)
+	if secret == nil {
+		return result
+	}
+	all := []dtos.PunqAuditEntry{}
+	err := json.Unmarshal(secret.Data[AuditEntriesKey], &all)
+	if err != nil {
+		logger.Log.Errorf("Failed to unmarshal audit entries: %s", err.Error())
+		return result
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].Timestamp.After(all[j].Timestamp)
+	})
+	for _, entry := range all {
+		if filter.Limit > 0 && len(result) >= filter.Limit {
+			break
+		}
+		if entry.Matches(filter) {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// AuditDiff returns the unified yaml diff of two objects after removing noise (managedFields, status, ...) and
+// redacting sensitive values. Secret data is replaced by a short keyed hash so changes remain visible.
+func AuditDiff(before map[string]interface{}, after map[string]interface{}) string {
+	beforeYaml := auditYaml(before)
+	afterYaml := auditYaml(after)
+	if beforeYaml == afterYaml {
+		return ""
+	}
+	diff, err := kubernetes.UnifiedDiff(beforeYaml, afterYaml, "current", "request")
+	if err != nil {
+		return err.Error()
+	}
+	return diff
+}
+
+func auditYaml(obj map[string]interface{}) string {
+	if obj == nil {
+		return ""
+	}
+	cleaned := auditClean(obj)
+	data, err := yaml.Marshal(cleaned)
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+// auditHashValue returns the redacted value with its keyed hash (without the hash if the key is not available)
+func auditHashValue(value interface{}) string {
+	key, err := secretKey(AUDIT_HASH_KEY)
+	if err != nil {
+		logger.Log.Errorf("Failed to hash audited secret value: %s", err.Error())
+		return "<redacted>"
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(fmt.Sprintf("%v", value)))
+	return fmt.Sprintf("<redacted %x>", mac.Sum(nil)[:6])
+}
+
+func auditClean(obj map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	_, isK8sObject := obj["apiVersion"]
+	for key, value := range obj {
+		result[key] = value
+	}
+
+	if !isK8sObject {
+		return auditRedact(result).(map[string]interface{})
+	}
+
+	delete(result, "status")
+	if metadata, ok := result["metadata"].(map[string]interface{}); ok {
+		cleanedMetadata := map[string]interface{}{}
+		for key, value := range metadata {
+			if key != "managedFields" && key != "resourceVersion" && key != "generation" && key != "uid" && key != "creationTimestamp" {
+				cleanedMetadata[key] = value
+			}
+		}
+		result["metadata"] = cleanedMetadata
+	}
+	if result["kind"] == "Secret" {
+		for _, key := range []string{"data", "stringData"} {
+			if data, ok := result[key].(map[string]interface{}); ok {
+				hashed := map[string]interface{}{}
+				for dataKey, dataValue := range data {
+					hashed[dataKey] = auditHashValue(dataValue)
+				}
+				result[key] = hashed
+			}
+		}
+	}
+	return result
+}
+
+func auditRedact(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+		for key, entry := range typed {
+			if utils.ContainsEqual(AUDIT_REDACTED_KEYS, strings.ToLower(key)) {
+				result[key] = "<redacted>"
+			} else {
+				result[key] = auditRedact(entry)
+			}
+		}
+		return result
+	case []interface{}:
+		result := []interface{}{}
+		for _, entry := range typed {
+			result = append(result, auditRedact(entry))
+		}
+		return result
+	}
+	return value
+}
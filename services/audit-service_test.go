@@ -0,0 +1,67 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAuditCleanSecret(t *testing.T) {
+	// the key is normally loaded from the punq-keys secret, every replica uses the same one
+	secretKeys[AUDIT_HASH_KEY] = []byte(strings.Repeat("a", SECRET_KEY_LENGTH))
+	t.Cleanup(func() { delete(secretKeys, AUDIT_HASH_KEY) })
+
+	secret := func() map[string]interface{} {
+		return map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata":   map[string]interface{}{"name": "db", "resourceVersion": "1"},
+			"data":       map[string]interface{}{"user": "YWRtaW4=", "password": "c2VjcmV0", "copy": "c2VjcmV0"},
+			"stringData": map[string]interface{}{"token": "plain"},
+		}
+	}
+	cleaned := auditClean(secret())
+	data := cleaned["data"].(map[string]interface{})
+
+	if data["password"] != data["copy"] {
+		t.Errorf("auditClean() hashes of equal values = %v and %v, want equal", data["password"], data["copy"])
+	}
+	if data["password"] == data["user"] {
+		t.Errorf("auditClean() hashes of different values = %v, want different", data["password"])
+	}
+	for key, value := range data {
+		if !strings.HasPrefix(value.(string), "<redacted ") || strings.Contains(value.(string), secret()["data"].(map[string]interface{})[key].(string)) {
+			t.Errorf("auditClean() %s = %v, want a redacted hash", key, value)
+		}
+	}
+	if value := cleaned["stringData"].(map[string]interface{})["token"]; !strings.HasPrefix(value.(string), "<redacted ") {
+		t.Errorf("auditClean() stringData = %v, want a redacted hash", value)
+	}
+	if _, ok := cleaned["metadata"].(map[string]interface{})["resourceVersion"]; ok {
+		t.Errorf("auditClean() kept the resourceVersion")
+	}
+
+	// hashes of the same key stay comparable after a restart
+	again := auditClean(secret())["data"].(map[string]interface{})
+	if again["password"] != data["password"] {
+		t.Errorf("auditClean() hash after a restart = %v, want %v", again["password"], data["password"])
+	}
+	secretKeys[AUDIT_HASH_KEY] = []byte(strings.Repeat("b", SECRET_KEY_LENGTH))
+	other := auditClean(secret())["data"].(map[string]interface{})
+	if other["password"] == data["password"] {
+		t.Errorf("auditClean() hash of another key = %v, want another hash", other["password"])
+	}
+}
+
+func TestAuditCleanRedactsKeys(t *testing.T) {
+	cleaned := auditClean(map[string]interface{}{
+		"email":    "jane@example.com",
+		"password": "secret",
+		"nested":   map[string]interface{}{"refreshToken": "token", "name": "x"},
+	})
+	if cleaned["password"] != "<redacted>" || cleaned["nested"].(map[string]interface{})["refreshToken"] != "<redacted>" {
+		t.Errorf("auditClean() = %v, want redacted credentials", cleaned)
+	}
+	if cleaned["email"] != "jane@example.com" || cleaned["nested"].(map[string]interface{})["name"] != "x" {
+		t.Errorf("auditClean() = %v, want other values unchanged", cleaned)
+	}
+}
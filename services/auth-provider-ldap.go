@@ -0,0 +1,152 @@
+package services
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/utils"
+)
+
+const LDAP_TIMEOUT = 10 * time.Second
+
+// ldapAuthProvider searches the user with the service account and verifies the password with a bind as the user
+type ldapAuthProvider struct{}
+
+func (p *ldapAuthProvider) Name() string {
+	return dtos.USER_PROVIDER_LDAP
+}
+
+// ldapIdentity is the entry of the directory which accepted the password
+type ldapIdentity struct {
+	Dn          string
+	Email       string
+	DisplayName string
+	Groups      []string
+}
+
+func (p *ldapAuthProvider) Authenticate(login string, password string) (*dtos.PunqUser, error) {
+	identity, err := ldapIdentityFor(login, password)
+	if err != nil {
+		return nil, err
+	}
+	accessLevel, err := accessLevelForGroups(identity.Groups, utils.CONFIG.Ldap.AdminGroups, utils.CONFIG.Ldap.UserGroups, utils.CONFIG.Ldap.ReaderGroups, utils.CONFIG.Ldap.DefaultAccessLevel)
+	if err != nil {
+		return nil, err
+	}
+	return provisionExternalUser(dtos.USER_PROVIDER_LDAP, identity.Email, identity.DisplayName, accessLevel, utils.CONFIG.Ldap.AutoProvision)
+}
+
+// ldapIdentityFor searches the login with the service account and binds as the found entry to verify the password
+func ldapIdentityFor(login string, password string) (*ldapIdentity, error) {
+	// an empty password would result in an unauthenticated bind which always succeeds
+	if password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := ldapConnect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if utils.CONFIG.Ldap.BindDn != "" {
+		err = conn.Bind(utils.CONFIG.Ldap.BindDn, utils.CONFIG.Ldap.BindPassword)
+		if err != nil {
+			return nil, fmt.Errorf("bind of service account failed: %s", err.Error())
+		}
+	}
+
+	attributes := []string{utils.CONFIG.Ldap.EmailAttribute, utils.CONFIG.Ldap.DisplayNameAttribute, utils.CONFIG.Ldap.GroupAttribute}
+	search := ldap.NewSearchRequest(
+		utils.CONFIG.Ldap.BaseDn,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, int(LDAP_TIMEOUT.Seconds()), false,
+		strings.ReplaceAll(utils.CONFIG.Ldap.UserFilter, "%s", ldap.EscapeFilter(login)),
+		attributes,
+		nil,
+	)
+	result, err := conn.Search(search)
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return nil, fmt.Errorf("search failed: %s", err.Error())
+	}
+	// unknown or ambiguous logins are rejected
+	if result == nil || len(result.Entries) != 1 {
+		return nil, ErrInvalidCredentials
+	}
+	entry := result.Entries[0]
+
+	err = conn.Bind(entry.DN, password)
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("bind of '%s' failed: %s", entry.DN, err.Error())
+	}
+
+	identity := ldapIdentity{
+		Dn:          entry.DN,
+		Email:       entry.GetAttributeValue(utils.CONFIG.Ldap.EmailAttribute),
+		DisplayName: entry.GetAttributeValue(utils.CONFIG.Ldap.DisplayNameAttribute),
+		Groups:      entry.GetAttributeValues(utils.CONFIG.Ldap.GroupAttribute),
+	}
+	if identity.Email == "" {
+		return nil, fmt.Errorf("'%s' has no attribute '%s'", entry.DN, utils.CONFIG.Ldap.EmailAttribute)
+	}
+	if identity.DisplayName == "" {
+		identity.DisplayName = identity.Email
+	}
+	return &identity, nil
+}
+
+func ldapConnect() (*ldap.Conn, error) {
+	tlsConfig, err := ldapTlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := ldap.DialURL(utils.CONFIG.Ldap.Url, ldap.DialWithTLSConfig(tlsConfig), ldap.DialWithDialer(&net.Dialer{Timeout: LDAP_TIMEOUT}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to '%s': %s", utils.CONFIG.Ldap.Url, err.Error())
+	}
+	conn.SetTimeout(LDAP_TIMEOUT)
+
+	if utils.CONFIG.Ldap.StartTls {
+		err = conn.StartTLS(tlsConfig)
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("StartTLS failed: %s", err.Error())
+		}
+	}
+	return conn, nil
+}
+
+func ldapTlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: utils.CONFIG.Ldap.InsecureSkipVerify,
+	}
+	// required for StartTLS, ldaps:// would set it on its own
+	if ldapUrl, err := url.Parse(utils.CONFIG.Ldap.Url); err == nil {
+		tlsConfig.ServerName = ldapUrl.Hostname()
+	}
+
+	if utils.CONFIG.Ldap.CaFile != "" {
+		caData, err := os.ReadFile(utils.CONFIG.Ldap.CaFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, errors.New("no certificates found in " + utils.CONFIG.Ldap.CaFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
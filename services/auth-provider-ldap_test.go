@@ -0,0 +1,182 @@
+package services
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/mogenius/punq/utils"
+)
+
+type testLdapEntry struct {
+	uid        string
+	password   string
+	attributes map[string][]string
+}
+
+// testLdapServer answers simple binds and searches by uid. Searches require the bind of the service account.
+type testLdapServer struct {
+	serviceDn       string
+	servicePassword string
+	entries         map[string]testLdapEntry
+}
+
+func (s *testLdapServer) serve(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.handle(conn)
+		}
+	}()
+	return "ldap://" + listener.Addr().String()
+}
+
+func (s *testLdapServer) handle(conn net.Conn) {
+	defer conn.Close()
+	boundDn := ""
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+		messageId := packet.Children[0].Value.(int64)
+		request := packet.Children[1]
+
+		switch request.Tag {
+		case ldap.ApplicationBindRequest:
+			dn := request.Children[1].Value.(string)
+			password := request.Children[2].Data.String()
+			resultCode := uint16(ldap.LDAPResultInvalidCredentials)
+			if entry, ok := s.entries[dn]; (ok && entry.password == password) || (dn == s.serviceDn && password == s.servicePassword) {
+				resultCode = ldap.LDAPResultSuccess
+				boundDn = dn
+			}
+			conn.Write(testLdapResponse(messageId, ldap.ApplicationBindResponse, resultCode).Bytes())
+		case ldap.ApplicationSearchRequest:
+			if boundDn != s.serviceDn {
+				conn.Write(testLdapResponse(messageId, ldap.ApplicationSearchResultDone, ldap.LDAPResultInsufficientAccessRights).Bytes())
+				continue
+			}
+			filter, _ := ldap.DecompileFilter(request.Children[6])
+			for dn, entry := range s.entries {
+				// substring filters like (uid=jan*) are matched by prefix
+				prefix, isSubstring := strings.CutSuffix(filter, "*)")
+				if filter != "(uid="+entry.uid+")" && !(isSubstring && strings.HasPrefix("(uid="+entry.uid, prefix)) {
+					continue
+				}
+				result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "")
+				result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, dn, ""))
+				attributes := ber.NewSequence("")
+				for name, values := range entry.attributes {
+					attribute := ber.NewSequence("")
+					attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, ""))
+					set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "")
+					for _, value := range values {
+						set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, ""))
+					}
+					attribute.AppendChild(set)
+					attributes.AppendChild(attribute)
+				}
+				result.AppendChild(attributes)
+				conn.Write(testLdapEnvelope(messageId, result).Bytes())
+			}
+			conn.Write(testLdapResponse(messageId, ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess).Bytes())
+		case ldap.ApplicationUnbindRequest:
+			return
+		}
+	}
+}
+
+func testLdapEnvelope(messageId int64, response *ber.Packet) *ber.Packet {
+	envelope := ber.NewSequence("")
+	envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageId, ""))
+	envelope.AppendChild(response)
+	return envelope
+}
+
+func testLdapResponse(messageId int64, tag ber.Tag, resultCode uint16) *ber.Packet {
+	response := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "")
+	response.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(resultCode), ""))
+	response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+	response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+	return testLdapEnvelope(messageId, response)
+}
+
+func TestLdapIdentityFor(t *testing.T) {
+	server := &testLdapServer{
+		serviceDn:       "cn=punq,ou=services,dc=example,dc=com",
+		servicePassword: "service-secret",
+		entries: map[string]testLdapEntry{
+			"uid=jane,ou=people,dc=example,dc=com": {uid: "jane", password: "jane-secret", attributes: map[string][]string{
+				"mail":     {"jane@example.com"},
+				"cn":       {"Jane Doe"},
+				"memberOf": {"cn=admins,ou=groups,dc=example,dc=com", "cn=devs,ou=groups,dc=example,dc=com"},
+			}},
+			"uid=nomail,ou=people,dc=example,dc=com": {uid: "nomail", password: "nomail-secret", attributes: map[string][]string{"cn": {"No Mail"}}},
+		},
+	}
+	url := server.serve(t)
+
+	previousConfig := utils.CONFIG.Ldap
+	t.Cleanup(func() { utils.CONFIG.Ldap = previousConfig })
+	utils.CONFIG.Ldap.Url = url
+	utils.CONFIG.Ldap.StartTls = false
+	utils.CONFIG.Ldap.CaFile = ""
+	utils.CONFIG.Ldap.BaseDn = "dc=example,dc=com"
+	utils.CONFIG.Ldap.UserFilter = "(uid=%s)"
+	utils.CONFIG.Ldap.EmailAttribute = "mail"
+	utils.CONFIG.Ldap.DisplayNameAttribute = "cn"
+	utils.CONFIG.Ldap.GroupAttribute = "memberOf"
+
+	tests := []struct {
+		name         string
+		bindPassword string
+		login        string
+		password     string
+		wantEmail    string
+		wantInvalid  bool
+		wantOtherErr bool
+	}{
+		{"valid bind", "service-secret", "jane", "jane-secret", "jane@example.com", false, false},
+		{"wrong password", "service-secret", "jane", "wrong", "", true, false},
+		{"empty password is no anonymous bind", "service-secret", "jane", "", "", true, false},
+		{"unknown user", "service-secret", "john", "jane-secret", "", true, false},
+		{"filter characters are escaped", "service-secret", "jan*", "jane-secret", "", true, false},
+		{"entry without email", "service-secret", "nomail", "nomail-secret", "", false, true},
+		{"service account rejected", "wrong", "jane", "jane-secret", "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utils.CONFIG.Ldap.BindDn = server.serviceDn
+			utils.CONFIG.Ldap.BindPassword = tt.bindPassword
+
+			identity, err := ldapIdentityFor(tt.login, tt.password)
+			if errors.Is(err, ErrInvalidCredentials) != tt.wantInvalid {
+				t.Fatalf("ldapIdentityFor() error = %v, want invalid credentials %t", err, tt.wantInvalid)
+			}
+			if tt.wantOtherErr && (err == nil || errors.Is(err, ErrInvalidCredentials)) {
+				t.Fatalf("ldapIdentityFor() error = %v, want another error", err)
+			}
+			if tt.wantEmail == "" {
+				return
+			}
+			if err != nil {
+				t.Fatalf("ldapIdentityFor() error = %v", err)
+			}
+			if identity.Email != tt.wantEmail || identity.DisplayName != "Jane Doe" || len(identity.Groups) != 2 || identity.Dn != "uid=jane,ou=people,dc=example,dc=com" {
+				t.Errorf("ldapIdentityFor() = %+v, want jane with 2 groups", identity)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/logger"
+	"github.com/mogenius/punq/utils"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var ErrInvalidCredentials = errors.New("username or password is incorrect")
+var ErrUserDisabled = errors.New("user is deactivated")
+var ErrUserNotFound = errors.New("user not found")
+
+// AuthProvider checks the credentials of /auth/login. Every provider resolves a PunqUser from the punq-users
+// secret (external users are provisioned there), so tokens and authorization work the same for all providers.
+type AuthProvider interface {
+	Name() string
+	Authenticate(login string, password string) (*dtos.PunqUser, error)
+}
+
+// AuthProviders returns the enabled providers in the order they are asked
+func AuthProviders() []AuthProvider {
+	providers := []AuthProvider{&localAuthProvider{}}
+	if utils.CONFIG.Ldap.Enabled {
+		providers = append(providers, &ldapAuthProvider{})
+	}
+	return providers
+}
+
+// Authenticate returns the user of the first provider accepting the credentials
+func Authenticate(login string, password string) (*dtos.PunqUser, error) {
+	return authenticateWith(AuthProviders(), login, password)
+}
+
+func authenticateWith(providers []AuthProvider, login string, password string) (*dtos.PunqUser, error) {
+	if login == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+	for _, provider := range providers {
+		user, err := provider.Authenticate(login, password)
+		if err == nil {
+			// disabled accounts get the same response as wrong passwords, so they cannot be told apart
+			if user.Disabled {
+				logger.Log.Warningf("Login of disabled user '%s' rejected.", user.Id)
+				return nil, ErrInvalidCredentials
+			}
+			return user, nil
+		}
+		if !errors.Is(err, ErrInvalidCredentials) {
+			logger.Log.Errorf("Auth provider '%s' failed for '%s': %s", provider.Name(), login, err.Error())
+		}
+	}
+	return nil, ErrInvalidCredentials
+}
+
+type localAuthProvider struct{}
+
+var dummyPasswordHashOnce sync.Once
+var dummyPasswordHashValue string
+
+// dummyPasswordHash has the cost of the user passwords (see AddUser)
+func dummyPasswordHash() string {
+	dummyPasswordHashOnce.Do(func() {
+		hash, err := bcrypt.GenerateFromPassword([]byte(utils.NanoId()), bcrypt.DefaultCost)
+		if err != nil {
+			logger.Log.Errorf("Failed to generate dummy password hash: %s", err.Error())
+		}
+		dummyPasswordHashValue = string(hash)
+	})
+	return dummyPasswordHashValue
+}
+
+func (p *localAuthProvider) Name() string {
+	return dtos.USER_PROVIDER_LOCAL
+}
+
+func (p *localAuthProvider) Authenticate(login string, password string) (*dtos.PunqUser, error) {
+	user, err := GetUserByEmail(login)
+	// external users have a random password and must use their provider
+	if err != nil || user.ProviderName() != dtos.USER_PROVIDER_LOCAL {
+		// compare anyway, so unknown users cannot be told apart by the response time
+		dummy := dtos.PunqUser{Password: dummyPasswordHash()}
+		dummy.PasswordCheck(password)
+		return nil, ErrInvalidCredentials
+	}
+	valid, err := user.PasswordCheck(password)
+	if err != nil || !valid {
+		return nil, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+// accessLevelForGroups returns the highest access level of the mapped groups (compared case-insensitively) or the default level
+func accessLevelForGroups(groups []string, adminGroups []string, userGroups []string, readerGroups []string, defaultLevel string) (dtos.AccessLevel, error) {
+	mapping := []struct {
+		level  dtos.AccessLevel
+		groups []string
+	}{
+		{dtos.ADMIN, adminGroups},
+		{dtos.USER, userGroups},
+		{dtos.READER, readerGroups},
+	}
+	for _, entry := range mapping {
+		for _, group := range groups {
+			for _, mapped := range entry.groups {
+				if strings.EqualFold(strings.TrimSpace(group), strings.TrimSpace(mapped)) {
+					return entry.level, nil
+				}
+			}
+		}
+	}
+	if defaultLevel != "" {
+		return dtos.AccessLevelFromString(defaultLevel), nil
+	}
+	return dtos.READER, errors.New("you are not a member of any group allowed to use punq")
+}
+
+// provisionExternalUser returns the punq user of the provider with the email (created if autoProvision is set) and
+// updates its access level to the one granted by the provider. Users provisioned via SCIM sign in with their
+// provider too, their access level is managed via SCIM. Local users and users of other providers are never matched,
+// otherwise whoever controls the email at the provider could take over the account.
+func provisionExternalUser(provider string, email string, displayName string, accessLevel dtos.AccessLevel, autoProvision bool) (*dtos.PunqUser, error) {
+	user, err := GetUserByEmail(email)
+	if err == nil && user.ProviderName() == dtos.USER_PROVIDER_SCIM {
+		return user, nil
+	}
+	if err == nil && user.ProviderName() != provider {
+		return nil, fmt.Errorf("'%s' is a %s user and cannot sign in with %s", email, user.ProviderName(), provider)
+	}
+	if err != nil {
+		if !autoProvision {
+			return nil, fmt.Errorf("no punq user found for '%s'", email)
+		}
+		// the password is never used, external users sign in via their provider only
+		user, err = createUser(dtos.PunqUserCreateInput{
+			Email:       email,
+			Password:    utils.NanoId() + utils.NanoId(),
+			DisplayName: displayName,
+			AccessLevel: accessLevel,
+			Provider:    provider,
+		})
+		if err != nil {
+			return nil, err
+		}
+		logger.Log.Noticef("Created user '%s' (%s) on first %s login.", user.Email, user.Id, provider)
+		return user, nil
+	}
+
+	if user.AccessLevel != accessLevel {
+		// the access level of the admin user is never changed to avoid locking out punq
+		admin, _ := GetAdmin()
+		if admin == nil || admin.Id != user.Id {
+			logger.Log.Noticef("Access level of '%s' changed from %d to %d by %s groups.", user.Email, user.AccessLevel, accessLevel, provider)
+			user.AccessLevel = accessLevel
+			return UpdateUser(*user)
+		}
+	}
+	return user, nil
+}
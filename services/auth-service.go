@@ -22,15 +22,18 @@ import (
 )
 
 const (
-	SecKeyPair    = "keyPair"
-	TokenExpHours = 24 * 7 // 1 week
+	SecKeyPair = "keyPair" // single key of older versions, migrated to SecKeyPairs on the first rotation
+	// access tokens are short-lived, clients renew them with the refresh token of their session
+	AccessTokenExp = 15 * time.Minute
 )
 
-var KeyPairInstance *KeyPair
-
 type KeyPair struct {
-	PrivateKeyString string `json:"privateKey" validate:"required"`
-	PublicKeyString  string `json:"publicKey" validate:"required"`
+	Id               string    `json:"kid"`
+	PrivateKeyString string    `json:"privateKey" validate:"required"`
+	PublicKeyString  string    `json:"publicKey" validate:"required"`
+	Created          time.Time `json:"createdAt"`
+	RetiredAt        time.Time `json:"retiredAt,omitempty"`   // zero = active signing key
+	VerifyUntil      time.Time `json:"verifyUntil,omitempty"` // retired keys verify tokens until then, zero = forever
 
 	PrivateKey *ecdsa.PrivateKey `json:"-"`
 	PublicKey  any               `json:"-"`
@@ -39,7 +42,8 @@ type KeyPair struct {
 type keyPairAlias KeyPair
 
 type PunqClaims struct {
-	UserID string `json:"userId"`
+	UserID    string `json:"userId"`
+	SessionID string `json:"sid"`
 	jwt.RegisteredClaims
 }
 
@@ -76,6 +80,11 @@ func (keyPair *KeyPair) UnmarshalJSON(data []byte) error {
 	}
 	keyPair.PublicKey = publicKey
 
+	if keyPair.Id == "" {
+		if ecdsaPublicKey, ok := publicKey.(*ecdsa.PublicKey); ok {
+			keyPair.Id = jwtKeyId(ecdsaPublicKey)
+		}
+	}
 	return nil
 }
 
@@ -120,6 +129,9 @@ func generateAuthKeyPair() (*KeyPair, error) {
 
 	keyPair.PublicKeyString = string(publicKeyPEM)
 	keyPair.PrivateKey = privateKey
+	keyPair.PublicKey = publicKey
+	keyPair.Id = jwtKeyId(publicKey)
+	keyPair.Created = time.Now()
 
 	return &keyPair, nil
 }
@@ -150,13 +162,13 @@ func CreateKeyPair() (*KeyPair, error) {
 		logger.Log.Error(msg)
 		return nil, errors.New(msg)
 	}
-	rawKeyPair, err := json.Marshal(keyPair)
+	rawKeyPairs, err := json.Marshal([]KeyPair{*keyPair})
 	if err != nil {
 		msg := fmt.Sprintf("failed marshaling %v", err)
 		logger.Log.Error(msg)
 		return nil, errors.New(msg)
 	}
-	secret.StringData[SecKeyPair] = string(rawKeyPair)
+	secret.StringData[SecKeyPairs] = string(rawKeyPairs)
 
 	// if not exist
 	if existingSecret == nil || getErr != nil {
@@ -171,7 +183,11 @@ func CreateKeyPair() (*KeyPair, error) {
 	}
 
 	// get from secret
-	return GetKeyPair()
+	keyPairs, err := jwtKeysFrom(existingSecret)
+	if err != nil {
+		return nil, err
+	}
+	return activeJwtKey(keyPairs)
 }
 
 func RemoveKeyPair() {
@@ -194,74 +210,45 @@ func RemoveKeyPair() {
 	fmt.Printf("Deleted %s/%s secret. ✅\n", utils.CONFIG.Kubernetes.OwnNamespace, utils.JWTSECRET)
 }
 
+// GetKeyPair returns the active signing key
 func GetKeyPair() (*KeyPair, error) {
-	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.JWTSECRET, nil)
-	if secret == nil {
-		logger.Log.Warningf("Failed to get '%s/%s' secret.", utils.CONFIG.Kubernetes.OwnNamespace, utils.JWTSECRET)
-		return CreateKeyPair()
-	}
-
-	if secret.Data[SecKeyPair] != nil {
-		keyPair := KeyPair{}
-		err := json.Unmarshal([]byte(secret.Data[SecKeyPair]), &keyPair)
-		if err != nil {
-			msg := fmt.Sprintf("failed to Unmarshal user '%s' %v.", SecKeyPair, err)
-			logger.Log.Error(msg)
-			return nil, err
-		}
-		return &keyPair, nil
+	keyPairs, err := jwtKeyRing(false)
+	if err != nil {
+		return nil, err
 	}
-
-	return CreateKeyPair()
+	return activeJwtKey(keyPairs)
 }
 
-func GenerateToken(user *dtos.PunqUser) (*dtos.PunqToken, error) {
-	if KeyPairInstance == nil {
-		keyPair, err := GetKeyPair()
-		if err != nil {
-			return nil, err
-		}
-		KeyPairInstance = keyPair
+// GenerateToken issues an access token for the session (see CreateSession)
+func GenerateToken(user *dtos.PunqUser, sessionId string) (*dtos.PunqToken, error) {
+	keyPair, err := GetKeyPair()
+	if err != nil {
+		return nil, err
 	}
 
 	claims := jwt.MapClaims{}
 	claims["accessLevel"] = user.AccessLevel
 	claims["userId"] = user.Id
-	claims["exp"] = time.Now().Add(time.Hour * time.Duration(TokenExpHours)).Unix()
+	claims["sid"] = sessionId
+	claims["iat"] = time.Now().Unix()
+	claims["exp"] = time.Now().Add(AccessTokenExp).Unix()
 	token := jwt.NewWithClaims(jwt.SigningMethodES512, claims)
+	token.Header["kid"] = keyPair.Id
 
 	// sign JWT-Token with private key
-	tokenString, err := token.SignedString(KeyPairInstance.PrivateKey)
+	tokenString, err := token.SignedString(keyPair.PrivateKey)
 	if err != nil {
 		logger.Log.Errorf("sign JWT-Token with private key failed %s", err)
 		return nil, err
 	}
-	return dtos.CreateToken(tokenString), nil
+	punqToken := dtos.CreateToken(tokenString)
+	punqToken.ExpiresIn = int64(AccessTokenExp.Seconds())
+	return punqToken, nil
 }
 
 func ValidationToken(tokenString string) (*PunqClaims, error) {
-	if KeyPairInstance == nil {
-		keyPair, err := GetKeyPair()
-		if err != nil {
-			return nil, err
-		}
-		KeyPairInstance = keyPair
-	}
-
-	ecdsaPublicKey, ok := KeyPairInstance.PublicKey.(*ecdsa.PublicKey)
-	if !ok {
-		msg := fmt.Sprintf("Invalid public key")
-		logger.Log.Error(msg)
-		return nil, errors.New(msg)
-	}
-
 	// Validation
-	token, err := jwt.ParseWithClaims(tokenString, &PunqClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodECDSA); !ok {
-			return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
-		}
-		return ecdsaPublicKey, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &PunqClaims{}, jwtKeyFunc)
 	if err != nil {
 		logger.Log.Error(err.Error())
 		return nil, err
@@ -278,5 +265,27 @@ func ValidationToken(tokenString string) (*PunqClaims, error) {
 		logger.Log.Error(msg)
 		return nil, errors.New(msg)
 	}
+	// tokens without session were issued before sessions existed and cannot be revoked
+	if claims.SessionID == "" || IsSessionRevoked(claims.SessionID) {
+		return nil, errors.New("session has ended. Please log in again")
+	}
 	return claims, nil
 }
+
+// jwtKeyFunc returns the public key of the key which signed the token
+func jwtKeyFunc(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodECDSA); !ok {
+		return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
+	}
+	// tokens without kid were signed before key rotation existed
+	kid, _ := token.Header["kid"].(string)
+	keyPair, err := jwtKeyFor(kid)
+	if err != nil {
+		return nil, err
+	}
+	ecdsaPublicKey, ok := keyPair.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("Invalid public key")
+	}
+	return ecdsaPublicKey, nil
+}
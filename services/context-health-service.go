@@ -0,0 +1,165 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/kubernetes"
+	"github.com/mogenius/punq/logger"
+	"github.com/mogenius/punq/utils"
+)
+
+// actor of the audit entries of reachability changes
+const CONTEXT_HEALTH_ACTOR = "context-health"
+
+// only the holder of this lease writes reachability changes to the contexts and the audit log
+const CONTEXT_HEALTH_LEASE = "punq-context-health"
+
+var contextHealth = map[string]dtos.PunqContextHealth{}
+var contextHealthMutex sync.Mutex
+var contextHealthLeader atomic.Bool
+
+// StartContextHealthMonitor probes all contexts every context_health.interval in the background. Every replica
+// probes for its own /context/health, but only the leader (see CONTEXT_HEALTH_LEASE) records the changes.
+func StartContextHealthMonitor() {
+	if utils.CONFIG.ContextHealth.Interval <= 0 {
+		logger.Log.Notice("Context health monitor is disabled.")
+		return
+	}
+	err := kubernetes.RunLeaderElection(CONTEXT_HEALTH_LEASE, contextHealthLeader.Store)
+	if err != nil {
+		logger.Log.Warningf("Leader election for the context health monitor failed, recording changes anyway: %s", err.Error())
+		contextHealthLeader.Store(true)
+	}
+	go func() {
+		for {
+			UpdateContextHealth()
+			time.Sleep(utils.CONFIG.ContextHealth.Interval)
+		}
+	}()
+}
+
+// UpdateContextHealth reloads the contexts, probes them in parallel and records the results. On the leader a change of the
+// reachability is logged, audited and written to the stored context, so Reachable and Provider of the context stay current.
+func UpdateContextHealth() []dtos.PunqContextHealth {
+	contexts := ListContexts()
+	// the own context cannot be deleted, no contexts at all means the secret could not be read
+	if len(contexts) > 0 {
+		kubernetes.ContextSync(contexts)
+	}
+	results := ProbeContexts(contexts)
+
+	changed := []int{}
+	wasReachable := make([]bool, len(contexts))
+	contextHealthMutex.Lock()
+	previous := contextHealth
+	contextHealth = map[string]dtos.PunqContextHealth{}
+	for i, health := range results {
+		wasReachable[i] = contexts[i].Reachable
+		health.Since = health.CheckedAt
+		if last, ok := previous[health.ContextId]; ok {
+			wasReachable[i] = last.Reachable
+			if last.Reachable == health.Reachable {
+				health.Since = last.Since
+			}
+		}
+		contextHealth[health.ContextId] = health
+		results[i] = health
+
+		if wasReachable[i] != health.Reachable || contexts[i].Reachable != health.Reachable || contexts[i].Provider != health.Provider {
+			changed = append(changed, i)
+		}
+	}
+	contextHealthMutex.Unlock()
+
+	if !contextHealthLeader.Load() {
+		return results
+	}
+	for _, i := range changed {
+		contextHealthChanged(contexts[i], results[i], wasReachable[i])
+	}
+	return results
+}
+
+// ProbeContexts checks the given contexts in parallel without recording the results (see kubernetes.ProbeContext)
+func ProbeContexts(contexts []dtos.PunqContext) []dtos.PunqContextHealth {
+	results := make([]dtos.PunqContextHealth, len(contexts))
+	var wg sync.WaitGroup
+	for i := range contexts {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = kubernetes.ProbeContext(contexts[i], utils.CONFIG.ContextHealth.Timeout)
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+// ListContextHealth returns the last probe results of the contexts the user can access. Errors can contain details
+// of the cluster (e.g. addresses), they are only returned to admins of the context.
+func ListContextHealth(user *dtos.PunqUser) []dtos.PunqContextHealth {
+	contexts := ListContextsForUser(user)
+	isAdmin := map[string]bool{}
+	for _, ctx := range contexts {
+		accessLevel, err := AccessLevelForContext(user, &ctx.Id)
+		isAdmin[ctx.Id] = err == nil && accessLevel >= dtos.ADMIN
+	}
+
+	contextHealthMutex.Lock()
+	defer contextHealthMutex.Unlock()
+
+	result := []dtos.PunqContextHealth{}
+	for _, ctx := range contexts {
+		health, ok := contextHealth[ctx.Id]
+		if !ok {
+			continue
+		}
+		if !isAdmin[ctx.Id] {
+			health.LastError = ""
+		}
+		result = append(result, health)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ContextName < result[j].ContextName
+	})
+	return result
+}
+
+func contextHealthChanged(ctx dtos.PunqContext, health dtos.PunqContextHealth, wasReachable bool) {
+	if wasReachable != health.Reachable {
+		entry := dtos.PunqAuditEntry{
+			UserEmail: CONTEXT_HEALTH_ACTOR,
+			ContextId: ctx.Id,
+			Action:    "health",
+			Kind:      "context",
+			Name:      ctx.Name,
+			Result:    dtos.AUDIT_RESULT_SUCCESS,
+			Message:   fmt.Sprintf("Context '%s' is reachable again.", ctx.Name),
+		}
+		if health.Reachable {
+			logger.Log.Notice(entry.Message)
+		} else {
+			entry.Result = dtos.AUDIT_RESULT_FAILURE
+			entry.Message = fmt.Sprintf("Context '%s' became unreachable: %s", ctx.Name, health.LastError)
+			logger.Log.Warning(entry.Message)
+		}
+		RecordAudit(entry)
+	}
+
+	// re-read the context, it might have been changed since the probe started
+	stored, err := GetContext(ctx.Id)
+	if err != nil {
+		return
+	}
+	stored.Reachable = health.Reachable
+	stored.Provider = health.Provider
+	_, err = UpdateContext(*stored)
+	if err != nil {
+		logger.Log.Errorf("Failed to update reachability of context '%s': %s", ctx.Name, err.Error())
+	}
+}
@@ -52,6 +52,11 @@ func AddContext(ctx dtos.PunqContext) (*dtos.PunqContext, error) {
 }
 
 func UpdateContext(ctx dtos.PunqContext) (interface{}, error) {
+	if ctx.Impersonation != nil {
+		if err := ctx.Impersonation.Validate(); err != nil {
+			return nil, err
+		}
+	}
 	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.CONTEXTSSECRET, nil)
 	if secret == nil {
 		msg := fmt.Sprintf("failed to get '%s/%s' secret", utils.CONFIG.Kubernetes.OwnNamespace, utils.CONTEXTSSECRET)
@@ -69,12 +74,11 @@ func UpdateContext(ctx dtos.PunqContext) (interface{}, error) {
 
 	workloadResult := kubernetes.UpdateK8sSecret(*secret, nil)
 	if workloadResult.Result != nil {
+		// Update LocalContextArray (cached clients are rebuilt if the kubeconfig changed)
+		kubernetes.ContextAddOne(ctx)
 		return workloadResult.Result, nil
 	}
 
-	// Update LocalContextArray
-	kubernetes.ContextAddMany(ListContexts())
-
 	return nil, errors.New(fmt.Sprintf("%v", workloadResult.Error))
 }
 
@@ -101,8 +105,8 @@ func DeleteContext(id string) (interface{}, error) {
 		// success
 		workloadResult.Result = fmt.Sprintf("Context %s successfully deleted.", id)
 
-		// Update LocalContextArray
-		kubernetes.ContextAddMany(ListContexts())
+		// Update LocalContextArray and drop cached clients
+		kubernetes.ContextRemove(id)
 
 		return workloadResult.Result, nil
 	}
@@ -110,6 +114,25 @@ func DeleteContext(id string) (interface{}, error) {
 	return nil, fmt.Errorf("%v", workloadResult.Error)
 }
 
+// SetContextImpersonation changes the impersonation settings of the context (nil disables impersonation)
+func SetContextImpersonation(contextId string, impersonation *dtos.PunqImpersonation) (*dtos.PunqContext, error) {
+	ctx, err := GetContext(contextId)
+	if err != nil {
+		return nil, err
+	}
+	ctx.Impersonation = impersonation
+	_, err = UpdateContext(*ctx)
+	if err != nil {
+		return nil, err
+	}
+	if impersonation != nil && impersonation.Enabled {
+		logger.Log.Noticef("Context '%s' impersonates its users now.", ctx.Name)
+	} else {
+		logger.Log.Noticef("Impersonation of context '%s' disabled.", ctx.Name)
+	}
+	return ctx, nil
+}
+
 func GetContext(id string) (*dtos.PunqContext, error) {
 	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.CONTEXTSSECRET, nil)
 	if secret == nil {
@@ -155,10 +178,69 @@ func GetOwnContext() (*dtos.PunqContext, error) {
 	return nil, fmt.Errorf("%s not found", utils.CONTEXTOWN)
 }
 
+func AccessLevelForContext(user *dtos.PunqUser, contextId *string) (dtos.AccessLevel, error) {
+	if contextId == nil || *contextId == "" {
+		return user.AccessLevel, nil
+	}
+
+	ctx, err := GetContext(*contextId)
+	if err != nil {
+		return dtos.READER, err
+	}
+
+	level, allowed := ctx.AccessLevelFor(user, GroupIdsForUser(user.Id))
+	if !allowed {
+		return dtos.READER, fmt.Errorf("user '%s' has no access to context '%s'", user.Id, *contextId)
+	}
+	return level, nil
+}
+
+func ListContextsForUser(user *dtos.PunqUser) []dtos.PunqContext {
+	result := []dtos.PunqContext{}
+	groupIds := GroupIdsForUser(user.Id)
+	for _, ctx := range ListContexts() {
+		if _, allowed := ctx.AccessLevelFor(user, groupIds); !allowed {
+			continue
+		}
+		if user.AccessLevel == dtos.ADMIN {
+			result = append(result, ctx)
+		} else {
+			result = append(result, ctx.Redacted())
+		}
+	}
+	return result
+}
+
+// KubeContextId returns the context id for kubernetes requests of the user. Contexts with impersonation get an
+// impersonated id (see kubernetes.ImpersonatedContextId), so the RBAC of the cluster applies to the user.
+func KubeContextId(user *dtos.PunqUser, contextId string) string {
+	ctx := kubernetes.ContextForId(contextId)
+	if ctx == nil || ctx.Impersonation == nil || !ctx.Impersonation.Enabled {
+		return contextId
+	}
+	username, groups := ctx.Impersonation.Identity(user, GroupNamesForUser(user.Id))
+	return kubernetes.ImpersonatedContextId(contextId, username, groups)
+}
+
+// GetGinKubeContextId returns the KubeContextId of the authorized user (or the plain context id on routes without user)
+func GetGinKubeContextId(c *gin.Context) *string {
+	contextId := GetGinContextId(c)
+	user := GetGinContextUser(c)
+	if contextId == nil || user == nil {
+		return contextId
+	}
+	kubeContextId := KubeContextId(user, *contextId)
+	return &kubeContextId
+}
+
 func GetGinContextId(c *gin.Context) *string {
 	if contextId := c.GetHeader("X-Context-Id"); contextId != "" {
 		return &contextId
 	}
+	// websocket clients cannot set custom headers
+	if contextId := c.Query("context-id"); contextId != "" {
+		return &contextId
+	}
 	return nil
 }
 
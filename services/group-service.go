@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/kubernetes"
+	"github.com/mogenius/punq/logger"
+	"github.com/mogenius/punq/utils"
+	v1 "k8s.io/api/core/v1"
+)
+
+var groupsMutex sync.Mutex
+
+func ListGroups() ([]dtos.PunqGroup, error) {
+	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.GROUPSSECRET, nil)
+	if secret == nil {
+		return []dtos.PunqGroup{}, nil
+	}
+	result := allGroups(secret)
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+func GetGroup(id string) (*dtos.PunqGroup, error) {
+	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.GROUPSSECRET, nil)
+	if secret == nil {
+		return nil, fmt.Errorf("group '%s' not found", id)
+	}
+	return groupFrom(secret, id)
+}
+
+func AddGroup(input dtos.PunqGroupCreateInput) (*dtos.PunqGroup, error) {
+	groupsMutex.Lock()
+	defer groupsMutex.Unlock()
+
+	secret, err := groupsSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	group := dtos.PunqGroup{
+		Id:          utils.NanoId(),
+		Name:        strings.TrimSpace(input.Name),
+		Description: input.Description,
+		Members:     input.Members,
+		Created:     time.Now().Format(time.RFC3339),
+		ExternalId:  input.ExternalId,
+	}
+	err = validateGroup(secret, &group)
+	if err != nil {
+		return nil, err
+	}
+	err = saveGroup(secret, group)
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// UpdateGroup changes name, description and members of the group
+func UpdateGroup(input dtos.PunqGroup) (*dtos.PunqGroup, error) {
+	groupsMutex.Lock()
+	defer groupsMutex.Unlock()
+
+	secret, err := groupsSecret()
+	if err != nil {
+		return nil, err
+	}
+	group, err := groupFrom(secret, input.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	group.Name = strings.TrimSpace(input.Name)
+	group.Description = input.Description
+	group.Members = input.Members
+	// only set by SCIM, other clients do not know it
+	if input.ExternalId != "" {
+		group.ExternalId = input.ExternalId
+	}
+	err = validateGroup(secret, group)
+	if err != nil {
+		return nil, err
+	}
+	err = saveGroup(secret, *group)
+	if err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// DeleteGroup deletes the group, its access entries of all contexts and its role bindings
+func DeleteGroup(id string) error {
+	groupsMutex.Lock()
+	defer groupsMutex.Unlock()
+
+	secret, err := groupsSecret()
+	if err != nil {
+		return err
+	}
+	if _, err := groupFrom(secret, id); err != nil {
+		return err
+	}
+
+	for _, ctx := range ListContexts() {
+		if ctx.RemoveGroupAccess(id) {
+			_, err := UpdateContext(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to remove group from context '%s': %s", ctx.Id, err.Error())
+			}
+		}
+	}
+
+	err = RemoveGroupFromRoles(id)
+	if err != nil {
+		return fmt.Errorf("failed to remove group from roles: %s", err.Error())
+	}
+
+	delete(secret.Data, id)
+	return updateGroupsSecret(secret)
+}
+
+func AddGroupMember(groupId string, userId string) (*dtos.PunqGroup, error) {
+	group, err := GetGroup(groupId)
+	if err != nil {
+		return nil, err
+	}
+	if group.HasMember(userId) {
+		return group, nil
+	}
+	group.Members = append(group.Members, userId)
+	return UpdateGroup(*group)
+}
+
+func RemoveGroupMember(groupId string, userId string) (*dtos.PunqGroup, error) {
+	group, err := GetGroup(groupId)
+	if err != nil {
+		return nil, err
+	}
+	if !group.HasMember(userId) {
+		return nil, fmt.Errorf("user '%s' is no member of group '%s'", userId, group.Name)
+	}
+	members := []string{}
+	for _, member := range group.Members {
+		if member != userId {
+			members = append(members, member)
+		}
+	}
+	group.Members = members
+	return UpdateGroup(*group)
+}
+
+// RemoveUserFromGroups removes the user from all groups (e.g. if the user has been deleted)
+func RemoveUserFromGroups(userId string) error {
+	groupsMutex.Lock()
+	defer groupsMutex.Unlock()
+
+	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.GROUPSSECRET, nil)
+	if secret == nil {
+		return nil
+	}
+	changed := false
+	for _, group := range allGroups(secret) {
+		if !group.HasMember(userId) {
+			continue
+		}
+		members := []string{}
+		for _, member := range group.Members {
+			if member != userId {
+				members = append(members, member)
+			}
+		}
+		group.Members = members
+		rawGroup, err := json.Marshal(group)
+		if err != nil {
+			return err
+		}
+		secret.Data[group.Id] = rawGroup
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return updateGroupsSecret(secret)
+}
+
+// GroupIdsForUser returns the ids of all groups the user is a member of
+func GroupIdsForUser(userId string) []string {
+	result := []string{}
+	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.GROUPSSECRET, nil)
+	if secret == nil {
+		return result
+	}
+	for _, group := range allGroups(secret) {
+		if group.HasMember(userId) {
+			result = append(result, group.Id)
+		}
+	}
+	return result
+}
+
+// GroupNamesForUser returns the names of all groups the user is a member of
+func GroupNamesForUser(userId string) []string {
+	result := []string{}
+	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.GROUPSSECRET, nil)
+	if secret == nil {
+		return result
+	}
+	for _, group := range allGroups(secret) {
+		if group.HasMember(userId) {
+			result = append(result, group.Name)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// validateGroup requires a unique name and existing members (duplicates are removed)
+func validateGroup(secret *v1.Secret, group *dtos.PunqGroup) error {
+	if group.Name == "" {
+		return errors.New("name of the group is required")
+	}
+	for _, other := range allGroups(secret) {
+		if other.Id != group.Id && strings.EqualFold(other.Name, group.Name) {
+			return fmt.Errorf("group '%s' already exists", group.Name)
+		}
+	}
+
+	members := []string{}
+	for _, member := range group.Members {
+		if utils.ContainsEqual(members, member) {
+			continue
+		}
+		if _, err := GetUser(member); err != nil {
+			return fmt.Errorf("user '%s' not found", member)
+		}
+		members = append(members, member)
+	}
+	group.Members = members
+	return nil
+}
+
+func groupsSecret() (*v1.Secret, error) {
+	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.GROUPSSECRET, nil)
+	if secret == nil {
+		provider, err := kubernetes.NewKubeProvider(nil)
+		if err != nil {
+			return nil, err
+		}
+		newSecret := utils.InitSecret()
+		newSecret.ObjectMeta.Name = utils.GROUPSSECRET
+		newSecret.ObjectMeta.Namespace = utils.CONFIG.Kubernetes.OwnNamespace
+		newSecret.StringData = map[string]string{}
+		secret, err = provider.ClientSet.CoreV1().Secrets(utils.CONFIG.Kubernetes.OwnNamespace).Create(context.TODO(), &newSecret, kubernetes.MoCreateOptions())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	return secret, nil
+}
+
+func saveGroup(secret *v1.Secret, group dtos.PunqGroup) error {
+	rawGroup, err := json.Marshal(group)
+	if err != nil {
+		return err
+	}
+	secret.Data[group.Id] = rawGroup
+	return updateGroupsSecret(secret)
+}
+
+func updateGroupsSecret(secret *v1.Secret) error {
+	workloadResult := kubernetes.UpdateK8sSecret(*secret, nil)
+	if workloadResult.Error != nil {
+		return fmt.Errorf("%v", workloadResult.Error)
+	}
+	return nil
+}
+
+func groupFrom(secret *v1.Secret, id string) (*dtos.PunqGroup, error) {
+	rawGroup, ok := secret.Data[id]
+	if !ok {
+		return nil, fmt.Errorf("group '%s' not found", id)
+	}
+	group := dtos.PunqGroup{}
+	err := json.Unmarshal(rawGroup, &group)
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func allGroups(secret *v1.Secret) []dtos.PunqGroup {
+	result := []dtos.PunqGroup{}
+	for key := range secret.Data {
+		group, err := groupFrom(secret, key)
+		if err != nil {
+			logger.Log.Errorf("Failed to unmarshal group '%s': %s", key, err.Error())
+			continue
+		}
+		result = append(result, *group)
+	}
+	return result
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mogenius/punq/dtos"
+	v1 "k8s.io/api/core/v1"
+)
+
+func newTestGroupsSecret(t *testing.T, groups ...dtos.PunqGroup) *v1.Secret {
+	secret := &v1.Secret{Data: map[string][]byte{}}
+	for _, group := range groups {
+		rawGroup, err := json.Marshal(group)
+		if err != nil {
+			t.Fatal(err)
+		}
+		secret.Data[group.Id] = rawGroup
+	}
+	return secret
+}
+
+func TestValidateGroupName(t *testing.T) {
+	secret := newTestGroupsSecret(t, dtos.PunqGroup{Id: "g1", Name: "Developers"})
+
+	tests := []struct {
+		name    string
+		group   dtos.PunqGroup
+		wantErr bool
+	}{
+		{"new name", dtos.PunqGroup{Id: "g2", Name: "Operators"}, false},
+		{"missing name", dtos.PunqGroup{Id: "g2"}, true},
+		{"name of another group", dtos.PunqGroup{Id: "g2", Name: "developers"}, true},
+		{"own name", dtos.PunqGroup{Id: "g1", Name: "Developers"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateGroup(secret, &tt.group)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateGroup() error = %v, wantErr %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAllGroups(t *testing.T) {
+	secret := newTestGroupsSecret(t,
+		dtos.PunqGroup{Id: "g1", Name: "Developers", Members: []string{"u1", "u2"}},
+		dtos.PunqGroup{Id: "g2", Name: "Operators", Members: []string{"u2"}},
+	)
+	secret.Data["broken"] = []byte("{")
+
+	groups := allGroups(secret)
+	if len(groups) != 2 {
+		t.Fatalf("allGroups() returned %d groups, want 2 (broken entries are skipped)", len(groups))
+	}
+	group, err := groupFrom(secret, "g1")
+	if err != nil || !group.HasMember("u1") || group.HasMember("u3") {
+		t.Errorf("groupFrom() = %v, %v, want g1 with member u1", group, err)
+	}
+	if _, err := groupFrom(secret, "unknown"); err == nil {
+		t.Errorf("groupFrom() of an unknown group error = nil, want an error")
+	}
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/logger"
+	"github.com/mogenius/punq/utils"
+)
+
+const (
+	// purpose claims of the one-time links, which are no access tokens (they have no session)
+	invitePurpose        = "invite"
+	passwordResetPurpose = "password-reset"
+
+	// a user gets at most one password reset mail in this time
+	PASSWORD_RESET_COOLDOWN = time.Minute
+)
+
+var ErrInvalidPasswordToken = errors.New("invalid or expired link. Please request a new one")
+
+// passwordTokenClaims are one-time because PasswordFingerprint changes as soon as the password is set
+type passwordTokenClaims struct {
+	UserID              string `json:"userId"`
+	Purpose             string `json:"purpose"`
+	PasswordFingerprint string `json:"pwf"`
+	jwt.RegisteredClaims
+}
+
+var passwordResetRequests = map[string]time.Time{}
+var passwordResetRequestsMutex sync.Mutex
+
+// InviteUser creates a local user with an unusable password and sends the link to set it. If no SMTP relay is
+// configured or the mail fails, the link is part of the result, so the admin can hand it over.
+func InviteUser(input dtos.PunqInvitationInput) (*dtos.PunqInvitation, error) {
+	input.Email = strings.TrimSpace(input.Email)
+	if input.Email == "" {
+		return nil, errors.New("email is required")
+	}
+	if input.DisplayName == "" {
+		input.DisplayName = input.Email
+	}
+
+	user, err := createUser(dtos.PunqUserCreateInput{
+		Email:       input.Email,
+		Password:    utils.NanoId() + utils.NanoId(),
+		DisplayName: input.DisplayName,
+		AccessLevel: input.AccessLevel,
+		Provider:    dtos.USER_PROVIDER_LOCAL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	link, expiresAt, err := passwordLink(user, invitePurpose, utils.CONFIG.Invite.InviteTimeout)
+	if err != nil {
+		return nil, err
+	}
+	result := dtos.PunqInvitation{
+		UserId:    user.Id,
+		Email:     user.Email,
+		ExpiresAt: expiresAt,
+	}
+	body := fmt.Sprintf("Hello %s,\n\nyou have been invited to punq. Please choose your password here:\n\n%s\n\nThe link is valid until %s.\n", user.DisplayName, link, expiresAt.Format(time.RFC1123))
+	result.Sent = sendPasswordMail(user.Email, "You have been invited to punq", body)
+	if !result.Sent {
+		logger.Log.Noticef("Invitation link for '%s': %s", user.Email, link)
+		result.Link = link
+	}
+	return &result, nil
+}
+
+// RequestPasswordReset sends a password reset link to local users. Unknown emails are not reported to the caller,
+// so accounts cannot be enumerated.
+func RequestPasswordReset(email string) error {
+	user, err := GetUserByEmail(strings.TrimSpace(email))
+	if err != nil || user.ProviderName() != dtos.USER_PROVIDER_LOCAL || user.Disabled {
+		logger.Log.Noticef("Password reset requested for unknown, external or deactivated user '%s'.", email)
+		return nil
+	}
+
+	passwordResetRequestsMutex.Lock()
+	lastRequest, ok := passwordResetRequests[user.Id]
+	if ok && time.Since(lastRequest) < PASSWORD_RESET_COOLDOWN {
+		passwordResetRequestsMutex.Unlock()
+		return nil
+	}
+	passwordResetRequests[user.Id] = time.Now()
+	passwordResetRequestsMutex.Unlock()
+
+	// the mail is sent in the background, so existing users cannot be told apart by the response time
+	go func() {
+		link, expiresAt, err := passwordLink(user, passwordResetPurpose, utils.CONFIG.Invite.ResetTimeout)
+		if err != nil {
+			logger.Log.Errorf("Failed to create password reset link for '%s': %s", user.Email, err.Error())
+			return
+		}
+		body := fmt.Sprintf("Hello %s,\n\na password reset has been requested for your punq account. Please choose a new password here:\n\n%s\n\nThe link is valid until %s. If you did not request it, you can ignore this mail.\n", user.DisplayName, link, expiresAt.Format(time.RFC1123))
+		if !sendPasswordMail(user.Email, "Reset your punq password", body) {
+			logger.Log.Noticef("Password reset link for '%s': %s", user.Email, link)
+		}
+	}()
+	return nil
+}
+
+// ResetPassword sets the password with the token of an invitation or password reset link. Sessions of the user end
+// and a locked login is unlocked.
+func ResetPassword(token string, password string) (*dtos.PunqUser, error) {
+	if password == "" {
+		return nil, errors.New("password is required")
+	}
+	claims, err := parsePasswordToken(token)
+	if err != nil {
+		return nil, err
+	}
+	user, err := GetUser(claims.UserID)
+	if err != nil || !claims.validFor(user) {
+		return nil, ErrInvalidPasswordToken
+	}
+
+	update := *user
+	update.Password = password
+	user, err = UpdateUser(update)
+	if err != nil {
+		return nil, err
+	}
+
+	err = RevokeUserSessions(user.Id)
+	if err != nil {
+		logger.Log.Errorf("Failed to revoke sessions of user '%s': %s", user.Id, err.Error())
+	}
+	err = UnlockLogin(dtos.LOGIN_ATTEMPT_ACCOUNT, user.Email)
+	if err != nil {
+		logger.Log.Errorf("Failed to unlock login of user '%s': %s", user.Id, err.Error())
+	}
+	passwordResetRequestsMutex.Lock()
+	delete(passwordResetRequests, user.Id)
+	passwordResetRequestsMutex.Unlock()
+
+	result := user.Redacted()
+	return &result, nil
+}
+
+// parsePasswordToken returns the claims of a valid invitation or password reset token (access tokens are rejected)
+func parsePasswordToken(token string) (*passwordTokenClaims, error) {
+	claims := passwordTokenClaims{}
+	_, err := jwt.ParseWithClaims(token, &claims, jwtKeyFunc)
+	if err != nil || (claims.Purpose != invitePurpose && claims.Purpose != passwordResetPurpose) {
+		return nil, ErrInvalidPasswordToken
+	}
+	return &claims, nil
+}
+
+// validFor is false once the password of the user has changed since the token was issued
+func (c *passwordTokenClaims) validFor(user *dtos.PunqUser) bool {
+	return user.Id == c.UserID && user.ProviderName() == dtos.USER_PROVIDER_LOCAL && !user.Disabled && passwordFingerprint(user) == c.PasswordFingerprint
+}
+
+func passwordLink(user *dtos.PunqUser, purpose string, timeout time.Duration) (string, time.Time, error) {
+	keyPair, err := GetKeyPair()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expiresAt := time.Now().Add(timeout)
+	claims := passwordTokenClaims{
+		UserID:              user.Id,
+		Purpose:             purpose,
+		PasswordFingerprint: passwordFingerprint(user),
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodES512, claims)
+	token.Header["kid"] = keyPair.Id
+	signed, err := token.SignedString(keyPair.PrivateKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	linkUrl := utils.CONFIG.Invite.LinkUrl
+	if linkUrl == "" {
+		linkUrl = fmt.Sprintf("http://%s:%d/set-password", utils.CONFIG.Frontend.Host, utils.CONFIG.Frontend.Port)
+	}
+	separator := "?"
+	if strings.Contains(linkUrl, "?") {
+		separator = "&"
+	}
+	return fmt.Sprintf("%s%stoken=%s", linkUrl, separator, url.QueryEscape(signed)), expiresAt, nil
+}
+
+// passwordFingerprint identifies the current password hash without revealing it
+func passwordFingerprint(user *dtos.PunqUser) string {
+	return hashToken(user.Password)[:16]
+}
+
+// sendPasswordMail returns false if the mail has not been sent
+func sendPasswordMail(to string, subject string, body string) bool {
+	if !utils.MailConfigured() {
+		return false
+	}
+	err := utils.SendMail(to, subject, body)
+	if err != nil {
+		logger.Log.Errorf("Failed to send mail to '%s': %s", to, err.Error())
+		return false
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/utils"
+)
+
+func passwordTokenOf(t *testing.T, link string) string {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed.Query().Get("token")
+}
+
+func TestPasswordLink(t *testing.T) {
+	setTestJwtKeys(t, newTestKeyPair(t))
+	previous := utils.CONFIG.Invite.LinkUrl
+	t.Cleanup(func() { utils.CONFIG.Invite.LinkUrl = previous })
+	utils.CONFIG.Invite.LinkUrl = "https://punq.example.com/set-password?lang=en"
+	user := &dtos.PunqUser{Id: "u1", Email: "jane@example.com", Password: "hash"}
+
+	link, expiresAt, err := passwordLink(user, invitePurpose, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(link, "https://punq.example.com/set-password?lang=en&token=") {
+		t.Errorf("passwordLink() = %s, want the token appended to the configured url", link)
+	}
+	if time.Until(expiresAt) > time.Hour || time.Until(expiresAt) < time.Hour-time.Minute {
+		t.Errorf("passwordLink() expires at %s, want in one hour", expiresAt)
+	}
+
+	claims, err := parsePasswordToken(passwordTokenOf(t, link))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != user.Id || claims.Purpose != invitePurpose {
+		t.Errorf("parsePasswordToken() = %+v, want the invitation of u1", claims)
+	}
+}
+
+func TestParsePasswordTokenRejects(t *testing.T) {
+	setTestJwtKeys(t, newTestKeyPair(t))
+	user := &dtos.PunqUser{Id: "u1", Email: "jane@example.com", Password: "hash", AccessLevel: dtos.ADMIN}
+
+	expiredLink, _, err := passwordLink(user, passwordResetPurpose, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPurposeLink, _, err := passwordLink(user, "other", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	accessToken, err := GenerateToken(user, "session-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"expired", passwordTokenOf(t, expiredLink)},
+		{"other purpose", passwordTokenOf(t, otherPurposeLink)},
+		{"access token", accessToken.Token},
+		{"garbage", "abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parsePasswordToken(tt.token); err != ErrInvalidPasswordToken {
+				t.Errorf("parsePasswordToken() error = %v, want %v", err, ErrInvalidPasswordToken)
+			}
+		})
+	}
+
+	// password tokens have no session and are no access tokens
+	resetLink, _, err := passwordLink(user, passwordResetPurpose, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ValidationToken(passwordTokenOf(t, resetLink)); err == nil {
+		t.Errorf("ValidationToken() of a password reset token error = nil, want an error")
+	}
+}
+
+func TestPasswordTokenValidFor(t *testing.T) {
+	user := dtos.PunqUser{Id: "u1", Email: "jane@example.com", Password: "hash"}
+	claims := passwordTokenClaims{UserID: user.Id, Purpose: passwordResetPurpose, PasswordFingerprint: passwordFingerprint(&user)}
+
+	withPassword := user
+	withPassword.Password = "new hash"
+	disabled := user
+	disabled.Disabled = true
+	external := user
+	external.Provider = dtos.USER_PROVIDER_OIDC
+	other := user
+	other.Id = "u2"
+
+	tests := []struct {
+		name string
+		user dtos.PunqUser
+		want bool
+	}{
+		{"unchanged password", user, true},
+		{"password set already", withPassword, false},
+		{"disabled", disabled, false},
+		{"external user", external, false},
+		{"other user", other, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := claims.validFor(&tt.user); got != tt.want {
+				t.Errorf("validFor() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/kubernetes"
+	"github.com/mogenius/punq/logger"
+	"github.com/mogenius/punq/utils"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	SecKeyPairs = "keyPairs"
+
+	// retired keys still verify tokens for this time by default
+	JWT_KEY_GRACE_PERIOD = 24 * time.Hour
+	// rotations by other replicas or the cli are picked up within this time
+	JWT_KEY_SYNC_INTERVAL = 30 * time.Second
+	// unknown kids trigger a reload at most this often
+	JWT_KEY_RELOAD_MIN_INTERVAL = 5 * time.Second
+)
+
+var jwtKeys []KeyPair
+var jwtKeysSyncedAt time.Time
+var jwtKeysMutex sync.Mutex
+
+// jwtKeyRing returns the cached keys which are valid for verification. The cache is reloaded from the punq-jwt
+// secret after JWT_KEY_SYNC_INTERVAL or if forced (e.g. for an unknown kid).
+func jwtKeyRing(force bool) ([]KeyPair, error) {
+	jwtKeysMutex.Lock()
+	defer jwtKeysMutex.Unlock()
+
+	age := time.Since(jwtKeysSyncedAt)
+	if jwtKeys == nil || age > JWT_KEY_SYNC_INTERVAL || (force && age > JWT_KEY_RELOAD_MIN_INTERVAL) {
+		keyPairs, err := loadJwtKeys()
+		if err != nil {
+			if jwtKeys != nil {
+				// keep working with the known keys if the cluster is not reachable
+				logger.Log.Errorf("Failed to reload jwt keys: %s", err.Error())
+				return jwtKeys, nil
+			}
+			return nil, err
+		}
+		jwtKeys = keyPairs
+		jwtKeysSyncedAt = time.Now()
+	}
+	return jwtKeys, nil
+}
+
+func loadJwtKeys() ([]KeyPair, error) {
+	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.JWTSECRET, nil)
+	if secret == nil {
+		logger.Log.Warningf("Failed to get '%s/%s' secret.", utils.CONFIG.Kubernetes.OwnNamespace, utils.JWTSECRET)
+		keyPair, err := CreateKeyPair()
+		if err != nil {
+			return nil, err
+		}
+		return []KeyPair{*keyPair}, nil
+	}
+
+	keyPairs, err := jwtKeysFrom(secret)
+	if err != nil {
+		return nil, err
+	}
+	if len(keyPairs) == 0 {
+		// e.g. all keys have been pruned by hand
+		return rotateJwtKeys(JWT_KEY_GRACE_PERIOD)
+	}
+	return keyPairs, nil
+}
+
+// jwtKeysFrom returns the keys of the secret which have not passed their grace period, newest first
+func jwtKeysFrom(secret *v1.Secret) ([]KeyPair, error) {
+	all, err := allJwtKeysFrom(secret)
+	if err != nil {
+		return nil, err
+	}
+	result := []KeyPair{}
+	for _, keyPair := range all {
+		if keyPair.VerifyUntil.IsZero() || time.Now().Before(keyPair.VerifyUntil) {
+			result = append(result, keyPair)
+		}
+	}
+	return result, nil
+}
+
+func allJwtKeysFrom(secret *v1.Secret) ([]KeyPair, error) {
+	keyPairs := []KeyPair{}
+	if rawKeyPairs, ok := secret.Data[SecKeyPairs]; ok {
+		err := json.Unmarshal(rawKeyPairs, &keyPairs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal '%s': %s", SecKeyPairs, err.Error())
+		}
+	} else if rawKeyPair, ok := secret.Data[SecKeyPair]; ok {
+		keyPair := KeyPair{}
+		err := json.Unmarshal(rawKeyPair, &keyPair)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal '%s': %s", SecKeyPair, err.Error())
+		}
+		keyPairs = append(keyPairs, keyPair)
+	}
+	sort.SliceStable(keyPairs, func(i, j int) bool {
+		return keyPairs[i].Created.After(keyPairs[j].Created)
+	})
+	return keyPairs, nil
+}
+
+// activeJwtKey returns the newest key which has not been retired
+func activeJwtKey(keyPairs []KeyPair) (*KeyPair, error) {
+	for _, keyPair := range keyPairs {
+		if keyPair.RetiredAt.IsZero() && keyPair.PrivateKey != nil {
+			return &keyPair, nil
+		}
+	}
+	return nil, errors.New("no active jwt signing key found")
+}
+
+// jwtKeyFor returns the key with the kid (the active key if kid is empty)
+func jwtKeyFor(kid string) (*KeyPair, error) {
+	for _, force := range []bool{false, true} {
+		keyPairs, err := jwtKeyRing(force)
+		if err != nil {
+			return nil, err
+		}
+		if kid == "" {
+			return activeJwtKey(keyPairs)
+		}
+		for _, keyPair := range keyPairs {
+			if keyPair.Id == kid {
+				return &keyPair, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("unknown jwt key '%s'", kid)
+}
+
+// RotateJwtKey generates a new active signing key. Previous keys are retired and verify tokens for the grace
+// period, keys whose grace period has passed are pruned. Returns the remaining keys, newest first.
+func RotateJwtKey(gracePeriod time.Duration) ([]KeyPair, error) {
+	keyPairs, err := rotateJwtKeys(gracePeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	// this process uses the new key immediately, other replicas after the next sync
+	jwtKeysMutex.Lock()
+	jwtKeys = nil
+	jwtKeysMutex.Unlock()
+	return keyPairs, nil
+}
+
+func rotateJwtKeys(gracePeriod time.Duration) ([]KeyPair, error) {
+	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.JWTSECRET, nil)
+	if secret == nil {
+		return nil, fmt.Errorf("failed to get '%s/%s' secret", utils.CONFIG.Kubernetes.OwnNamespace, utils.JWTSECRET)
+	}
+	keyPairs, err := allJwtKeysFrom(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	newKeyPair, err := generateAuthKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	result := rotatedJwtKeys(keyPairs, *newKeyPair, gracePeriod, time.Now())
+
+	rawKeyPairs, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[SecKeyPairs] = rawKeyPairs
+	delete(secret.Data, SecKeyPair)
+	workloadResult := kubernetes.UpdateK8sSecret(*secret, nil)
+	if workloadResult.Error != nil {
+		return nil, fmt.Errorf("%v", workloadResult.Error)
+	}
+	return result, nil
+}
+
+// rotatedJwtKeys puts the new key in front of the previous keys, retires the active ones and prunes the keys whose
+// grace period has passed
+func rotatedJwtKeys(keyPairs []KeyPair, newKeyPair KeyPair, gracePeriod time.Duration, now time.Time) []KeyPair {
+	result := []KeyPair{newKeyPair}
+	for _, keyPair := range keyPairs {
+		if keyPair.RetiredAt.IsZero() {
+			keyPair.RetiredAt = now
+			keyPair.VerifyUntil = now.Add(gracePeriod)
+		}
+		if !keyPair.VerifyUntil.IsZero() && now.After(keyPair.VerifyUntil) {
+			logger.Log.Infof("Pruning jwt key '%s' (retired at %s).", keyPair.Id, keyPair.RetiredAt.Format(time.RFC3339))
+			continue
+		}
+		result = append(result, keyPair)
+	}
+	return result
+}
+
+// JwtKeySet returns the public keys of all keys valid for verification as JWKS
+func JwtKeySet() (*dtos.PunqJwks, error) {
+	keyPairs, err := jwtKeyRing(false)
+	if err != nil {
+		return nil, err
+	}
+
+	result := dtos.PunqJwks{Keys: []dtos.PunqJwk{}}
+	for _, keyPair := range keyPairs {
+		publicKey, ok := keyPair.PublicKey.(*ecdsa.PublicKey)
+		if !ok {
+			continue
+		}
+		size := (publicKey.Curve.Params().BitSize + 7) / 8
+		result.Keys = append(result.Keys, dtos.PunqJwk{
+			Kty: "EC",
+			Crv: publicKey.Curve.Params().Name,
+			X:   base64.RawURLEncoding.EncodeToString(publicKey.X.FillBytes(make([]byte, size))),
+			Y:   base64.RawURLEncoding.EncodeToString(publicKey.Y.FillBytes(make([]byte, size))),
+			Kid: keyPair.Id,
+			Alg: "ES512",
+			Use: "sig",
+		})
+	}
+	return &result, nil
+}
+
+// jwtKeyId is derived from the public key, so keys of older versions get a stable kid as well
+func jwtKeyId(publicKey *ecdsa.PublicKey) string {
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(publicKeyBytes)
+	return hex.EncodeToString(sum[:8])
+}
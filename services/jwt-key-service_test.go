@@ -0,0 +1,119 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mogenius/punq/dtos"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestRotatedJwtKeys(t *testing.T) {
+	now := time.Now()
+	active := KeyPair{Id: "active", Created: now.Add(-time.Hour)}
+	retired := KeyPair{Id: "retired", Created: now.Add(-2 * time.Hour), RetiredAt: now.Add(-time.Hour), VerifyUntil: now.Add(time.Hour)}
+	expired := KeyPair{Id: "expired", Created: now.Add(-3 * time.Hour), RetiredAt: now.Add(-2 * time.Hour), VerifyUntil: now.Add(-time.Second)}
+
+	result := rotatedJwtKeys([]KeyPair{active, retired, expired}, KeyPair{Id: "new", Created: now}, JWT_KEY_GRACE_PERIOD, now)
+
+	ids := []string{}
+	for _, keyPair := range result {
+		ids = append(ids, keyPair.Id)
+	}
+	if len(ids) != 3 || ids[0] != "new" || ids[1] != "active" || ids[2] != "retired" {
+		t.Fatalf("rotatedJwtKeys() ids = %v, want [new active retired]", ids)
+	}
+	if !result[0].RetiredAt.IsZero() {
+		t.Errorf("rotatedJwtKeys() retired the new key")
+	}
+	if !result[1].RetiredAt.Equal(now) || !result[1].VerifyUntil.Equal(now.Add(JWT_KEY_GRACE_PERIOD)) {
+		t.Errorf("rotatedJwtKeys() previous key = %v - %v, want retired now with the grace period", result[1].RetiredAt, result[1].VerifyUntil)
+	}
+	if !result[2].VerifyUntil.Equal(retired.VerifyUntil) {
+		t.Errorf("rotatedJwtKeys() changed the grace period of an already retired key")
+	}
+}
+
+func TestJwtKeysFrom(t *testing.T) {
+	now := time.Now()
+	newer := newTestKeyPair(t)
+	newer.Created = now
+	retired := newTestKeyPair(t)
+	retired.Created = now.Add(-time.Hour)
+	retired.RetiredAt = now.Add(-time.Minute)
+	retired.VerifyUntil = now.Add(time.Hour)
+	expired := newTestKeyPair(t)
+	expired.Created = now.Add(-2 * time.Hour)
+	expired.RetiredAt = now.Add(-time.Hour)
+	expired.VerifyUntil = now.Add(-time.Second)
+
+	rawKeyPairs, err := json.Marshal([]KeyPair{expired, newer, retired})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPairs, err := jwtKeysFrom(&v1.Secret{Data: map[string][]byte{SecKeyPairs: rawKeyPairs}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keyPairs) != 2 || keyPairs[0].Id != newer.Id || keyPairs[1].Id != retired.Id {
+		t.Fatalf("jwtKeysFrom() returned %d keys, want the active and the retired key, newest first", len(keyPairs))
+	}
+	if keyPairs[0].PrivateKey == nil || keyPairs[1].PublicKey == nil {
+		t.Errorf("jwtKeysFrom() did not parse the keys")
+	}
+
+	active, err := activeJwtKey(keyPairs)
+	if err != nil || active.Id != newer.Id {
+		t.Errorf("activeJwtKey() = %v, %v, want the newest key", active, err)
+	}
+}
+
+func TestValidationTokenAfterRotation(t *testing.T) {
+	now := time.Now()
+	previous := newTestKeyPair(t)
+	previous.Created = now.Add(-time.Hour)
+	setTestJwtKeys(t, previous)
+	user := &dtos.PunqUser{Id: "user-1", AccessLevel: dtos.ADMIN}
+
+	token, err := GenerateToken(user, "session-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next := newTestKeyPair(t)
+	next.Created = now
+	rotated := rotatedJwtKeys([]KeyPair{previous}, next, JWT_KEY_GRACE_PERIOD, now)
+	setTestJwtKeys(t, rotated...)
+
+	if _, err := ValidationToken(token.Token); err != nil {
+		t.Errorf("ValidationToken() with the retired key in its grace period error = %v, want nil", err)
+	}
+	newToken, err := GenerateToken(user, "session-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ValidationToken(newToken.Token); err != nil {
+		t.Errorf("ValidationToken() with the new key error = %v, want nil", err)
+	}
+
+	jwks, err := JwtKeySet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kids := map[string]bool{}
+	for _, key := range jwks.Keys {
+		kids[key.Kid] = true
+	}
+	if len(kids) != 2 || !kids[previous.Id] || !kids[next.Id] {
+		t.Errorf("JwtKeySet() kids = %v, want %s and %s", kids, previous.Id, next.Id)
+	}
+
+	// the grace period has passed
+	pruned := rotatedJwtKeys(rotated, newTestKeyPair(t), JWT_KEY_GRACE_PERIOD, now.Add(JWT_KEY_GRACE_PERIOD+time.Second))
+	setTestJwtKeys(t, pruned...)
+
+	if _, err := ValidationToken(token.Token); err == nil {
+		t.Errorf("ValidationToken() with a pruned key error = nil, want an error")
+	}
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/kubernetes"
+	"github.com/mogenius/punq/logger"
+	"github.com/mogenius/punq/utils"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// LoginThrottledError is returned for accounts and IP addresses which are locked or have to wait after a failed login
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("too many failed login attempts. Please try again in %s", e.RetryAfter.Round(time.Second))
+}
+
+var loginAttemptsMutex sync.Mutex
+
+// logins with an attempt in progress (by loginInProgressKey)
+var loginsInProgress = map[string]bool{}
+
+// CheckLogin returns a LoginThrottledError if the account or the IP address has to wait. Accounts are tracked by the
+// login as entered, so unknown users are throttled exactly like existing ones. Otherwise the attempt is reserved
+// until FinishLogin: parallel attempts for the same account from the same IP address are throttled, so they cannot
+// all pass the check before the first failure is recorded. Other users behind the same IP address (e.g. a NAT) are
+// not blocked by it.
+func CheckLogin(login string, clientIp string) error {
+	loginAttemptsMutex.Lock()
+	defer loginAttemptsMutex.Unlock()
+
+	inProgressKey := loginInProgressKey(login, clientIp)
+	if loginsInProgress[inProgressKey] {
+		return &LoginThrottledError{RetryAfter: time.Second}
+	}
+
+	keys := []string{loginAttemptKey(dtos.LOGIN_ATTEMPT_ACCOUNT, login), loginAttemptKey(dtos.LOGIN_ATTEMPT_IP, clientIp)}
+
+	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.LOGINATTEMPTSSECRET, nil)
+	if secret != nil {
+		retryAfter := time.Duration(0)
+		for _, key := range keys {
+			attempt := loginAttemptFrom(secret, key)
+			if attempt == nil {
+				continue
+			}
+			if wait := loginRetryAfter(attempt); wait > retryAfter {
+				retryAfter = wait
+			}
+		}
+		if retryAfter > 0 {
+			return &LoginThrottledError{RetryAfter: retryAfter}
+		}
+	}
+
+	loginsInProgress[inProgressKey] = true
+	return nil
+}
+
+// FinishLogin releases the attempt reserved by CheckLogin. It has to be called after the failure or success has
+// been recorded.
+func FinishLogin(login string, clientIp string) {
+	loginAttemptsMutex.Lock()
+	defer loginAttemptsMutex.Unlock()
+
+	delete(loginsInProgress, loginInProgressKey(login, clientIp))
+}
+
+// RecordFailedLogin counts the failure for the account and the IP address and locks them if their limit is reached.
+// Failures recorded by other replicas in the meantime are merged by retrying on conflicts.
+func RecordFailedLogin(login string, clientIp string) {
+	loginAttemptsMutex.Lock()
+	defer loginAttemptsMutex.Unlock()
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := loginAttemptsSecret()
+		if err != nil {
+			return err
+		}
+		countFailedLogin(secret, dtos.LOGIN_ATTEMPT_ACCOUNT, normalizeLogin(login), utils.CONFIG.Login.MaxFailures)
+		countFailedLogin(secret, dtos.LOGIN_ATTEMPT_IP, clientIp, utils.CONFIG.Login.IpMaxFailures)
+		return updateLoginAttemptsSecret(secret)
+	})
+	if err != nil {
+		logger.Log.Errorf("Failed to record failed login: %s", err.Error())
+	}
+}
+
+// countFailedLogin adds a failure to the attempt of the subject in the secret and locks it if maxFailures is reached
+func countFailedLogin(secret *v1.Secret, kind string, subject string, maxFailures int) {
+	key := loginAttemptKey(kind, subject)
+	attempt := loginAttemptFrom(secret, key)
+	if attempt == nil || isStaleLoginAttempt(attempt) {
+		attempt = &dtos.PunqLoginAttempt{Kind: kind, Subject: subject}
+	}
+	attempt.Failures++
+	attempt.LastFailure = time.Now()
+	if maxFailures > 0 && attempt.Failures >= maxFailures {
+		logger.Log.Warningf("Locking %s '%s' for %s after %d failed logins.", kind, subject, utils.CONFIG.Login.LockoutDuration, attempt.Failures)
+		attempt.LockedUntil = time.Now().Add(utils.CONFIG.Login.LockoutDuration)
+		// the backoff starts again after the lockout
+		attempt.Failures = 0
+	}
+	rawAttempt, err := json.Marshal(attempt)
+	if err != nil {
+		logger.Log.Errorf("Failed to marshal login attempt: %s", err.Error())
+		return
+	}
+	secret.Data[key] = rawAttempt
+}
+
+// RecordSuccessfulLogin forgets the failures of the account. Failures of the IP address are kept, otherwise an
+// attacker with one valid account could reset them.
+func RecordSuccessfulLogin(login string) {
+	err := UnlockLogin(dtos.LOGIN_ATTEMPT_ACCOUNT, login)
+	if err != nil {
+		logger.Log.Errorf("Failed to reset failed logins: %s", err.Error())
+	}
+}
+
+// ListLoginAttempts returns the accounts and IP addresses with recent failures, locked ones first
+func ListLoginAttempts() ([]dtos.PunqLoginAttempt, error) {
+	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.LOGINATTEMPTSSECRET, nil)
+	if secret == nil {
+		return []dtos.PunqLoginAttempt{}, nil
+	}
+
+	result := []dtos.PunqLoginAttempt{}
+	for key := range secret.Data {
+		attempt := loginAttemptFrom(secret, key)
+		if attempt == nil || isStaleLoginAttempt(attempt) {
+			continue
+		}
+		result = append(result, *attempt)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].IsLocked() != result[j].IsLocked() {
+			return result[i].IsLocked()
+		}
+		return result[i].LastFailure.After(result[j].LastFailure)
+	})
+	return result, nil
+}
+
+// UnlockLogin removes the failures and the lock of an account (login) or IP address
+func UnlockLogin(kind string, subject string) error {
+	if kind != dtos.LOGIN_ATTEMPT_ACCOUNT && kind != dtos.LOGIN_ATTEMPT_IP {
+		return fmt.Errorf("unknown kind '%s' (%s, %s)", kind, dtos.LOGIN_ATTEMPT_ACCOUNT, dtos.LOGIN_ATTEMPT_IP)
+	}
+
+	loginAttemptsMutex.Lock()
+	defer loginAttemptsMutex.Unlock()
+
+	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.LOGINATTEMPTSSECRET, nil)
+	if secret == nil {
+		return nil
+	}
+	key := loginAttemptKey(kind, subject)
+	if _, ok := secret.Data[key]; !ok {
+		return nil
+	}
+	delete(secret.Data, key)
+	return updateLoginAttemptsSecret(secret)
+}
+
+// loginRetryAfter returns how long the subject has to wait: until the end of its lockout or base * 2^(failures-1)
+// after its last failure
+func loginRetryAfter(attempt *dtos.PunqLoginAttempt) time.Duration {
+	if attempt.IsLocked() {
+		return time.Until(attempt.LockedUntil)
+	}
+	if attempt.Failures == 0 || isStaleLoginAttempt(attempt) {
+		return 0
+	}
+	backoff := time.Duration(float64(utils.CONFIG.Login.BackoffBase) * math.Pow(2, float64(attempt.Failures-1)))
+	if backoff > utils.CONFIG.Login.BackoffMax {
+		backoff = utils.CONFIG.Login.BackoffMax
+	}
+	if wait := time.Until(attempt.LastFailure.Add(backoff)); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// failures are forgotten if there was none within the lockout duration
+func isStaleLoginAttempt(attempt *dtos.PunqLoginAttempt) bool {
+	return !attempt.IsLocked() && time.Now().After(attempt.LastFailure.Add(utils.CONFIG.Login.LockoutDuration))
+}
+
+// loginAttemptKey hashes the subject because logins and IPv6 addresses may contain characters which are not
+// allowed in secret keys
+func loginAttemptKey(kind string, subject string) string {
+	if kind == dtos.LOGIN_ATTEMPT_ACCOUNT {
+		subject = normalizeLogin(subject)
+	}
+	return fmt.Sprintf("%s.%s", kind, hashToken(subject)[:32])
+}
+
+// loginInProgressKey identifies an attempt by account and IP address
+func loginInProgressKey(login string, clientIp string) string {
+	return fmt.Sprintf("%s|%s", normalizeLogin(login), clientIp)
+}
+
+func normalizeLogin(login string) string {
+	return strings.ToLower(strings.TrimSpace(login))
+}
+
+func loginAttemptsSecret() (*v1.Secret, error) {
+	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.LOGINATTEMPTSSECRET, nil)
+	if secret == nil {
+		provider, err := kubernetes.NewKubeProvider(nil)
+		if err != nil {
+			return nil, err
+		}
+		newSecret := utils.InitSecret()
+		newSecret.ObjectMeta.Name = utils.LOGINATTEMPTSSECRET
+		newSecret.ObjectMeta.Namespace = utils.CONFIG.Kubernetes.OwnNamespace
+		newSecret.StringData = map[string]string{}
+		secret, err = provider.ClientSet.CoreV1().Secrets(utils.CONFIG.Kubernetes.OwnNamespace).Create(context.TODO(), &newSecret, kubernetes.MoCreateOptions())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	return secret, nil
+}
+
+// updateLoginAttemptsSecret drops stale attempts before writing the secret
+func updateLoginAttemptsSecret(secret *v1.Secret) error {
+	for key := range secret.Data {
+		attempt := loginAttemptFrom(secret, key)
+		if attempt == nil || isStaleLoginAttempt(attempt) {
+			delete(secret.Data, key)
+		}
+	}
+	workloadResult := kubernetes.UpdateK8sSecret(*secret, nil)
+	if err, ok := workloadResult.Error.(error); ok {
+		// keeps conflicts detectable for RecordFailedLogin
+		return err
+	}
+	if workloadResult.Error != nil {
+		return fmt.Errorf("%v", workloadResult.Error)
+	}
+	return nil
+}
+
+func loginAttemptFrom(secret *v1.Secret, key string) *dtos.PunqLoginAttempt {
+	rawAttempt, ok := secret.Data[key]
+	if !ok {
+		return nil
+	}
+	attempt := dtos.PunqLoginAttempt{}
+	err := json.Unmarshal(rawAttempt, &attempt)
+	if err != nil {
+		logger.Log.Errorf("Failed to unmarshal login attempt '%s': %s", key, err.Error())
+		return nil
+	}
+	return &attempt
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/utils"
+	v1 "k8s.io/api/core/v1"
+)
+
+func setTestLoginConfig(t *testing.T) {
+	previous := utils.CONFIG.Login
+	t.Cleanup(func() { utils.CONFIG.Login = previous })
+	utils.CONFIG.Login.MaxFailures = 3
+	utils.CONFIG.Login.IpMaxFailures = 10
+	utils.CONFIG.Login.LockoutDuration = 15 * time.Minute
+	utils.CONFIG.Login.BackoffBase = time.Second
+	utils.CONFIG.Login.BackoffMax = 4 * time.Second
+}
+
+func TestCountFailedLogin(t *testing.T) {
+	setTestLoginConfig(t)
+	secret := &v1.Secret{Data: map[string][]byte{}}
+	key := loginAttemptKey(dtos.LOGIN_ATTEMPT_ACCOUNT, "jane@example.com")
+
+	for failure := 1; failure < utils.CONFIG.Login.MaxFailures; failure++ {
+		countFailedLogin(secret, dtos.LOGIN_ATTEMPT_ACCOUNT, "jane@example.com", utils.CONFIG.Login.MaxFailures)
+		attempt := loginAttemptFrom(secret, key)
+		if attempt == nil || attempt.Failures != failure || attempt.IsLocked() {
+			t.Fatalf("countFailedLogin() after %d failures = %+v, want %d failures and no lock", failure, attempt, failure)
+		}
+	}
+
+	countFailedLogin(secret, dtos.LOGIN_ATTEMPT_ACCOUNT, "jane@example.com", utils.CONFIG.Login.MaxFailures)
+	attempt := loginAttemptFrom(secret, key)
+	if attempt == nil || !attempt.IsLocked() || attempt.Failures != 0 {
+		t.Fatalf("countFailedLogin() after %d failures = %+v, want a lock and the backoff reset", utils.CONFIG.Login.MaxFailures, attempt)
+	}
+	if got := loginRetryAfter(attempt); got <= utils.CONFIG.Login.LockoutDuration-time.Minute {
+		t.Errorf("loginRetryAfter() of a locked account = %s, want about %s", got, utils.CONFIG.Login.LockoutDuration)
+	}
+
+	t.Run("stale failures are forgotten", func(t *testing.T) {
+		stale := &v1.Secret{Data: map[string][]byte{}}
+		countFailedLogin(stale, dtos.LOGIN_ATTEMPT_IP, "10.0.0.1", 0)
+		countFailedLogin(stale, dtos.LOGIN_ATTEMPT_IP, "10.0.0.1", 0)
+		ipKey := loginAttemptKey(dtos.LOGIN_ATTEMPT_IP, "10.0.0.1")
+		old := loginAttemptFrom(stale, ipKey)
+		old.LastFailure = time.Now().Add(-utils.CONFIG.Login.LockoutDuration - time.Second)
+		stale.Data[ipKey], _ = json.Marshal(old)
+
+		countFailedLogin(stale, dtos.LOGIN_ATTEMPT_IP, "10.0.0.1", 0)
+		if got := loginAttemptFrom(stale, ipKey); got == nil || got.Failures != 1 {
+			t.Errorf("countFailedLogin() after stale failures = %+v, want 1 failure", got)
+		}
+	})
+}
+
+func TestLoginRetryAfter(t *testing.T) {
+	setTestLoginConfig(t)
+
+	tests := []struct {
+		name    string
+		attempt dtos.PunqLoginAttempt
+		min     time.Duration
+		max     time.Duration
+	}{
+		{"no failures", dtos.PunqLoginAttempt{LastFailure: time.Now()}, 0, 0},
+		{"first failure", dtos.PunqLoginAttempt{Failures: 1, LastFailure: time.Now()}, 900 * time.Millisecond, time.Second},
+		{"doubled", dtos.PunqLoginAttempt{Failures: 2, LastFailure: time.Now()}, 1900 * time.Millisecond, 2 * time.Second},
+		{"capped", dtos.PunqLoginAttempt{Failures: 10, LastFailure: time.Now()}, 3900 * time.Millisecond, 4 * time.Second},
+		{"backoff over", dtos.PunqLoginAttempt{Failures: 2, LastFailure: time.Now().Add(-time.Minute)}, 0, 0},
+		{"stale", dtos.PunqLoginAttempt{Failures: 10, LastFailure: time.Now().Add(-time.Hour)}, 0, 0},
+		{"locked", dtos.PunqLoginAttempt{LastFailure: time.Now(), LockedUntil: time.Now().Add(time.Minute)}, 59 * time.Second, time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := loginRetryAfter(&tt.attempt); got < tt.min || got > tt.max {
+				t.Errorf("loginRetryAfter() = %s, want between %s and %s", got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+// parallel attempts are only throttled for the same account from the same IP address
+func TestLoginInProgressKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		login    string
+		clientIp string
+		wantSame bool
+	}{
+		{"same account and IP address", "jane@example.com", "10.0.0.1", true},
+		{"same account with other case", " Jane@Example.com", "10.0.0.1", true},
+		{"other account behind the same IP address", "john@example.com", "10.0.0.1", false},
+		{"same account from another IP address", "jane@example.com", "10.0.0.2", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			same := loginInProgressKey(tt.login, tt.clientIp) == loginInProgressKey("jane@example.com", "10.0.0.1")
+			if same != tt.wantSame {
+				t.Errorf("loginInProgressKey(%s, %s) same = %t, want %t", tt.login, tt.clientIp, same, tt.wantSame)
+			}
+		})
+	}
+}
+
+type testAuthProvider struct {
+	user *dtos.PunqUser
+}
+
+func (p *testAuthProvider) Name() string {
+	return "test"
+}
+
+func (p *testAuthProvider) Authenticate(login string, password string) (*dtos.PunqUser, error) {
+	if p.user == nil || password != "secret" {
+		return nil, ErrInvalidCredentials
+	}
+	return p.user, nil
+}
+
+func TestAuthenticateDisabledUser(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     *dtos.PunqUser
+		password string
+		wantErr  error
+	}{
+		{"valid", &dtos.PunqUser{Id: "1"}, "secret", nil},
+		{"wrong password", &dtos.PunqUser{Id: "1"}, "wrong", ErrInvalidCredentials},
+		{"unknown user", nil, "secret", ErrInvalidCredentials},
+		{"disabled user", &dtos.PunqUser{Id: "1", Disabled: true}, "secret", ErrInvalidCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := authenticateWith([]AuthProvider{&testAuthProvider{user: tt.user}}, "jane@example.com", tt.password)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("authenticateWith() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/logger"
+	"github.com/mogenius/punq/utils"
+	"golang.org/x/oauth2"
+)
+
+// a login has to be completed at the provider within this time
+const OIDC_LOGIN_TIMEOUT = 10 * time.Minute
+
+// name of the key in the punq-keys secret which encrypts pending logins
+const OIDC_LOGIN_KEY = "oidc-login"
+
+// a pending login is kept encrypted in a cookie of the browser which started it, so the callback can reach any
+// punq instance and nothing has to be stored until the login is completed
+type oidcLogin struct {
+	State     string    `json:"state"`
+	Verifier  string    `json:"verifier"`
+	Nonce     string    `json:"nonce"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type OidcIdentity struct {
+	Email       string
+	DisplayName string
+	Groups      []string
+}
+
+var oidcProvider *oidc.Provider
+var oidcProviderMutex sync.Mutex
+
+func OidcEnabled() bool {
+	return utils.CONFIG.Oidc.Enabled
+}
+
+// oidcSetup discovers the provider on first use. Failed discoveries are retried with the next login.
+func oidcSetup() (*oidc.Provider, *oauth2.Config, error) {
+	if !OidcEnabled() {
+		return nil, nil, errors.New("OIDC login is not enabled")
+	}
+
+	oidcProviderMutex.Lock()
+	defer oidcProviderMutex.Unlock()
+	if oidcProvider == nil {
+		// the provider keeps using this context to refresh its keys
+		provider, err := oidc.NewProvider(context.Background(), utils.CONFIG.Oidc.IssuerUrl)
+		if err != nil {
+			logger.Log.Errorf("OIDC discovery of '%s' failed: %s", utils.CONFIG.Oidc.IssuerUrl, err.Error())
+			return nil, nil, fmt.Errorf("OIDC provider not available: %s", err.Error())
+		}
+		oidcProvider = provider
+	}
+
+	scopes := utils.CONFIG.Oidc.Scopes
+	if !utils.ContainsEqual(scopes, oidc.ScopeOpenID) {
+		scopes = append([]string{oidc.ScopeOpenID}, scopes...)
+	}
+	config := &oauth2.Config{
+		ClientID:     utils.CONFIG.Oidc.ClientId,
+		ClientSecret: utils.CONFIG.Oidc.ClientSecret,
+		RedirectURL:  utils.CONFIG.Oidc.RedirectUrl,
+		Endpoint:     oidcProvider.Endpoint(),
+		Scopes:       scopes,
+	}
+	return oidcProvider, config, nil
+}
+
+// OidcLoginUrl starts a new login and returns the authorization URL of the provider (authorization code flow with PKCE)
+// and the encrypted login, which the browser has to present again in the callback
+func OidcLoginUrl() (string, string, error) {
+	_, config, err := oidcSetup()
+	if err != nil {
+		return "", "", err
+	}
+	key, err := secretKey(OIDC_LOGIN_KEY)
+	if err != nil {
+		return "", "", err
+	}
+
+	login := oidcLogin{
+		State:     utils.NanoId(),
+		Verifier:  oauth2.GenerateVerifier(),
+		Nonce:     utils.NanoId(),
+		CreatedAt: time.Now(),
+	}
+	sealedLogin, err := sealOidcLogin(key, login)
+	if err != nil {
+		return "", "", err
+	}
+	return config.AuthCodeURL(login.State, oidc.Nonce(login.Nonce), oauth2.S256ChallengeOption(login.Verifier)), sealedLogin, nil
+}
+
+// OidcIdentityFor checks the state of the callback against the encrypted login of the browser, exchanges the code
+// and returns the identity of the verified ID token
+func OidcIdentityFor(ctx context.Context, state string, code string, sealedLogin string) (*OidcIdentity, error) {
+	provider, config, err := oidcSetup()
+	if err != nil {
+		return nil, err
+	}
+	key, err := secretKey(OIDC_LOGIN_KEY)
+	if err != nil {
+		return nil, err
+	}
+
+	login, err := openOidcLogin(key, sealedLogin)
+	if err != nil || time.Since(login.CreatedAt) > OIDC_LOGIN_TIMEOUT {
+		return nil, errors.New("unknown or expired login. Please try again")
+	}
+	if subtle.ConstantTimeCompare([]byte(login.State), []byte(state)) != 1 {
+		return nil, errors.New("the login was started in another browser. Please try again")
+	}
+
+	token, err := config.Exchange(ctx, code, oauth2.VerifierOption(login.Verifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange code: %s", err.Error())
+	}
+	rawIdToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("token response of the provider contains no id_token")
+	}
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: utils.CONFIG.Oidc.ClientId}).Verify(ctx, rawIdToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify id_token: %s", err.Error())
+	}
+	if subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(login.Nonce)) != 1 {
+		return nil, errors.New("id_token nonce does not match")
+	}
+
+	claims := map[string]interface{}{}
+	err = idToken.Claims(&claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse id_token claims: %s", err.Error())
+	}
+
+	identity := OidcIdentity{}
+	identity.Email, _ = claims["email"].(string)
+	if identity.Email == "" {
+		return nil, errors.New("id_token contains no email. Please request the 'email' scope")
+	}
+	// users are matched by email, so an unverified one could be used to take over another account
+	if verified, _ := claims["email_verified"].(bool); !verified && !utils.CONFIG.Oidc.AllowUnverifiedEmail {
+		return nil, fmt.Errorf("email '%s' is not verified by the provider", identity.Email)
+	}
+	identity.DisplayName, _ = claims["name"].(string)
+	if identity.DisplayName == "" {
+		identity.DisplayName = identity.Email
+	}
+
+	switch groups := claims[utils.CONFIG.Oidc.GroupsClaim].(type) {
+	case []interface{}:
+		for _, group := range groups {
+			if name, ok := group.(string); ok {
+				identity.Groups = append(identity.Groups, name)
+			}
+		}
+	case string:
+		identity.Groups = strings.Split(groups, ",")
+	}
+	return &identity, nil
+}
+
+// OidcAccessLevel returns the highest access level of the configured groups the user is a member of
+func OidcAccessLevel(groups []string) (dtos.AccessLevel, error) {
+	return accessLevelForGroups(groups, utils.CONFIG.Oidc.AdminGroups, utils.CONFIG.Oidc.UserGroups, utils.CONFIG.Oidc.ReaderGroups, utils.CONFIG.Oidc.DefaultAccessLevel)
+}
+
+// OidcLogin completes the login and returns a punq token. The user is matched by email (or created if auto
+// provisioning is enabled) and gets the access level of its groups.
+func OidcLogin(ctx context.Context, state string, code string, sealedLogin string, client dtos.PunqSessionClient) (*dtos.PunqToken, error) {
+	identity, err := OidcIdentityFor(ctx, state, code, sealedLogin)
+	if err != nil {
+		return nil, err
+	}
+	accessLevel, err := OidcAccessLevel(identity.Groups)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := provisionExternalUser(dtos.USER_PROVIDER_OIDC, identity.Email, identity.DisplayName, accessLevel, utils.CONFIG.Oidc.AutoProvision)
+	if err != nil {
+		return nil, err
+	}
+	return CreateSession(user, client)
+}
+
+// sealOidcLogin encrypts and authenticates the login with AES-GCM, the result is safe to be used as cookie value
+func sealOidcLogin(key []byte, login oidcLogin) (string, error) {
+	plaintext, err := json.Marshal(login)
+	if err != nil {
+		return "", err
+	}
+	aead, err := oidcLoginCipher(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+// openOidcLogin decrypts a login of sealOidcLogin and rejects modified ones
+func openOidcLogin(key []byte, sealedLogin string) (*oidcLogin, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(sealedLogin)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := oidcLoginCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) < aead.NonceSize() {
+		return nil, errors.New("login is too short")
+	}
+	plaintext, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, err
+	}
+	login := oidcLogin{}
+	err = json.Unmarshal(plaintext, &login)
+	if err != nil {
+		return nil, err
+	}
+	return &login, nil
+}
+
+func oidcLoginCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
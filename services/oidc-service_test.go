@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mogenius/punq/utils"
+)
+
+// testOidcCode is what the provider remembers about an authorization code
+type testOidcCode struct {
+	challenge     string
+	nonce         string
+	emailVerified bool
+}
+
+// newTestOidcProvider serves discovery, keys and the token endpoint of a provider which signs its ID tokens with key.
+// Codes are registered by the test instead of an authorization endpoint.
+func newTestOidcProvider(t *testing.T, key *rsa.PrivateKey, codes map[string]testOidcCode, codesMutex *sync.Mutex) *httptest.Server {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                                server.URL,
+			"authorization_endpoint":                server.URL + "/authorize",
+			"token_endpoint":                        server.URL + "/token",
+			"jwks_uri":                              server.URL + "/keys",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"alg": "RS256",
+				"use": "sig",
+				"kid": "test",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		codesMutex.Lock()
+		code, ok := codes[r.FormValue("code")]
+		delete(codes, r.FormValue("code"))
+		codesMutex.Unlock()
+
+		challenge := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+		if !ok || base64.RawURLEncoding.EncodeToString(challenge[:]) != code.challenge {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":            server.URL,
+			"aud":            utils.CONFIG.Oidc.ClientId,
+			"sub":            "user-1",
+			"iat":            time.Now().Unix(),
+			"exp":            time.Now().Add(time.Minute).Unix(),
+			"nonce":          code.nonce,
+			"email":          "jane@example.com",
+			"email_verified": code.emailVerified,
+			"groups":         []string{"admins"},
+		})
+		idToken.Header["kid"] = "test"
+		rawIdToken, err := idToken.SignedString(key)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"expires_in":   60,
+			"id_token":     rawIdToken,
+		})
+	})
+	return server
+}
+
+func TestOidcIdentityFor(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes := map[string]testOidcCode{}
+	codesMutex := sync.Mutex{}
+	server := newTestOidcProvider(t, key, codes, &codesMutex)
+
+	previousConfig := utils.CONFIG.Oidc
+	t.Cleanup(func() {
+		utils.CONFIG.Oidc = previousConfig
+		oidcProvider = nil
+		delete(secretKeys, OIDC_LOGIN_KEY)
+	})
+	utils.CONFIG.Oidc.Enabled = true
+	utils.CONFIG.Oidc.IssuerUrl = server.URL
+	utils.CONFIG.Oidc.ClientId = "punq"
+	utils.CONFIG.Oidc.RedirectUrl = "https://punq.example.com/backend/auth/oidc/callback"
+	utils.CONFIG.Oidc.GroupsClaim = "groups"
+	utils.CONFIG.Oidc.AllowUnverifiedEmail = false
+	oidcProvider = nil
+	// the key is normally created in the punq-keys secret
+	secretKeys[OIDC_LOGIN_KEY] = []byte(strings.Repeat("k", SECRET_KEY_LENGTH))
+
+	tests := []struct {
+		name           string
+		otherState     bool
+		otherChallenge bool
+		otherNonce     bool
+		unverified     bool
+		tamper         func(sealedLogin string) string
+		wantErr        bool
+	}{
+		{name: "valid login"},
+		{name: "state of another login", otherState: true, wantErr: true},
+		{name: "pkce verifier does not match the challenge", otherChallenge: true, wantErr: true},
+		{name: "nonce of another login", otherNonce: true, wantErr: true},
+		{name: "unverified email", unverified: true, wantErr: true},
+		{name: "no login cookie", tamper: func(string) string { return "" }, wantErr: true},
+		{name: "modified login cookie", tamper: func(sealedLogin string) string {
+			raw, _ := base64.RawURLEncoding.DecodeString(sealedLogin)
+			raw[len(raw)-1] ^= 1
+			return base64.RawURLEncoding.EncodeToString(raw)
+		}, wantErr: true},
+		{name: "login cookie of another key", tamper: func(string) string {
+			sealedLogin, _ := sealOidcLogin([]byte(strings.Repeat("o", SECRET_KEY_LENGTH)), oidcLogin{State: "state", CreatedAt: time.Now()})
+			return sealedLogin
+		}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loginUrl, sealedLogin, err := OidcLoginUrl()
+			if err != nil {
+				t.Fatal(err)
+			}
+			parsedUrl, err := url.Parse(loginUrl)
+			if err != nil {
+				t.Fatal(err)
+			}
+			query := parsedUrl.Query()
+			if query.Get("code_challenge_method") != "S256" || query.Get("nonce") == "" {
+				t.Fatalf("OidcLoginUrl() = %s, want a S256 challenge and a nonce", loginUrl)
+			}
+
+			code := testOidcCode{challenge: query.Get("code_challenge"), nonce: query.Get("nonce"), emailVerified: !tt.unverified}
+			if tt.otherChallenge {
+				otherChallenge := sha256.Sum256([]byte("other"))
+				code.challenge = base64.RawURLEncoding.EncodeToString(otherChallenge[:])
+			}
+			if tt.otherNonce {
+				code.nonce = "other"
+			}
+			codesMutex.Lock()
+			codes[tt.name] = code
+			codesMutex.Unlock()
+
+			state := query.Get("state")
+			if tt.otherState {
+				state = "other"
+			}
+			if tt.tamper != nil {
+				sealedLogin = tt.tamper(sealedLogin)
+			}
+
+			identity, err := OidcIdentityFor(context.Background(), state, tt.name, sealedLogin)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("OidcIdentityFor() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if err == nil && (identity.Email != "jane@example.com" || len(identity.Groups) != 1 || identity.Groups[0] != "admins") {
+				t.Errorf("OidcIdentityFor() = %+v, want jane@example.com of group admins", identity)
+			}
+		})
+	}
+
+	t.Run("expired login", func(t *testing.T) {
+		sealedLogin, err := sealOidcLogin(secretKeys[OIDC_LOGIN_KEY], oidcLogin{State: "state", CreatedAt: time.Now().Add(-OIDC_LOGIN_TIMEOUT - time.Second)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = OidcIdentityFor(context.Background(), "state", "code", sealedLogin)
+		if err == nil {
+			t.Errorf("OidcIdentityFor() error = nil, want an expired login")
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/logger"
+	"github.com/mogenius/punq/utils"
+)
+
+// bcrypt ignores everything after 72 bytes
+const PASSWORD_MAX_LENGTH = 72
+
+// the denylist is kept in memory (roughly 100 bytes per entry), further entries of the file are ignored. Lists of
+// breached passwords should be sorted by frequency, so the most common ones are loaded.
+const PASSWORD_DENYLIST_MAX_ENTRIES = 1000000
+
+type passwordDenylist struct {
+	file      string
+	passwords map[string]bool // lower case
+	sha1s     map[[sha1.Size]byte]bool
+}
+
+var denylist *passwordDenylist
+var denylistMutex sync.Mutex
+
+// CheckPasswordPolicy returns an error if the new password of the user violates password_policy. The user is nil
+// for new users, otherwise its current and previous passwords are rejected.
+func CheckPasswordPolicy(user *dtos.PunqUser, password string) error {
+	minLength := utils.CONFIG.PasswordPolicy.MinLength
+	if len([]rune(password)) < minLength {
+		return fmt.Errorf("password must be at least %d characters long", minLength)
+	}
+	if len(password) > PASSWORD_MAX_LENGTH {
+		return fmt.Errorf("password must not be longer than %d bytes", PASSWORD_MAX_LENGTH)
+	}
+	if isDeniedPassword(password) {
+		return fmt.Errorf("password is too common or has appeared in a data breach")
+	}
+	if user == nil {
+		return nil
+	}
+	if strings.EqualFold(password, user.Email) {
+		return fmt.Errorf("password must not be the email")
+	}
+	for _, hash := range append([]string{user.Password}, user.PasswordHistory...) {
+		previous := dtos.PunqUser{Password: hash}
+		if valid, _ := previous.PasswordCheck(password); valid {
+			return fmt.Errorf("password has been used before")
+		}
+	}
+	return nil
+}
+
+// passwordHistoryWith returns the history after the current hash has been replaced, limited to password_policy.history_size
+func passwordHistoryWith(user *dtos.PunqUser) []string {
+	size := utils.CONFIG.PasswordPolicy.HistorySize
+	if size <= 0 || user.Password == "" {
+		return nil
+	}
+	history := append([]string{user.Password}, user.PasswordHistory...)
+	if len(history) > size {
+		history = history[:size]
+	}
+	return history
+}
+
+func isDeniedPassword(password string) bool {
+	list := loadDenylist()
+	if list == nil {
+		return false
+	}
+	if list.passwords[strings.ToLower(password)] {
+		return true
+	}
+	return list.sha1s[sha1.Sum([]byte(password))]
+}
+
+// loadDenylist reads password_policy.denylist_file once. A missing file is logged and does not block passwords.
+func loadDenylist() *passwordDenylist {
+	file := utils.CONFIG.PasswordPolicy.DenylistFile
+	if file == "" {
+		return nil
+	}
+
+	denylistMutex.Lock()
+	defer denylistMutex.Unlock()
+	if denylist != nil && denylist.file == file {
+		return denylist
+	}
+
+	list := &passwordDenylist{file: file, passwords: map[string]bool{}, sha1s: map[[sha1.Size]byte]bool{}}
+	f, err := os.Open(file)
+	if err != nil {
+		logger.Log.Errorf("Failed to read password denylist: %s", err.Error())
+		denylist = list
+		return list
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(list.passwords)+len(list.sha1s) >= PASSWORD_DENYLIST_MAX_ENTRIES {
+			logger.Log.Warningf("Password denylist %s has more than %d entries, the rest is ignored.", file, PASSWORD_DENYLIST_MAX_ENTRIES)
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// e.g. the "SHA1:count" format of haveibeenpwned
+		if hash, _, _ := strings.Cut(line, ":"); len(hash) == sha1.Size*2 {
+			if sum, err := hex.DecodeString(hash); err == nil {
+				list.sha1s[[sha1.Size]byte(sum)] = true
+				continue
+			}
+		}
+		list.passwords[strings.ToLower(line)] = true
+	}
+	if err := scanner.Err(); err != nil {
+		logger.Log.Errorf("Failed to read password denylist: %s", err.Error())
+	}
+	logger.Log.Noticef("Loaded %d passwords and %d hashes from password denylist %s.", len(list.passwords), len(list.sha1s), file)
+	denylist = list
+	return list
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/utils"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func usePasswordPolicy(t *testing.T, minLength int, denylistLines []string) {
+	t.Helper()
+	previous := utils.CONFIG.PasswordPolicy
+	t.Cleanup(func() {
+		utils.CONFIG.PasswordPolicy = previous
+		denylist = nil
+	})
+
+	file := filepath.Join(t.TempDir(), "denylist.txt")
+	if err := os.WriteFile(file, []byte(strings.Join(denylistLines, "\n")), 0600); err != nil {
+		t.Fatal(err)
+	}
+	utils.CONFIG.PasswordPolicy.MinLength = minLength
+	utils.CONFIG.PasswordPolicy.DenylistFile = file
+	denylist = nil
+}
+
+func bcryptHash(t *testing.T, password string) string {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(hash)
+}
+
+func TestCheckPasswordPolicy(t *testing.T) {
+	breached := sha1.Sum([]byte("Tr0ub4dor&3-breached"))
+	usePasswordPolicy(t, 12, []string{
+		"# common passwords",
+		"",
+		"Password123456",
+		strings.ToUpper(hex.EncodeToString(breached[:])) + ":4711",
+	})
+
+	user := &dtos.PunqUser{
+		Email:           "jane.doe@example.com",
+		Password:        bcryptHash(t, "current-password-1"),
+		PasswordHistory: []string{bcryptHash(t, "previous-password-1")},
+	}
+
+	tests := []struct {
+		name     string
+		user     *dtos.PunqUser
+		password string
+		wantErr  bool
+	}{
+		{"valid", user, "correct horse battery staple", false},
+		{"valid for new users", nil, "correct horse battery staple", false},
+		{"too short", user, "short-pw", true},
+		{"length counts characters", nil, "üüüüüüüüüüüü", false},
+		{"too long for bcrypt", user, strings.Repeat("a", PASSWORD_MAX_LENGTH+1), true},
+		{"denied", user, "Password123456", true},
+		{"denied case-insensitively", nil, "PASSWORD123456", true},
+		{"denied by hash", nil, "Tr0ub4dor&3-breached", true},
+		{"email", user, "Jane.Doe@example.com", true},
+		{"email is fine for new users", nil, "Jane.Doe@example.com", false},
+		{"current password", user, "current-password-1", true},
+		{"previous password", user, "previous-password-1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckPasswordPolicy(tt.user, tt.password)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckPasswordPolicy(%s) error = %v, wantErr %t", tt.password, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckPasswordPolicyMissingDenylist(t *testing.T) {
+	usePasswordPolicy(t, 12, nil)
+	utils.CONFIG.PasswordPolicy.DenylistFile = filepath.Join(t.TempDir(), "missing.txt")
+
+	if err := CheckPasswordPolicy(nil, "Password123456"); err != nil {
+		t.Errorf("CheckPasswordPolicy() error = %v, a missing denylist must not block passwords", err)
+	}
+}
+
+func TestPasswordHistoryWith(t *testing.T) {
+	previous := utils.CONFIG.PasswordPolicy.HistorySize
+	t.Cleanup(func() { utils.CONFIG.PasswordPolicy.HistorySize = previous })
+
+	tests := []struct {
+		name        string
+		historySize int
+		user        dtos.PunqUser
+		want        []string
+	}{
+		{"disabled", 0, dtos.PunqUser{Password: "c", PasswordHistory: []string{"b", "a"}}, nil},
+		{"without password", 3, dtos.PunqUser{}, nil},
+		{"prepends the current hash", 3, dtos.PunqUser{Password: "c", PasswordHistory: []string{"b"}}, []string{"c", "b"}},
+		{"drops the oldest hashes", 2, dtos.PunqUser{Password: "c", PasswordHistory: []string{"b", "a"}}, []string{"c", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utils.CONFIG.PasswordPolicy.HistorySize = tt.historySize
+			got := passwordHistoryWith(&tt.user)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") || (got == nil) != (tt.want == nil) {
+				t.Errorf("passwordHistoryWith() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
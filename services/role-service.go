@@ -0,0 +1,480 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/kubernetes"
+	"github.com/mogenius/punq/logger"
+	"github.com/mogenius/punq/utils"
+	v1 "k8s.io/api/core/v1"
+)
+
+// ids of the built-in roles (one per access level)
+const (
+	ROLE_READER = "reader"
+	ROLE_USER   = "user"
+	ROLE_ADMIN  = "admin"
+)
+
+var rolesMutex sync.Mutex
+
+// BuiltInRoles describes what the access levels allow on workloads. Every user gets the built-in role of its
+// effective access level for the requested context.
+func BuiltInRoles() []dtos.PunqRole {
+	return []dtos.PunqRole{
+		{
+			Id:          ROLE_READER,
+			Name:        "READER",
+			Description: "No access to workloads, custom roles can grant it.",
+			BuiltIn:     true,
+			Rules:       []dtos.PunqRule{},
+		},
+		{
+			Id:          ROLE_USER,
+			Name:        "USER",
+			Description: "Manage namespaced workloads except secrets, storage, rbac and network policies.",
+			BuiltIn:     true,
+			Rules: []dtos.PunqRule{
+				// shell sessions need ADMIN or a custom role with "exec"
+				{Verbs: []string{dtos.ROLE_VERB_GET, dtos.ROLE_VERB_CREATE, dtos.ROLE_VERB_UPDATE, dtos.ROLE_VERB_DELETE, dtos.ROLE_VERB_LOGS, dtos.ROLE_VERB_RESTART}, Kinds: []string{
+					kubernetes.RES_POD,
+				}},
+				{Verbs: []string{dtos.ROLE_WILDCARD}, Kinds: []string{
+					kubernetes.RES_DEPLOYMENT,
+					kubernetes.RES_SERVICE,
+					kubernetes.RES_INGRESS,
+					kubernetes.RES_CONFIG_MAP,
+					kubernetes.RES_DAEMON_SET,
+					kubernetes.RES_STATEFUL_SET,
+					kubernetes.RES_JOB,
+					kubernetes.RES_CRON_JOB,
+					kubernetes.RES_REPLICA_SET,
+					kubernetes.RES_CERTIFICATE,
+					kubernetes.RES_CERTIFICATE_REQUEST,
+					kubernetes.RES_ORDER,
+					kubernetes.RES_ISSUER,
+					kubernetes.RES_ENDPOINT,
+					kubernetes.RES_LEASE,
+					kubernetes.RES_VOLUME_SNAPSHOT,
+				}},
+				{Verbs: []string{dtos.ROLE_VERB_GET, dtos.ROLE_VERB_CREATE, dtos.ROLE_VERB_UPDATE}, Kinds: []string{
+					kubernetes.RES_NAMESPACE,
+					kubernetes.RES_HELM_RELEASE,
+					kubernetes.RES_CUSTOM_RESOURCE,
+				}},
+				{Verbs: []string{dtos.ROLE_VERB_GET}, Kinds: []string{
+					kubernetes.RES_NODE,
+					kubernetes.RES_EVENT,
+					kubernetes.RES_PERSISTENT_VOLUME_CLAIM,
+					kubernetes.RES_HORIZONTAL_POD_AUTOSCALER,
+					kubernetes.RES_ROLE,
+					kubernetes.RES_ROLE_BINDING,
+					kubernetes.RES_NETWORK_POLICY,
+					kubernetes.RES_STORAGE_CLASS,
+				}},
+			},
+		},
+		{
+			Id:          ROLE_ADMIN,
+			Name:        "ADMIN",
+			Description: "Everything.",
+			BuiltIn:     true,
+			Rules: []dtos.PunqRule{
+				{Verbs: []string{dtos.ROLE_WILDCARD}, Kinds: []string{dtos.ROLE_WILDCARD}},
+			},
+		},
+	}
+}
+
+func BuiltInRoleFor(level dtos.AccessLevel) dtos.PunqRole {
+	roles := BuiltInRoles()
+	switch level {
+	case dtos.ADMIN:
+		return roles[2]
+	case dtos.USER:
+		return roles[1]
+	default:
+		return roles[0]
+	}
+}
+
+// RoleKinds returns all kinds which can be used in rules
+func RoleKinds() []string {
+	return append(append([]string{}, kubernetes.ALL_RESOURCES...), kubernetes.RES_HELM_RELEASE, kubernetes.RES_CUSTOM_RESOURCE)
+}
+
+// ListRoles returns the built-in roles followed by the custom roles
+func ListRoles() ([]dtos.PunqRole, error) {
+	result := BuiltInRoles()
+	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.ROLESSECRET, nil)
+	if secret == nil {
+		return result, nil
+	}
+	custom := allRoles(secret)
+	sort.Slice(custom, func(i, j int) bool {
+		return custom[i].Name < custom[j].Name
+	})
+	return append(result, custom...), nil
+}
+
+func GetRole(id string) (*dtos.PunqRole, error) {
+	for _, role := range BuiltInRoles() {
+		if role.Id == id {
+			return &role, nil
+		}
+	}
+	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.ROLESSECRET, nil)
+	if secret == nil {
+		return nil, fmt.Errorf("role '%s' not found", id)
+	}
+	return roleFrom(secret, id)
+}
+
+func AddRole(input dtos.PunqRoleCreateInput) (*dtos.PunqRole, error) {
+	rolesMutex.Lock()
+	defer rolesMutex.Unlock()
+
+	secret, err := rolesSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	role := dtos.PunqRole{
+		Id:          utils.NanoId(),
+		Name:        strings.TrimSpace(input.Name),
+		Description: input.Description,
+		Rules:       input.Rules,
+		Users:       input.Users,
+		Groups:      input.Groups,
+		Created:     time.Now().Format(time.RFC3339),
+	}
+	err = validateRole(secret, &role)
+	if err != nil {
+		return nil, err
+	}
+	err = saveRole(secret, role)
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// UpdateRole changes name, description, rules and bindings of a custom role
+func UpdateRole(input dtos.PunqRole) (*dtos.PunqRole, error) {
+	if isBuiltInRole(input.Id) {
+		return nil, fmt.Errorf("built-in role '%s' cannot be changed", input.Id)
+	}
+
+	rolesMutex.Lock()
+	defer rolesMutex.Unlock()
+
+	secret, err := rolesSecret()
+	if err != nil {
+		return nil, err
+	}
+	role, err := roleFrom(secret, input.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	role.Name = strings.TrimSpace(input.Name)
+	role.Description = input.Description
+	role.Rules = input.Rules
+	role.Users = input.Users
+	role.Groups = input.Groups
+	err = validateRole(secret, role)
+	if err != nil {
+		return nil, err
+	}
+	err = saveRole(secret, *role)
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func DeleteRole(id string) error {
+	if isBuiltInRole(id) {
+		return fmt.Errorf("built-in role '%s' cannot be deleted", id)
+	}
+
+	rolesMutex.Lock()
+	defer rolesMutex.Unlock()
+
+	secret, err := rolesSecret()
+	if err != nil {
+		return err
+	}
+	if _, err := roleFrom(secret, id); err != nil {
+		return err
+	}
+	delete(secret.Data, id)
+	return updateRolesSecret(secret)
+}
+
+// RemoveUserFromRoles removes the bindings of the user (e.g. if the user has been deleted)
+func RemoveUserFromRoles(userId string) error {
+	return removeRoleBindings(func(role *dtos.PunqRole) bool {
+		return removeFromList(&role.Users, userId)
+	})
+}
+
+// RemoveGroupFromRoles removes the bindings of the group (e.g. if the group has been deleted)
+func RemoveGroupFromRoles(groupId string) error {
+	return removeRoleBindings(func(role *dtos.PunqRole) bool {
+		return removeFromList(&role.Groups, groupId)
+	})
+}
+
+// RolesForUser returns the built-in role of the effective access level and all custom roles bound to the user
+// or its groups. Users without access to the context have no roles at all.
+func RolesForUser(user *dtos.PunqUser, contextId *string) (dtos.AccessLevel, []dtos.PunqRole, error) {
+	accessLevel, err := AccessLevelForContext(user, contextId)
+	if err != nil {
+		return accessLevel, nil, err
+	}
+
+	result := []dtos.PunqRole{BuiltInRoleFor(accessLevel)}
+	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.ROLESSECRET, nil)
+	if secret == nil {
+		return accessLevel, result, nil
+	}
+	groupIds := GroupIdsForUser(user.Id)
+	for _, role := range allRoles(secret) {
+		if role.BindsTo(user.Id, groupIds) {
+			result = append(result, role)
+		}
+	}
+	return accessLevel, result, nil
+}
+
+// Authorize checks the verb on a kind (and namespace) against all roles of the user and returns the effective
+// access level of the context
+func Authorize(user *dtos.PunqUser, contextId *string, verb string, kind string, namespace string) (dtos.AccessLevel, error) {
+	accessLevel, roles, err := RolesForUser(user, contextId)
+	if err != nil {
+		return accessLevel, err
+	}
+	ctxId := ""
+	if contextId != nil {
+		ctxId = *contextId
+	}
+	if rolesAllow(roles, verb, kind, namespace, ctxId) {
+		return accessLevel, nil
+	}
+	if namespace == "" {
+		return accessLevel, fmt.Errorf("no role of user '%s' allows '%s' on '%s'", user.Id, verb, kind)
+	}
+	return accessLevel, fmt.Errorf("no role of user '%s' allows '%s' on '%s' in namespace '%s'", user.Id, verb, kind, namespace)
+}
+
+// rolesAllow is true if at least one of the roles allows the verb on the kind
+func rolesAllow(roles []dtos.PunqRole, verb string, kind string, namespace string, contextId string) bool {
+	for _, role := range roles {
+		if role.Allows(verb, kind, namespace, contextId) {
+			return true
+		}
+	}
+	return false
+}
+
+// WorkloadsForUser returns the kinds the user can get in at least one namespace of the context
+func WorkloadsForUser(user *dtos.PunqUser, contextId *string) []string {
+	result := []string{}
+	_, roles, err := RolesForUser(user, contextId)
+	if err != nil {
+		return result
+	}
+	ctxId := ""
+	if contextId != nil {
+		ctxId = *contextId
+	}
+	for _, kind := range kubernetes.ALL_RESOURCES {
+		for _, role := range roles {
+			if role.AllowsKind(dtos.ROLE_VERB_GET, kind, ctxId) {
+				result = append(result, kind)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func isBuiltInRole(id string) bool {
+	return id == ROLE_READER || id == ROLE_USER || id == ROLE_ADMIN
+}
+
+// validateRole requires a unique name, valid rules and existing users and groups (duplicates are removed)
+func validateRole(secret *v1.Secret, role *dtos.PunqRole) error {
+	if role.Name == "" {
+		return errors.New("name of the role is required")
+	}
+	for _, other := range append(BuiltInRoles(), allRoles(secret)...) {
+		if other.Id != role.Id && strings.EqualFold(other.Name, role.Name) {
+			return fmt.Errorf("role '%s' already exists", role.Name)
+		}
+	}
+
+	if len(role.Rules) == 0 {
+		return errors.New("a role needs at least one rule")
+	}
+	kinds := RoleKinds()
+	for _, rule := range role.Rules {
+		err := rule.Validate()
+		if err != nil {
+			return err
+		}
+		for _, kind := range rule.Kinds {
+			if kind != dtos.ROLE_WILDCARD && !containsEqualFold(kinds, kind) {
+				return fmt.Errorf("unknown kind '%s'", kind)
+			}
+		}
+	}
+
+	users := []string{}
+	for _, user := range role.Users {
+		if utils.ContainsEqual(users, user) {
+			continue
+		}
+		if _, err := GetUser(user); err != nil {
+			return fmt.Errorf("user '%s' not found", user)
+		}
+		users = append(users, user)
+	}
+	role.Users = users
+
+	groups := []string{}
+	for _, group := range role.Groups {
+		if utils.ContainsEqual(groups, group) {
+			continue
+		}
+		if _, err := GetGroup(group); err != nil {
+			return err
+		}
+		groups = append(groups, group)
+	}
+	role.Groups = groups
+	return nil
+}
+
+func containsEqualFold(list []string, value string) bool {
+	for _, entry := range list {
+		if strings.EqualFold(entry, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func removeFromList(list *[]string, value string) bool {
+	if !utils.ContainsEqual(*list, value) {
+		return false
+	}
+	result := []string{}
+	for _, entry := range *list {
+		if entry != value {
+			result = append(result, entry)
+		}
+	}
+	*list = result
+	return true
+}
+
+func removeRoleBindings(remove func(role *dtos.PunqRole) bool) error {
+	rolesMutex.Lock()
+	defer rolesMutex.Unlock()
+
+	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.ROLESSECRET, nil)
+	if secret == nil {
+		return nil
+	}
+	changed := false
+	for _, role := range allRoles(secret) {
+		if !remove(&role) {
+			continue
+		}
+		rawRole, err := json.Marshal(role)
+		if err != nil {
+			return err
+		}
+		secret.Data[role.Id] = rawRole
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return updateRolesSecret(secret)
+}
+
+func rolesSecret() (*v1.Secret, error) {
+	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.ROLESSECRET, nil)
+	if secret == nil {
+		provider, err := kubernetes.NewKubeProvider(nil)
+		if err != nil {
+			return nil, err
+		}
+		newSecret := utils.InitSecret()
+		newSecret.ObjectMeta.Name = utils.ROLESSECRET
+		newSecret.ObjectMeta.Namespace = utils.CONFIG.Kubernetes.OwnNamespace
+		newSecret.StringData = map[string]string{}
+		secret, err = provider.ClientSet.CoreV1().Secrets(utils.CONFIG.Kubernetes.OwnNamespace).Create(context.TODO(), &newSecret, kubernetes.MoCreateOptions())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	return secret, nil
+}
+
+func saveRole(secret *v1.Secret, role dtos.PunqRole) error {
+	rawRole, err := json.Marshal(role)
+	if err != nil {
+		return err
+	}
+	secret.Data[role.Id] = rawRole
+	return updateRolesSecret(secret)
+}
+
+func updateRolesSecret(secret *v1.Secret) error {
+	workloadResult := kubernetes.UpdateK8sSecret(*secret, nil)
+	if workloadResult.Error != nil {
+		return fmt.Errorf("%v", workloadResult.Error)
+	}
+	return nil
+}
+
+func roleFrom(secret *v1.Secret, id string) (*dtos.PunqRole, error) {
+	rawRole, ok := secret.Data[id]
+	if !ok {
+		return nil, fmt.Errorf("role '%s' not found", id)
+	}
+	role := dtos.PunqRole{}
+	err := json.Unmarshal(rawRole, &role)
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func allRoles(secret *v1.Secret) []dtos.PunqRole {
+	result := []dtos.PunqRole{}
+	for key := range secret.Data {
+		role, err := roleFrom(secret, key)
+		if err != nil {
+			logger.Log.Errorf("Failed to unmarshal role '%s': %s", key, err.Error())
+			continue
+		}
+		result = append(result, *role)
+	}
+	return result
+}
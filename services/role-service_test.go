@@ -0,0 +1,48 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/kubernetes"
+)
+
+func TestRolesAllow(t *testing.T) {
+	deployer := dtos.PunqRole{Id: "deployer", Rules: []dtos.PunqRule{
+		{Verbs: []string{dtos.ROLE_VERB_EXEC}, Kinds: []string{kubernetes.RES_POD}, Namespaces: []string{"team-a-*"}, Contexts: []string{"dev"}},
+	}}
+
+	tests := []struct {
+		name      string
+		roles     []dtos.PunqRole
+		verb      string
+		kind      string
+		namespace string
+		want      bool
+	}{
+		{"reader gets no pods", []dtos.PunqRole{BuiltInRoleFor(dtos.READER)}, dtos.ROLE_VERB_GET, kubernetes.RES_POD, "default", false},
+		{"user gets pods", []dtos.PunqRole{BuiltInRoleFor(dtos.USER)}, dtos.ROLE_VERB_GET, kubernetes.RES_POD, "default", true},
+		{"user reads pod logs", []dtos.PunqRole{BuiltInRoleFor(dtos.USER)}, dtos.ROLE_VERB_LOGS, kubernetes.RES_POD, "default", true},
+		{"user has no shell", []dtos.PunqRole{BuiltInRoleFor(dtos.USER)}, dtos.ROLE_VERB_EXEC, kubernetes.RES_POD, "default", false},
+		{"user gets no secrets", []dtos.PunqRole{BuiltInRoleFor(dtos.USER)}, dtos.ROLE_VERB_GET, kubernetes.RES_SECRET, "default", false},
+		{"user deletes deployments", []dtos.PunqRole{BuiltInRoleFor(dtos.USER)}, dtos.ROLE_VERB_DELETE, kubernetes.RES_DEPLOYMENT, "default", true},
+		{"user deletes no namespaces", []dtos.PunqRole{BuiltInRoleFor(dtos.USER)}, dtos.ROLE_VERB_DELETE, kubernetes.RES_NAMESPACE, "default", false},
+		{"user only gets nodes", []dtos.PunqRole{BuiltInRoleFor(dtos.USER)}, dtos.ROLE_VERB_UPDATE, kubernetes.RES_NODE, "", false},
+		{"admin has a shell", []dtos.PunqRole{BuiltInRoleFor(dtos.ADMIN)}, dtos.ROLE_VERB_EXEC, kubernetes.RES_POD, "default", true},
+		{"admin gets secrets", []dtos.PunqRole{BuiltInRoleFor(dtos.ADMIN)}, dtos.ROLE_VERB_GET, kubernetes.RES_SECRET, "", true},
+		{"custom role grants a shell", []dtos.PunqRole{BuiltInRoleFor(dtos.USER), deployer}, dtos.ROLE_VERB_EXEC, kubernetes.RES_POD, "team-a-dev", true},
+		{"custom role is limited to its namespaces", []dtos.PunqRole{BuiltInRoleFor(dtos.USER), deployer}, dtos.ROLE_VERB_EXEC, kubernetes.RES_POD, "team-b-dev", false},
+		{"no roles", []dtos.PunqRole{}, dtos.ROLE_VERB_GET, kubernetes.RES_POD, "default", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rolesAllow(tt.roles, tt.verb, tt.kind, tt.namespace, "dev"); got != tt.want {
+				t.Errorf("rolesAllow(%s, %s, %s) = %t, want %t", tt.verb, tt.kind, tt.namespace, got, tt.want)
+			}
+		})
+	}
+
+	if rolesAllow([]dtos.PunqRole{deployer}, dtos.ROLE_VERB_EXEC, kubernetes.RES_POD, "team-a-dev", "prod") {
+		t.Errorf("custom role bound to context 'dev' allows 'prod'")
+	}
+}
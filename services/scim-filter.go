@@ -0,0 +1,558 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mogenius/punq/dtos"
+)
+
+// SCIM filters (RFC 7644 3.4.2.2) and PATCH operations (RFC 7644 3.5.2) are evaluated on the JSON representation of
+// the resource. Attribute names are case-insensitive, string comparisons too.
+
+type scimFilter interface {
+	matches(resource map[string]interface{}) bool
+}
+
+type scimLogicalFilter struct {
+	and         bool
+	left, right scimFilter
+}
+
+type scimNotFilter struct {
+	inner scimFilter
+}
+
+type scimCompareFilter struct {
+	path  []string // attribute and optional sub-attribute
+	op    string
+	value interface{}
+}
+
+// e.g. emails[type eq "work"]
+type scimValuePathFilter struct {
+	attribute string
+	inner     scimFilter
+}
+
+func (f *scimLogicalFilter) matches(resource map[string]interface{}) bool {
+	if f.and {
+		return f.left.matches(resource) && f.right.matches(resource)
+	}
+	return f.left.matches(resource) || f.right.matches(resource)
+}
+
+func (f *scimNotFilter) matches(resource map[string]interface{}) bool {
+	return !f.inner.matches(resource)
+}
+
+func (f *scimCompareFilter) matches(resource map[string]interface{}) bool {
+	values := scimValues(resource, f.path)
+	if f.op == "pr" {
+		for _, value := range values {
+			if value != nil && value != "" {
+				return true
+			}
+		}
+		return false
+	}
+	for _, value := range values {
+		if scimCompare(value, f.op, f.value) {
+			return true
+		}
+	}
+	// ne also matches missing attributes
+	return f.op == "ne" && len(values) == 0
+}
+
+func (f *scimValuePathFilter) matches(resource map[string]interface{}) bool {
+	for _, element := range scimElements(resource, f.attribute) {
+		if f.inner.matches(element) {
+			return true
+		}
+	}
+	return false
+}
+
+// scimValues returns the values of the attribute path, multi-valued attributes are flattened. Complex values without
+// sub-attribute are compared by their "value" sub-attribute.
+func scimValues(resource map[string]interface{}, path []string) []interface{} {
+	current := []interface{}{resource}
+	for _, name := range path {
+		next := []interface{}{}
+		for _, entry := range current {
+			object, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			value, ok := object[scimKey(object, name)]
+			if !ok {
+				continue
+			}
+			if list, ok := value.([]interface{}); ok {
+				next = append(next, list...)
+			} else {
+				next = append(next, value)
+			}
+		}
+		current = next
+	}
+	result := []interface{}{}
+	for _, entry := range current {
+		if object, ok := entry.(map[string]interface{}); ok {
+			if value, ok := object[scimKey(object, "value")]; ok {
+				result = append(result, value)
+			}
+			continue
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+// scimElements returns the complex values of a (multi-valued) attribute
+func scimElements(resource map[string]interface{}, attribute string) []map[string]interface{} {
+	result := []map[string]interface{}{}
+	switch value := resource[scimKey(resource, attribute)].(type) {
+	case []interface{}:
+		for _, entry := range value {
+			if object, ok := entry.(map[string]interface{}); ok {
+				result = append(result, object)
+			}
+		}
+	case map[string]interface{}:
+		result = append(result, value)
+	}
+	return result
+}
+
+func scimCompare(actual interface{}, op string, expected interface{}) bool {
+	switch expectedValue := expected.(type) {
+	case bool:
+		actualValue, ok := scimBool(actual)
+		if !ok {
+			return false
+		}
+		switch op {
+		case "eq":
+			return actualValue == expectedValue
+		case "ne":
+			return actualValue != expectedValue
+		}
+		return false
+	case nil:
+		return (op == "eq") == (actual == nil)
+	}
+
+	actualString := strings.ToLower(fmt.Sprint(actual))
+	expectedString := strings.ToLower(fmt.Sprint(expected))
+	switch op {
+	case "eq":
+		return actualString == expectedString
+	case "ne":
+		return actualString != expectedString
+	case "co":
+		return strings.Contains(actualString, expectedString)
+	case "sw":
+		return strings.HasPrefix(actualString, expectedString)
+	case "ew":
+		return strings.HasSuffix(actualString, expectedString)
+	case "gt":
+		return actualString > expectedString
+	case "ge":
+		return actualString >= expectedString
+	case "lt":
+		return actualString < expectedString
+	case "le":
+		return actualString <= expectedString
+	}
+	return false
+}
+
+// scimBool also accepts strings, some identity providers send "True" and "False"
+func scimBool(value interface{}) (bool, bool) {
+	switch typed := value.(type) {
+	case bool:
+		return typed, true
+	case string:
+		parsed, err := strconv.ParseBool(strings.ToLower(typed))
+		return parsed, err == nil
+	}
+	return false, false
+}
+
+// scimKey returns the existing key of the object matching the attribute name case-insensitively
+func scimKey(object map[string]interface{}, name string) string {
+	if _, ok := object[name]; ok {
+		return name
+	}
+	for key := range object {
+		if strings.EqualFold(key, name) {
+			return key
+		}
+	}
+	return name
+}
+
+// scimAttributePath splits "urn:...:User:name.givenName" into its attribute names without schema
+func scimAttributePath(path string) []string {
+	if strings.HasPrefix(strings.ToLower(path), "urn:") {
+		path = path[strings.LastIndex(path, ":")+1:]
+	}
+	return strings.Split(path, ".")
+}
+
+type scimFilterParser struct {
+	tokens   []string
+	position int
+}
+
+func parseScimFilter(filter string) (scimFilter, error) {
+	tokens, err := scimTokenize(filter)
+	if err != nil {
+		return nil, err
+	}
+	parser := &scimFilterParser{tokens: tokens}
+	result, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if parser.position < len(tokens) {
+		return nil, fmt.Errorf("unexpected '%s' in filter", tokens[parser.position])
+	}
+	return result, nil
+}
+
+func (p *scimFilterParser) peek() string {
+	if p.position < len(p.tokens) {
+		return p.tokens[p.position]
+	}
+	return ""
+}
+
+func (p *scimFilterParser) next() string {
+	token := p.peek()
+	p.position++
+	return token
+}
+
+func (p *scimFilterParser) parseOr() (scimFilter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &scimLogicalFilter{and: false, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *scimFilterParser) parseAnd() (scimFilter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &scimLogicalFilter{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *scimFilterParser) parseUnary() (scimFilter, error) {
+	if strings.EqualFold(p.peek(), "not") {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &scimNotFilter{inner: inner}, nil
+	}
+	if p.peek() == "(" {
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing ')' in filter")
+		}
+		return inner, nil
+	}
+	return p.parseAttribute()
+}
+
+func (p *scimFilterParser) parseAttribute() (scimFilter, error) {
+	attribute := p.next()
+	if attribute == "" || strings.ContainsAny(attribute, "()[]\"") {
+		return nil, fmt.Errorf("attribute expected in filter")
+	}
+	if p.peek() == "[" {
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != "]" {
+			return nil, fmt.Errorf("missing ']' in filter")
+		}
+		return &scimValuePathFilter{attribute: scimAttributePath(attribute)[0], inner: inner}, nil
+	}
+
+	op := strings.ToLower(p.next())
+	switch op {
+	case "pr":
+		return &scimCompareFilter{path: scimAttributePath(attribute), op: op}, nil
+	case "eq", "ne", "co", "sw", "ew", "gt", "ge", "lt", "le":
+	default:
+		return nil, fmt.Errorf("unknown operator '%s' in filter", op)
+	}
+	if p.position >= len(p.tokens) {
+		return nil, fmt.Errorf("value expected in filter")
+	}
+	value, err := scimFilterValue(p.next())
+	if err != nil {
+		return nil, err
+	}
+	return &scimCompareFilter{path: scimAttributePath(attribute), op: op, value: value}, nil
+}
+
+func scimFilterValue(token string) (interface{}, error) {
+	if strings.HasPrefix(token, "\"") {
+		var value string
+		if err := json.Unmarshal([]byte(token), &value); err != nil {
+			return nil, fmt.Errorf("invalid string %s in filter", token)
+		}
+		return value, nil
+	}
+	switch strings.ToLower(token) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if _, err := strconv.ParseFloat(token, 64); err == nil {
+		return token, nil
+	}
+	return nil, fmt.Errorf("invalid value '%s' in filter", token)
+}
+
+// scimTokenize splits a filter into words, quoted strings (with their quotes) and the characters ()[]
+func scimTokenize(filter string) ([]string, error) {
+	tokens := []string{}
+	for i := 0; i < len(filter); {
+		switch c := filter[i]; {
+		case c == ' ' || c == '\t':
+			i++
+		case strings.IndexByte("()[]", c) >= 0:
+			tokens = append(tokens, string(c))
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(filter) && filter[end] != '"' {
+				if filter[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(filter) {
+				return nil, fmt.Errorf("unterminated string in filter")
+			}
+			tokens = append(tokens, filter[i:end+1])
+			i = end + 1
+		default:
+			end := i
+			for end < len(filter) && strings.IndexByte(" \t()[]\"", filter[end]) < 0 {
+				end++
+			}
+			tokens = append(tokens, filter[i:end])
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+// applyScimPatch applies one operation to the JSON representation of a resource
+func applyScimPatch(resource map[string]interface{}, operation dtos.ScimPatchOperation) error {
+	op := strings.ToLower(operation.Op)
+	if op != "add" && op != "replace" && op != "remove" {
+		return fmt.Errorf("unknown patch operation '%s'", operation.Op)
+	}
+
+	if operation.Path == "" {
+		if op == "remove" {
+			return fmt.Errorf("remove requires a path")
+		}
+		values, ok := operation.Value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s without path requires an object as value", op)
+		}
+		for name, value := range values {
+			err := applyScimPatch(resource, dtos.ScimPatchOperation{Op: op, Path: name, Value: value})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	attribute, filter, subAttribute, err := parseScimPatchPath(operation.Path)
+	if err != nil {
+		return err
+	}
+	key := scimKey(resource, attribute)
+
+	if filter == nil {
+		if subAttribute != "" {
+			object, ok := resource[key].(map[string]interface{})
+			if !ok {
+				if op == "remove" {
+					return nil
+				}
+				object = map[string]interface{}{}
+				resource[key] = object
+			}
+			if op == "remove" {
+				delete(object, scimKey(object, subAttribute))
+			} else {
+				object[scimKey(object, subAttribute)] = operation.Value
+			}
+			return nil
+		}
+
+		existing, isList := resource[key].([]interface{})
+		switch op {
+		case "remove":
+			// e.g. {"op": "remove", "path": "members", "value": [{"value": "<id>"}]}
+			if removed, ok := operation.Value.([]interface{}); ok && isList {
+				resource[key] = scimWithout(existing, removed)
+			} else {
+				delete(resource, key)
+			}
+		case "add":
+			if added, ok := operation.Value.([]interface{}); ok && (isList || resource[key] == nil) {
+				resource[key] = scimWith(existing, added)
+			} else {
+				resource[key] = operation.Value
+			}
+		default:
+			resource[key] = operation.Value
+		}
+		return nil
+	}
+
+	elements, _ := resource[key].([]interface{})
+	matched := false
+	result := []interface{}{}
+	for _, entry := range elements {
+		element, ok := entry.(map[string]interface{})
+		if !ok || !filter.matches(element) {
+			result = append(result, entry)
+			continue
+		}
+		matched = true
+		switch {
+		case op == "remove" && subAttribute == "":
+			continue
+		case op == "remove":
+			delete(element, scimKey(element, subAttribute))
+		case subAttribute != "":
+			element[scimKey(element, subAttribute)] = operation.Value
+		default:
+			if values, ok := operation.Value.(map[string]interface{}); ok {
+				for name, value := range values {
+					element[scimKey(element, name)] = value
+				}
+			}
+		}
+		result = append(result, element)
+	}
+	// e.g. replace of emails[type eq "work"].value for a user without work email
+	if !matched && op != "remove" {
+		compare, ok := filter.(*scimCompareFilter)
+		if !ok || compare.op != "eq" || len(compare.path) != 1 {
+			return fmt.Errorf("no value matches path '%s'", operation.Path)
+		}
+		element := map[string]interface{}{compare.path[0]: compare.value}
+		if subAttribute != "" {
+			element[subAttribute] = operation.Value
+		} else if values, ok := operation.Value.(map[string]interface{}); ok {
+			for name, value := range values {
+				element[name] = value
+			}
+		}
+		result = append(result, element)
+	}
+	resource[key] = result
+	return nil
+}
+
+// parseScimPatchPath splits 'attribute[filter].subAttribute' (filter and sub-attribute are optional)
+func parseScimPatchPath(path string) (string, scimFilter, string, error) {
+	open := strings.Index(path, "[")
+	if open < 0 {
+		names := scimAttributePath(path)
+		if len(names) > 2 {
+			return "", nil, "", fmt.Errorf("invalid path '%s'", path)
+		}
+		if len(names) == 2 {
+			return names[0], nil, names[1], nil
+		}
+		return names[0], nil, "", nil
+	}
+	closing := strings.LastIndex(path, "]")
+	if closing < open {
+		return "", nil, "", fmt.Errorf("invalid path '%s'", path)
+	}
+	filter, err := parseScimFilter(path[open+1 : closing])
+	if err != nil {
+		return "", nil, "", err
+	}
+	subAttribute := strings.TrimPrefix(path[closing+1:], ".")
+	return scimAttributePath(path[:open])[0], filter, subAttribute, nil
+}
+
+// scimWith appends the values which are not in the list yet (compared by their "value" sub-attribute)
+func scimWith(list []interface{}, values []interface{}) []interface{} {
+	result := append([]interface{}{}, list...)
+	for _, value := range values {
+		if !scimContains(result, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func scimWithout(list []interface{}, values []interface{}) []interface{} {
+	result := []interface{}{}
+	for _, entry := range list {
+		if !scimContains(values, entry) {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+func scimContains(list []interface{}, value interface{}) bool {
+	identity := scimValues(map[string]interface{}{"v": value}, []string{"v"})
+	for _, entry := range list {
+		other := scimValues(map[string]interface{}{"v": entry}, []string{"v"})
+		if len(identity) > 0 && len(other) > 0 && fmt.Sprint(identity[0]) == fmt.Sprint(other[0]) {
+			return true
+		}
+	}
+	return false
+}
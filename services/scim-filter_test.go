@@ -0,0 +1,150 @@
+package services
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/mogenius/punq/dtos"
+)
+
+func scimTestUser() map[string]interface{} {
+	return map[string]interface{}{
+		"schemas":  []interface{}{"urn:ietf:params:scim:schemas:core:2.0:User"},
+		"userName": "Jane.Doe@example.com",
+		"active":   true,
+		"name": map[string]interface{}{
+			"givenName":  "Jane",
+			"familyName": "Doe",
+		},
+		"emails": []interface{}{
+			map[string]interface{}{"type": "work", "value": "jane@example.com", "primary": true},
+			map[string]interface{}{"type": "home", "value": "jane@home.example.org"},
+		},
+	}
+}
+
+func TestParseScimFilter(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{`userName eq "jane.doe@example.com"`, true},
+		{`USERNAME Eq "Jane.Doe@example.com"`, true},
+		{`userName ne "jane.doe@example.com"`, false},
+		{`userName co "doe"`, true},
+		{`userName sw "jane"`, true},
+		{`userName ew "example.com"`, true},
+		{`userName gt "a"`, true},
+		{`userName lt "a"`, false},
+		{`userName ge "jane.doe@example.com"`, true},
+		{`userName le "jane.doe@example.com"`, true},
+		{`userName pr`, true},
+		{`title pr`, false},
+		{`title ne "boss"`, true},
+		{`active eq true`, true},
+		{`active eq false`, false},
+		{`active ne false`, true},
+		{`name.givenName eq "jane"`, true},
+		{`urn:ietf:params:scim:schemas:core:2.0:User:name.familyName eq "Doe"`, true},
+		{`emails eq "jane@home.example.org"`, true},
+		{`emails.value ew "example.org"`, true},
+		{`emails[type eq "work"]`, true},
+		{`emails[type eq "work" and value co "home"]`, false},
+		{`emails[type eq "home" and value co "home"]`, true},
+		{`emails[type eq "other"]`, false},
+		{`userName eq "x" or active eq true`, true},
+		{`userName eq "x" or active eq false`, false},
+		{`userName sw "jane" and active eq true`, true},
+		{`userName sw "jane" and active eq false`, false},
+		{`not (active eq false)`, true},
+		{`not active eq true`, false},
+		{`userName eq "x" or userName eq "y" and active eq true`, false},
+		{`(userName eq "x" or userName co "jane") and active eq true`, true},
+		{`userName eq "jane.doe@example.com" and (emails[type eq "home"] or title pr)`, true},
+		{`userName eq "with \"quotes\""`, false},
+	}
+	user := scimTestUser()
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			filter, err := parseScimFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("parseScimFilter() error = %v", err)
+			}
+			if got := filter.matches(user); got != tt.want {
+				t.Errorf("matches() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseScimFilterErrors(t *testing.T) {
+	tests := []string{
+		``,
+		`userName`,
+		`userName eq`,
+		`userName is "jane"`,
+		`userName eq jane`,
+		`userName eq "jane`,
+		`(userName eq "jane"`,
+		`emails[type eq "work"`,
+		`userName eq "jane")`,
+		`userName eq "jane" and`,
+		`userName eq "jane" active eq true`,
+		`"jane" eq userName`,
+	}
+	for _, filter := range tests {
+		t.Run(filter, func(t *testing.T) {
+			if _, err := parseScimFilter(filter); err == nil {
+				t.Errorf("parseScimFilter(%s) succeeded, want an error", filter)
+			}
+		})
+	}
+}
+
+func TestApplyScimPatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation dtos.ScimPatchOperation
+		attribute string
+		want      string
+		wantErr   bool
+	}{
+		{"replace attribute", dtos.ScimPatchOperation{Op: "Replace", Path: "active", Value: false}, "active", `false`, false},
+		{"replace sub-attribute", dtos.ScimPatchOperation{Op: "replace", Path: "name.givenName", Value: "Janet"}, "name", `{"familyName":"Doe","givenName":"Janet"}`, false},
+		{"replace without path", dtos.ScimPatchOperation{Op: "replace", Value: map[string]interface{}{"ACTIVE": false}}, "active", `false`, false},
+		{"replace filtered value", dtos.ScimPatchOperation{Op: "replace", Path: `emails[type eq "work"].value`, Value: "j@example.com"}, "emails", `[{"primary":true,"type":"work","value":"j@example.com"},{"type":"home","value":"jane@home.example.org"}]`, false},
+		{"add missing filtered value", dtos.ScimPatchOperation{Op: "add", Path: `emails[type eq "other"].value`, Value: "j@other.example.com"}, "emails", `[{"primary":true,"type":"work","value":"jane@example.com"},{"type":"home","value":"jane@home.example.org"},{"type":"other","value":"j@other.example.com"}]`, false},
+		{"add to list", dtos.ScimPatchOperation{Op: "add", Path: "emails", Value: []interface{}{map[string]interface{}{"value": "jane@example.com"}, map[string]interface{}{"value": "new@example.com"}}}, "emails", `[{"primary":true,"type":"work","value":"jane@example.com"},{"type":"home","value":"jane@home.example.org"},{"value":"new@example.com"}]`, false},
+		{"remove filtered value", dtos.ScimPatchOperation{Op: "remove", Path: `emails[type eq "home"]`}, "emails", `[{"primary":true,"type":"work","value":"jane@example.com"}]`, false},
+		{"remove values from list", dtos.ScimPatchOperation{Op: "remove", Path: "emails", Value: []interface{}{map[string]interface{}{"value": "jane@example.com"}}}, "emails", `[{"type":"home","value":"jane@home.example.org"}]`, false},
+		{"remove attribute", dtos.ScimPatchOperation{Op: "remove", Path: "name"}, "name", `null`, false},
+		{"unknown operation", dtos.ScimPatchOperation{Op: "move", Path: "active"}, "", "", true},
+		{"remove without path", dtos.ScimPatchOperation{Op: "remove"}, "", "", true},
+		{"no matching value", dtos.ScimPatchOperation{Op: "replace", Path: `emails[type co "x"].value`, Value: "x"}, "", "", true},
+		{"invalid path", dtos.ScimPatchOperation{Op: "replace", Path: "name.givenName.first", Value: "x"}, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := scimTestUser()
+			err := applyScimPatch(user, tt.operation)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyScimPatch() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			var want interface{}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatalf("invalid expectation: %v", err)
+			}
+			// round trip through JSON to compare independent of the go types
+			gotJson, _ := json.Marshal(user[tt.attribute])
+			var got interface{}
+			_ = json.Unmarshal(gotJson, &got)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s = %s, want %s", tt.attribute, gotJson, tt.want)
+			}
+		})
+	}
+}
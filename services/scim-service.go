@@ -0,0 +1,448 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/logger"
+	"github.com/mogenius/punq/utils"
+)
+
+// ScimError is returned to the identity provider as dtos.ScimError with its HTTP status
+type ScimError struct {
+	Status   int
+	ScimType string // e.g. uniqueness, invalidFilter, invalidPath, invalidValue
+	Detail   string
+}
+
+func (e *ScimError) Error() string {
+	return e.Detail
+}
+
+func scimError(status int, scimType string, format string, args ...interface{}) *ScimError {
+	return &ScimError{Status: status, ScimType: scimType, Detail: fmt.Sprintf(format, args...)}
+}
+
+// ScimListUsers returns the users matching the filter, startIndex is 1-based. Only users provisioned via SCIM are
+// visible, local and other external users cannot be read or changed with the SCIM token.
+func ScimListUsers(filter string, startIndex int, count int, baseUrl string) (*dtos.ScimListResponse, error) {
+	groups, _ := ListGroups()
+	resources := []interface{}{}
+	users := ListUsers()
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Created < users[j].Created
+	})
+	for _, user := range users {
+		if user.Id == "" || user.Provider != dtos.USER_PROVIDER_SCIM {
+			continue
+		}
+		resources = append(resources, scimUserFrom(&user, groups, baseUrl))
+	}
+	return scimList(resources, filter, startIndex, count)
+}
+
+func ScimGetUser(id string, baseUrl string) (*dtos.ScimUser, error) {
+	user, err := scimUser(id)
+	if err != nil {
+		return nil, err
+	}
+	groups, _ := ListGroups()
+	result := scimUserFrom(user, groups, baseUrl)
+	return &result, nil
+}
+
+// ScimCreateUser creates a user of provider scim, which signs in with OIDC or LDAP. Its password is never used.
+func ScimCreateUser(input dtos.ScimUser, baseUrl string) (*dtos.ScimUser, error) {
+	email, displayName, err := scimUserAttributes(input)
+	if err != nil {
+		return nil, err
+	}
+	if existing, _ := GetUserByEmail(email); existing != nil {
+		return nil, scimError(http.StatusConflict, "uniqueness", "user '%s' already exists", email)
+	}
+
+	user, err := AddUser(dtos.PunqUserCreateInput{
+		Email:       email,
+		Password:    utils.NanoId() + utils.NanoId(),
+		DisplayName: displayName,
+		AccessLevel: dtos.AccessLevelFromString(utils.CONFIG.Scim.DefaultAccessLevel),
+		Provider:    dtos.USER_PROVIDER_SCIM,
+		ExternalId:  input.ExternalId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.Log.Noticef("Created user '%s' (%s) via SCIM.", user.Email, user.Id)
+	if input.Active != nil && !*input.Active {
+		user, err = SetUserDisabled(user.Id, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+	groups, _ := ListGroups()
+	result := scimUserFrom(user, groups, baseUrl)
+	return &result, nil
+}
+
+// ScimReplaceUser updates userName, displayName, externalId and active of the user
+func ScimReplaceUser(id string, input dtos.ScimUser, baseUrl string) (*dtos.ScimUser, error) {
+	user, err := scimUser(id)
+	if err != nil {
+		return nil, err
+	}
+	email, displayName, err := scimUserAttributes(input)
+	if err != nil {
+		return nil, err
+	}
+	if existing, _ := GetUserByEmail(email); existing != nil && existing.Id != user.Id {
+		return nil, scimError(http.StatusConflict, "uniqueness", "user '%s' already exists", email)
+	}
+
+	user.Email = email
+	user.DisplayName = displayName
+	if input.ExternalId != "" {
+		user.ExternalId = input.ExternalId
+	}
+	user, err = UpdateUser(*user)
+	if err != nil {
+		return nil, err
+	}
+
+	active := input.Active == nil || *input.Active
+	if active == user.Disabled {
+		user, err = SetUserDisabled(user.Id, !active)
+		if err != nil {
+			return nil, err
+		}
+	}
+	groups, _ := ListGroups()
+	result := scimUserFrom(user, groups, baseUrl)
+	return &result, nil
+}
+
+func ScimPatchUser(id string, patch dtos.ScimPatchRequest, baseUrl string) (*dtos.ScimUser, error) {
+	current, err := ScimGetUser(id, baseUrl)
+	if err != nil {
+		return nil, err
+	}
+	patched := dtos.ScimUser{}
+	err = scimPatch(current, patch, &patched)
+	if err != nil {
+		return nil, err
+	}
+	return ScimReplaceUser(id, patched, baseUrl)
+}
+
+// ScimDeleteUser deactivates the user, unless scim.delete_users is set
+func ScimDeleteUser(id string) error {
+	if _, err := scimUser(id); err != nil {
+		return err
+	}
+	if utils.CONFIG.Scim.DeleteUsers {
+		return DeleteUser(id)
+	}
+	_, err := SetUserDisabled(id, true)
+	return err
+}
+
+func ScimListGroups(filter string, startIndex int, count int, baseUrl string) (*dtos.ScimListResponse, error) {
+	groups, err := ListGroups()
+	if err != nil {
+		return nil, err
+	}
+	users := scimUsersById()
+	resources := []interface{}{}
+	for _, group := range groups {
+		resources = append(resources, scimGroupFrom(&group, users, baseUrl))
+	}
+	return scimList(resources, filter, startIndex, count)
+}
+
+func ScimGetGroup(id string, baseUrl string) (*dtos.ScimGroup, error) {
+	group, err := GetGroup(id)
+	if err != nil {
+		return nil, scimError(http.StatusNotFound, "", "group '%s' not found", id)
+	}
+	result := scimGroupFrom(group, scimUsersById(), baseUrl)
+	return &result, nil
+}
+
+func ScimCreateGroup(input dtos.ScimGroup, baseUrl string) (*dtos.ScimGroup, error) {
+	if err := scimCheckGroupName("", input.DisplayName); err != nil {
+		return nil, err
+	}
+	group, err := AddGroup(dtos.PunqGroupCreateInput{
+		Name:       input.DisplayName,
+		Members:    scimMemberIds(input.Members, scimUsersById()),
+		ExternalId: input.ExternalId,
+	})
+	if err != nil {
+		return nil, scimError(http.StatusBadRequest, "invalidValue", "%s", err.Error())
+	}
+	logger.Log.Noticef("Created group '%s' (%s) via SCIM.", group.Name, group.Id)
+	result := scimGroupFrom(group, scimUsersById(), baseUrl)
+	return &result, nil
+}
+
+// ScimReplaceGroup updates displayName, externalId and members, the description and members which were not
+// provisioned via SCIM are kept
+func ScimReplaceGroup(id string, input dtos.ScimGroup, baseUrl string) (*dtos.ScimGroup, error) {
+	group, err := GetGroup(id)
+	if err != nil {
+		return nil, scimError(http.StatusNotFound, "", "group '%s' not found", id)
+	}
+	if err := scimCheckGroupName(id, input.DisplayName); err != nil {
+		return nil, err
+	}
+	group.Name = input.DisplayName
+	group.ExternalId = input.ExternalId
+	scimUsers := scimUsersById()
+	members := scimMemberIds(input.Members, scimUsers)
+	for _, member := range group.Members {
+		if _, isScimUser := scimUsers[member]; !isScimUser {
+			members = append(members, member)
+		}
+	}
+	group.Members = members
+	group, err = UpdateGroup(*group)
+	if err != nil {
+		return nil, scimError(http.StatusBadRequest, "invalidValue", "%s", err.Error())
+	}
+	result := scimGroupFrom(group, scimUsersById(), baseUrl)
+	return &result, nil
+}
+
+func ScimPatchGroup(id string, patch dtos.ScimPatchRequest, baseUrl string) (*dtos.ScimGroup, error) {
+	current, err := ScimGetGroup(id, baseUrl)
+	if err != nil {
+		return nil, err
+	}
+	patched := dtos.ScimGroup{}
+	err = scimPatch(current, patch, &patched)
+	if err != nil {
+		return nil, err
+	}
+	return ScimReplaceGroup(id, patched, baseUrl)
+}
+
+func ScimDeleteGroup(id string) error {
+	if _, err := GetGroup(id); err != nil {
+		return scimError(http.StatusNotFound, "", "group '%s' not found", id)
+	}
+	return DeleteGroup(id)
+}
+
+func scimUserFrom(user *dtos.PunqUser, groups []dtos.PunqGroup, baseUrl string) dtos.ScimUser {
+	active := !user.Disabled
+	result := dtos.ScimUser{
+		Schemas:     []string{dtos.SCIM_SCHEMA_USER},
+		Id:          user.Id,
+		ExternalId:  user.ExternalId,
+		UserName:    user.Email,
+		Name:        &dtos.ScimName{Formatted: user.DisplayName},
+		DisplayName: user.DisplayName,
+		Emails:      []dtos.ScimMultiValue{{Value: user.Email, Type: "work", Primary: true}},
+		Active:      &active,
+		Meta: &dtos.ScimMeta{
+			ResourceType: dtos.SCIM_RESOURCE_USER,
+			Created:      user.Created,
+			Location:     fmt.Sprintf("%s/Users/%s", baseUrl, user.Id),
+		},
+	}
+	for _, group := range groups {
+		if group.HasMember(user.Id) {
+			result.Groups = append(result.Groups, dtos.ScimMultiValue{
+				Value:   group.Id,
+				Display: group.Name,
+				Ref:     fmt.Sprintf("%s/Groups/%s", baseUrl, group.Id),
+			})
+		}
+	}
+	return result
+}
+
+func scimGroupFrom(group *dtos.PunqGroup, users map[string]dtos.PunqUser, baseUrl string) dtos.ScimGroup {
+	result := dtos.ScimGroup{
+		Schemas:     []string{dtos.SCIM_SCHEMA_GROUP},
+		Id:          group.Id,
+		ExternalId:  group.ExternalId,
+		DisplayName: group.Name,
+		Members:     []dtos.ScimMultiValue{},
+		Meta: &dtos.ScimMeta{
+			ResourceType: dtos.SCIM_RESOURCE_GROUP,
+			Created:      group.Created,
+			Location:     fmt.Sprintf("%s/Groups/%s", baseUrl, group.Id),
+		},
+	}
+	for _, member := range group.Members {
+		if _, isScimUser := users[member]; !isScimUser {
+			continue
+		}
+		result.Members = append(result.Members, dtos.ScimMultiValue{
+			Value:   member,
+			Display: users[member].Email,
+			Ref:     fmt.Sprintf("%s/Users/%s", baseUrl, member),
+		})
+	}
+	return result
+}
+
+// scimUserAttributes returns the email (userName) and display name of the user
+func scimUserAttributes(input dtos.ScimUser) (string, string, error) {
+	email := strings.TrimSpace(input.UserName)
+	if email == "" {
+		return "", "", scimError(http.StatusBadRequest, "invalidValue", "userName is required")
+	}
+	displayName := strings.TrimSpace(input.DisplayName)
+	if displayName == "" && input.Name != nil {
+		displayName = strings.TrimSpace(input.Name.Formatted)
+		if displayName == "" {
+			displayName = strings.TrimSpace(fmt.Sprintf("%s %s", input.Name.GivenName, input.Name.FamilyName))
+		}
+	}
+	if displayName == "" {
+		displayName = email
+	}
+	return email, displayName, nil
+}
+
+func scimCheckGroupName(id string, name string) error {
+	if strings.TrimSpace(name) == "" {
+		return scimError(http.StatusBadRequest, "invalidValue", "displayName is required")
+	}
+	groups, _ := ListGroups()
+	for _, group := range groups {
+		if group.Id != id && strings.EqualFold(group.Name, strings.TrimSpace(name)) {
+			return scimError(http.StatusConflict, "uniqueness", "group '%s' already exists", name)
+		}
+	}
+	return nil
+}
+
+// scimMemberIds returns the ids of the members which are users provisioned via SCIM
+func scimMemberIds(members []dtos.ScimMultiValue, scimUsers map[string]dtos.PunqUser) []string {
+	result := []string{}
+	for _, member := range members {
+		if _, isScimUser := scimUsers[member.Value]; isScimUser && !utils.ContainsEqual(result, member.Value) {
+			result = append(result, member.Value)
+		}
+	}
+	return result
+}
+
+// scimUsersById returns the users provisioned via SCIM
+func scimUsersById() map[string]dtos.PunqUser {
+	result := map[string]dtos.PunqUser{}
+	for _, user := range ListUsers() {
+		if user.Provider == dtos.USER_PROVIDER_SCIM {
+			result[user.Id] = user
+		}
+	}
+	return result
+}
+
+// scimUser returns the user if it was provisioned via SCIM, all other users do not exist for the SCIM client
+func scimUser(id string) (*dtos.PunqUser, error) {
+	user, err := GetUser(id)
+	if err != nil || user.Provider != dtos.USER_PROVIDER_SCIM {
+		return nil, scimError(http.StatusNotFound, "", "user '%s' not found", id)
+	}
+	return user, nil
+}
+
+// scimPatch applies the operations to the JSON representation of current and stores the result in target
+func scimPatch(current interface{}, patch dtos.ScimPatchRequest, target interface{}) error {
+	if len(patch.Operations) == 0 {
+		return scimError(http.StatusBadRequest, "invalidValue", "no operations")
+	}
+	resource, err := scimMap(current)
+	if err != nil {
+		return err
+	}
+	for _, operation := range patch.Operations {
+		err = applyScimPatch(resource, operation)
+		if err != nil {
+			return scimError(http.StatusBadRequest, "invalidPath", "%s", err.Error())
+		}
+	}
+	// e.g. {"active": "False"} of some identity providers
+	if active, ok := scimBool(resource[scimKey(resource, "active")]); ok {
+		resource[scimKey(resource, "active")] = active
+	}
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(data, target)
+	if err != nil {
+		return scimError(http.StatusBadRequest, "invalidValue", "%s", err.Error())
+	}
+	return nil
+}
+
+func scimMap(value interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]interface{}{}
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
+
+// scimList filters the resources and returns the requested page
+func scimList(resources []interface{}, filter string, startIndex int, count int) (*dtos.ScimListResponse, error) {
+	if filter != "" {
+		parsed, err := parseScimFilter(filter)
+		if err != nil {
+			return nil, scimError(http.StatusBadRequest, "invalidFilter", "%s", err.Error())
+		}
+		matching := []interface{}{}
+		for _, resource := range resources {
+			object, err := scimMap(resource)
+			if err != nil {
+				return nil, err
+			}
+			if parsed.matches(object) {
+				matching = append(matching, resource)
+			}
+		}
+		resources = matching
+	}
+
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if count < 0 || count > dtos.SCIM_MAX_RESULTS {
+		count = dtos.SCIM_MAX_RESULTS
+	}
+	result := dtos.ScimListResponse{
+		Schemas:      []string{dtos.SCIM_SCHEMA_LIST_RESPONSE},
+		TotalResults: len(resources),
+		StartIndex:   startIndex,
+		Resources:    []interface{}{},
+	}
+	if startIndex <= len(resources) {
+		end := startIndex - 1 + count
+		if end > len(resources) {
+			end = len(resources)
+		}
+		result.Resources = resources[startIndex-1 : end]
+	}
+	result.ItemsPerPage = len(result.Resources)
+	return &result, nil
+}
+
+// ScimErrorFor returns the ScimError of err, other errors are invalid values
+func ScimErrorFor(err error) *ScimError {
+	var scimErr *ScimError
+	if errors.As(err, &scimErr) {
+		return scimErr
+	}
+	return scimError(http.StatusBadRequest, "invalidValue", "%s", err.Error())
+}
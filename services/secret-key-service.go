@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sync"
+
+	"github.com/mogenius/punq/kubernetes"
+	"github.com/mogenius/punq/utils"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// length of the symmetric keys in the punq-keys secret (AES-256, HMAC-SHA256)
+const SECRET_KEY_LENGTH = 32
+
+// symmetric keys by name. They are created once and shared by all replicas through the punq-keys secret.
+var secretKeys = map[string][]byte{}
+var secretKeysMutex sync.Mutex
+
+// secretKey returns the key with the given name and creates it on first use
+func secretKey(name string) ([]byte, error) {
+	secretKeysMutex.Lock()
+	defer secretKeysMutex.Unlock()
+
+	if key, ok := secretKeys[name]; ok {
+		return key, nil
+	}
+
+	provider, err := kubernetes.NewKubeProvider(nil)
+	if err != nil {
+		return nil, err
+	}
+	secretClient := provider.ClientSet.CoreV1().Secrets(utils.CONFIG.Kubernetes.OwnNamespace)
+	var key []byte
+	// another replica may create the secret or the key in the meantime, its key wins
+	retriable := func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}
+	err = retry.OnError(retry.DefaultRetry, retriable, func() error {
+		secret, err := secretClient.Get(context.TODO(), utils.KEYSSECRET, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			newSecret := utils.InitSecret()
+			newSecret.ObjectMeta.Name = utils.KEYSSECRET
+			newSecret.ObjectMeta.Namespace = utils.CONFIG.Kubernetes.OwnNamespace
+			newSecret.StringData = map[string]string{}
+			secret, err = secretClient.Create(context.TODO(), &newSecret, kubernetes.MoCreateOptions())
+		}
+		if err != nil {
+			return err
+		}
+		if existing, ok := secret.Data[name]; ok && len(existing) == SECRET_KEY_LENGTH {
+			key = existing
+			return nil
+		}
+
+		key = make([]byte, SECRET_KEY_LENGTH)
+		if _, err := rand.Read(key); err != nil {
+			return err
+		}
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[name] = key
+		_, err = secretClient.Update(context.TODO(), secret, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load key '%s': %s", name, err.Error())
+	}
+	secretKeys[name] = key
+	return key, nil
+}
@@ -0,0 +1,369 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/kubernetes"
+	"github.com/mogenius/punq/logger"
+	"github.com/mogenius/punq/utils"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// key of the revocation list in the sessions secret, all other keys are session ids
+	SessionsRevokedKey = "revoked"
+
+	// a session ends if its refresh token is not used within this time
+	SESSION_REFRESH_TTL = time.Hour * 24 * 7
+	// revocations of other punq instances are picked up within this time
+	SESSION_REVOCATION_SYNC = 10 * time.Second
+)
+
+var ErrInvalidRefreshToken = errors.New("invalid or expired refresh token. Please log in again")
+
+// revoked session ids until all access tokens of the session have expired
+var revokedSessions = map[string]time.Time{}
+var revokedSessionsSyncedAt time.Time
+var revokedSessionsMutex sync.Mutex
+
+var sessionsMutex sync.Mutex
+
+// CreateSession starts a new session for the user and returns its access and refresh token
+func CreateSession(user *dtos.PunqUser, client dtos.PunqSessionClient) (*dtos.PunqToken, error) {
+	if user.Disabled {
+		return nil, ErrUserDisabled
+	}
+
+	sessionsMutex.Lock()
+	defer sessionsMutex.Unlock()
+
+	secret, err := sessionsSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, refreshTokenHash := newRefreshToken()
+	session := dtos.PunqSession{
+		Id:               utils.NanoId(),
+		UserId:           user.Id,
+		Provider:         user.ProviderName(),
+		UserAgent:        client.UserAgent,
+		ClientIp:         client.ClientIp,
+		Created:          time.Now(),
+		LastRefresh:      time.Now(),
+		Expires:          time.Now().Add(SESSION_REFRESH_TTL),
+		RefreshTokenHash: refreshTokenHash,
+	}
+	err = saveSession(secret, session)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := GenerateToken(user, session.Id)
+	if err != nil {
+		return nil, err
+	}
+	token.RefreshToken = fmt.Sprintf("%s.%s", session.Id, refreshToken)
+	return token, nil
+}
+
+// RefreshSession rotates the refresh token and returns a new access token with the current access level of the
+// user. Using an already rotated refresh token again ends the session (the token has probably been stolen).
+func RefreshSession(refreshToken string) (*dtos.PunqToken, error) {
+	sessionId, secretPart, found := strings.Cut(refreshToken, ".")
+	if !found || sessionId == "" || secretPart == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	sessionsMutex.Lock()
+	defer sessionsMutex.Unlock()
+
+	secret, err := sessionsSecret()
+	if err != nil {
+		return nil, err
+	}
+	session, err := sessionFrom(secret, sessionId)
+	if err != nil || time.Now().After(session.Expires) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	valid, reused := refreshTokenState(session, secretPart)
+	if reused {
+		logger.Log.Warningf("Refresh token of session '%s' (user '%s') was used twice. Revoking session.", session.Id, session.UserId)
+		revokeSessions(secret, []dtos.PunqSession{*session})
+		return nil, ErrInvalidRefreshToken
+	}
+	if !valid {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := GetUser(session.UserId)
+	if err != nil || user.Disabled {
+		revokeSessions(secret, []dtos.PunqSession{*session})
+		return nil, ErrInvalidRefreshToken
+	}
+
+	newToken, newHash := newRefreshToken()
+	session.PreviousRefreshTokenHash = session.RefreshTokenHash
+	session.RefreshTokenHash = newHash
+	session.LastRefresh = time.Now()
+	session.Expires = time.Now().Add(SESSION_REFRESH_TTL)
+	err = saveSession(secret, *session)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := GenerateToken(user, session.Id)
+	if err != nil {
+		return nil, err
+	}
+	token.RefreshToken = fmt.Sprintf("%s.%s", session.Id, newToken)
+	return token, nil
+}
+
+// ListSessions returns the active sessions of the user (without refresh token hashes), newest first
+func ListSessions(userId string) ([]dtos.PunqSession, error) {
+	secret, err := sessionsSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []dtos.PunqSession{}
+	for _, session := range allSessions(secret) {
+		if session.UserId != userId || time.Now().After(session.Expires) {
+			continue
+		}
+		session.RefreshTokenHash = ""
+		session.PreviousRefreshTokenHash = ""
+		result = append(result, session)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Created.After(result[j].Created)
+	})
+	return result, nil
+}
+
+// RevokeSession ends the session. Its refresh token becomes invalid and its access tokens are rejected.
+func RevokeSession(userId string, sessionId string) error {
+	sessionsMutex.Lock()
+	defer sessionsMutex.Unlock()
+
+	secret, err := sessionsSecret()
+	if err != nil {
+		return err
+	}
+	session, err := sessionFrom(secret, sessionId)
+	if err != nil || session.UserId != userId {
+		return fmt.Errorf("session '%s' not found", sessionId)
+	}
+	return revokeSessions(secret, []dtos.PunqSession{*session})
+}
+
+// RevokeUserSessions ends all sessions of the user (e.g. if the user has been deleted)
+func RevokeUserSessions(userId string) error {
+	sessionsMutex.Lock()
+	defer sessionsMutex.Unlock()
+
+	secret, err := sessionsSecret()
+	if err != nil {
+		return err
+	}
+	sessions := []dtos.PunqSession{}
+	for _, session := range allSessions(secret) {
+		if session.UserId == userId {
+			sessions = append(sessions, session)
+		}
+	}
+	if len(sessions) == 0 {
+		return nil
+	}
+	return revokeSessions(secret, sessions)
+}
+
+// IsSessionRevoked checks the revocation list, which is synced from the cluster every SESSION_REVOCATION_SYNC
+func IsSessionRevoked(sessionId string) bool {
+	revokedSessionsMutex.Lock()
+	defer revokedSessionsMutex.Unlock()
+
+	if time.Since(revokedSessionsSyncedAt) > SESSION_REVOCATION_SYNC {
+		secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.SESSIONSSECRET, nil)
+		if secret != nil {
+			revokedSessions = revokedFrom(secret)
+		}
+		revokedSessionsSyncedAt = time.Now()
+	}
+	_, revoked := revokedSessions[sessionId]
+	return revoked
+}
+
+func revokeSessions(secret *v1.Secret, sessions []dtos.PunqSession) error {
+	revoked := revokedFrom(secret)
+	for _, session := range sessions {
+		delete(secret.Data, session.Id)
+		// access tokens are issued until the session ends and expire AccessTokenExp later
+		revoked[session.Id] = time.Now().Add(AccessTokenExp)
+	}
+	rawRevoked, err := json.Marshal(revoked)
+	if err != nil {
+		return err
+	}
+	secret.Data[SessionsRevokedKey] = rawRevoked
+
+	err = updateSessionsSecret(secret)
+	if err != nil {
+		return err
+	}
+
+	revokedSessionsMutex.Lock()
+	for _, session := range sessions {
+		revokedSessions[session.Id] = revoked[session.Id]
+	}
+	revokedSessionsMutex.Unlock()
+	return nil
+}
+
+func sessionsSecret() (*v1.Secret, error) {
+	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.SESSIONSSECRET, nil)
+	if secret != nil {
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		return secret, nil
+	}
+
+	provider, err := kubernetes.NewKubeProvider(nil)
+	if err != nil {
+		return nil, err
+	}
+	newSecret := utils.InitSecret()
+	newSecret.ObjectMeta.Name = utils.SESSIONSSECRET
+	newSecret.ObjectMeta.Namespace = utils.CONFIG.Kubernetes.OwnNamespace
+	newSecret.StringData = map[string]string{}
+	secret, err = provider.ClientSet.CoreV1().Secrets(utils.CONFIG.Kubernetes.OwnNamespace).Create(context.TODO(), &newSecret, kubernetes.MoCreateOptions())
+	if err != nil {
+		return nil, err
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	return secret, nil
+}
+
+func saveSession(secret *v1.Secret, session dtos.PunqSession) error {
+	rawSession, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	secret.Data[session.Id] = rawSession
+	return updateSessionsSecret(secret)
+}
+
+// refreshTokenState compares the secret part of a refresh token with the current and the already rotated refresh
+// token of the session
+func refreshTokenState(session *dtos.PunqSession, secretPart string) (valid bool, reused bool) {
+	hash := hashToken(secretPart)
+	if session.PreviousRefreshTokenHash != "" && subtle.ConstantTimeCompare([]byte(hash), []byte(session.PreviousRefreshTokenHash)) == 1 {
+		return false, true
+	}
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(session.RefreshTokenHash)) == 1, false
+}
+
+// updateSessionsSecret drops expired sessions and revocations before writing the secret
+func updateSessionsSecret(secret *v1.Secret) error {
+	err := pruneSessionsSecret(secret)
+	if err != nil {
+		return err
+	}
+	workloadResult := kubernetes.UpdateK8sSecret(*secret, nil)
+	if workloadResult.Error != nil {
+		return fmt.Errorf("%v", workloadResult.Error)
+	}
+	return nil
+}
+
+func pruneSessionsSecret(secret *v1.Secret) error {
+	for _, session := range allSessions(secret) {
+		if time.Now().After(session.Expires) {
+			delete(secret.Data, session.Id)
+		}
+	}
+	revoked := revokedFrom(secret)
+	for sessionId, until := range revoked {
+		if time.Now().After(until) {
+			delete(revoked, sessionId)
+		}
+	}
+	rawRevoked, err := json.Marshal(revoked)
+	if err != nil {
+		return err
+	}
+	secret.Data[SessionsRevokedKey] = rawRevoked
+	return nil
+}
+
+func sessionFrom(secret *v1.Secret, sessionId string) (*dtos.PunqSession, error) {
+	rawSession, ok := secret.Data[sessionId]
+	if !ok || sessionId == SessionsRevokedKey {
+		return nil, fmt.Errorf("session '%s' not found", sessionId)
+	}
+	session := dtos.PunqSession{}
+	err := json.Unmarshal(rawSession, &session)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func allSessions(secret *v1.Secret) []dtos.PunqSession {
+	result := []dtos.PunqSession{}
+	for key := range secret.Data {
+		if key == SessionsRevokedKey {
+			continue
+		}
+		session, err := sessionFrom(secret, key)
+		if err != nil {
+			logger.Log.Errorf("Failed to unmarshal session '%s': %s", key, err.Error())
+			continue
+		}
+		result = append(result, *session)
+	}
+	return result
+}
+
+func revokedFrom(secret *v1.Secret) map[string]time.Time {
+	revoked := map[string]time.Time{}
+	if rawRevoked, ok := secret.Data[SessionsRevokedKey]; ok {
+		err := json.Unmarshal(rawRevoked, &revoked)
+		if err != nil {
+			logger.Log.Errorf("Failed to unmarshal revoked sessions: %s", err.Error())
+		}
+	}
+	return revoked
+}
+
+func newRefreshToken() (token string, hash string) {
+	token = utils.NanoId() + utils.NanoId()
+	return token, hashToken(token)
+}
+
+// hashToken returns the sha256 of refresh and api tokens, which are stored hashed only
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func GetGinContextSessionId(c *gin.Context) string {
+	return c.GetString("sessionId")
+}
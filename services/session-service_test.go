@@ -0,0 +1,138 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mogenius/punq/dtos"
+	v1 "k8s.io/api/core/v1"
+)
+
+// setTestJwtKeys replaces the key ring and the revocation list, so tokens can be issued and validated without a cluster
+func setTestJwtKeys(t *testing.T, keyPairs ...KeyPair) {
+	jwtKeysMutex.Lock()
+	jwtKeys = keyPairs
+	jwtKeysSyncedAt = time.Now()
+	jwtKeysMutex.Unlock()
+	revokedSessionsMutex.Lock()
+	revokedSessions = map[string]time.Time{}
+	revokedSessionsSyncedAt = time.Now().Add(time.Hour)
+	revokedSessionsMutex.Unlock()
+
+	t.Cleanup(func() {
+		jwtKeysMutex.Lock()
+		jwtKeys = nil
+		jwtKeysSyncedAt = time.Time{}
+		jwtKeysMutex.Unlock()
+		revokedSessionsMutex.Lock()
+		revokedSessions = map[string]time.Time{}
+		revokedSessionsSyncedAt = time.Time{}
+		revokedSessionsMutex.Unlock()
+	})
+}
+
+func newTestKeyPair(t *testing.T) KeyPair {
+	keyPair, err := generateAuthKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *keyPair
+}
+
+func TestValidationTokenOfRevokedSession(t *testing.T) {
+	setTestJwtKeys(t, newTestKeyPair(t))
+	user := &dtos.PunqUser{Id: "user-1", AccessLevel: dtos.ADMIN}
+
+	token, err := GenerateToken(user, "session-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherToken, err := GenerateToken(user, "session-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := ValidationToken(token.Token)
+	if err != nil || claims.UserID != user.Id || claims.SessionID != "session-1" {
+		t.Fatalf("ValidationToken() = %+v, %v, want the claims of session-1", claims, err)
+	}
+
+	revokedSessionsMutex.Lock()
+	revokedSessions["session-1"] = time.Now().Add(AccessTokenExp)
+	revokedSessionsMutex.Unlock()
+
+	if _, err := ValidationToken(token.Token); err == nil {
+		t.Errorf("ValidationToken() of a revoked session error = nil, want an error")
+	}
+	if _, err := ValidationToken(otherToken.Token); err != nil {
+		t.Errorf("ValidationToken() of another session error = %v, want nil", err)
+	}
+
+	withoutSession, err := GenerateToken(user, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ValidationToken(withoutSession.Token); err == nil {
+		t.Errorf("ValidationToken() without session error = nil, want an error")
+	}
+}
+
+func TestRefreshTokenState(t *testing.T) {
+	session := &dtos.PunqSession{RefreshTokenHash: hashToken("current"), PreviousRefreshTokenHash: hashToken("previous")}
+
+	tests := []struct {
+		name       string
+		session    *dtos.PunqSession
+		secretPart string
+		wantValid  bool
+		wantReused bool
+	}{
+		{"current token", session, "current", true, false},
+		{"rotated token used again", session, "previous", false, true},
+		{"unknown token", session, "other", false, false},
+		{"first refresh", &dtos.PunqSession{RefreshTokenHash: hashToken("current")}, "current", true, false},
+		{"empty token of a new session", &dtos.PunqSession{RefreshTokenHash: hashToken("current")}, "", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, reused := refreshTokenState(tt.session, tt.secretPart)
+			if valid != tt.wantValid || reused != tt.wantReused {
+				t.Errorf("refreshTokenState() = %t, %t, want %t, %t", valid, reused, tt.wantValid, tt.wantReused)
+			}
+		})
+	}
+}
+
+func TestPruneSessionsSecret(t *testing.T) {
+	active := dtos.PunqSession{Id: "active", UserId: "user-1", Expires: time.Now().Add(time.Hour)}
+	expired := dtos.PunqSession{Id: "expired", UserId: "user-1", Expires: time.Now().Add(-time.Second)}
+	rawActive, _ := json.Marshal(active)
+	rawExpired, _ := json.Marshal(expired)
+	rawRevoked, _ := json.Marshal(map[string]time.Time{
+		"revoked": time.Now().Add(AccessTokenExp),
+		"old":     time.Now().Add(-time.Second),
+	})
+	secret := &v1.Secret{Data: map[string][]byte{
+		active.Id:          rawActive,
+		expired.Id:         rawExpired,
+		SessionsRevokedKey: rawRevoked,
+	}}
+
+	err := pruneSessionsSecret(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessionFrom(secret, active.Id); err != nil {
+		t.Errorf("pruneSessionsSecret() dropped the active session")
+	}
+	if _, err := sessionFrom(secret, expired.Id); err == nil {
+		t.Errorf("pruneSessionsSecret() kept the expired session")
+	}
+	revoked := revokedFrom(secret)
+	if _, ok := revoked["revoked"]; !ok || len(revoked) != 1 {
+		t.Errorf("pruneSessionsSecret() revoked = %v, want only the revocation whose access tokens are still valid", revoked)
+	}
+	if _, err := sessionFrom(secret, SessionsRevokedKey); err == nil {
+		t.Errorf("sessionFrom(%s) error = nil, want the revocation list not to be a session", SessionsRevokedKey)
+	}
+}
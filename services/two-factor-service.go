@@ -0,0 +1,281 @@
+package services
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/logger"
+	"github.com/mogenius/punq/utils"
+)
+
+const (
+	TWO_FACTOR_RECOVERY_CODES = 10
+	// purpose claim of challenge tokens, which are no access tokens (they have no session)
+	twoFactorChallengePurpose = "2fa-challenge"
+)
+
+var ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+
+type twoFactorChallengeClaims struct {
+	UserID  string `json:"userId"`
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+// TwoFactorRequired returns true if the user has enrolled 2fa or has to enroll because of its access level
+func TwoFactorRequired(user *dtos.PunqUser) bool {
+	return user.HasTwoFactor() || twoFactorEnforced(user)
+}
+
+func twoFactorEnforced(user *dtos.PunqUser) bool {
+	if utils.CONFIG.TwoFactor.EnforceAccessLevel == "" {
+		return false
+	}
+	return user.AccessLevel >= dtos.AccessLevelFromString(utils.CONFIG.TwoFactor.EnforceAccessLevel)
+}
+
+// CreateTwoFactorChallenge is the result of a login with correct password if TwoFactorRequired. Users without 2fa
+// get a secret to enroll with, the enrollment is confirmed by the first code (see CompleteTwoFactorChallenge).
+func CreateTwoFactorChallenge(user *dtos.PunqUser) (*dtos.PunqTwoFactorChallenge, error) {
+	challenge := dtos.PunqTwoFactorChallenge{
+		TwoFactorRequired: true,
+		ExpiresIn:         int64(utils.CONFIG.TwoFactor.ChallengeTimeout.Seconds()),
+	}
+	if !user.HasTwoFactor() {
+		enrollment, err := StartTwoFactorEnrollment(user.Id)
+		if err != nil {
+			return nil, err
+		}
+		challenge.Enrollment = enrollment
+	}
+
+	keyPair, err := GetKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	claims := twoFactorChallengeClaims{
+		UserID:  user.Id,
+		Purpose: twoFactorChallengePurpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(utils.CONFIG.TwoFactor.ChallengeTimeout)),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodES512, claims)
+	token.Header["kid"] = keyPair.Id
+	challenge.Challenge, err = token.SignedString(keyPair.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// CompleteTwoFactorChallenge checks the code (TOTP or recovery code) and starts the session. Wrong codes count as
+// failed logins of the user.
+func CompleteTwoFactorChallenge(challenge string, code string, client dtos.PunqSessionClient) (*dtos.PunqToken, error) {
+	claims := twoFactorChallengeClaims{}
+	_, err := jwt.ParseWithClaims(challenge, &claims, jwtKeyFunc)
+	if err != nil || claims.Purpose != twoFactorChallengePurpose {
+		return nil, errors.New("invalid or expired challenge. Please log in again")
+	}
+	user, err := GetUser(claims.UserID)
+	if err != nil {
+		return nil, errors.New("invalid or expired challenge. Please log in again")
+	}
+
+	err = CheckLogin(user.Email, client.ClientIp)
+	if err != nil {
+		return nil, err
+	}
+	defer FinishLogin(user.Email, client.ClientIp)
+
+	var recoveryCodes []string
+	if user.HasTwoFactor() {
+		err = verifyTwoFactorCode(user, code, true)
+	} else {
+		recoveryCodes, err = ConfirmTwoFactorEnrollment(user.Id, code)
+	}
+	if err != nil {
+		RecordFailedLogin(user.Email, client.ClientIp)
+		return nil, err
+	}
+	RecordSuccessfulLogin(user.Email)
+
+	token, err := CreateSession(user, client)
+	if err != nil {
+		return nil, err
+	}
+	token.RecoveryCodes = recoveryCodes
+	return token, nil
+}
+
+// StartTwoFactorEnrollment stores a new secret which is activated by ConfirmTwoFactorEnrollment. A pending secret
+// is returned again, so a login can be retried after the secret has been added to the app.
+func StartTwoFactorEnrollment(userId string) (*dtos.PunqTwoFactorEnrollment, error) {
+	user, err := GetUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	if user.HasTwoFactor() {
+		return nil, errors.New("2fa is already enabled. Disable it first to enroll a new device")
+	}
+
+	if user.TwoFactor == nil || user.TwoFactor.Secret == "" {
+		secret, err := utils.NewTotpSecret()
+		if err != nil {
+			return nil, err
+		}
+		user, err = saveUserTwoFactor(userId, &dtos.PunqUserTwoFactor{Secret: secret})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &dtos.PunqTwoFactorEnrollment{
+		Secret: user.TwoFactor.Secret,
+		Uri:    utils.TotpUri(utils.CONFIG.TwoFactor.Issuer, user.Email, user.TwoFactor.Secret),
+	}, nil
+}
+
+// ConfirmTwoFactorEnrollment enables 2fa if the code matches the pending secret and returns the recovery codes
+func ConfirmTwoFactorEnrollment(userId string, code string) ([]string, error) {
+	user, err := GetUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	if user.HasTwoFactor() {
+		return nil, errors.New("2fa is already enabled")
+	}
+	if user.TwoFactor == nil || user.TwoFactor.Secret == "" {
+		return nil, errors.New("no 2fa enrollment started")
+	}
+	step, valid := utils.TotpValidate(user.TwoFactor.Secret, code, time.Now())
+	if !valid {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	recoveryCodes, hashes := newRecoveryCodes()
+	_, err = saveUserTwoFactor(userId, &dtos.PunqUserTwoFactor{
+		Enabled:            true,
+		EnrolledAt:         time.Now(),
+		Secret:             user.TwoFactor.Secret,
+		RecoveryCodeHashes: hashes,
+		LastUsedStep:       step,
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.Log.Noticef("User '%s' enabled 2fa.", user.Email)
+	return recoveryCodes, nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes of the user after checking a current code
+func RegenerateRecoveryCodes(userId string, code string) ([]string, error) {
+	user, err := GetUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	if !user.HasTwoFactor() {
+		return nil, errors.New("2fa is not enabled")
+	}
+	err = verifyTwoFactorCode(user, code, false)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, hashes := newRecoveryCodes()
+	user.TwoFactor.RecoveryCodeHashes = hashes
+	_, err = saveUserTwoFactor(userId, user.TwoFactor)
+	if err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+// DisableTwoFactor removes 2fa after checking a current code. Users who are required to use 2fa cannot disable it.
+func DisableTwoFactor(userId string, code string) error {
+	user, err := GetUser(userId)
+	if err != nil {
+		return err
+	}
+	if !user.HasTwoFactor() {
+		return errors.New("2fa is not enabled")
+	}
+	if twoFactorEnforced(user) {
+		return errors.New("2fa is required for your access level")
+	}
+	err = verifyTwoFactorCode(user, code, true)
+	if err != nil {
+		return err
+	}
+	_, err = saveUserTwoFactor(userId, nil)
+	if err == nil {
+		logger.Log.Noticef("User '%s' disabled 2fa.", user.Email)
+	}
+	return err
+}
+
+// ResetTwoFactor removes 2fa of a user who lost the device and the recovery codes (admins only). Users with
+// an enforced 2fa have to enroll again on their next login.
+func ResetTwoFactor(userId string) error {
+	user, err := GetUser(userId)
+	if err != nil {
+		return err
+	}
+	_, err = saveUserTwoFactor(userId, nil)
+	if err == nil {
+		logger.Log.Noticef("2fa of user '%s' has been reset.", user.Email)
+	}
+	return err
+}
+
+// verifyTwoFactorCode accepts an unused TOTP code or (if allowed) a recovery code, which is removed afterwards
+func verifyTwoFactorCode(user *dtos.PunqUser, code string, allowRecoveryCode bool) error {
+	twoFactor := user.TwoFactor
+	if step, valid := utils.TotpValidate(twoFactor.Secret, code, time.Now()); valid {
+		if step <= twoFactor.LastUsedStep {
+			return ErrInvalidTwoFactorCode
+		}
+		twoFactor.LastUsedStep = step
+		_, err := saveUserTwoFactor(user.Id, twoFactor)
+		return err
+	}
+
+	if allowRecoveryCode {
+		hash := hashToken(normalizeRecoveryCode(code))
+		for index, recoveryCodeHash := range twoFactor.RecoveryCodeHashes {
+			if subtle.ConstantTimeCompare([]byte(hash), []byte(recoveryCodeHash)) == 1 {
+				twoFactor.RecoveryCodeHashes = append(twoFactor.RecoveryCodeHashes[:index], twoFactor.RecoveryCodeHashes[index+1:]...)
+				logger.Log.Noticef("User '%s' used a recovery code (%d left).", user.Email, len(twoFactor.RecoveryCodeHashes))
+				_, err := saveUserTwoFactor(user.Id, twoFactor)
+				return err
+			}
+		}
+	}
+	return ErrInvalidTwoFactorCode
+}
+
+func newRecoveryCodes() (codes []string, hashes []string) {
+	for i := 0; i < TWO_FACTOR_RECOVERY_CODES; i++ {
+		id := strings.ToLower(utils.NanoId())
+		code := fmt.Sprintf("%s-%s", id[0:5], id[5:10])
+		codes = append(codes, code)
+		hashes = append(hashes, hashToken(normalizeRecoveryCode(code)))
+	}
+	return codes, hashes
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
+
+// saveUserTwoFactor writes the 2fa part of the user record (nil = disabled), which UpdateUser never changes
+func saveUserTwoFactor(userId string, twoFactor *dtos.PunqUserTwoFactor) (*dtos.PunqUser, error) {
+	return updateUserRecord(userId, func(user *dtos.PunqUser) {
+		user.TwoFactor = twoFactor
+	})
+}
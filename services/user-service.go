@@ -65,7 +65,7 @@ func CreateAdminUser() {
 
 	password := utils.NanoId()
 
-	adminUser, _ := AddUser(dtos.PunqUserCreateInput{
+	adminUser, _ := createUser(dtos.PunqUserCreateInput{
 		Email:       fmt.Sprintf("%s-%s@punq.dev", strings.ToLower(utils.RandomFirstName()), strings.ToLower(utils.RandomLastName())),
 		Password:    password,
 		DisplayName: "ADMIN USER",
@@ -108,7 +108,17 @@ func ListUsers() []dtos.PunqUser {
 	return users
 }
 
+// AddUser creates a user whose password must comply with password_policy
 func AddUser(userCreateInput dtos.PunqUserCreateInput) (*dtos.PunqUser, error) {
+	err := CheckPasswordPolicy(nil, userCreateInput.Password)
+	if err != nil {
+		return nil, err
+	}
+	return createUser(userCreateInput)
+}
+
+// createUser is used directly for generated passwords, e.g. of external or invited users
+func createUser(userCreateInput dtos.PunqUserCreateInput) (*dtos.PunqUser, error) {
 	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.USERSSECRET, nil)
 	if secret == nil {
 		return nil, errors.New(fmt.Sprintf("failed to get '%s/%s' secret", utils.CONFIG.Kubernetes.OwnNamespace, utils.USERSSECRET))
@@ -192,16 +202,28 @@ func UpdateUser(userUpdateInput dtos.PunqUser) (*dtos.PunqUser, error) {
 		}
 	}
 
+	// the history is only changed together with the password
+	userUpdateInput.PasswordHistory = user.PasswordHistory
+
 	// hash new password
 	if userUpdateInput.Password != "" && user.Password != userUpdateInput.Password {
+		err = CheckPasswordPolicy(user, userUpdateInput.Password)
+		if err != nil {
+			return nil, err
+		}
 		// hash password
 		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(userUpdateInput.Password), bcrypt.DefaultCost)
 		if err != nil {
 			return nil, errors.New(err.Error())
 		}
 		userUpdateInput.Password = string(hashedPassword)
+		userUpdateInput.PasswordHistory = passwordHistoryWith(user)
 	}
 
+	// 2fa is only changed by the enrollment and reset functions (see two-factor-service.go)
+	userUpdateInput.TwoFactor = user.TwoFactor
+	userUpdateInput.Disabled = user.Disabled
+
 	jsonData, err := json.Marshal(userUpdateInput)
 	if err != nil {
 		errStr := fmt.Sprintf("failed marshalling userCreateInput %v", err)
@@ -215,6 +237,8 @@ func UpdateUser(userUpdateInput dtos.PunqUser) (*dtos.PunqUser, error) {
 		logger.Log.Error(errStr)
 		return nil, errors.New(errStr)
 	}
+	// omitted (empty) in jsonData, so it would not be cleared by Unmarshal
+	user.PasswordHistory = userUpdateInput.PasswordHistory
 
 	rawData, err := json.Marshal(user)
 	secret.Data[userUpdateInput.Id] = rawData
@@ -246,9 +270,84 @@ func DeleteUser(id string) error {
 		// success
 		result.Result = fmt.Sprintf("User %s successfully deleted.", id)
 	}
+
+	// tokens of the user must not be accepted anymore
+	err := RevokeUserSessions(id)
+	if err != nil {
+		logger.Log.Errorf("Failed to revoke sessions of user '%s': %s", id, err.Error())
+	}
+	err = RevokeUserApiTokens(id)
+	if err != nil {
+		logger.Log.Errorf("Failed to revoke api tokens of user '%s': %s", id, err.Error())
+	}
+	err = RemoveUserFromGroups(id)
+	if err != nil {
+		logger.Log.Errorf("Failed to remove user '%s' from groups: %s", id, err.Error())
+	}
+	err = RemoveUserFromRoles(id)
+	if err != nil {
+		logger.Log.Errorf("Failed to remove user '%s' from roles: %s", id, err.Error())
+	}
 	return nil
 }
 
+// SetUserDisabled deactivates or reactivates the user. Deactivated users keep their record (e.g. for the audit
+// log), but their sessions and api tokens end and they cannot sign in anymore.
+func SetUserDisabled(userId string, disabled bool) (*dtos.PunqUser, error) {
+	if disabled {
+		admin, _ := GetAdmin()
+		if admin != nil && admin.Id == userId {
+			return nil, errors.New("admin user cannot be deactivated")
+		}
+	}
+	user, err := updateUserRecord(userId, func(user *dtos.PunqUser) {
+		user.Disabled = disabled
+	})
+	if err != nil || !disabled {
+		return user, err
+	}
+
+	err = RevokeUserSessions(userId)
+	if err != nil {
+		logger.Log.Errorf("Failed to revoke sessions of user '%s': %s", userId, err.Error())
+	}
+	err = RevokeUserApiTokens(userId)
+	if err != nil {
+		logger.Log.Errorf("Failed to revoke api tokens of user '%s': %s", userId, err.Error())
+	}
+	logger.Log.Noticef("User '%s' has been deactivated.", user.Email)
+	return user, nil
+}
+
+// updateUserRecord changes fields of the stored user which UpdateUser keeps (e.g. 2fa or disabled)
+func updateUserRecord(userId string, update func(user *dtos.PunqUser)) (*dtos.PunqUser, error) {
+	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.USERSSECRET, nil)
+	if secret == nil {
+		return nil, fmt.Errorf("failed to get '%s/%s' secret", utils.CONFIG.Kubernetes.OwnNamespace, utils.USERSSECRET)
+	}
+	rawUser, ok := secret.Data[userId]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	user := dtos.PunqUser{}
+	err := json.Unmarshal(rawUser, &user)
+	if err != nil {
+		return nil, err
+	}
+
+	update(&user)
+	rawUser, err = json.Marshal(user)
+	if err != nil {
+		return nil, err
+	}
+	secret.Data[userId] = rawUser
+	workloadResult := kubernetes.UpdateK8sSecret(*secret, nil)
+	if workloadResult.Error != nil {
+		return nil, fmt.Errorf("%v", workloadResult.Error)
+	}
+	return &user, nil
+}
+
 func GetUser(id string) (*dtos.PunqUser, error) {
 	secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.USERSSECRET, nil)
 	if secret == nil {
@@ -268,7 +367,7 @@ func GetUser(id string) (*dtos.PunqUser, error) {
 		return &user, nil
 	}
 
-	return nil, errors.New("user not found")
+	return nil, ErrUserNotFound
 }
 
 func GetUserByEmail(email string) (*dtos.PunqUser, error) {
@@ -295,7 +394,7 @@ func GetUserByEmail(email string) (*dtos.PunqUser, error) {
 		}
 	}
 
-	return nil, errors.New("user not found")
+	return nil, ErrUserNotFound
 }
 
 func GetAdmin() (*dtos.PunqUser, error) {
@@ -327,3 +426,15 @@ func GetGinContextUser(c *gin.Context) *dtos.PunqUser {
 	}
 	return nil
 }
+
+func GetGinContextAccessLevel(c *gin.Context) dtos.AccessLevel {
+	if temp, exists := c.Get("accessLevel"); exists {
+		if accessLevel, ok := temp.(dtos.AccessLevel); ok {
+			return accessLevel
+		}
+	}
+	if user := GetGinContextUser(c); user != nil {
+		return user.AccessLevel
+	}
+	return dtos.READER
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/kubernetes"
+	"github.com/mogenius/punq/logger"
+	"github.com/mogenius/punq/utils"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// websocket clients cannot set the authorization header, so they get a ticket instead of passing their token in the
+// query string (where it ends up in proxy logs, the browser history and the access log)
+const WS_TICKET_TIMEOUT = 30 * time.Second
+
+var ErrInvalidWsTicket = errors.New("invalid, expired or already used websocket ticket")
+
+type wsTicket struct {
+	UserId     string                 `json:"userId"`
+	SessionId  string                 `json:"sessionId,omitempty"`
+	ApiTokenId string                 `json:"apiTokenId,omitempty"`
+	ContextId  string                 `json:"contextId,omitempty"`
+	Target     dtos.PunqWsTicketInput `json:"target"`
+	ExpiresAt  time.Time              `json:"expiresAt"`
+}
+
+// WsTicketGrant is the result of a redeemed ticket. The credential it was issued with (SessionId or ApiToken) is
+// checked again for open connections (see CheckWsCredentials).
+type WsTicketGrant struct {
+	User      *dtos.PunqUser
+	SessionId string
+	ApiToken  *dtos.PunqApiToken
+}
+
+// tickets are stored by hash in a secret, so a ticket issued by one replica can be redeemed by another
+var wsTicketsMutex sync.Mutex
+
+// CreateWsTicket issues a ticket for the target. sessionId or apiTokenId is the credential of the ticket request,
+// the ticket becomes invalid with it.
+func CreateWsTicket(userId string, sessionId string, apiTokenId string, contextId string, target dtos.PunqWsTicketInput) (*dtos.PunqWsTicket, error) {
+	ticket, err := newWsTicket(userId, sessionId, apiTokenId, contextId, target)
+	if err != nil {
+		return nil, err
+	}
+	rawTicket, err := json.Marshal(ticket)
+	if err != nil {
+		return nil, err
+	}
+
+	value, hash := newRefreshToken()
+	err = updateWsTicketsSecret(func(secret *v1.Secret) bool {
+		secret.Data[hash] = rawTicket
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dtos.PunqWsTicket{Ticket: value, ExpiresAt: ticket.ExpiresAt}, nil
+}
+
+// RedeemWsTicket removes the ticket and returns its user (and the credential it was issued with) if the ticket was
+// issued for the requested context and target. A ticket is consumed by the first attempt, also by a failed one.
+func RedeemWsTicket(value string, contextId string, target dtos.PunqWsTicketInput) (*WsTicketGrant, error) {
+	var ticket *wsTicket
+	err := updateWsTicketsSecret(func(secret *v1.Secret) bool {
+		ticket = takeWsTicket(secret, hashToken(value))
+		return ticket != nil
+	})
+	if err != nil {
+		logger.Log.Errorf("Failed to redeem websocket ticket: %s", err.Error())
+		return nil, ErrInvalidWsTicket
+	}
+
+	err = checkWsTicket(ticket, contextId, target)
+	if err != nil {
+		return nil, err
+	}
+	grant, err := CheckWsCredentials(ticket.UserId, ticket.SessionId, ticket.ApiTokenId)
+	if err != nil {
+		return nil, err
+	}
+	if grant.ApiToken != nil {
+		err = checkWsTicketApiToken(*ticket, grant.ApiToken, contextId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return grant, nil
+}
+
+// CheckWsCredentials returns an error if the session has been revoked, the api token has been deleted or has
+// expired, or the user has been disabled. It is checked on redemption and periodically for open connections.
+func CheckWsCredentials(userId string, sessionId string, apiTokenId string) (*WsTicketGrant, error) {
+	if sessionId != "" && IsSessionRevoked(sessionId) {
+		return nil, ErrInvalidRefreshToken
+	}
+	var token *dtos.PunqApiToken
+	if apiTokenId != "" {
+		secret := kubernetes.SecretFor(utils.CONFIG.Kubernetes.OwnNamespace, utils.APITOKENSSECRET, nil)
+		if secret == nil {
+			return nil, ErrInvalidApiToken
+		}
+		var err error
+		token, err = apiTokenFrom(secret, apiTokenId)
+		if err != nil || token.IsExpired() || token.UserId != userId {
+			return nil, ErrInvalidApiToken
+		}
+	}
+
+	user, err := GetUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	if user.Disabled {
+		return nil, ErrUserDisabled
+	}
+	return &WsTicketGrant{User: user, SessionId: sessionId, ApiToken: token}, nil
+}
+
+// newWsTicket validates the target and returns the ticket to store
+func newWsTicket(userId string, sessionId string, apiTokenId string, contextId string, target dtos.PunqWsTicketInput) (*wsTicket, error) {
+	if target.PodName != "" && (target.Namespace == "" || target.Container == "") {
+		return nil, fmt.Errorf("namespace and container are required for a ticket of pod '%s'", target.PodName)
+	}
+	target = normalizeWsTicketTarget(target)
+	if target.Mode != "" && target.Mode != kubernetes.EXEC_MODE_EXEC && target.Mode != kubernetes.EXEC_MODE_ATTACH && target.Mode != kubernetes.EXEC_MODE_DEBUG {
+		return nil, fmt.Errorf("mode must be one of: %s, %s, %s", kubernetes.EXEC_MODE_EXEC, kubernetes.EXEC_MODE_ATTACH, kubernetes.EXEC_MODE_DEBUG)
+	}
+	if target.PodName == "" {
+		// watch subscriptions are authorized one by one for their own context
+		contextId = ""
+	}
+	return &wsTicket{
+		UserId:     userId,
+		SessionId:  sessionId,
+		ApiTokenId: apiTokenId,
+		ContextId:  contextId,
+		Target:     target,
+		ExpiresAt:  time.Now().Add(WS_TICKET_TIMEOUT),
+	}, nil
+}
+
+// checkWsTicket rejects unknown and expired tickets and tickets of another target
+func checkWsTicket(ticket *wsTicket, contextId string, target dtos.PunqWsTicketInput) error {
+	if ticket == nil || time.Now().After(ticket.ExpiresAt) {
+		return ErrInvalidWsTicket
+	}
+	if ticket.Target != normalizeWsTicketTarget(target) || ticket.Target.PodName != "" && ticket.ContextId != contextId {
+		return fmt.Errorf("websocket ticket was issued for another target")
+	}
+	return nil
+}
+
+// takeWsTicket removes the ticket from the secret and returns it (nil = unknown ticket)
+func takeWsTicket(secret *v1.Secret, hash string) *wsTicket {
+	rawTicket, ok := secret.Data[hash]
+	if !ok {
+		return nil
+	}
+	delete(secret.Data, hash)
+	ticket := wsTicket{}
+	err := json.Unmarshal(rawTicket, &ticket)
+	if err != nil {
+		logger.Log.Errorf("Failed to unmarshal websocket ticket: %s", err.Error())
+		return nil
+	}
+	return &ticket
+}
+
+// dropExpiredWsTickets removes the tickets which have not been redeemed in time
+func dropExpiredWsTickets(secret *v1.Secret) {
+	for key, rawTicket := range secret.Data {
+		ticket := wsTicket{}
+		err := json.Unmarshal(rawTicket, &ticket)
+		if err != nil || time.Now().After(ticket.ExpiresAt) {
+			delete(secret.Data, key)
+		}
+	}
+}
+
+// updateWsTicketsSecret applies change to the current secret and writes it if change returns true. The update is
+// rejected if another replica changed the secret in the meantime, so a ticket can only be taken once.
+func updateWsTicketsSecret(change func(secret *v1.Secret) bool) error {
+	wsTicketsMutex.Lock()
+	defer wsTicketsMutex.Unlock()
+
+	provider, err := kubernetes.NewKubeProvider(nil)
+	if err != nil {
+		return err
+	}
+	secretClient := provider.ClientSet.CoreV1().Secrets(utils.CONFIG.Kubernetes.OwnNamespace)
+	// another replica may have created the secret in the meantime as well
+	retriable := func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}
+	return retry.OnError(retry.DefaultRetry, retriable, func() error {
+		secret, err := secretClient.Get(context.TODO(), utils.WSTICKETSSECRET, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			newSecret := utils.InitSecret()
+			newSecret.ObjectMeta.Name = utils.WSTICKETSSECRET
+			newSecret.ObjectMeta.Namespace = utils.CONFIG.Kubernetes.OwnNamespace
+			newSecret.StringData = map[string]string{}
+			secret, err = secretClient.Create(context.TODO(), &newSecret, kubernetes.MoCreateOptions())
+		}
+		if err != nil {
+			return err
+		}
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		if !change(secret) {
+			return nil
+		}
+		dropExpiredWsTickets(secret)
+		_, err = secretClient.Update(context.TODO(), secret, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// normalizeWsTicketTarget drops everything but the pod of watch tickets, defaults the mode and drops the image
+// outside of debug sessions, so tickets and requests can be compared
+func normalizeWsTicketTarget(target dtos.PunqWsTicketInput) dtos.PunqWsTicketInput {
+	if target.PodName == "" {
+		return dtos.PunqWsTicketInput{}
+	}
+	if target.Mode == "" {
+		target.Mode = kubernetes.EXEC_MODE_EXEC
+	}
+	if target.Mode != kubernetes.EXEC_MODE_DEBUG {
+		target.Image = ""
+	}
+	return target
+}
+
+// the scope of an api token is checked on redemption, because only then the target is known. Watch subscriptions
+// choose context and namespace later, they are checked one by one against the returned token.
+func checkWsTicketApiToken(ticket wsTicket, token *dtos.PunqApiToken, contextId string) error {
+	if ticket.Target.PodName == "" {
+		if !utils.ContainsEqual(token.Verbs, dtos.API_TOKEN_VERB_GET) {
+			return fmt.Errorf("api token '%s' does not allow '%s'", token.Name, dtos.API_TOKEN_VERB_GET)
+		}
+		return nil
+	}
+	return token.Allows(dtos.API_TOKEN_VERB_EXEC, contextId, ticket.Target.Namespace)
+}
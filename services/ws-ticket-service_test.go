@@ -0,0 +1,137 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mogenius/punq/dtos"
+	"github.com/mogenius/punq/kubernetes"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestNormalizeWsTicketTarget(t *testing.T) {
+	tests := []struct {
+		name   string
+		target dtos.PunqWsTicketInput
+		want   dtos.PunqWsTicketInput
+	}{
+		{"watch", dtos.PunqWsTicketInput{Namespace: "team-a", Mode: kubernetes.EXEC_MODE_DEBUG}, dtos.PunqWsTicketInput{}},
+		{"default mode", dtos.PunqWsTicketInput{Namespace: "team-a", PodName: "web", Container: "app"}, dtos.PunqWsTicketInput{Namespace: "team-a", PodName: "web", Container: "app", Mode: kubernetes.EXEC_MODE_EXEC}},
+		{"image outside of debug sessions", dtos.PunqWsTicketInput{Namespace: "team-a", PodName: "web", Container: "app", Mode: kubernetes.EXEC_MODE_ATTACH, Image: "busybox"}, dtos.PunqWsTicketInput{Namespace: "team-a", PodName: "web", Container: "app", Mode: kubernetes.EXEC_MODE_ATTACH}},
+		{"debug image", dtos.PunqWsTicketInput{Namespace: "team-a", PodName: "web", Container: "app", Mode: kubernetes.EXEC_MODE_DEBUG, Image: "busybox"}, dtos.PunqWsTicketInput{Namespace: "team-a", PodName: "web", Container: "app", Mode: kubernetes.EXEC_MODE_DEBUG, Image: "busybox"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeWsTicketTarget(tt.target); got != tt.want {
+				t.Errorf("normalizeWsTicketTarget() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewWsTicketValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  dtos.PunqWsTicketInput
+		wantErr bool
+	}{
+		{"watch", dtos.PunqWsTicketInput{}, false},
+		{"exec", dtos.PunqWsTicketInput{Namespace: "team-a", PodName: "web", Container: "app"}, false},
+		{"debug", dtos.PunqWsTicketInput{Namespace: "team-a", PodName: "web", Container: "app", Mode: kubernetes.EXEC_MODE_DEBUG, Image: "busybox"}, false},
+		{"missing namespace", dtos.PunqWsTicketInput{PodName: "web", Container: "app"}, true},
+		{"missing container", dtos.PunqWsTicketInput{Namespace: "team-a", PodName: "web"}, true},
+		{"invalid mode", dtos.PunqWsTicketInput{Namespace: "team-a", PodName: "web", Container: "app", Mode: "port-forward"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticket, err := newWsTicket("user", "", "", "ctx", tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newWsTicket() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if err == nil && !ticket.ExpiresAt.After(time.Now()) {
+				t.Errorf("newWsTicket() = %+v, want a valid ticket", ticket)
+			}
+		})
+	}
+}
+
+func TestCheckWsTicket(t *testing.T) {
+	target := dtos.PunqWsTicketInput{Namespace: "team-a", PodName: "web", Container: "app"}
+	ticket, err := newWsTicket("user", "", "", "ctx", target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	watchTicket, err := newWsTicket("user", "", "", "ctx", dtos.PunqWsTicketInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired := *ticket
+	expired.ExpiresAt = time.Now().Add(-time.Second)
+
+	tests := []struct {
+		name        string
+		ticket      *wsTicket
+		contextId   string
+		target      dtos.PunqWsTicketInput
+		wantErr     bool
+		wantInvalid bool
+	}{
+		{"same target", ticket, "ctx", target, false, false},
+		{"default mode", ticket, "ctx", dtos.PunqWsTicketInput{Namespace: "team-a", PodName: "web", Container: "app", Mode: kubernetes.EXEC_MODE_EXEC}, false, false},
+		{"watch of any context", watchTicket, "other", dtos.PunqWsTicketInput{}, false, false},
+		{"other pod", ticket, "ctx", dtos.PunqWsTicketInput{Namespace: "team-a", PodName: "db", Container: "app"}, true, false},
+		{"other namespace", ticket, "ctx", dtos.PunqWsTicketInput{Namespace: "team-b", PodName: "web", Container: "app"}, true, false},
+		{"other container", ticket, "ctx", dtos.PunqWsTicketInput{Namespace: "team-a", PodName: "web", Container: "sidecar"}, true, false},
+		{"other mode", ticket, "ctx", dtos.PunqWsTicketInput{Namespace: "team-a", PodName: "web", Container: "app", Mode: kubernetes.EXEC_MODE_DEBUG}, true, false},
+		{"other context", ticket, "other", target, true, false},
+		{"watch", ticket, "ctx", dtos.PunqWsTicketInput{}, true, false},
+		{"unknown ticket", nil, "ctx", target, true, true},
+		{"expired ticket", &expired, "ctx", target, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkWsTicket(tt.ticket, tt.contextId, tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkWsTicket() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrInvalidWsTicket) != tt.wantInvalid {
+				t.Errorf("checkWsTicket() error = %v, want invalid %t", err, tt.wantInvalid)
+			}
+		})
+	}
+}
+
+// tickets are shared by all replicas through the secret, taking one removes it
+func TestTakeWsTicket(t *testing.T) {
+	ticket, err := newWsTicket("user", "session", "", "ctx", dtos.PunqWsTicketInput{Namespace: "team-a", PodName: "web", Container: "app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired := *ticket
+	expired.ExpiresAt = time.Now().Add(-time.Second)
+	rawTicket, _ := json.Marshal(ticket)
+	rawExpired, _ := json.Marshal(expired)
+	secret := &v1.Secret{Data: map[string][]byte{
+		hashToken("valid"):   rawTicket,
+		hashToken("expired"): rawExpired,
+		hashToken("broken"):  []byte("{"),
+	}}
+
+	dropExpiredWsTickets(secret)
+	if len(secret.Data) != 1 {
+		t.Fatalf("dropExpiredWsTickets() kept %d tickets, want 1", len(secret.Data))
+	}
+
+	got := takeWsTicket(secret, hashToken("valid"))
+	if got == nil || got.SessionId != ticket.SessionId || got.Target != ticket.Target || !got.ExpiresAt.Equal(ticket.ExpiresAt) {
+		t.Fatalf("takeWsTicket() = %+v, want %+v", got, ticket)
+	}
+	if got := takeWsTicket(secret, hashToken("valid")); got != nil {
+		t.Errorf("takeWsTicket() a second time = %+v, want nil", got)
+	}
+	if got := takeWsTicket(secret, hashToken("unknown")); got != nil {
+		t.Errorf("takeWsTicket() of an unknown ticket = %+v, want nil", got)
+	}
+}
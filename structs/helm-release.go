@@ -0,0 +1,71 @@
+package structs
+
+import "time"
+
+// HelmRelease is the subset of the release object helm stores (gzipped json) in "sh.helm.release.v1.<name>.v<revision>" secrets
+type HelmRelease struct {
+	Name      string                 `json:"name"`
+	Namespace string                 `json:"namespace"`
+	Version   int                    `json:"version"`
+	Info      HelmReleaseInfo        `json:"info"`
+	Chart     HelmReleaseChart       `json:"chart"`
+	Config    map[string]interface{} `json:"config,omitempty"`
+	Manifest  string                 `json:"manifest,omitempty"`
+}
+
+type HelmReleaseInfo struct {
+	FirstDeployed time.Time `json:"first_deployed,omitempty"`
+	LastDeployed  time.Time `json:"last_deployed,omitempty"`
+	Deleted       time.Time `json:"deleted,omitempty"`
+	Description   string    `json:"description,omitempty"`
+	Status        string    `json:"status,omitempty"`
+	Notes         string    `json:"notes,omitempty"`
+}
+
+type HelmReleaseChart struct {
+	Metadata HelmReleaseChartMetadata `json:"metadata"`
+	Values   map[string]interface{}   `json:"values,omitempty"`
+}
+
+type HelmReleaseChartMetadata struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	AppVersion  string `json:"appVersion,omitempty"`
+	Description string `json:"description,omitempty"`
+	Icon        string `json:"icon,omitempty"`
+}
+
+type HelmReleaseSummary struct {
+	Name         string    `json:"name"`
+	Namespace    string    `json:"namespace"`
+	Revision     int       `json:"revision"`
+	Status       string    `json:"status"`
+	Chart        string    `json:"chart"`
+	ChartVersion string    `json:"chartVersion"`
+	AppVersion   string    `json:"appVersion"`
+	Description  string    `json:"description"`
+	Updated      time.Time `json:"updated"`
+}
+
+type HelmReleaseDiff struct {
+	Name         string `json:"name"`
+	Namespace    string `json:"namespace"`
+	FromRevision int    `json:"fromRevision"`
+	ToRevision   int    `json:"toRevision"`
+	Values       string `json:"values"`
+	Manifest     string `json:"manifest"`
+}
+
+func (r HelmRelease) Summary() HelmReleaseSummary {
+	return HelmReleaseSummary{
+		Name:         r.Name,
+		Namespace:    r.Namespace,
+		Revision:     r.Version,
+		Status:       r.Info.Status,
+		Chart:        r.Chart.Metadata.Name,
+		ChartVersion: r.Chart.Metadata.Version,
+		AppVersion:   r.Chart.Metadata.AppVersion,
+		Description:  r.Info.Description,
+		Updated:      r.Info.LastDeployed,
+	}
+}
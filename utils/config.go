@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/glamour"
 	"golang.org/x/term"
@@ -22,6 +23,14 @@ const USERSSECRET = "punq-users"
 const JWTSECRET = "punq-jwt"
 const USERADMIN = "admin"
 const CONTEXTSSECRET = "punq-contexts"
+const AUDITSECRET = "punq-audit"
+const SESSIONSSECRET = "punq-sessions"
+const APITOKENSSECRET = "punq-api-tokens"
+const LOGINATTEMPTSSECRET = "punq-login-attempts"
+const WSTICKETSSECRET = "punq-ws-tickets"
+const KEYSSECRET = "punq-keys"
+const GROUPSSECRET = "punq-groups"
+const ROLESSECRET = "punq-roles"
 const CONTEXTOWN = "own-context"
 
 // This object will initially created in secrets when the software is installed into the cluster for the first time (resource: secret -> mogenius/mogenius)
@@ -40,18 +49,96 @@ type Config struct {
 		Port int    `yaml:"port" env:"frontend_port" env-description:"Port of the frontend server."`
 	} `yaml:"frontend"`
 	Backend struct {
-		Host string `yaml:"host" env:"backend_host" env-description:"Host of the backend server."`
-		Port int    `yaml:"port" env:"backend_port" env-description:"Port of the backend server."`
+		Host           string   `yaml:"host" env:"backend_host" env-description:"Host of the backend server."`
+		Port           int      `yaml:"port" env:"backend_port" env-description:"Port of the backend server."`
+		TrustedProxies []string `yaml:"trusted_proxies" env:"backend_trusted_proxies" env-description:"IP addresses or CIDRs of the reverse proxies (e.g. the ingress controller) whose X-Forwarded-For header is trusted. If empty, the client IP is the address of the connection. It is used for login throttling and sessions."`
 	} `yaml:"backend"`
 	Websocket struct {
-		Host string `yaml:"host" env:"websocket_host" env-description:"Host of the websocket server."`
-		Port int    `yaml:"port" env:"websocket_port" env-description:"Port of the websocket server."`
+		Host              string   `yaml:"host" env:"websocket_host" env-description:"Host of the websocket server."`
+		Port              int      `yaml:"port" env:"websocket_port" env-description:"Port of the websocket server."`
+		AllowedOrigins    []string `yaml:"allowed_origins" env:"websocket_allowed_origins" env-description:"Origins (scheme://host:port) of the pages which may open websockets. Same-origin requests and clients without Origin header (e.g. CLIs) are always accepted. If empty, only the frontend (http://<frontend host>:<frontend port>) is allowed. * allows all origins."`
+		MaxWatchesPerUser int      `yaml:"max_watches_per_user" env:"websocket_max_watches_per_user" env-description:"Maximum number of watch subscriptions of one user over all connections (0 = unlimited)."`
 	} `yaml:"websocket"`
 	Kubernetes struct {
 		ClusterName  string `yaml:"cluster_name" env:"cluster_name" env-description:"The Name of the Kubernetes Cluster"`
 		OwnNamespace string `yaml:"own_namespace" env:"OWN_NAMESPACE" env-description:"The Namespace of mogenius platform"`
 		RunInCluster bool   `yaml:"run_in_cluster" env:"run_in_cluster" env-description:"If set to true, the application will run in the cluster (using the service account token). Otherwise it will try to load your local default context." env-default:"false"`
 	} `yaml:"kubernetes"`
+	Login struct {
+		MaxFailures     int           `yaml:"max_failures" env:"login_max_failures" env-description:"Failed logins of an account until it is locked." env-default:"5"`
+		IpMaxFailures   int           `yaml:"ip_max_failures" env:"login_ip_max_failures" env-description:"Failed logins from an IP address until it is locked." env-default:"50"`
+		LockoutDuration time.Duration `yaml:"lockout_duration" env:"login_lockout_duration" env-description:"Time an account or IP address stays locked. Failures are also forgotten after this time without further failures." env-default:"15m"`
+		BackoffBase     time.Duration `yaml:"backoff_base" env:"login_backoff_base" env-description:"Delay after the first failed login, doubled with every further failure." env-default:"1s"`
+		BackoffMax      time.Duration `yaml:"backoff_max" env:"login_backoff_max" env-description:"Maximum delay between failed logins." env-default:"30s"`
+	} `yaml:"login"`
+	PasswordPolicy struct {
+		MinLength    int    `yaml:"min_length" env:"password_min_length" env-description:"Minimum length of passwords of local users." env-default:"12"`
+		DenylistFile string `yaml:"denylist_file" env:"password_denylist_file" env-description:"File with passwords which must not be used, one per line (e.g. a list of breached passwords). Lines can also be SHA-1 hashes (optionally followed by :count). Only the first million entries are used, so lists should be sorted by frequency. If empty, no denylist is used."`
+		HistorySize  int    `yaml:"history_size" env:"password_history_size" env-description:"Number of previous passwords which cannot be used again. 0 only rejects the current password."`
+	} `yaml:"password_policy"`
+	TwoFactor struct {
+		Issuer             string        `yaml:"issuer" env:"two_factor_issuer" env-description:"Name of punq in authenticator apps." env-default:"punq"`
+		EnforceAccessLevel string        `yaml:"enforce_access_level" env:"two_factor_enforce_access_level" env-description:"Users with this access level or higher (READER, USER or ADMIN) must enroll TOTP on their next login via /auth/login. If empty, 2FA is optional."`
+		ChallengeTimeout   time.Duration `yaml:"challenge_timeout" env:"two_factor_challenge_timeout" env-description:"Time to enter the code after the password has been accepted." env-default:"5m"`
+	} `yaml:"two_factor"`
+	Smtp struct {
+		Host        string `yaml:"host" env:"smtp_host" env-description:"Host of the SMTP relay used for invitations and password resets. If empty, the links are printed to the log instead."`
+		Port        int    `yaml:"port" env:"smtp_port" env-description:"Port of the SMTP relay." env-default:"587"`
+		Username    string `yaml:"username" env:"smtp_username" env-description:"Username for SMTP authentication. If empty, mails are sent without authentication."`
+		Password    string `yaml:"password" env:"smtp_password" env-description:"Password for SMTP authentication."`
+		From        string `yaml:"from" env:"smtp_from" env-description:"Sender address of all mails." env-default:"punq@localhost"`
+		ImplicitTls bool   `yaml:"implicit_tls" env:"smtp_implicit_tls" env-description:"If set to true, the connection uses TLS from the start (usually port 465). Otherwise STARTTLS is used if the relay offers it."`
+	} `yaml:"smtp"`
+	Invite struct {
+		LinkUrl       string        `yaml:"link_url" env:"invite_link_url" env-description:"Page of the frontend where users set their password. The one-time token is appended as ?token=... If empty, http://<frontend host>:<frontend port>/set-password is used."`
+		InviteTimeout time.Duration `yaml:"invite_timeout" env:"invite_timeout" env-description:"Time an invitation link stays valid." env-default:"72h"`
+		ResetTimeout  time.Duration `yaml:"reset_timeout" env:"invite_reset_timeout" env-description:"Time a password reset link stays valid." env-default:"1h"`
+	} `yaml:"invite"`
+	Oidc struct {
+		Enabled              bool     `yaml:"enabled" env:"oidc_enabled" env-description:"If set to true, users can sign in with an OpenID Connect provider." env-default:"false"`
+		IssuerUrl            string   `yaml:"issuer_url" env:"oidc_issuer_url" env-description:"Issuer URL of the OpenID Connect provider (must serve /.well-known/openid-configuration)."`
+		ClientId             string   `yaml:"client_id" env:"oidc_client_id" env-description:"Client ID of punq at the provider."`
+		ClientSecret         string   `yaml:"client_secret" env:"oidc_client_secret" env-description:"Client secret of punq at the provider. Can be empty for public clients (PKCE is always used)."`
+		RedirectUrl          string   `yaml:"redirect_url" env:"oidc_redirect_url" env-description:"Callback URL registered at the provider, e.g. https://punq.example.com/backend/auth/oidc/callback."`
+		FrontendUrl          string   `yaml:"frontend_url" env:"oidc_frontend_url" env-description:"After a successful login the browser is redirected to this URL with the punq token in the fragment (#token=...). If empty, the token is returned as JSON."`
+		Scopes               []string `yaml:"scopes" env:"oidc_scopes" env-description:"Requested scopes." env-default:"openid,profile,email,groups"`
+		GroupsClaim          string   `yaml:"groups_claim" env:"oidc_groups_claim" env-description:"ID token claim containing the groups of the user." env-default:"groups"`
+		AdminGroups          []string `yaml:"admin_groups" env:"oidc_admin_groups" env-description:"Members of these groups get the access level ADMIN."`
+		UserGroups           []string `yaml:"user_groups" env:"oidc_user_groups" env-description:"Members of these groups get the access level USER."`
+		ReaderGroups         []string `yaml:"reader_groups" env:"oidc_reader_groups" env-description:"Members of these groups get the access level READER."`
+		DefaultAccessLevel   string   `yaml:"default_access_level" env:"oidc_default_access_level" env-description:"Access level of users without a matching group (READER, USER or ADMIN). If empty, these users cannot sign in."`
+		AutoProvision        bool     `yaml:"auto_provision" env:"oidc_auto_provision" env-description:"If set to true, a punq user is created on the first login. Otherwise the user must already exist (matched by email)."`
+		AllowUnverifiedEmail bool     `yaml:"allow_unverified_email" env:"oidc_allow_unverified_email" env-description:"If set to true, ID tokens without email_verified=true are accepted. Only enable it for providers which verify all emails but do not send the claim."`
+	} `yaml:"oidc"`
+	Ldap struct {
+		Enabled              bool     `yaml:"enabled" env:"ldap_enabled" env-description:"If set to true, /auth/login also authenticates users against an LDAP directory (e.g. Active Directory)." env-default:"false"`
+		Url                  string   `yaml:"url" env:"ldap_url" env-description:"URL of the directory, e.g. ldaps://ad.example.com:636 or ldap://ad.example.com:389."`
+		StartTls             bool     `yaml:"start_tls" env:"ldap_start_tls" env-description:"If set to true, the connection to a ldap:// URL is upgraded with StartTLS."`
+		CaFile               string   `yaml:"ca_file" env:"ldap_ca_file" env-description:"PEM file with the CA certificates of the directory. If empty, the system CAs are used."`
+		InsecureSkipVerify   bool     `yaml:"insecure_skip_verify" env:"ldap_insecure_skip_verify" env-description:"If set to true, the certificate of the directory is not verified (testing only)."`
+		BindDn               string   `yaml:"bind_dn" env:"ldap_bind_dn" env-description:"DN of the service account used to search users. If empty, the search is done anonymously."`
+		BindPassword         string   `yaml:"bind_password" env:"ldap_bind_password" env-description:"Password of the service account."`
+		BaseDn               string   `yaml:"base_dn" env:"ldap_base_dn" env-description:"Users are searched below this DN."`
+		UserFilter           string   `yaml:"user_filter" env:"ldap_user_filter" env-description:"Filter to find the user. Every %s is replaced by the (escaped) login." env-default:"(|(mail=%s)(sAMAccountName=%s)(uid=%s))"`
+		EmailAttribute       string   `yaml:"email_attribute" env:"ldap_email_attribute" env-description:"Attribute containing the email of the user (used to match punq users)." env-default:"mail"`
+		DisplayNameAttribute string   `yaml:"display_name_attribute" env:"ldap_display_name_attribute" env-description:"Attribute containing the display name of the user." env-default:"displayName"`
+		GroupAttribute       string   `yaml:"group_attribute" env:"ldap_group_attribute" env-description:"Attribute of the user containing the DNs of its groups." env-default:"memberOf"`
+		AdminGroups          []string `yaml:"admin_groups" env:"ldap_admin_groups" env-description:"Members of these group DNs get the access level ADMIN."`
+		UserGroups           []string `yaml:"user_groups" env:"ldap_user_groups" env-description:"Members of these group DNs get the access level USER."`
+		ReaderGroups         []string `yaml:"reader_groups" env:"ldap_reader_groups" env-description:"Members of these group DNs get the access level READER."`
+		DefaultAccessLevel   string   `yaml:"default_access_level" env:"ldap_default_access_level" env-description:"Access level of users without a matching group (READER, USER or ADMIN). If empty, these users cannot sign in."`
+		AutoProvision        bool     `yaml:"auto_provision" env:"ldap_auto_provision" env-description:"If set to true, a punq user is created on the first login. Otherwise the user must already exist (matched by email)."`
+	} `yaml:"ldap"`
+	Scim struct {
+		Enabled            bool   `yaml:"enabled" env:"scim_enabled" env-description:"If set to true, identity providers can provision users and groups via SCIM 2.0 at /scim/v2." env-default:"false"`
+		Token              string `yaml:"token" env:"scim_token" env-description:"Bearer token of the identity provider for /scim/v2 (e.g. openssl rand -hex 32). SCIM is unavailable while it is empty."`
+		DefaultAccessLevel string `yaml:"default_access_level" env:"scim_default_access_level" env-description:"Access level of users created via SCIM (READER, USER or ADMIN). More rights can be granted with groups and roles." env-default:"READER"`
+		DeleteUsers        bool   `yaml:"delete_users" env:"scim_delete_users" env-description:"If set to true, DELETE /scim/v2/Users/{id} deletes the user. Otherwise the user is deactivated, so its audit history keeps a valid reference." env-default:"false"`
+	} `yaml:"scim"`
+	ContextHealth struct {
+		Interval time.Duration `yaml:"interval" env:"context_health_interval" env-description:"Time between two health probes of all contexts by the operator. 0 disables the monitor."`
+		Timeout  time.Duration `yaml:"timeout" env:"context_health_timeout" env-description:"Maximum duration of a probe. A context which does not answer in time is unreachable." env-default:"10s"`
+	} `yaml:"context_health"`
 	Misc struct {
 		Stage            string   `yaml:"stage" env:"stage" env-description:"mogenius k8s-manager stage" env-default:"prod"`
 		Debug            bool     `yaml:"debug" env:"debug" env-description:"If set to true, debug features will be enabled." env-default:"false"`
@@ -137,12 +224,75 @@ func PrintSettings() {
 	fmt.Printf("\nBackend\n")
 	fmt.Printf("Host:                     %s\n", CONFIG.Backend.Host)
 	fmt.Printf("Port:                     %d\n", CONFIG.Backend.Port)
+	fmt.Printf("TrustedProxies:           %s\n", strings.Join(CONFIG.Backend.TrustedProxies, ", "))
+
+	fmt.Printf("\nWebsocket\n")
+	fmt.Printf("Host:                     %s\n", CONFIG.Websocket.Host)
+	fmt.Printf("Port:                     %d\n", CONFIG.Websocket.Port)
+	fmt.Printf("AllowedOrigins:           %s\n", strings.Join(CONFIG.Websocket.AllowedOrigins, ", "))
+	fmt.Printf("MaxWatchesPerUser:        %d\n", CONFIG.Websocket.MaxWatchesPerUser)
 
 	fmt.Printf("\nKUBERNETES\n")
 	fmt.Printf("ClusterName:              %s\n", CONFIG.Kubernetes.ClusterName)
 	fmt.Printf("OwnNamespace:             %s\n", CONFIG.Kubernetes.OwnNamespace)
 	fmt.Printf("RunInCluster:             %t\n", CONFIG.Kubernetes.RunInCluster)
 
+	fmt.Printf("\nLOGIN\n")
+	fmt.Printf("MaxFailures:              %d\n", CONFIG.Login.MaxFailures)
+	fmt.Printf("IpMaxFailures:            %d\n", CONFIG.Login.IpMaxFailures)
+	fmt.Printf("LockoutDuration:          %s\n", CONFIG.Login.LockoutDuration)
+	fmt.Printf("BackoffBase:              %s\n", CONFIG.Login.BackoffBase)
+	fmt.Printf("BackoffMax:               %s\n", CONFIG.Login.BackoffMax)
+
+	fmt.Printf("\nPASSWORD POLICY\n")
+	fmt.Printf("MinLength:                %d\n", CONFIG.PasswordPolicy.MinLength)
+	fmt.Printf("DenylistFile:             %s\n", CONFIG.PasswordPolicy.DenylistFile)
+	fmt.Printf("HistorySize:              %d\n", CONFIG.PasswordPolicy.HistorySize)
+
+	fmt.Printf("\nTWO FACTOR\n")
+	fmt.Printf("Issuer:                   %s\n", CONFIG.TwoFactor.Issuer)
+	fmt.Printf("EnforceAccessLevel:       %s\n", CONFIG.TwoFactor.EnforceAccessLevel)
+	fmt.Printf("ChallengeTimeout:         %s\n", CONFIG.TwoFactor.ChallengeTimeout)
+
+	fmt.Printf("\nSMTP\n")
+	fmt.Printf("Host:                     %s\n", CONFIG.Smtp.Host)
+	fmt.Printf("Port:                     %d\n", CONFIG.Smtp.Port)
+	fmt.Printf("Username:                 %s\n", CONFIG.Smtp.Username)
+	fmt.Printf("From:                     %s\n", CONFIG.Smtp.From)
+	fmt.Printf("ImplicitTls:              %t\n", CONFIG.Smtp.ImplicitTls)
+
+	fmt.Printf("\nINVITE\n")
+	fmt.Printf("LinkUrl:                  %s\n", CONFIG.Invite.LinkUrl)
+	fmt.Printf("InviteTimeout:            %s\n", CONFIG.Invite.InviteTimeout)
+	fmt.Printf("ResetTimeout:             %s\n", CONFIG.Invite.ResetTimeout)
+
+	fmt.Printf("\nOIDC\n")
+	fmt.Printf("Enabled:                  %t\n", CONFIG.Oidc.Enabled)
+	fmt.Printf("IssuerUrl:                %s\n", CONFIG.Oidc.IssuerUrl)
+	fmt.Printf("ClientId:                 %s\n", CONFIG.Oidc.ClientId)
+	fmt.Printf("RedirectUrl:              %s\n", CONFIG.Oidc.RedirectUrl)
+	fmt.Printf("GroupsClaim:              %s\n", CONFIG.Oidc.GroupsClaim)
+	fmt.Printf("AutoProvision:            %t\n", CONFIG.Oidc.AutoProvision)
+	fmt.Printf("AllowUnverifiedEmail:     %t\n", CONFIG.Oidc.AllowUnverifiedEmail)
+
+	fmt.Printf("\nLDAP\n")
+	fmt.Printf("Enabled:                  %t\n", CONFIG.Ldap.Enabled)
+	fmt.Printf("Url:                      %s\n", CONFIG.Ldap.Url)
+	fmt.Printf("StartTls:                 %t\n", CONFIG.Ldap.StartTls)
+	fmt.Printf("BindDn:                   %s\n", CONFIG.Ldap.BindDn)
+	fmt.Printf("BaseDn:                   %s\n", CONFIG.Ldap.BaseDn)
+	fmt.Printf("UserFilter:               %s\n", CONFIG.Ldap.UserFilter)
+	fmt.Printf("AutoProvision:            %t\n", CONFIG.Ldap.AutoProvision)
+
+	fmt.Printf("\nSCIM\n")
+	fmt.Printf("Enabled:                  %t\n", CONFIG.Scim.Enabled)
+	fmt.Printf("DefaultAccessLevel:       %s\n", CONFIG.Scim.DefaultAccessLevel)
+	fmt.Printf("DeleteUsers:              %t\n", CONFIG.Scim.DeleteUsers)
+
+	fmt.Printf("\nCONTEXT HEALTH\n")
+	fmt.Printf("Interval:                 %s\n", CONFIG.ContextHealth.Interval)
+	fmt.Printf("Timeout:                  %s\n", CONFIG.ContextHealth.Timeout)
+
 	fmt.Printf("\nMISC\n")
 	fmt.Printf("Stage:                    %s\n", CONFIG.Misc.Stage)
 	fmt.Printf("Debug:                    %t\n", CONFIG.Misc.Debug)
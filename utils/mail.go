@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+const MAIL_TIMEOUT = 30 * time.Second
+
+// MailConfigured returns false if no SMTP relay is configured. Callers print the content of the mail instead.
+func MailConfigured() bool {
+	return CONFIG.Smtp.Host != ""
+}
+
+// SendMail sends a plain text mail through the configured SMTP relay. The connection uses TLS from the start
+// (smtp.implicit_tls) or is upgraded with STARTTLS if the relay offers it, so local sinks without TLS work too.
+func SendMail(to string, subject string, body string) error {
+	if !MailConfigured() {
+		return fmt.Errorf("no SMTP relay configured")
+	}
+	address := net.JoinHostPort(CONFIG.Smtp.Host, fmt.Sprint(CONFIG.Smtp.Port))
+	tlsConfig := &tls.Config{ServerName: CONFIG.Smtp.Host}
+
+	var conn net.Conn
+	var err error
+	dialer := &net.Dialer{Timeout: MAIL_TIMEOUT}
+	if CONFIG.Smtp.ImplicitTls {
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", address)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP relay %s: %s", address, err.Error())
+	}
+	conn.SetDeadline(time.Now().Add(MAIL_TIMEOUT))
+
+	client, err := smtp.NewClient(conn, CONFIG.Smtp.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if !CONFIG.Smtp.ImplicitTls {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err = client.StartTLS(tlsConfig); err != nil {
+				return err
+			}
+		}
+	}
+	if CONFIG.Smtp.Username != "" {
+		// smtp.PlainAuth refuses to send the password without TLS, except to localhost
+		if err = client.Auth(smtp.PlainAuth("", CONFIG.Smtp.Username, CONFIG.Smtp.Password, CONFIG.Smtp.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err = client.Mail(CONFIG.Smtp.From); err != nil {
+		return err
+	}
+	if strings.ContainsAny(to, "\r\n") {
+		return fmt.Errorf("invalid recipient '%s'", to)
+	}
+	if err = client.Rcpt(to); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(mailMessage(to, subject, body))
+	if err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func mailMessage(to string, subject string, body string) []byte {
+	// line breaks in header values would allow to inject headers
+	header := strings.NewReplacer("\r", "", "\n", "")
+	to = header.Replace(to)
+	subject = header.Replace(subject)
+	headers := []string{
+		fmt.Sprintf("From: %s", CONFIG.Smtp.From),
+		fmt.Sprintf("To: %s", to),
+		fmt.Sprintf("Subject: %s", subject),
+		fmt.Sprintf("Date: %s", time.Now().Format(time.RFC1123Z)),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"Content-Transfer-Encoding: 8bit",
+	}
+	body = strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n")
+	return []byte(strings.Join(headers, "\r\n") + "\r\n\r\n" + body + "\r\n")
+}
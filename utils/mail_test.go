@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+)
+
+// smtpSink accepts one mail per connection without STARTTLS and authentication and sends the data to mails
+func smtpSink(t *testing.T) (net.Listener, chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	mails := make(chan string, 1)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+				reply("220 sink")
+				envelope := []string{}
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					command := strings.ToUpper(strings.TrimSpace(line))
+					switch {
+					case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+						reply("250 sink")
+					case strings.HasPrefix(command, "MAIL FROM:"), strings.HasPrefix(command, "RCPT TO:"):
+						envelope = append(envelope, strings.TrimSpace(line))
+						reply("250 ok")
+					case command == "DATA":
+						reply("354 go ahead")
+						data := strings.Builder{}
+						for {
+							dataLine, err := reader.ReadString('\n')
+							if err != nil {
+								return
+							}
+							if dataLine == ".\r\n" {
+								break
+							}
+							data.WriteString(dataLine)
+						}
+						mails <- strings.Join(envelope, "\r\n") + "\r\n\r\n" + data.String()
+						reply("250 queued")
+					case command == "QUIT":
+						reply("221 bye")
+						return
+					default:
+						reply("502 not implemented")
+					}
+				}
+			}(conn)
+		}
+	}()
+	return listener, mails
+}
+
+func TestSendMail(t *testing.T) {
+	listener, mails := smtpSink(t)
+	previous := CONFIG.Smtp
+	t.Cleanup(func() { CONFIG.Smtp = previous })
+	CONFIG.Smtp.Host = "127.0.0.1"
+	CONFIG.Smtp.Port = listener.Addr().(*net.TCPAddr).Port
+	CONFIG.Smtp.From = "punq@localhost"
+	CONFIG.Smtp.Username = ""
+	CONFIG.Smtp.ImplicitTls = false
+
+	err := SendMail("jane@example.com", "Reset your punq password", "Hello Jane,\n\nhttps://punq.example.com/set-password?token=abc\n")
+	if err != nil {
+		t.Fatalf("SendMail() error = %v", err)
+	}
+	mail := <-mails
+	for _, want := range []string{"MAIL FROM:<punq@localhost>", "RCPT TO:<jane@example.com>", "Subject: Reset your punq password\r\n", "\r\n\r\nHello Jane,\r\n\r\nhttps://punq.example.com/set-password?token=abc\r\n"} {
+		if !strings.Contains(mail, want) {
+			t.Errorf("SendMail() sent %q, want it to contain %q", mail, want)
+		}
+	}
+
+	if err := SendMail("jane@example.com\r\nRCPT TO:<other@example.com>", "subject", "body"); err == nil {
+		t.Errorf("SendMail() with a line break in the recipient error = nil, want an error")
+	}
+
+	CONFIG.Smtp.Host = ""
+	if MailConfigured() || SendMail("jane@example.com", "subject", "body") == nil {
+		t.Errorf("SendMail() without SMTP relay error = nil, want an error")
+	}
+}
+
+func TestMailMessageHeaderInjection(t *testing.T) {
+	message := string(mailMessage("jane@example.com", "Hello\r\nBcc: other@example.com", "body"))
+	if strings.Contains(message, "\r\nBcc:") {
+		t.Errorf("mailMessage() = %q, want the line break in the subject to be removed", message)
+	}
+}
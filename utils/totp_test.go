@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// base32 of the RFC 6238 SHA1 seed "12345678901234567890"
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTotpCode(t *testing.T) {
+	// RFC 6238 appendix B, truncated to 6 digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		got, err := TotpCode(rfc6238Secret, TotpStep(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("TotpCode(%d) error = %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("TotpCode(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestTotpCodeSecretFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		wantErr bool
+	}{
+		{"lower case", strings.ToLower(rfc6238Secret), false},
+		{"padded", rfc6238Secret + "====", false},
+		{"invalid", "not base32!", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TotpCode(tt.secret, 1)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TotpCode() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if err == nil && got != "287082" {
+				t.Errorf("TotpCode() = %s, want 287082", got)
+			}
+		})
+	}
+}
+
+func TestTotpValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	codeAt := func(offset int64) string {
+		code, err := TotpCode(rfc6238Secret, TotpStep(now)+offset)
+		if err != nil {
+			t.Fatalf("TotpCode() error = %v", err)
+		}
+		return code
+	}
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		wantStep int64
+		wantOk   bool
+	}{
+		{"current step", rfc6238Secret, codeAt(0), TotpStep(now), true},
+		{"previous step", rfc6238Secret, codeAt(-1), TotpStep(now) - 1, true},
+		{"next step", rfc6238Secret, codeAt(1), TotpStep(now) + 1, true},
+		{"two steps behind", rfc6238Secret, codeAt(-2), 0, false},
+		{"two steps ahead", rfc6238Secret, codeAt(2), 0, false},
+		{"spaces", rfc6238Secret, " " + codeAt(0)[:3] + " " + codeAt(0)[3:] + " ", TotpStep(now), true},
+		{"too short", rfc6238Secret, codeAt(0)[:5], 0, false},
+		{"too long", rfc6238Secret, codeAt(0) + "0", 0, false},
+		{"empty", rfc6238Secret, "", 0, false},
+		{"invalid secret", "not base32!", codeAt(0), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := TotpValidate(tt.secret, tt.code, now)
+			if ok != tt.wantOk || step != tt.wantStep {
+				t.Errorf("TotpValidate(%s) = (%d, %t), want (%d, %t)", tt.code, step, ok, tt.wantStep, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestNewTotpSecret(t *testing.T) {
+	secret, err := NewTotpSecret()
+	if err != nil {
+		t.Fatalf("NewTotpSecret() error = %v", err)
+	}
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("NewTotpSecret() = %s is not base32: %v", secret, err)
+	}
+	if len(key) != 20 {
+		t.Errorf("NewTotpSecret() has %d bytes, want 20", len(key))
+	}
+}
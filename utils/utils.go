@@ -85,7 +85,7 @@ func IsNewReleaseAvailable() bool {
 		fmt.Println("You are up-to-date 🥰.")
 		return false
 	} else {
-		fmt.Println("Your version is outdated 😭!\n❗️Please update punq: https://punq.dev\n")
+		fmt.Print("Your version is outdated 😭!\n❗️Please update punq: https://punq.dev\n\n")
 		return true
 	}
 }